@@ -0,0 +1,154 @@
+// Package canonical provides a URL canonicalization layer that maps
+// equivalent spellings of a URL to a single canonical form so that they end up
+// as a single link in the link graph.
+package canonical
+
+import (
+	"net"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// DefaultTrackingParams contains the set of query parameters that are
+// commonly used for tracking purposes and do not affect the contents of the
+// page that a URL points to. Entries ending in '*' match any parameter with
+// the same prefix.
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"mc_cid",
+	"mc_eid",
+	"_ga",
+	"yclid",
+}
+
+// ErrInvalidURL is returned by Canonicalize for URLs that cannot be parsed or
+// that are not absolute.
+var ErrInvalidURL = xerrors.New("invalid URL")
+
+// URLCanonicalizer converts URLs into a canonical form by applying the
+// following rules:
+//
+//   - the scheme and host are converted to lower case.
+//   - default ports (80 for http and 443 for https) are removed.
+//   - dot-segments and duplicate slashes are removed from the path.
+//   - trailing slashes are removed from the path.
+//   - fragments are removed.
+//   - tracking query parameters are removed and the remaining parameters are
+//     sorted by name.
+type URLCanonicalizer struct {
+	stripParams   map[string]struct{}
+	stripPrefixes []string
+}
+
+// NewURLCanonicalizer returns a new URLCanonicalizer that strips the specified
+// list of query parameters. Parameter names ending in '*' are treated as
+// prefixes. If no parameters are specified, DefaultTrackingParams will be used
+// instead.
+func NewURLCanonicalizer(stripParams ...string) *URLCanonicalizer {
+	if len(stripParams) == 0 {
+		stripParams = DefaultTrackingParams
+	}
+
+	c := &URLCanonicalizer{stripParams: make(map[string]struct{})}
+	for _, param := range stripParams {
+		param = strings.ToLower(param)
+		if strings.HasSuffix(param, "*") {
+			c.stripPrefixes = append(c.stripPrefixes, strings.TrimSuffix(param, "*"))
+			continue
+		}
+		c.stripParams[param] = struct{}{}
+	}
+	return c
+}
+
+// Canonicalize returns the canonical form of rawURL.
+func (c *URLCanonicalizer) Canonicalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", xerrors.Errorf("canonicalize: %w: %v", ErrInvalidURL, err)
+	} else if !u.IsAbs() || u.Host == "" {
+		return "", xerrors.Errorf("canonicalize: %w: %q is not an absolute URL", ErrInvalidURL, rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = canonicalHost(u.Scheme, u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if err = canonicalizePath(u); err != nil {
+		return "", xerrors.Errorf("canonicalize: %w: %v", ErrInvalidURL, err)
+	}
+	u.RawQuery = c.canonicalQuery(u.Query())
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// canonicalHost lower-cases host and strips the port if it is the default port
+// for scheme.
+func canonicalHost(scheme, host string) string {
+	host = strings.ToLower(host)
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		// No port specified.
+		return strings.TrimSuffix(host, ".")
+	}
+
+	hostname = strings.TrimSuffix(hostname, ".")
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") || port == "" {
+		if strings.Contains(hostname, ":") {
+			// Retain brackets for IPv6 addresses.
+			return "[" + hostname + "]"
+		}
+		return hostname
+	}
+	return net.JoinHostPort(hostname, port)
+}
+
+// canonicalizePath resolves dot-segments and strips duplicate and trailing
+// slashes from the escaped path of u.
+func canonicalizePath(u *url.URL) error {
+	escPath := u.EscapedPath()
+	if escPath == "" {
+		return nil
+	}
+
+	escPath = strings.TrimSuffix(path.Clean("/"+escPath), "/")
+	unescPath, err := url.PathUnescape(escPath)
+	if err != nil {
+		return err
+	}
+	u.Path, u.RawPath = unescPath, escPath
+	return nil
+}
+
+// canonicalQuery removes any tracking parameters from values and encodes the
+// remaining parameters sorted by name.
+func (c *URLCanonicalizer) canonicalQuery(values url.Values) string {
+	for param := range values {
+		if c.isTrackingParam(param) {
+			delete(values, param)
+		}
+	}
+	return values.Encode()
+}
+
+func (c *URLCanonicalizer) isTrackingParam(param string) bool {
+	param = strings.ToLower(param)
+	if _, exists := c.stripParams[param]; exists {
+		return true
+	}
+	for _, prefix := range c.stripPrefixes {
+		if strings.HasPrefix(param, prefix) {
+			return true
+		}
+	}
+	return false
+}
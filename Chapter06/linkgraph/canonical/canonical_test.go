@@ -0,0 +1,111 @@
+package canonical
+
+import (
+	"testing"
+
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(CanonicalizerTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type CanonicalizerTestSuite struct{}
+
+func (s *CanonicalizerTestSuite) TestCanonicalize(c *gc.C) {
+	specs := []struct {
+		descr string
+		in    string
+		exp   string
+	}{
+		{
+			descr: "scheme and host case folding",
+			in:    "HTTP://Example.COM/Path",
+			exp:   "http://example.com/Path",
+		},
+		{
+			descr: "default http port",
+			in:    "http://example.com:80/a",
+			exp:   "http://example.com/a",
+		},
+		{
+			descr: "default https port",
+			in:    "https://example.com:443/a",
+			exp:   "https://example.com/a",
+		},
+		{
+			descr: "non-default port",
+			in:    "https://example.com:8443/a",
+			exp:   "https://example.com:8443/a",
+		},
+		{
+			descr: "trailing slash",
+			in:    "http://Example.com/a/",
+			exp:   "http://example.com/a",
+		},
+		{
+			descr: "root path",
+			in:    "https://example.com/",
+			exp:   "https://example.com",
+		},
+		{
+			descr: "dot segments and duplicate slashes",
+			in:    "https://example.com/a/./b/../c//d",
+			exp:   "https://example.com/a/c/d",
+		},
+		{
+			descr: "escaped path",
+			in:    "https://example.com/a%2Fb/c%20d/",
+			exp:   "https://example.com/a%2Fb/c%20d",
+		},
+		{
+			descr: "fragment",
+			in:    "https://example.com/a#section",
+			exp:   "https://example.com/a",
+		},
+		{
+			descr: "tracking parameters",
+			in:    "https://example.com/a?utm_source=x&UTM_Medium=y&fbclid=z",
+			exp:   "https://example.com/a",
+		},
+		{
+			descr: "sorted query parameters",
+			in:    "https://example.com/a?b=2&utm_campaign=x&a=1",
+			exp:   "https://example.com/a?a=1&b=2",
+		},
+		{
+			descr: "empty query",
+			in:    "https://example.com/a?",
+			exp:   "https://example.com/a",
+		},
+		{
+			descr: "IPv6 host with default port",
+			in:    "http://[::1]:80/a",
+			exp:   "http://[::1]/a",
+		},
+	}
+
+	canon := NewURLCanonicalizer()
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		got, err := canon.Canonicalize(spec.in)
+		c.Assert(err, gc.IsNil)
+		c.Assert(got, gc.Equals, spec.exp)
+	}
+}
+
+func (s *CanonicalizerTestSuite) TestCustomTrackingParams(c *gc.C) {
+	canon := NewURLCanonicalizer("sessionid", "ref_*")
+	got, err := canon.Canonicalize("https://example.com/a?sessionid=1&ref_src=2&utm_source=3")
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.Equals, "https://example.com/a?utm_source=3")
+}
+
+func (s *CanonicalizerTestSuite) TestInvalidURL(c *gc.C) {
+	canon := NewURLCanonicalizer()
+	for _, in := range []string{"/relative/path", "example.com", "http://[::1"} {
+		_, err := canon.Canonicalize(in)
+		c.Assert(xerrors.Is(err, ErrInvalidURL), gc.Equals, true, gc.Commentf("input %q", in))
+	}
+}
@@ -9,4 +9,8 @@ var (
 	// ErrUnknownEdgeLinks is returned when attempting to create an edge
 	// with an invalid source and/or destination ID
 	ErrUnknownEdgeLinks = xerrors.New("unknown source and/or destination for edge")
+
+	// ErrChangeLogTruncated is returned by Watch when some of the change
+	// events since the requested timestamp are no longer available.
+	ErrChangeLogTruncated = xerrors.New("change log truncated")
 )
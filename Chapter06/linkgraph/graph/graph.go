@@ -35,6 +35,15 @@ type EdgeIterator interface {
 	Edge() *Edge
 }
 
+// ChangeEventIterator is implemented by objects that can iterate the change
+// events of a graph.
+type ChangeEventIterator interface {
+	Iterator
+
+	// Event returns the currently fetched change event.
+	Event() *ChangeEvent
+}
+
 // Link encapsulates all information about a link discovered by the Links 'R'
 // Us crawler.
 type Link struct {
@@ -46,6 +55,20 @@ type Link struct {
 
 	// The timestamp when the link was last retrieved.
 	RetrievedAt time.Time
+
+	// The HTTP status code returned by the last retrieval attempt or zero
+	// if the link could not be retrieved.
+	HTTPStatus int
+
+	// The content type reported by the remote server for the last
+	// retrieval attempt.
+	ContentType string
+
+	// A hash of the content returned by the last successful retrieval.
+	ContentHash string
+
+	// The number of consecutive failed attempts to retrieve the link.
+	FailureCount int
 }
 
 // Edge describes a graph edge that originates from Src and terminates
@@ -64,27 +87,105 @@ type Edge struct {
 	UpdatedAt time.Time
 }
 
+// ChangeType describes the type of mutation captured by a ChangeEvent.
+type ChangeType uint8
+
+const (
+	// LinkUpserted indicates that a link was created or updated.
+	LinkUpserted ChangeType = iota + 1
+
+	// LinkRemoved indicates that a link was removed.
+	LinkRemoved
+
+	// EdgeUpserted indicates that an edge was created or updated.
+	EdgeUpserted
+
+	// EdgeRemoved indicates that an edge was removed.
+	EdgeRemoved
+)
+
+// ChangeEvent describes a mutation of the link graph.
+type ChangeEvent struct {
+	// The type of the mutation.
+	Type ChangeType
+
+	// The timestamp assigned to the mutation by the graph store when it
+	// was applied. Timestamps never decrease in the order in which
+	// mutations are applied and are unrelated to the RetrievedAt field of
+	// the affected link. Mutations applied together, e.g. by a batch
+	// upsert or a link removal, may share the same timestamp.
+	Timestamp time.Time
+
+	// The affected link for LinkUpserted and LinkRemoved events.
+	Link *Link
+
+	// The affected edge for EdgeUpserted and EdgeRemoved events.
+	Edge *Edge
+}
+
 // Graph is implemented by objects that can mutate or query a link graph.
 type Graph interface {
-	// UpsertLink creates a new link or updates an existing link.
+	// UpsertLink creates a new link or updates an existing link. The
+	// retrieval metadata of an existing link is only updated if the
+	// provided link was retrieved at the same time or after the stored link.
 	UpsertLink(link *Link) error
 
+	// UpsertLinks creates or updates a batch of links. Each link in the
+	// batch is upserted following the same rules as UpsertLink.
+	UpsertLinks(links []*Link) error
+
 	// FindLink looks up a link by its ID.
 	FindLink(id uuid.UUID) (*Link, error)
 
 	// Links returns an iterator for the set of links whose IDs belong to the
 	// [fromID, toID) range and were retrieved before the provided timestamp.
+	// Links are returned in ascending ID order.
 	Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (LinkIterator, error)
 
 	// UpsertEdge creates a new edge or updates an existing edge.
 	UpsertEdge(edge *Edge) error
 
+	// UpsertEdges creates or updates a batch of edges. Each edge in the
+	// batch is upserted following the same rules as UpsertEdge.
+	UpsertEdges(edges []*Edge) error
+
 	// Edges returns an iterator for the set of edges whose source vertex IDs
 	// belong to the [fromID, toID) range and were updated before the provided
 	// timestamp.
 	Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (EdgeIterator, error)
 
+	// InboundEdges returns an iterator for the set of edges that terminate
+	// at the specified link ID.
+	InboundEdges(dstID uuid.UUID) (EdgeIterator, error)
+
+	// InDegree returns the number of edges that terminate at the specified
+	// link ID.
+	InDegree(id uuid.UUID) (int, error)
+
+	// OutDegree returns the number of edges that originate from the
+	// specified link ID.
+	OutDegree(id uuid.UUID) (int, error)
+
 	// RemoveStaleEdges removes any edge that originates from the specified
 	// link ID and was updated before the specified timestamp.
 	RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error
+
+	// RemoveLink removes the link with the specified ID as well as any edge
+	// that originates from or terminates at it.
+	RemoveLink(id uuid.UUID) error
+
+	// RemoveLinksByHost removes all links whose URL points to the specified
+	// host as well as any edges that originate from or terminate at them.
+	RemoveLinksByHost(host string) error
+
+	// Watch returns an iterator for the link and edge mutations that took
+	// place after the provided timestamp, ordered by their timestamps.
+	// Implementations may cap the number of returned events but never
+	// split the events that share a timestamp across calls; callers
+	// should keep invoking Watch with the timestamp of the last received
+	// event until no more events are returned. If some of the requested
+	// change events are no longer available, Watch returns
+	// ErrChangeLogTruncated and callers must fall back to a full scan of
+	// the graph.
+	Watch(since time.Time) (ChangeEventIterator, error)
 }
@@ -1,6 +1,7 @@
 package graphtest
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"sort"
@@ -75,6 +76,95 @@ func (s *SuiteBase) TestUpsertLink(c *gc.C) {
 	c.Assert(dup.ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected a linkID to be assigned to the new link"))
 }
 
+// TestUpsertLinkMetadata verifies that the link retrieval metadata is
+// persisted and never overwritten by stale information.
+func (s *SuiteBase) TestUpsertLinkMetadata(c *gc.C) {
+	retrievedAt := time.Now().Truncate(time.Second).UTC()
+	link := &graph.Link{
+		URL:          "https://example.com",
+		RetrievedAt:  retrievedAt,
+		HTTPStatus:   200,
+		ContentType:  "text/html",
+		ContentHash:  "abcd",
+		FailureCount: 0,
+	}
+	c.Assert(s.g.UpsertLink(link), gc.IsNil)
+
+	stored, err := s.g.FindLink(link.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.DeepEquals, link)
+
+	// Upserting the same URL without retrieval information (e.g. when the
+	// link is discovered in another page) must not reset the metadata.
+	discovered := &graph.Link{URL: link.URL}
+	c.Assert(s.g.UpsertLink(discovered), gc.IsNil)
+	c.Assert(discovered.ID, gc.Equals, link.ID)
+
+	stored, err = s.g.FindLink(link.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.DeepEquals, link, gc.Commentf("link metadata was overwritten with stale information"))
+
+	// Record a failed retrieval attempt
+	failed := &graph.Link{
+		URL:          link.URL,
+		RetrievedAt:  retrievedAt.Add(time.Hour),
+		HTTPStatus:   404,
+		ContentType:  "text/plain",
+		FailureCount: 1,
+	}
+	c.Assert(s.g.UpsertLink(failed), gc.IsNil)
+	c.Assert(failed.ID, gc.Equals, link.ID)
+
+	stored, err = s.g.FindLink(link.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.DeepEquals, failed, gc.Commentf("link metadata was not updated"))
+
+	// Iterators should also return the link metadata
+	it, err := s.partitionedLinkIterator(c, 0, 1, time.Now().Add(2*time.Hour))
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Link(), gc.DeepEquals, failed)
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
+
+// TestUpsertLinks verifies the batch link upsert logic.
+func (s *SuiteBase) TestUpsertLinks(c *gc.C) {
+	existing := &graph.Link{URL: "https://example.com"}
+	c.Assert(s.g.UpsertLink(existing), gc.IsNil)
+
+	retrievedAt := time.Now().Truncate(time.Second).UTC()
+	batch := []*graph.Link{
+		{URL: "https://example.com", RetrievedAt: retrievedAt},
+		{URL: "https://foo.com"},
+		{URL: "https://bar.com"},
+		// Duplicate URLs within the same batch must be mapped to the
+		// same link ID.
+		{URL: "https://foo.com"},
+	}
+	c.Assert(s.g.UpsertLinks(batch), gc.IsNil)
+
+	c.Assert(batch[0].ID, gc.Equals, existing.ID, gc.Commentf("link ID changed while upserting"))
+	c.Assert(batch[1].ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected a linkID to be assigned to the new link"))
+	c.Assert(batch[2].ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected a linkID to be assigned to the new link"))
+	c.Assert(batch[1].ID, gc.Not(gc.Equals), batch[2].ID)
+	c.Assert(batch[3].ID, gc.Equals, batch[1].ID)
+
+	stored, err := s.g.FindLink(existing.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored.RetrievedAt, gc.Equals, retrievedAt, gc.Commentf("last accessed timestamp was not updated"))
+
+	for _, link := range batch[1:] {
+		stored, err = s.g.FindLink(link.ID)
+		c.Assert(err, gc.IsNil)
+		c.Assert(stored.URL, gc.Equals, link.URL)
+	}
+
+	// Upserting an empty batch is a no-op
+	c.Assert(s.g.UpsertLinks(nil), gc.IsNil)
+}
+
 // TestFindLink verifies the link lookup logic.
 func (s *SuiteBase) TestFindLink(c *gc.C) {
 	// Create a new link
@@ -199,6 +289,31 @@ func (s *SuiteBase) TestPartitionedLinkIterators(c *gc.C) {
 	c.Assert(s.iteratePartitionedLinks(c, numPartitions+1), gc.Equals, numLinks)
 }
 
+// TestLinkIteratorOrder verifies that link iterators return links in
+// ascending ID order.
+func (s *SuiteBase) TestLinkIteratorOrder(c *gc.C) {
+	numLinks := 50
+	for i := 0; i < numLinks; i++ {
+		c.Assert(s.g.UpsertLink(&graph.Link{URL: fmt.Sprint(i)}), gc.IsNil)
+	}
+
+	it, err := s.partitionedLinkIterator(c, 0, 1, time.Now())
+	c.Assert(err, gc.IsNil)
+
+	var prevID uuid.UUID
+	var linkCount int
+	for ; it.Next(); linkCount++ {
+		linkID := it.Link().ID
+		if linkCount != 0 {
+			c.Assert(bytes.Compare(prevID[:], linkID[:]) < 0, gc.Equals, true, gc.Commentf("link %s returned after %s", linkID, prevID))
+		}
+		prevID = linkID
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+	c.Assert(linkCount, gc.Equals, numLinks)
+}
+
 func (s *SuiteBase) iteratePartitionedLinks(c *gc.C, numPartitions int) int {
 	seen := make(map[string]bool)
 	for partition := 0; partition < numPartitions; partition++ {
@@ -263,6 +378,54 @@ func (s *SuiteBase) TestUpsertEdge(c *gc.C) {
 	c.Assert(xerrors.Is(err, graph.ErrUnknownEdgeLinks), gc.Equals, true)
 }
 
+// TestUpsertEdges verifies the batch edge upsert logic.
+func (s *SuiteBase) TestUpsertEdges(c *gc.C) {
+	// Create links
+	linkUUIDs := make([]uuid.UUID, 3)
+	for i := 0; i < 3; i++ {
+		link := &graph.Link{URL: fmt.Sprint(i)}
+		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		linkUUIDs[i] = link.ID
+	}
+
+	existing := &graph.Edge{Src: linkUUIDs[0], Dst: linkUUIDs[1]}
+	c.Assert(s.g.UpsertEdge(existing), gc.IsNil)
+
+	batch := []*graph.Edge{
+		{Src: linkUUIDs[0], Dst: linkUUIDs[1]},
+		{Src: linkUUIDs[0], Dst: linkUUIDs[2]},
+		{Src: linkUUIDs[1], Dst: linkUUIDs[2]},
+		// Duplicate edges within the same batch must be mapped to the
+		// same edge ID.
+		{Src: linkUUIDs[1], Dst: linkUUIDs[2]},
+	}
+	c.Assert(s.g.UpsertEdges(batch), gc.IsNil)
+
+	c.Assert(batch[0].ID, gc.Equals, existing.ID, gc.Commentf("edge ID changed while upserting"))
+	c.Assert(batch[0].UpdatedAt.After(existing.UpdatedAt), gc.Equals, true, gc.Commentf("UpdatedAt field not modified"))
+	for _, edge := range batch[1:] {
+		c.Assert(edge.ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected an edgeID to be assigned to the new edge"))
+		c.Assert(edge.UpdatedAt.IsZero(), gc.Equals, false, gc.Commentf("UpdatedAt field not set"))
+	}
+	c.Assert(batch[1].ID, gc.Not(gc.Equals), batch[2].ID)
+	c.Assert(batch[3].ID, gc.Equals, batch[2].ID)
+
+	outDegree, err := s.g.OutDegree(linkUUIDs[0])
+	c.Assert(err, gc.IsNil)
+	c.Assert(outDegree, gc.Equals, 2)
+
+	// Upsert a batch containing an edge with unknown link IDs
+	bogus := []*graph.Edge{
+		{Src: linkUUIDs[2], Dst: linkUUIDs[0]},
+		{Src: linkUUIDs[2], Dst: uuid.New()},
+	}
+	err = s.g.UpsertEdges(bogus)
+	c.Assert(xerrors.Is(err, graph.ErrUnknownEdgeLinks), gc.Equals, true)
+
+	// Upserting an empty batch is a no-op
+	c.Assert(s.g.UpsertEdges(nil), gc.IsNil)
+}
+
 // TestConcurrentEdgeIterators verifies that multiple clients can concurrently
 // access the store.
 func (s *SuiteBase) TestConcurrentEdgeIterators(c *gc.C) {
@@ -477,6 +640,277 @@ func (s *SuiteBase) TestRemoveStaleEdges(c *gc.C) {
 	c.Assert(seen, gc.Equals, numEdges)
 }
 
+// TestInboundEdges verifies that the edges terminating at a particular link
+// can be iterated.
+func (s *SuiteBase) TestInboundEdges(c *gc.C) {
+	linkUUIDs := make([]uuid.UUID, 4)
+	for i := 0; i < len(linkUUIDs); i++ {
+		link := &graph.Link{URL: fmt.Sprint(i)}
+		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		linkUUIDs[i] = link.ID
+	}
+
+	// Create edges 1 -> 0, 2 -> 0, 3 -> 0 and 0 -> 1
+	var expEdgeIDs []uuid.UUID
+	for i := 1; i < len(linkUUIDs); i++ {
+		edge := &graph.Edge{Src: linkUUIDs[i], Dst: linkUUIDs[0]}
+		c.Assert(s.g.UpsertEdge(edge), gc.IsNil)
+		expEdgeIDs = append(expEdgeIDs, edge.ID)
+	}
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: linkUUIDs[0], Dst: linkUUIDs[1]}), gc.IsNil)
+
+	s.assertInboundEdgeIDsMatch(c, linkUUIDs[0], expEdgeIDs)
+	s.assertInboundEdgeIDsMatch(c, linkUUIDs[3], nil)
+
+	// Inbound edges should be updated when stale edges get removed
+	c.Assert(s.g.RemoveStaleEdges(linkUUIDs[1], time.Now().Add(time.Minute)), gc.IsNil)
+	s.assertInboundEdgeIDsMatch(c, linkUUIDs[0], expEdgeIDs[1:])
+
+	// Inbound edges should be updated when the source link gets removed
+	c.Assert(s.g.RemoveLink(linkUUIDs[2]), gc.IsNil)
+	s.assertInboundEdgeIDsMatch(c, linkUUIDs[0], expEdgeIDs[2:])
+}
+
+func (s *SuiteBase) assertInboundEdgeIDsMatch(c *gc.C, dstID uuid.UUID, exp []uuid.UUID) {
+	it, err := s.g.InboundEdges(dstID)
+	c.Assert(err, gc.IsNil)
+
+	var got []uuid.UUID
+	for it.Next() {
+		edge := it.Edge()
+		c.Assert(edge.Dst, gc.Equals, dstID)
+		got = append(got, edge.ID)
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+
+	// Sort a copy of exp so as not to modify the caller's slice
+	exp = append([]uuid.UUID(nil), exp...)
+	sort.Slice(got, func(l, r int) bool { return got[l].String() < got[r].String() })
+	sort.Slice(exp, func(l, r int) bool { return exp[l].String() < exp[r].String() })
+	c.Assert(got, gc.DeepEquals, exp)
+}
+
+// TestDegree verifies that the in/out degree counters work as expected.
+func (s *SuiteBase) TestDegree(c *gc.C) {
+	linkUUIDs := make([]uuid.UUID, 4)
+	for i := 0; i < len(linkUUIDs); i++ {
+		link := &graph.Link{URL: fmt.Sprint(i)}
+		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		linkUUIDs[i] = link.ID
+	}
+
+	// Create edges 0 -> 1, 0 -> 2, 0 -> 3 and 1 -> 2
+	for i := 1; i < len(linkUUIDs); i++ {
+		c.Assert(s.g.UpsertEdge(&graph.Edge{Src: linkUUIDs[0], Dst: linkUUIDs[i]}), gc.IsNil)
+	}
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: linkUUIDs[1], Dst: linkUUIDs[2]}), gc.IsNil)
+
+	// Upserting an existing edge should not affect the counters
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: linkUUIDs[1], Dst: linkUUIDs[2]}), gc.IsNil)
+
+	specs := []struct {
+		linkID        uuid.UUID
+		expIn, expOut int
+	}{
+		{linkID: linkUUIDs[0], expIn: 0, expOut: 3},
+		{linkID: linkUUIDs[1], expIn: 1, expOut: 1},
+		{linkID: linkUUIDs[2], expIn: 2, expOut: 0},
+		{linkID: linkUUIDs[3], expIn: 1, expOut: 0},
+	}
+	for specIndex, spec := range specs {
+		comment := gc.Commentf("link %d", specIndex)
+		in, err := s.g.InDegree(spec.linkID)
+		c.Assert(err, gc.IsNil, comment)
+		c.Assert(in, gc.Equals, spec.expIn, comment)
+
+		out, err := s.g.OutDegree(spec.linkID)
+		c.Assert(err, gc.IsNil, comment)
+		c.Assert(out, gc.Equals, spec.expOut, comment)
+	}
+
+	// Query counters for unknown link
+	_, err := s.g.InDegree(uuid.New())
+	c.Assert(xerrors.Is(err, graph.ErrNotFound), gc.Equals, true)
+	_, err = s.g.OutDegree(uuid.New())
+	c.Assert(xerrors.Is(err, graph.ErrNotFound), gc.Equals, true)
+}
+
+// TestRemoveLink verifies that removing a link also removes any edges that
+// originate from or terminate at it.
+func (s *SuiteBase) TestRemoveLink(c *gc.C) {
+	linkUUIDs := make([]uuid.UUID, 3)
+	for i := 0; i < len(linkUUIDs); i++ {
+		link := &graph.Link{URL: fmt.Sprint(i)}
+		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		linkUUIDs[i] = link.ID
+	}
+
+	// Create edges 0 -> 1, 1 -> 2 and 2 -> 0
+	keptEdge := &graph.Edge{Src: linkUUIDs[2], Dst: linkUUIDs[0]}
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: linkUUIDs[0], Dst: linkUUIDs[1]}), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: linkUUIDs[1], Dst: linkUUIDs[2]}), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(keptEdge), gc.IsNil)
+
+	c.Assert(s.g.RemoveLink(linkUUIDs[1]), gc.IsNil)
+
+	_, err := s.g.FindLink(linkUUIDs[1])
+	c.Assert(xerrors.Is(err, graph.ErrNotFound), gc.Equals, true)
+	s.assertIteratedLinkIDsMatch(c, time.Now(), []uuid.UUID{linkUUIDs[0], linkUUIDs[2]})
+	s.assertIteratedEdgeIDsMatch(c, time.Now(), []uuid.UUID{keptEdge.ID})
+
+	// Edges pointing to a removed link should be rejected
+	err = s.g.UpsertEdge(&graph.Edge{Src: linkUUIDs[0], Dst: linkUUIDs[1]})
+	c.Assert(xerrors.Is(err, graph.ErrUnknownEdgeLinks), gc.Equals, true)
+
+	// Re-inserting a link with the same URL should yield a new link
+	readded := &graph.Link{URL: "1"}
+	c.Assert(s.g.UpsertLink(readded), gc.IsNil)
+	c.Assert(readded.ID, gc.Not(gc.Equals), linkUUIDs[1])
+
+	// Removing an unknown link should fail
+	err = s.g.RemoveLink(uuid.New())
+	c.Assert(xerrors.Is(err, graph.ErrNotFound), gc.Equals, true)
+}
+
+// TestRemoveLinksByHost verifies that all links that point to a particular
+// host as well as their edges can be removed in bulk.
+func (s *SuiteBase) TestRemoveLinksByHost(c *gc.C) {
+	urls := []string{
+		"https://example.com",
+		"http://spam.example.com/a",
+		"https://SPAM.example.com:8080/b?c=d",
+		"https://spam.example.com.evil/",
+		"https://other.com/spam.example.com",
+	}
+	linkUUIDs := make([]uuid.UUID, len(urls))
+	for i, url := range urls {
+		link := &graph.Link{URL: url}
+		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		linkUUIDs[i] = link.ID
+	}
+
+	keptEdge := &graph.Edge{Src: linkUUIDs[0], Dst: linkUUIDs[3]}
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: linkUUIDs[0], Dst: linkUUIDs[1]}), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: linkUUIDs[2], Dst: linkUUIDs[4]}), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(keptEdge), gc.IsNil)
+
+	c.Assert(s.g.RemoveLinksByHost("spam.example.com"), gc.IsNil)
+	s.assertIteratedLinkIDsMatch(c, time.Now(), []uuid.UUID{linkUUIDs[0], linkUUIDs[3], linkUUIDs[4]})
+	s.assertIteratedEdgeIDsMatch(c, time.Now(), []uuid.UUID{keptEdge.ID})
+
+	// Removing links for a host with no links should be a no-op
+	c.Assert(s.g.RemoveLinksByHost("unknown.com"), gc.IsNil)
+	s.assertIteratedLinkIDsMatch(c, time.Now(), []uuid.UUID{linkUUIDs[0], linkUUIDs[3], linkUUIDs[4]})
+}
+
+// TestWatch verifies that link and edge upserts are reported by the change
+// feed in timestamp order.
+func (s *SuiteBase) TestWatch(c *gc.C) {
+	old := &graph.Link{URL: "https://example.com/old", RetrievedAt: time.Now().Add(-time.Hour)}
+	c.Assert(s.g.UpsertLink(old), gc.IsNil)
+
+	// Ensure that the changes below are strictly newer than since.
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	fresh := &graph.Link{URL: "https://example.com/fresh", RetrievedAt: time.Now()}
+	c.Assert(s.g.UpsertLink(fresh), gc.IsNil)
+	time.Sleep(10 * time.Millisecond)
+	edge := &graph.Edge{Src: old.ID, Dst: fresh.ID}
+	c.Assert(s.g.UpsertEdge(edge), gc.IsNil)
+
+	it, err := s.g.Watch(since)
+	c.Assert(err, gc.IsNil)
+
+	var (
+		events   []*graph.ChangeEvent
+		lastSeen time.Time
+	)
+	for it.Next() {
+		evt := it.Event()
+		c.Assert(evt.Timestamp.Before(lastSeen), gc.Equals, false, gc.Commentf("events are not ordered by timestamp"))
+		c.Assert(evt.Timestamp.After(since), gc.Equals, true, gc.Commentf("event timestamp %v is not after %v", evt.Timestamp, since))
+		lastSeen = evt.Timestamp
+		events = append(events, evt)
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+
+	c.Assert(events, gc.HasLen, 2)
+	c.Assert(events[0].Type, gc.Equals, graph.LinkUpserted)
+	c.Assert(events[0].Link.ID, gc.Equals, fresh.ID)
+	c.Assert(events[1].Type, gc.Equals, graph.EdgeUpserted)
+	c.Assert(events[1].Edge.ID, gc.Equals, edge.ID)
+
+	// Watching from the last seen event should yield no further events.
+	it, err = s.g.Watch(lastSeen)
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
+
+// TestWatchMutationTimestamps verifies that change events are timestamped
+// when the mutation is applied rather than by the link and edge timestamps
+// and that removals are also reported.
+func (s *SuiteBase) TestWatchMutationTimestamps(c *gc.C) {
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	src := &graph.Link{URL: "https://example.com/src"}
+	c.Assert(s.g.UpsertLink(src), gc.IsNil)
+	dst := &graph.Link{URL: "https://example.com/dst", RetrievedAt: time.Now().Add(-time.Hour)}
+	c.Assert(s.g.UpsertLink(dst), gc.IsNil)
+	edge := &graph.Edge{Src: src.ID, Dst: dst.ID}
+	c.Assert(s.g.UpsertEdge(edge), gc.IsNil)
+
+	events := s.watchEvents(c, since)
+	c.Assert(events, gc.HasLen, 3)
+	c.Assert(events[0].Type, gc.Equals, graph.LinkUpserted)
+	c.Assert(events[0].Link.ID, gc.Equals, src.ID)
+	c.Assert(events[1].Type, gc.Equals, graph.LinkUpserted)
+	c.Assert(events[1].Link.ID, gc.Equals, dst.ID)
+	c.Assert(events[2].Type, gc.Equals, graph.EdgeUpserted)
+	c.Assert(events[2].Edge.ID, gc.Equals, edge.ID)
+
+	// Links that are upserted after the last poll should be reported even
+	// if their retrieval timestamp predates the last seen event.
+	since = events[len(events)-1].Timestamp
+	late := &graph.Link{URL: "https://example.com/late", RetrievedAt: since.Add(-time.Minute)}
+	c.Assert(s.g.UpsertLink(late), gc.IsNil)
+	c.Assert(s.g.RemoveLink(dst.ID), gc.IsNil)
+
+	events = s.watchEvents(c, since)
+	c.Assert(events, gc.HasLen, 3)
+	c.Assert(events[0].Type, gc.Equals, graph.LinkUpserted)
+	c.Assert(events[0].Link.ID, gc.Equals, late.ID)
+	c.Assert(events[1].Type, gc.Equals, graph.EdgeRemoved)
+	c.Assert(events[1].Edge.ID, gc.Equals, edge.ID)
+	c.Assert(events[2].Type, gc.Equals, graph.LinkRemoved)
+	c.Assert(events[2].Link.ID, gc.Equals, dst.ID)
+}
+
+// watchEvents returns the change events after since and verifies that they
+// are ordered by their timestamps.
+func (s *SuiteBase) watchEvents(c *gc.C, since time.Time) []*graph.ChangeEvent {
+	it, err := s.g.Watch(since)
+	c.Assert(err, gc.IsNil)
+
+	var events []*graph.ChangeEvent
+	lastSeen := since
+	for it.Next() {
+		evt := it.Event()
+		c.Assert(evt.Timestamp.After(since), gc.Equals, true, gc.Commentf("event timestamp %v is not after %v", evt.Timestamp, since))
+		c.Assert(evt.Timestamp.Before(lastSeen), gc.Equals, false, gc.Commentf("events are not ordered by timestamp"))
+		lastSeen = evt.Timestamp
+		events = append(events, evt)
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+	return events
+}
+
 func (s *SuiteBase) partitionedLinkIterator(c *gc.C, partition, numPartitions int, accessedBefore time.Time) (graph.LinkIterator, error) {
 	from, to := s.partitionRange(c, partition, numPartitions)
 	return s.g.Links(from, to, accessedBefore)
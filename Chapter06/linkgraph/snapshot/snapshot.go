@@ -0,0 +1,224 @@
+// Package snapshot implements a streaming export/import facility for moving
+// the contents of a link graph between different graph instances.
+//
+// Snapshots are gzip-compressed streams of line-delimited JSON records. The
+// first record is a header that describes the snapshot format version. It is
+// followed by a record for each link in the graph and, finally, by a record
+// for each edge in the graph.
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// FormatVersion is the version of the snapshot format emitted by Export.
+const FormatVersion = 1
+
+const formatName = "linkgraph-snapshot"
+
+var (
+	minUUID = uuid.Nil
+	maxUUID = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
+
+	// ErrUnsupportedFormat is returned by Import when the snapshot header
+	// is missing or specifies an unsupported format version.
+	ErrUnsupportedFormat = xerrors.New("unsupported snapshot format")
+)
+
+// Source is implemented by link graphs that can be exported.
+type Source interface {
+	// Links returns an iterator for the set of links whose IDs belong to the
+	// [fromID, toID) range and were retrieved before the provided timestamp.
+	Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
+
+	// Edges returns an iterator for the set of edges whose source vertex IDs
+	// belong to the [fromID, toID) range and were updated before the provided
+	// timestamp.
+	Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error)
+}
+
+// Target is implemented by link graphs that snapshots can be imported into.
+type Target interface {
+	// UpsertLink creates a new link or updates an existing link.
+	UpsertLink(link *graph.Link) error
+
+	// UpsertEdge creates a new edge or updates an existing edge.
+	UpsertEdge(edge *graph.Edge) error
+}
+
+// Stats contains the number of links and edges that were exported or
+// imported.
+type Stats struct {
+	Links int
+	Edges int
+}
+
+type header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type record struct {
+	Link *linkRecord `json:"link,omitempty"`
+	Edge *edgeRecord `json:"edge,omitempty"`
+}
+
+type linkRecord struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	RetrievedAt  time.Time `json:"retrieved_at"`
+	HTTPStatus   int       `json:"http_status,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	ContentHash  string    `json:"content_hash,omitempty"`
+	FailureCount int       `json:"failure_count,omitempty"`
+}
+
+type edgeRecord struct {
+	ID        uuid.UUID `json:"id"`
+	Src       uuid.UUID `json:"src"`
+	Dst       uuid.UUID `json:"dst"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Export writes a compressed snapshot of all links and edges in src to w.
+func Export(w io.Writer, src Source) (Stats, error) {
+	var (
+		stats Stats
+		now   = time.Now()
+		gz    = gzip.NewWriter(w)
+		enc   = json.NewEncoder(gz)
+	)
+
+	if err := enc.Encode(header{Format: formatName, Version: FormatVersion, CreatedAt: now.UTC()}); err != nil {
+		return stats, xerrors.Errorf("export: %w", err)
+	}
+
+	linkIt, err := src.Links(minUUID, maxUUID, now)
+	if err != nil {
+		return stats, xerrors.Errorf("export: %w", err)
+	}
+	for linkIt.Next() {
+		link := linkIt.Link()
+		err = enc.Encode(record{Link: &linkRecord{
+			ID:           link.ID,
+			URL:          link.URL,
+			RetrievedAt:  link.RetrievedAt.UTC(),
+			HTTPStatus:   link.HTTPStatus,
+			ContentType:  link.ContentType,
+			ContentHash:  link.ContentHash,
+			FailureCount: link.FailureCount,
+		}})
+		if err != nil {
+			_ = linkIt.Close()
+			return stats, xerrors.Errorf("export: %w", err)
+		}
+		stats.Links++
+	}
+	if err = closeIterator(linkIt); err != nil {
+		return stats, xerrors.Errorf("export: %w", err)
+	}
+
+	edgeIt, err := src.Edges(minUUID, maxUUID, now)
+	if err != nil {
+		return stats, xerrors.Errorf("export: %w", err)
+	}
+	for edgeIt.Next() {
+		edge := edgeIt.Edge()
+		err = enc.Encode(record{Edge: &edgeRecord{
+			ID:        edge.ID,
+			Src:       edge.Src,
+			Dst:       edge.Dst,
+			UpdatedAt: edge.UpdatedAt.UTC(),
+		}})
+		if err != nil {
+			_ = edgeIt.Close()
+			return stats, xerrors.Errorf("export: %w", err)
+		}
+		stats.Edges++
+	}
+	if err = closeIterator(edgeIt); err != nil {
+		return stats, xerrors.Errorf("export: %w", err)
+	}
+
+	if err = gz.Close(); err != nil {
+		return stats, xerrors.Errorf("export: %w", err)
+	}
+	return stats, nil
+}
+
+// Import reads a snapshot previously generated by Export from r and upserts
+// its contents into dst. As dst assigns its own IDs to the imported links,
+// the link IDs referenced by the imported edges are re-mapped accordingly.
+func Import(r io.Reader, dst Target) (Stats, error) {
+	var stats Stats
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return stats, xerrors.Errorf("import: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	dec := json.NewDecoder(gz)
+	var hdr header
+	if err = dec.Decode(&hdr); err != nil {
+		return stats, xerrors.Errorf("import: unable to read snapshot header: %w", err)
+	}
+	if hdr.Format != formatName || hdr.Version < 1 || hdr.Version > FormatVersion {
+		return stats, xerrors.Errorf("import: %w (format %q, version %d)", ErrUnsupportedFormat, hdr.Format, hdr.Version)
+	}
+
+	linkIDs := make(map[uuid.UUID]uuid.UUID)
+	for {
+		var rec record
+		if err = dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return stats, xerrors.Errorf("import: %w", err)
+		}
+
+		switch {
+		case rec.Link != nil:
+			link := &graph.Link{
+				URL:          rec.Link.URL,
+				RetrievedAt:  rec.Link.RetrievedAt,
+				HTTPStatus:   rec.Link.HTTPStatus,
+				ContentType:  rec.Link.ContentType,
+				ContentHash:  rec.Link.ContentHash,
+				FailureCount: rec.Link.FailureCount,
+			}
+			if err = dst.UpsertLink(link); err != nil {
+				return stats, xerrors.Errorf("import: %w", err)
+			}
+			linkIDs[rec.Link.ID] = link.ID
+			stats.Links++
+		case rec.Edge != nil:
+			srcID, srcKnown := linkIDs[rec.Edge.Src]
+			dstID, dstKnown := linkIDs[rec.Edge.Dst]
+			if !srcKnown || !dstKnown {
+				return stats, xerrors.Errorf("import: edge %s: %w", rec.Edge.ID, graph.ErrUnknownEdgeLinks)
+			}
+			if err = dst.UpsertEdge(&graph.Edge{Src: srcID, Dst: dstID}); err != nil {
+				return stats, xerrors.Errorf("import: %w", err)
+			}
+			stats.Edges++
+		}
+	}
+
+	return stats, nil
+}
+
+func closeIterator(it graph.Iterator) error {
+	if err := it.Error(); err != nil {
+		_ = it.Close()
+		return err
+	}
+	return it.Close()
+}
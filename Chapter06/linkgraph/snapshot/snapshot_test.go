@@ -0,0 +1,115 @@
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/store/memory"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(SnapshotTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type SnapshotTestSuite struct{}
+
+func (s *SnapshotTestSuite) TestExportImport(c *gc.C) {
+	src := memory.NewInMemoryGraph()
+	retrievedAt := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+
+	links := make([]*graph.Link, 5)
+	for i := 0; i < len(links); i++ {
+		links[i] = &graph.Link{
+			URL:          fmt.Sprintf("http://example.com/%d", i),
+			RetrievedAt:  retrievedAt,
+			HTTPStatus:   200,
+			ContentType:  "text/html",
+			ContentHash:  fmt.Sprint(i),
+			FailureCount: i,
+		}
+		c.Assert(src.UpsertLink(links[i]), gc.IsNil)
+	}
+	for i := 1; i < len(links); i++ {
+		c.Assert(src.UpsertEdge(&graph.Edge{Src: links[0].ID, Dst: links[i].ID}), gc.IsNil)
+	}
+
+	var buf bytes.Buffer
+	stats, err := Export(&buf, src)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stats, gc.DeepEquals, Stats{Links: 5, Edges: 4})
+
+	dst := memory.NewInMemoryGraph()
+	stats, err = Import(&buf, dst)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stats, gc.DeepEquals, Stats{Links: 5, Edges: 4})
+
+	// Links get assigned new IDs so compare them by URL.
+	c.Assert(s.linksByURL(c, dst), gc.DeepEquals, s.linksByURL(c, src))
+	c.Assert(s.edgesByURL(c, dst), gc.DeepEquals, s.edgesByURL(c, src))
+}
+
+func (s *SnapshotTestSuite) TestImportUnsupportedVersion(c *gc.C) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := fmt.Fprintf(gz, `{"format":%q,"version":%d}`+"\n", formatName, FormatVersion+1)
+	c.Assert(err, gc.IsNil)
+	c.Assert(gz.Close(), gc.IsNil)
+
+	_, err = Import(&buf, memory.NewInMemoryGraph())
+	c.Assert(xerrors.Is(err, ErrUnsupportedFormat), gc.Equals, true)
+}
+
+func (s *SnapshotTestSuite) TestImportEdgeWithUnknownLinks(c *gc.C) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := fmt.Fprintf(gz, `{"format":%q,"version":%d}`+"\n", formatName, FormatVersion)
+	c.Assert(err, gc.IsNil)
+	_, err = fmt.Fprintf(gz, `{"edge":{"id":%q,"src":%q,"dst":%q}}`+"\n", minUUID, minUUID, maxUUID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(gz.Close(), gc.IsNil)
+
+	_, err = Import(&buf, memory.NewInMemoryGraph())
+	c.Assert(xerrors.Is(err, graph.ErrUnknownEdgeLinks), gc.Equals, true)
+}
+
+func (s *SnapshotTestSuite) linksByURL(c *gc.C, g graph.Graph) map[string]graph.Link {
+	it, err := g.Links(minUUID, maxUUID, time.Now())
+	c.Assert(err, gc.IsNil)
+
+	res := make(map[string]graph.Link)
+	for it.Next() {
+		link := *it.Link()
+		link.ID = minUUID
+		res[link.URL] = link
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+	return res
+}
+
+func (s *SnapshotTestSuite) edgesByURL(c *gc.C, g graph.Graph) []string {
+	it, err := g.Edges(minUUID, maxUUID, time.Now())
+	c.Assert(err, gc.IsNil)
+
+	var res []string
+	for it.Next() {
+		edge := it.Edge()
+		src, err := g.FindLink(edge.Src)
+		c.Assert(err, gc.IsNil)
+		dst, err := g.FindLink(edge.Dst)
+		c.Assert(err, gc.IsNil)
+		res = append(res, src.URL+" -> "+dst.URL)
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,701 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/google/uuid"
+	bbolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+var (
+	// The name of the file where the graph data is stored.
+	dbFile = "linkgraph.db"
+
+	// Bucket names
+	linkBucket            = []byte("links")
+	linkURLBucket         = []byte("link_urls")
+	edgeBucket            = []byte("edges")
+	linkEdgeBucket        = []byte("link_edges")
+	linkInboundEdgeBucket = []byte("link_inbound_edges")
+	changeBucket          = []byte("changes")
+	metaBucket            = []byte("meta")
+
+	// The key in the meta bucket that stores the timestamp of the most
+	// recent change event that was pruned from the change log.
+	changeLogTruncatedAtKey = []byte("change_log_truncated_at")
+
+	// Compile-time check for ensuring BoltGraph implements Graph.
+	_ graph.Graph = (*BoltGraph)(nil)
+)
+
+const (
+	// changeLogRetention is the amount of time that change events are
+	// kept in the change log.
+	changeLogRetention = 7 * 24 * time.Hour
+
+	// maxWatchBatchSize is the maximum number of change events returned
+	// by a single Watch call.
+	maxWatchBatchSize = 1000
+)
+
+// BoltGraph implements a graph that persists its links and edges to an
+// embedded bolt key-value store that lives in a local directory.
+//
+// The store maintains the following buckets:
+//   - links: maps link IDs to serialized links.
+//   - link_urls: maps link URLs to link IDs.
+//   - edges: maps edge IDs to serialized edges.
+//   - link_edges: maps the concatenation of an edge's source and destination
+//     link IDs to the edge ID.
+//   - link_inbound_edges: maps the concatenation of an edge's destination and
+//     source link IDs to the edge ID.
+//   - changes: maps the big-endian encoded UnixNano timestamp of each graph
+//     mutation to a serialized change event. Timestamps are assigned by the
+//     write transaction that applies the mutation and are strictly
+//     increasing.
+//   - meta: stores the timestamp of the most recent event that was pruned
+//     from the change log.
+//
+// As UUIDs are stored in their binary form, the byte-wise key ordering that
+// bolt provides matches the ordering of the UUID string representations. This
+// allows the Links and Edges methods to efficiently scan UUID ranges.
+type BoltGraph struct {
+	db *bbolt.DB
+}
+
+// NewBoltGraph returns a BoltGraph instance that persists its data in the
+// specified directory. The directory will be created if it does not exist.
+func NewBoltGraph(dir string) (*BoltGraph, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, xerrors.Errorf("create graph directory: %w", err)
+	}
+
+	db, err := bbolt.Open(filepath.Join(dir, dbFile), 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, xerrors.Errorf("open graph db: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, bucket := range [][]byte{linkBucket, linkURLBucket, edgeBucket, linkEdgeBucket, linkInboundEdgeBucket, changeBucket, metaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, xerrors.Errorf("create graph buckets: %w", err)
+	}
+
+	return &BoltGraph{db: db}, nil
+}
+
+// Close flushes any pending changes and releases the lock on the backing
+// bolt database.
+func (b *BoltGraph) Close() error {
+	return b.db.Close()
+}
+
+// UpsertLink creates a new link or updates an existing link.
+func (b *BoltGraph) UpsertLink(link *graph.Link) error {
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		return upsertLink(tx, link)
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert link: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertLinks creates or updates a batch of links using a single
+// transaction.
+func (b *BoltGraph) UpsertLinks(links []*graph.Link) error {
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		for _, link := range links {
+			if err := upsertLink(tx, link); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert links: %w", err)
+	}
+
+	return nil
+}
+
+func upsertLink(tx *bbolt.Tx, link *graph.Link) error {
+	links := tx.Bucket(linkBucket)
+	urls := tx.Bucket(linkURLBucket)
+
+	// Check if a link with the same URL already exists. If so, convert
+	// this into an update and point the link ID to the existing link.
+	lCopy := *link
+	if existingID := urls.Get([]byte(link.URL)); existingID != nil {
+		existing, err := unmarshalLink(links.Get(existingID))
+		if err != nil {
+			return err
+		}
+
+		// Never overwrite the link details with stale information.
+		if existing.RetrievedAt.After(lCopy.RetrievedAt) {
+			*link = *existing
+			return nil
+		}
+		lCopy.ID = existing.ID
+	} else {
+		// Assign new ID
+		for {
+			lCopy.ID = uuid.New()
+			if links.Get(lCopy.ID[:]) == nil {
+				break
+			}
+		}
+
+		if err := urls.Put([]byte(lCopy.URL), lCopy.ID[:]); err != nil {
+			return err
+		}
+	}
+
+	lCopy.RetrievedAt = lCopy.RetrievedAt.UTC()
+	data, err := json.Marshal(&lCopy)
+	if err != nil {
+		return err
+	}
+	if err = links.Put(lCopy.ID[:], data); err != nil {
+		return err
+	}
+	if err = recordChange(tx, graph.LinkUpserted, &lCopy, nil); err != nil {
+		return err
+	}
+
+	*link = lCopy
+	return nil
+}
+
+// FindLink looks up a link by its ID.
+func (b *BoltGraph) FindLink(id uuid.UUID) (*graph.Link, error) {
+	var link *graph.Link
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(linkBucket).Get(id[:])
+		if data == nil {
+			return graph.ErrNotFound
+		}
+
+		var err error
+		link, err = unmarshalLink(data)
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("find link: %w", err)
+	}
+
+	return link, nil
+}
+
+// Links returns an iterator for the set of links whose IDs belong to the
+// [fromID, toID) range and were retrieved before the provided timestamp.
+func (b *BoltGraph) Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error) {
+	var list []*graph.Link
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		cur := tx.Bucket(linkBucket).Cursor()
+		for k, v := cur.Seek(fromID[:]); k != nil && bytes.Compare(k, toID[:]) < 0; k, v = cur.Next() {
+			link, err := unmarshalLink(v)
+			if err != nil {
+				return err
+			}
+
+			if link.RetrievedAt.Before(retrievedBefore) {
+				list = append(list, link)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("links: %w", err)
+	}
+
+	return &linkIterator{links: list}, nil
+}
+
+// UpsertEdge creates a new edge or updates an existing edge.
+func (b *BoltGraph) UpsertEdge(edge *graph.Edge) error {
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		return upsertEdge(tx, edge)
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert edge: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertEdges creates or updates a batch of edges using a single
+// transaction. If any of the edges refers to an unknown link, none of the
+// edges in the batch will be upserted.
+func (b *BoltGraph) UpsertEdges(edges []*graph.Edge) error {
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		for _, edge := range edges {
+			if err := upsertEdge(tx, edge); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert edges: %w", err)
+	}
+
+	return nil
+}
+
+func upsertEdge(tx *bbolt.Tx, edge *graph.Edge) error {
+	links := tx.Bucket(linkBucket)
+	if links.Get(edge.Src[:]) == nil || links.Get(edge.Dst[:]) == nil {
+		return graph.ErrUnknownEdgeLinks
+	}
+
+	edges := tx.Bucket(edgeBucket)
+	linkEdges := tx.Bucket(linkEdgeBucket)
+
+	eCopy := *edge
+	edgeKey := linkEdgeKey(edge.Src, edge.Dst)
+	if existingID := linkEdges.Get(edgeKey); existingID != nil {
+		copy(eCopy.ID[:], existingID)
+	} else {
+		// Assign new ID
+		for {
+			eCopy.ID = uuid.New()
+			if edges.Get(eCopy.ID[:]) == nil {
+				break
+			}
+		}
+
+		if err := linkEdges.Put(edgeKey, eCopy.ID[:]); err != nil {
+			return err
+		}
+		if err := tx.Bucket(linkInboundEdgeBucket).Put(linkEdgeKey(edge.Dst, edge.Src), eCopy.ID[:]); err != nil {
+			return err
+		}
+	}
+
+	eCopy.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(&eCopy)
+	if err != nil {
+		return err
+	}
+	if err = edges.Put(eCopy.ID[:], data); err != nil {
+		return err
+	}
+	if err = recordChange(tx, graph.EdgeUpserted, nil, &eCopy); err != nil {
+		return err
+	}
+
+	*edge = eCopy
+	return nil
+}
+
+// Edges returns an iterator for the set of edges whose source vertex IDs
+// belong to the [fromID, toID) range and were updated before the provided
+// timestamp.
+func (b *BoltGraph) Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error) {
+	var list []*graph.Edge
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		cur := tx.Bucket(linkEdgeBucket).Cursor()
+		for k, v := cur.Seek(fromID[:]); k != nil && bytes.Compare(k[:16], toID[:]) < 0; k, v = cur.Next() {
+			edge, err := unmarshalEdge(edges.Get(v))
+			if err != nil {
+				return err
+			}
+
+			if edge.UpdatedAt.Before(updatedBefore) {
+				list = append(list, edge)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("edges: %w", err)
+	}
+
+	return &edgeIterator{edges: list}, nil
+}
+
+// InboundEdges returns an iterator for the set of edges that terminate at the
+// specified link ID.
+func (b *BoltGraph) InboundEdges(dstID uuid.UUID) (graph.EdgeIterator, error) {
+	var list []*graph.Edge
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		cur := tx.Bucket(linkInboundEdgeBucket).Cursor()
+		for k, v := cur.Seek(dstID[:]); k != nil && bytes.HasPrefix(k, dstID[:]); k, v = cur.Next() {
+			edge, err := unmarshalEdge(edges.Get(v))
+			if err != nil {
+				return err
+			}
+			list = append(list, edge)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("inbound edges: %w", err)
+	}
+
+	return &edgeIterator{edges: list}, nil
+}
+
+// InDegree returns the number of edges that terminate at the specified link ID.
+func (b *BoltGraph) InDegree(id uuid.UUID) (int, error) {
+	degree, err := b.countLinkEdges(linkInboundEdgeBucket, id)
+	if err != nil {
+		return 0, xerrors.Errorf("in degree: %w", err)
+	}
+	return degree, nil
+}
+
+// OutDegree returns the number of edges that originate from the specified link
+// ID.
+func (b *BoltGraph) OutDegree(id uuid.UUID) (int, error) {
+	degree, err := b.countLinkEdges(linkEdgeBucket, id)
+	if err != nil {
+		return 0, xerrors.Errorf("out degree: %w", err)
+	}
+	return degree, nil
+}
+
+// countLinkEdges returns the number of keys in bucket that are prefixed by
+// the specified link ID.
+func (b *BoltGraph) countLinkEdges(bucket []byte, id uuid.UUID) (int, error) {
+	var count int
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(linkBucket).Get(id[:]) == nil {
+			return graph.ErrNotFound
+		}
+
+		cur := tx.Bucket(bucket).Cursor()
+		for k, _ := cur.Seek(id[:]); k != nil && bytes.HasPrefix(k, id[:]); k, _ = cur.Next() {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// RemoveStaleEdges removes any edge that originates from the specified link ID
+// and was updated before the specified timestamp.
+func (b *BoltGraph) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error {
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+
+		// Collect the stale edges first as bolt does not allow buckets
+		// to be modified while iterating them with a cursor.
+		var stale []*graph.Edge
+		cur := tx.Bucket(linkEdgeBucket).Cursor()
+		for k, v := cur.Seek(fromID[:]); k != nil && bytes.HasPrefix(k, fromID[:]); k, v = cur.Next() {
+			edge, err := unmarshalEdge(edges.Get(v))
+			if err != nil {
+				return err
+			}
+
+			if edge.UpdatedAt.Before(updatedBefore) {
+				stale = append(stale, edge)
+			}
+		}
+
+		return deleteEdges(tx, stale)
+	})
+	if err != nil {
+		return xerrors.Errorf("remove stale edges: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveLink removes the link with the specified ID as well as any edge that
+// originates from or terminates at it.
+func (b *BoltGraph) RemoveLink(id uuid.UUID) error {
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket(linkBucket).Get(id[:]) == nil {
+			return graph.ErrNotFound
+		}
+
+		return removeLinks(tx, map[uuid.UUID]struct{}{id: {}})
+	})
+	if err != nil {
+		return xerrors.Errorf("remove link: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveLinksByHost removes all links whose URL points to the specified host
+// as well as any edges that originate from or terminate at them.
+func (b *BoltGraph) RemoveLinksByHost(host string) error {
+	err := b.db.Update(func(tx *bbolt.Tx) error {
+		toRemove := make(map[uuid.UUID]struct{})
+		err := tx.Bucket(linkURLBucket).ForEach(func(k, v []byte) error {
+			if urlMatchesHost(string(k), host) {
+				var linkID uuid.UUID
+				copy(linkID[:], v)
+				toRemove[linkID] = struct{}{}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return removeLinks(tx, toRemove)
+	})
+	if err != nil {
+		return xerrors.Errorf("remove links by host: %w", err)
+	}
+
+	return nil
+}
+
+// removeLinks deletes the specified set of links and purges any edges that
+// reference them.
+func removeLinks(tx *bbolt.Tx, toRemove map[uuid.UUID]struct{}) error {
+	if len(toRemove) == 0 {
+		return nil
+	}
+
+	links := tx.Bucket(linkBucket)
+	removed := make([]*graph.Link, 0, len(toRemove))
+	for linkID := range toRemove {
+		link, err := unmarshalLink(links.Get(linkID[:]))
+		if err != nil {
+			return err
+		}
+		removed = append(removed, link)
+	}
+
+	// Collect the edges that either originate from or terminate at one of
+	// the removed links; bolt does not allow buckets to be modified while
+	// iterating them with a cursor.
+	edges := tx.Bucket(edgeBucket)
+	toDelete := make(map[uuid.UUID]*graph.Edge)
+	for linkID := range toRemove {
+		for _, bucket := range [][]byte{linkEdgeBucket, linkInboundEdgeBucket} {
+			cur := tx.Bucket(bucket).Cursor()
+			for k, v := cur.Seek(linkID[:]); k != nil && bytes.HasPrefix(k, linkID[:]); k, v = cur.Next() {
+				edge, err := unmarshalEdge(edges.Get(v))
+				if err != nil {
+					return err
+				}
+				toDelete[edge.ID] = edge
+			}
+		}
+	}
+
+	list := make([]*graph.Edge, 0, len(toDelete))
+	for _, edge := range toDelete {
+		list = append(list, edge)
+	}
+	if err := deleteEdges(tx, list); err != nil {
+		return err
+	}
+
+	urls := tx.Bucket(linkURLBucket)
+	for _, link := range removed {
+		if err := urls.Delete([]byte(link.URL)); err != nil {
+			return err
+		}
+		if err := links.Delete(link.ID[:]); err != nil {
+			return err
+		}
+		if err := recordChange(tx, graph.LinkRemoved, link, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteEdges removes the specified edges from the edges bucket as well as
+// the outgoing and inbound edge indices.
+func deleteEdges(tx *bbolt.Tx, list []*graph.Edge) error {
+	edges := tx.Bucket(edgeBucket)
+	linkEdges := tx.Bucket(linkEdgeBucket)
+	linkInboundEdges := tx.Bucket(linkInboundEdgeBucket)
+	for _, edge := range list {
+		if err := linkEdges.Delete(linkEdgeKey(edge.Src, edge.Dst)); err != nil {
+			return err
+		}
+		if err := linkInboundEdges.Delete(linkEdgeKey(edge.Dst, edge.Src)); err != nil {
+			return err
+		}
+		if err := edges.Delete(edge.ID[:]); err != nil {
+			return err
+		}
+		if err := recordChange(tx, graph.EdgeRemoved, nil, edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch returns an iterator for the link and edge mutations that took place
+// after the provided timestamp, ordered by their timestamps. At most
+// maxWatchBatchSize events are returned by each call.
+func (b *BoltGraph) Watch(since time.Time) (graph.ChangeEventIterator, error) {
+	var list []*graph.ChangeEvent
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		if truncatedAt := tx.Bucket(metaBucket).Get(changeLogTruncatedAtKey); truncatedAt != nil {
+			if since.Before(changeTimestamp(truncatedAt)) {
+				return graph.ErrChangeLogTruncated
+			}
+		}
+
+		// Timestamps before the Unix epoch cannot be encoded as keys
+		// but are older than any recorded event anyway.
+		cur := tx.Bucket(changeBucket).Cursor()
+		k, v := cur.First()
+		if since.After(time.Unix(0, 0)) {
+			k, v = cur.Seek(changeKey(since.UnixNano() + 1))
+		}
+		for ; k != nil && len(list) < maxWatchBatchSize; k, v = cur.Next() {
+			evt, err := unmarshalChangeEvent(v)
+			if err != nil {
+				return err
+			}
+			list = append(list, evt)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("watch: %w", err)
+	}
+
+	return &changeEventIterator{events: list}, nil
+}
+
+// recordChange appends a change event for the provided link or edge to the
+// change log and prunes any events that have exceeded the retention period.
+//
+// Event timestamps are derived from the current time but are always greater
+// than the timestamp of the last recorded event. As bolt serializes write
+// transactions, the timestamps increase in the order in which the mutations
+// are committed.
+func recordChange(tx *bbolt.Tx, evType graph.ChangeType, link *graph.Link, edge *graph.Edge) error {
+	changes := tx.Bucket(changeBucket)
+
+	now := time.Now()
+	ts := now.UnixNano()
+	if lastKey, _ := changes.Cursor().Last(); lastKey != nil {
+		if last := int64(binary.BigEndian.Uint64(lastKey)); ts <= last {
+			ts = last + 1
+		}
+	}
+
+	data, err := json.Marshal(&graph.ChangeEvent{Type: evType, Timestamp: time.Unix(0, ts).UTC(), Link: link, Edge: edge})
+	if err != nil {
+		return err
+	}
+	if err = changes.Put(changeKey(ts), data); err != nil {
+		return err
+	}
+
+	return pruneChangeLog(tx, now.Add(-changeLogRetention))
+}
+
+// pruneChangeLog removes the change events recorded before the specified
+// time and records the timestamp of the last removed event in the meta
+// bucket.
+func pruneChangeLog(tx *bbolt.Tx, before time.Time) error {
+	// Collect the expired keys first as bolt does not allow buckets to be
+	// modified while iterating them with a cursor.
+	var expired [][]byte
+	maxKey := changeKey(before.UnixNano())
+	cur := tx.Bucket(changeBucket).Cursor()
+	for k, _ := cur.First(); k != nil && bytes.Compare(k, maxKey) < 0; k, _ = cur.Next() {
+		expired = append(expired, k)
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	changes := tx.Bucket(changeBucket)
+	for _, k := range expired {
+		if err := changes.Delete(k); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(metaBucket).Put(changeLogTruncatedAtKey, expired[len(expired)-1])
+}
+
+// changeKey returns the key for a change event with the specified UnixNano
+// timestamp.
+func changeKey(ts int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(ts))
+	return key
+}
+
+// changeTimestamp decodes a key produced by changeKey.
+func changeTimestamp(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key))).UTC()
+}
+
+// urlMatchesHost returns true if linkURL points to the specified host.
+func urlMatchesHost(linkURL, host string) bool {
+	u, err := url.Parse(linkURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Hostname(), host)
+}
+
+// linkEdgeKey returns the concatenation of the from and to link IDs which is
+// used as the key for the link_edges and link_inbound_edges buckets.
+func linkEdgeKey(from, to uuid.UUID) []byte {
+	key := make([]byte, 0, len(from)+len(to))
+	key = append(key, from[:]...)
+	return append(key, to[:]...)
+}
+
+func unmarshalLink(data []byte) (*graph.Link, error) {
+	link := new(graph.Link)
+	if err := json.Unmarshal(data, link); err != nil {
+		return nil, xerrors.Errorf("unmarshal link: %w", err)
+	}
+	link.RetrievedAt = link.RetrievedAt.UTC()
+	return link, nil
+}
+
+func unmarshalEdge(data []byte) (*graph.Edge, error) {
+	edge := new(graph.Edge)
+	if err := json.Unmarshal(data, edge); err != nil {
+		return nil, xerrors.Errorf("unmarshal edge: %w", err)
+	}
+	edge.UpdatedAt = edge.UpdatedAt.UTC()
+	return edge, nil
+}
+
+func unmarshalChangeEvent(data []byte) (*graph.ChangeEvent, error) {
+	evt := new(graph.ChangeEvent)
+	if err := json.Unmarshal(data, evt); err != nil {
+		return nil, xerrors.Errorf("unmarshal change event: %w", err)
+	}
+	evt.Timestamp = evt.Timestamp.UTC()
+	if evt.Link != nil {
+		evt.Link.RetrievedAt = evt.Link.RetrievedAt.UTC()
+	}
+	if evt.Edge != nil {
+		evt.Edge.UpdatedAt = evt.Edge.UpdatedAt.UTC()
+	}
+	return evt, nil
+}
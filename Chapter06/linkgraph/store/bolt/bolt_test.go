@@ -0,0 +1,66 @@
+package bolt
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph/graphtest"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(BoltGraphTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type BoltGraphTestSuite struct {
+	graphtest.SuiteBase
+	dir string
+	g   *BoltGraph
+}
+
+func (s *BoltGraphTestSuite) SetUpTest(c *gc.C) {
+	dir, err := ioutil.TempDir("", "bolt-graph-test")
+	c.Assert(err, gc.IsNil)
+	s.dir = dir
+
+	s.g, err = NewBoltGraph(dir)
+	c.Assert(err, gc.IsNil)
+	s.SetGraph(s.g)
+}
+
+func (s *BoltGraphTestSuite) TearDownTest(c *gc.C) {
+	c.Assert(s.g.Close(), gc.IsNil)
+	c.Assert(os.RemoveAll(s.dir), gc.IsNil)
+}
+
+func (s *BoltGraphTestSuite) TestPersistenceAcrossReopen(c *gc.C) {
+	src := &graph.Link{URL: "https://example.com"}
+	dst := &graph.Link{URL: "https://example.com/about"}
+	c.Assert(s.g.UpsertLink(src), gc.IsNil)
+	c.Assert(s.g.UpsertLink(dst), gc.IsNil)
+	edge := &graph.Edge{Src: src.ID, Dst: dst.ID}
+	c.Assert(s.g.UpsertEdge(edge), gc.IsNil)
+
+	// Close and re-open the graph
+	c.Assert(s.g.Close(), gc.IsNil)
+	g, err := NewBoltGraph(s.dir)
+	c.Assert(err, gc.IsNil)
+	s.g = g
+	s.SetGraph(g)
+
+	stored, err := g.FindLink(src.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.DeepEquals, src)
+
+	// Upserting a link with the same URL should reuse the existing ID.
+	dup := &graph.Link{URL: dst.URL}
+	c.Assert(g.UpsertLink(dup), gc.IsNil)
+	c.Assert(dup.ID, gc.Equals, dst.ID)
+
+	// Upserting the same edge should reuse the existing ID.
+	dupEdge := &graph.Edge{Src: src.ID, Dst: dst.ID}
+	c.Assert(g.UpsertEdge(dupEdge), gc.IsNil)
+	c.Assert(dupEdge.ID, gc.Equals, edge.ID)
+}
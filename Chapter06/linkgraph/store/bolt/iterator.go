@@ -0,0 +1,94 @@
+package bolt
+
+import "github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+
+// linkIterator is a graph.LinkIterator implementation for the bolt graph.
+type linkIterator struct {
+	links    []*graph.Link
+	curIndex int
+}
+
+// Next implements graph.LinkIterator.
+func (i *linkIterator) Next() bool {
+	if i.curIndex >= len(i.links) {
+		return false
+	}
+	i.curIndex++
+	return true
+}
+
+// Error implements graph.LinkIterator.
+func (i *linkIterator) Error() error {
+	return nil
+}
+
+// Close implements graph.LinkIterator.
+func (i *linkIterator) Close() error {
+	return nil
+}
+
+// Link implements graph.LinkIterator.
+func (i *linkIterator) Link() *graph.Link {
+	return i.links[i.curIndex-1]
+}
+
+// edgeIterator is a graph.EdgeIterator implementation for the bolt graph.
+type edgeIterator struct {
+	edges    []*graph.Edge
+	curIndex int
+}
+
+// Next implements graph.EdgeIterator.
+func (i *edgeIterator) Next() bool {
+	if i.curIndex >= len(i.edges) {
+		return false
+	}
+	i.curIndex++
+	return true
+}
+
+// Error implements graph.EdgeIterator.
+func (i *edgeIterator) Error() error {
+	return nil
+}
+
+// Close implements graph.EdgeIterator.
+func (i *edgeIterator) Close() error {
+	return nil
+}
+
+// Edge implements graph.EdgeIterator.
+func (i *edgeIterator) Edge() *graph.Edge {
+	return i.edges[i.curIndex-1]
+}
+
+// changeEventIterator is a graph.ChangeEventIterator implementation for the
+// bolt graph.
+type changeEventIterator struct {
+	events   []*graph.ChangeEvent
+	curIndex int
+}
+
+// Next implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Next() bool {
+	if i.curIndex >= len(i.events) {
+		return false
+	}
+	i.curIndex++
+	return true
+}
+
+// Error implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Error() error {
+	return nil
+}
+
+// Close implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Close() error {
+	return nil
+}
+
+// Event implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Event() *graph.ChangeEvent {
+	return i.events[i.curIndex-1]
+}
@@ -1,7 +1,13 @@
 package cdb
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
@@ -12,29 +18,118 @@ import (
 
 var (
 	upsertLinkQuery = `
-INSERT INTO links (url, retrieved_at) VALUES ($1, $2) 
-ON CONFLICT (url) DO UPDATE SET retrieved_at=GREATEST(links.retrieved_at, $2)
-RETURNING id, retrieved_at
+INSERT INTO links (url, retrieved_at, http_status, content_type, content_hash, failure_count, updated_at) VALUES ($1, $2, $3, $4, $5, $6, NOW()) 
+ON CONFLICT (url) DO UPDATE SET
+	http_status=CASE WHEN links.retrieved_at > $2 THEN links.http_status ELSE $3 END,
+	content_type=CASE WHEN links.retrieved_at > $2 THEN links.content_type ELSE $4 END,
+	content_hash=CASE WHEN links.retrieved_at > $2 THEN links.content_hash ELSE $5 END,
+	failure_count=CASE WHEN links.retrieved_at > $2 THEN links.failure_count ELSE $6 END,
+	updated_at=CASE WHEN links.retrieved_at > $2 THEN links.updated_at ELSE NOW() END,
+	retrieved_at=GREATEST(links.retrieved_at, $2)
+RETURNING id, url, retrieved_at, http_status, content_type, content_hash, failure_count
 `
-	findLinkQuery         = "SELECT url, retrieved_at FROM links WHERE id=$1"
-	linksInPartitionQuery = "SELECT id, url, retrieved_at FROM links WHERE id >= $1 AND id < $2 AND retrieved_at < $3"
+	upsertLinksQueryPrefix = "INSERT INTO links (url, retrieved_at, http_status, content_type, content_hash, failure_count, updated_at) VALUES "
+	upsertLinksQuerySuffix = `
+ON CONFLICT (url) DO UPDATE SET
+	http_status=CASE WHEN links.retrieved_at > excluded.retrieved_at THEN links.http_status ELSE excluded.http_status END,
+	content_type=CASE WHEN links.retrieved_at > excluded.retrieved_at THEN links.content_type ELSE excluded.content_type END,
+	content_hash=CASE WHEN links.retrieved_at > excluded.retrieved_at THEN links.content_hash ELSE excluded.content_hash END,
+	failure_count=CASE WHEN links.retrieved_at > excluded.retrieved_at THEN links.failure_count ELSE excluded.failure_count END,
+	updated_at=CASE WHEN links.retrieved_at > excluded.retrieved_at THEN links.updated_at ELSE excluded.updated_at END,
+	retrieved_at=GREATEST(links.retrieved_at, excluded.retrieved_at)
+RETURNING id, url, retrieved_at, http_status, content_type, content_hash, failure_count
+`
+	findLinkQuery         = "SELECT url, retrieved_at, http_status, content_type, content_hash, failure_count FROM links WHERE id=$1"
+	linksInPartitionQuery = "SELECT id, url, retrieved_at, http_status, content_type, content_hash, failure_count FROM links WHERE id >= $1 AND id < $2 AND retrieved_at < $3 ORDER BY id"
 
 	upsertEdgeQuery = `
 INSERT INTO edges (src, dst, updated_at) VALUES ($1, $2, NOW())
 ON CONFLICT (src,dst) DO UPDATE SET updated_at=NOW()
-RETURNING id, updated_at
+RETURNING id, src, dst, updated_at
+`
+	upsertEdgesQueryPrefix = "INSERT INTO edges (src, dst, updated_at) VALUES "
+	upsertEdgesQuerySuffix = `
+ON CONFLICT (src,dst) DO UPDATE SET updated_at=NOW()
+RETURNING id, src, dst, updated_at
 `
 	edgesInPartitionQuery = "SELECT id, src, dst, updated_at FROM edges WHERE src >= $1 AND src < $2 AND updated_at < $3"
-	removeStaleEdgesQuery = "DELETE FROM edges WHERE src=$1 AND updated_at < $2"
+	inboundEdgesQuery     = "SELECT id, src, dst, updated_at FROM edges WHERE dst=$1"
+	inDegreeQuery         = "SELECT (SELECT count(*) FROM edges WHERE dst=$1) FROM links WHERE id=$1"
+	outDegreeQuery        = "SELECT (SELECT count(*) FROM edges WHERE src=$1) FROM links WHERE id=$1"
+	removeStaleEdgesQuery = "DELETE FROM edges WHERE src=$1 AND updated_at < $2 RETURNING id, src, dst, updated_at"
+
+	// Edges referencing the removed links are explicitly deleted before
+	// the links themselves so that tombstones can be recorded for them;
+	// the ON DELETE CASCADE clause of the edges table foreign keys would
+	// otherwise drop them silently.
+	removeLinkEdgesQuery        = "DELETE FROM edges WHERE src=$1 OR dst=$1 RETURNING id, src, dst, updated_at"
+	removeLinkQuery             = "DELETE FROM links WHERE id=$1 RETURNING id, url, retrieved_at, http_status, content_type, content_hash, failure_count"
+	linkHostMatchesCond         = `lower(substring(url, '^[a-zA-Z][a-zA-Z0-9+.-]*://(?:[^/?#@]*@)?([^/?#:]*)')) = lower($1)`
+	removeLinksByHostEdgesQuery = "DELETE FROM edges WHERE src IN (SELECT id FROM links WHERE " + linkHostMatchesCond + ") OR dst IN (SELECT id FROM links WHERE " + linkHostMatchesCond + ") RETURNING id, src, dst, updated_at"
+	removeLinksByHostQuery      = "DELETE FROM links WHERE " + linkHostMatchesCond + " RETURNING id, url, retrieved_at, http_status, content_type, content_hash, failure_count"
+
+	insertTombstonesQueryPrefix = "INSERT INTO tombstones (type, id, data, removed_at) VALUES "
+	pruneTombstonesQuery        = "DELETE FROM tombstones WHERE removed_at < NOW() - $1 * INTERVAL '1 second'"
+
+	// The queries for polling the changes that took place within the
+	// (since, upTo] time range. Links and edges are stamped with the
+	// transaction timestamp of the last statement that modified them
+	// and removed links and edges are recorded in the tombstones table.
+	watchClockQuery      = "SELECT NOW()::TIMESTAMP"
+	watchLinksQuery      = "SELECT id, url, retrieved_at, http_status, content_type, content_hash, failure_count, updated_at FROM links WHERE updated_at > $1 AND updated_at <= $2 ORDER BY updated_at"
+	watchEdgesQuery      = "SELECT id, src, dst, updated_at FROM edges WHERE updated_at > $1 AND updated_at <= $2 ORDER BY updated_at"
+	watchTombstonesQuery = "SELECT type, data, removed_at FROM tombstones WHERE removed_at > $1 AND removed_at <= $2 ORDER BY removed_at"
+
+	// The max number of rows to insert with a single multi-row upsert
+	// statement.
+	maxRowsPerBatchUpsert = 256
+
+	// The max number of change events that Watch fetches from each table
+	// with a single call. Events that share the same timestamp are always
+	// returned together so Watch may return more events than this.
+	maxWatchBatchSize = 1000
+
+	// The amount of time that tombstones for removed links and edges are
+	// kept around and how often expired tombstones are pruned.
+	changeLogRetention     = 7 * 24 * time.Hour
+	changeLogPruneInterval = time.Hour
+
+	// The default amount of time that Watch lags behind the current time.
+	// Changes are stamped with the timestamp of the transaction that
+	// applied them, which may be committed after the transactions that
+	// started after it, so the most recent changes are not reported until
+	// any transaction that could still be stamping rows with an earlier
+	// timestamp has been committed.
+	defaultWatchSettleDelay = 5 * time.Second
+
+	// The max number of attempts for executing a write transaction that
+	// gets aborted due to contention.
+	maxTxAttempts = 5
+
+	// The order in which Watch returns events that share a timestamp.
+	changeTypeOrder = map[graph.ChangeType]int{
+		graph.LinkUpserted: 0,
+		graph.EdgeUpserted: 1,
+		graph.EdgeRemoved:  2,
+		graph.LinkRemoved:  3,
+	}
 
 	// Compile-time check for ensuring CockroachDbGraph implements Graph.
 	_ graph.Graph = (*CockroachDBGraph)(nil)
 )
 
 // CockroachDBGraph implements a graph that persists its links and edges to a
-// cockroachdb instance.
+// cockroachdb instance. Link and edge mutations are served to Watch callers by
+// polling the indexed updated_at columns of the links and edges tables as well
+// as the tombstones table where removed links and edges are recorded.
 type CockroachDBGraph struct {
 	db *sql.DB
+
+	// The amount of time that Watch lags behind the current time.
+	watchSettleDelay time.Duration
+
+	mu          sync.Mutex
+	nextPruneAt time.Time
 }
 
 // NewCockroachDbGraph returns a CockroachDbGraph instance that connects to the cockroachdb
@@ -45,7 +140,7 @@ func NewCockroachDbGraph(dsn string) (*CockroachDBGraph, error) {
 		return nil, err
 	}
 
-	return &CockroachDBGraph{db: db}, nil
+	return &CockroachDBGraph{db: db, watchSettleDelay: defaultWatchSettleDelay}, nil
 }
 
 // Close terminates the connection to the backing cockroachdb instance.
@@ -55,12 +150,91 @@ func (c *CockroachDBGraph) Close() error {
 
 // UpsertLink creates a new link or updates an existing link.
 func (c *CockroachDBGraph) UpsertLink(link *graph.Link) error {
-	row := c.db.QueryRow(upsertLinkQuery, link.URL, link.RetrievedAt.UTC())
-	if err := row.Scan(&link.ID, &link.RetrievedAt); err != nil {
+	err := c.update(func(tx *sql.Tx) ([]*graph.ChangeEvent, error) {
+		row := tx.QueryRow(
+			upsertLinkQuery,
+			link.URL,
+			link.RetrievedAt.UTC(),
+			link.HTTPStatus,
+			link.ContentType,
+			link.ContentHash,
+			link.FailureCount,
+		)
+		upserted, err := scanLink(row)
+		if err != nil {
+			return nil, err
+		}
+
+		link.ID = upserted.ID
+		link.RetrievedAt = upserted.RetrievedAt
+		return []*graph.ChangeEvent{{Type: graph.LinkUpserted, Link: upserted}}, nil
+	})
+	if err != nil {
 		return xerrors.Errorf("upsert link: %w", err)
 	}
 
-	link.RetrievedAt = link.RetrievedAt.UTC()
+	return nil
+}
+
+// UpsertLinks creates or updates a batch of links using multi-row upsert
+// statements.
+func (c *CockroachDBGraph) UpsertLinks(links []*graph.Link) error {
+	// A multi-row upsert statement cannot modify the same row twice so
+	// links that share the same URL are collapsed into the most recently
+	// retrieved one.
+	var (
+		batch    []*graph.Link
+		batchIdx = make(map[string]int)
+		byURL    = make(map[string][]*graph.Link)
+	)
+	for _, link := range links {
+		byURL[link.URL] = append(byURL[link.URL], link)
+		if idx, exists := batchIdx[link.URL]; !exists {
+			batchIdx[link.URL] = len(batch)
+			batch = append(batch, link)
+		} else if !batch[idx].RetrievedAt.After(link.RetrievedAt) {
+			batch[idx] = link
+		}
+	}
+
+	err := c.update(func(tx *sql.Tx) ([]*graph.ChangeEvent, error) {
+		var events []*graph.ChangeEvent
+		for remaining := batch; len(remaining) > 0; {
+			n := len(remaining)
+			if n > maxRowsPerBatchUpsert {
+				n = maxRowsPerBatchUpsert
+			}
+
+			args := make([]interface{}, 0, n*6)
+			for _, link := range remaining[:n] {
+				args = append(args, link.URL, link.RetrievedAt.UTC(), link.HTTPStatus, link.ContentType, link.ContentHash, link.FailureCount)
+			}
+
+			err := queryRows(tx, func(rows *sql.Rows) error {
+				upserted, err := scanLink(rows)
+				if err != nil {
+					return err
+				}
+
+				for _, link := range byURL[upserted.URL] {
+					link.ID = upserted.ID
+					link.RetrievedAt = upserted.RetrievedAt
+				}
+				events = append(events, &graph.ChangeEvent{Type: graph.LinkUpserted, Link: upserted})
+				return nil
+			}, upsertLinksQueryPrefix+valueTuples(n, 6, "NOW()")+upsertLinksQuerySuffix, args...)
+			if err != nil {
+				return nil, err
+			}
+
+			remaining = remaining[n:]
+		}
+		return events, nil
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert links: %w", err)
+	}
+
 	return nil
 }
 
@@ -68,7 +242,7 @@ func (c *CockroachDBGraph) UpsertLink(link *graph.Link) error {
 func (c *CockroachDBGraph) FindLink(id uuid.UUID) (*graph.Link, error) {
 	row := c.db.QueryRow(findLinkQuery, id)
 	link := &graph.Link{ID: id}
-	if err := row.Scan(&link.URL, &link.RetrievedAt); err != nil {
+	if err := row.Scan(&link.URL, &link.RetrievedAt, &link.HTTPStatus, &link.ContentType, &link.ContentHash, &link.FailureCount); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, xerrors.Errorf("find link: %w", graph.ErrNotFound)
 		}
@@ -93,15 +267,85 @@ func (c *CockroachDBGraph) Links(fromID, toID uuid.UUID, accessedBefore time.Tim
 
 // UpsertEdge creates a new edge or updates an existing edge.
 func (c *CockroachDBGraph) UpsertEdge(edge *graph.Edge) error {
-	row := c.db.QueryRow(upsertEdgeQuery, edge.Src, edge.Dst)
-	if err := row.Scan(&edge.ID, &edge.UpdatedAt); err != nil {
-		if isForeignKeyViolationError(err) {
-			err = graph.ErrUnknownEdgeLinks
+	err := c.update(func(tx *sql.Tx) ([]*graph.ChangeEvent, error) {
+		upserted, err := scanEdge(tx.QueryRow(upsertEdgeQuery, edge.Src, edge.Dst))
+		if err != nil {
+			if isForeignKeyViolationError(err) {
+				err = graph.ErrUnknownEdgeLinks
+			}
+			return nil, err
 		}
+
+		edge.ID = upserted.ID
+		edge.UpdatedAt = upserted.UpdatedAt
+		return []*graph.ChangeEvent{{Type: graph.EdgeUpserted, Edge: upserted}}, nil
+	})
+	if err != nil {
 		return xerrors.Errorf("upsert edge: %w", err)
 	}
 
-	edge.UpdatedAt = edge.UpdatedAt.UTC()
+	return nil
+}
+
+// UpsertEdges creates or updates a batch of edges using multi-row upsert
+// statements.
+func (c *CockroachDBGraph) UpsertEdges(edges []*graph.Edge) error {
+	// A multi-row upsert statement cannot modify the same row twice so
+	// edges that share the same (src, dst) tuple are only inserted once.
+	type edgeKey struct{ src, dst uuid.UUID }
+	var (
+		batch   []*graph.Edge
+		byLinks = make(map[edgeKey][]*graph.Edge)
+	)
+	for _, edge := range edges {
+		key := edgeKey{src: edge.Src, dst: edge.Dst}
+		if len(byLinks[key]) == 0 {
+			batch = append(batch, edge)
+		}
+		byLinks[key] = append(byLinks[key], edge)
+	}
+
+	err := c.update(func(tx *sql.Tx) ([]*graph.ChangeEvent, error) {
+		var events []*graph.ChangeEvent
+		for remaining := batch; len(remaining) > 0; {
+			n := len(remaining)
+			if n > maxRowsPerBatchUpsert {
+				n = maxRowsPerBatchUpsert
+			}
+
+			args := make([]interface{}, 0, n*2)
+			for _, edge := range remaining[:n] {
+				args = append(args, edge.Src, edge.Dst)
+			}
+
+			err := queryRows(tx, func(rows *sql.Rows) error {
+				upserted, err := scanEdge(rows)
+				if err != nil {
+					return err
+				}
+
+				for _, edge := range byLinks[edgeKey{src: upserted.Src, dst: upserted.Dst}] {
+					edge.ID = upserted.ID
+					edge.UpdatedAt = upserted.UpdatedAt
+				}
+				events = append(events, &graph.ChangeEvent{Type: graph.EdgeUpserted, Edge: upserted})
+				return nil
+			}, upsertEdgesQueryPrefix+valueTuples(n, 2, "NOW()")+upsertEdgesQuerySuffix, args...)
+			if err != nil {
+				if isForeignKeyViolationError(err) {
+					err = graph.ErrUnknownEdgeLinks
+				}
+				return nil, err
+			}
+
+			remaining = remaining[n:]
+		}
+		return events, nil
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert edges: %w", err)
+	}
+
 	return nil
 }
 
@@ -117,10 +361,54 @@ func (c *CockroachDBGraph) Edges(fromID, toID uuid.UUID, updatedBefore time.Time
 	return &edgeIterator{rows: rows}, nil
 }
 
+// InboundEdges returns an iterator for the set of edges that terminate at the
+// specified link ID.
+func (c *CockroachDBGraph) InboundEdges(dstID uuid.UUID) (graph.EdgeIterator, error) {
+	rows, err := c.db.Query(inboundEdgesQuery, dstID)
+	if err != nil {
+		return nil, xerrors.Errorf("inbound edges: %w", err)
+	}
+
+	return &edgeIterator{rows: rows}, nil
+}
+
+// InDegree returns the number of edges that terminate at the specified link ID.
+func (c *CockroachDBGraph) InDegree(id uuid.UUID) (int, error) {
+	degree, err := c.queryDegree(inDegreeQuery, id)
+	if err != nil {
+		return 0, xerrors.Errorf("in degree: %w", err)
+	}
+	return degree, nil
+}
+
+// OutDegree returns the number of edges that originate from the specified link
+// ID.
+func (c *CockroachDBGraph) OutDegree(id uuid.UUID) (int, error) {
+	degree, err := c.queryDegree(outDegreeQuery, id)
+	if err != nil {
+		return 0, xerrors.Errorf("out degree: %w", err)
+	}
+	return degree, nil
+}
+
+// queryDegree executes a degree query for the specified link ID.
+func (c *CockroachDBGraph) queryDegree(query string, id uuid.UUID) (int, error) {
+	var degree int
+	if err := c.db.QueryRow(query, id).Scan(&degree); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, graph.ErrNotFound
+		}
+		return 0, err
+	}
+	return degree, nil
+}
+
 // RemoveStaleEdges removes any edge that originates from the specified link ID
 // and was updated before the specified timestamp.
 func (c *CockroachDBGraph) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error {
-	_, err := c.db.Exec(removeStaleEdgesQuery, fromID, updatedBefore.UTC())
+	err := c.update(func(tx *sql.Tx) ([]*graph.ChangeEvent, error) {
+		return queryEdgeEvents(tx, graph.EdgeRemoved, removeStaleEdgesQuery, fromID, updatedBefore.UTC())
+	})
 	if err != nil {
 		return xerrors.Errorf("remove stale edges: %w", err)
 	}
@@ -128,6 +416,405 @@ func (c *CockroachDBGraph) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time
 	return nil
 }
 
+// RemoveLink removes the link with the specified ID as well as any edge that
+// originates from or terminates at it.
+func (c *CockroachDBGraph) RemoveLink(id uuid.UUID) error {
+	err := c.update(func(tx *sql.Tx) ([]*graph.ChangeEvent, error) {
+		events, err := queryEdgeEvents(tx, graph.EdgeRemoved, removeLinkEdgesQuery, id)
+		if err != nil {
+			return nil, err
+		}
+
+		linkEvents, err := queryLinkEvents(tx, graph.LinkRemoved, removeLinkQuery, id)
+		if err != nil {
+			return nil, err
+		} else if len(linkEvents) == 0 {
+			return nil, graph.ErrNotFound
+		}
+
+		return append(events, linkEvents...), nil
+	})
+	if err != nil {
+		return xerrors.Errorf("remove link: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveLinksByHost removes all links whose URL points to the specified host
+// as well as any edges that originate from or terminate at them.
+func (c *CockroachDBGraph) RemoveLinksByHost(host string) error {
+	err := c.update(func(tx *sql.Tx) ([]*graph.ChangeEvent, error) {
+		events, err := queryEdgeEvents(tx, graph.EdgeRemoved, removeLinksByHostEdgesQuery, host)
+		if err != nil {
+			return nil, err
+		}
+
+		linkEvents, err := queryLinkEvents(tx, graph.LinkRemoved, removeLinksByHostQuery, host)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(events, linkEvents...), nil
+	})
+	if err != nil {
+		return xerrors.Errorf("remove links by host: %w", err)
+	}
+
+	return nil
+}
+
+// Watch returns an iterator for the link and edge mutations that took place
+// after the provided timestamp, ordered by their timestamps.
+//
+// Watch polls the links and edges tables for rows updated after since and the
+// tombstones table for links and edges removed after since. As a result, a
+// link or edge that was updated several times is only reported once, with its
+// current state. All mutations applied by the same transaction share the same
+// timestamp and are always returned by the same call. Mutations applied within
+// the last watchSettleDelay are not reported yet so that any transactions
+// still stamping rows with an earlier timestamp can commit first.
+func (c *CockroachDBGraph) Watch(since time.Time) (graph.ChangeEventIterator, error) {
+	// Run all queries against the same snapshot so that the events fetched
+	// from each table are consistent with each other.
+	tx, err := c.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, xerrors.Errorf("watch: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var now time.Time
+	if err = tx.QueryRow(watchClockQuery).Scan(&now); err != nil {
+		return nil, xerrors.Errorf("watch: %w", err)
+	}
+	now = now.UTC()
+
+	// Tombstones older than changeLogRetention may have been pruned.
+	if !since.IsZero() && since.Before(now.Add(-changeLogRetention)) {
+		return nil, xerrors.Errorf("watch: %w", graph.ErrChangeLogTruncated)
+	}
+
+	// The updated_at and removed_at columns have microsecond precision.
+	since = since.UTC().Truncate(time.Microsecond)
+	upTo := now.Add(-c.watchSettleDelay).Truncate(time.Microsecond)
+	if !upTo.After(since) {
+		return &changeEventIterator{}, nil
+	}
+
+	list, cutoff, err := queryChanges(tx, since, upTo, maxWatchBatchSize)
+	if err != nil {
+		return nil, xerrors.Errorf("watch: %w", err)
+	}
+
+	// When any of the tables had more than maxWatchBatchSize changes, the
+	// fetched events are only complete up to the cutoff timestamp. Drop
+	// the events after it and fetch all events stamped with it.
+	if !cutoff.IsZero() {
+		complete := list[:0]
+		for _, evt := range list {
+			if evt.Timestamp.Before(cutoff) {
+				complete = append(complete, evt)
+			}
+		}
+
+		tied, _, err := queryChanges(tx, cutoff.Add(-time.Microsecond), cutoff, 0)
+		if err != nil {
+			return nil, xerrors.Errorf("watch: %w", err)
+		}
+		list = append(complete, tied...)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, xerrors.Errorf("watch: %w", err)
+	}
+
+	// Events sharing a timestamp are ordered so that links are upserted
+	// before the edges that reference them and removed after them.
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].Timestamp.Equal(list[j].Timestamp) {
+			return list[i].Timestamp.Before(list[j].Timestamp)
+		}
+		return changeTypeOrder[list[i].Type] < changeTypeOrder[list[j].Type]
+	})
+
+	// Cap the number of returned events without splitting the events that
+	// share the same timestamp across calls.
+	n := len(list)
+	if n > maxWatchBatchSize {
+		n = maxWatchBatchSize
+		for n < len(list) && list[n].Timestamp.Equal(list[n-1].Timestamp) {
+			n++
+		}
+	}
+	return &changeEventIterator{events: list[:n]}, nil
+}
+
+// update executes fn within a transaction and records tombstones for the
+// links and edges removed by fn before committing the transaction.
+// Transactions that get aborted due to contention are retried.
+func (c *CockroachDBGraph) update(fn func(tx *sql.Tx) ([]*graph.ChangeEvent, error)) error {
+	for attempt := 1; ; attempt++ {
+		err := c.tryUpdate(fn)
+		if err == nil || attempt == maxTxAttempts || !isRetryableTxError(err) {
+			return err
+		}
+	}
+}
+
+func (c *CockroachDBGraph) tryUpdate(fn func(tx *sql.Tx) ([]*graph.ChangeEvent, error)) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	events, err := fn(tx)
+	if err == nil {
+		err = c.recordTombstones(tx, events)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// recordTombstones inserts a tombstone, stamped with the transaction
+// timestamp, for each removal event in events. It also prunes expired
+// tombstones once every changeLogPruneInterval.
+func (c *CockroachDBGraph) recordTombstones(tx *sql.Tx, events []*graph.ChangeEvent) error {
+	var removed []*graph.ChangeEvent
+	for _, evt := range events {
+		if evt.Type == graph.LinkRemoved || evt.Type == graph.EdgeRemoved {
+			removed = append(removed, evt)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	for len(removed) > 0 {
+		n := len(removed)
+		if n > maxRowsPerBatchUpsert {
+			n = maxRowsPerBatchUpsert
+		}
+
+		args := make([]interface{}, 0, n*3)
+		for _, evt := range removed[:n] {
+			data, err := marshalChangeData(evt)
+			if err != nil {
+				return err
+			}
+
+			var id uuid.UUID
+			if evt.Link != nil {
+				id = evt.Link.ID
+			} else {
+				id = evt.Edge.ID
+			}
+			args = append(args, int(evt.Type), id, string(data))
+		}
+
+		if _, err := tx.Exec(insertTombstonesQueryPrefix+valueTuples(n, 3, "NOW()"), args...); err != nil {
+			return err
+		}
+
+		removed = removed[n:]
+	}
+
+	if !c.pruneDue() {
+		return nil
+	}
+	_, err := tx.Exec(pruneTombstonesQuery, changeLogRetention.Seconds())
+	return err
+}
+
+// pruneDue returns true if expired tombstones should be pruned.
+func (c *CockroachDBGraph) pruneDue() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(c.nextPruneAt) {
+		return false
+	}
+	c.nextPruneAt = now.Add(changeLogPruneInterval)
+	return true
+}
+
+// queryChanges fetches the link and edge mutations that took place within the
+// (since, upTo] range. If limit is positive, at most limit events are fetched
+// from each table and the returned cutoff is set to the earliest timestamp
+// past which the fetched events may be incomplete.
+func queryChanges(tx *sql.Tx, since, upTo time.Time, limit int) ([]*graph.ChangeEvent, time.Time, error) {
+	var (
+		list   []*graph.ChangeEvent
+		cutoff time.Time
+	)
+	for _, src := range []struct {
+		query string
+		scan  func(*sql.Rows) (*graph.ChangeEvent, error)
+	}{
+		{query: watchLinksQuery, scan: scanLinkChange},
+		{query: watchEdgesQuery, scan: scanEdgeChange},
+		{query: watchTombstonesQuery, scan: scanTombstone},
+	} {
+		query, args := src.query, []interface{}{since, upTo}
+		if limit > 0 {
+			query, args = query+" LIMIT $3", append(args, limit)
+		}
+
+		var (
+			count  int
+			lastTS time.Time
+		)
+		err := queryRows(tx, func(rows *sql.Rows) error {
+			evt, err := src.scan(rows)
+			if err != nil {
+				return err
+			}
+			list = append(list, evt)
+			count, lastTS = count+1, evt.Timestamp
+			return nil
+		}, query, args...)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		if limit > 0 && count == limit && (cutoff.IsZero() || lastTS.Before(cutoff)) {
+			cutoff = lastTS
+		}
+	}
+
+	return list, cutoff, nil
+}
+
+// queryRows executes a query within tx and invokes fn for each returned row.
+func queryRows(tx *sql.Tx, fn func(*sql.Rows) error, query string, args ...interface{}) error {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		if err = fn(rows); err != nil {
+			_ = rows.Close()
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	return rows.Close()
+}
+
+// queryLinkEvents executes a query that returns link rows and converts each
+// row into a change event of the specified type.
+func queryLinkEvents(tx *sql.Tx, evType graph.ChangeType, query string, args ...interface{}) ([]*graph.ChangeEvent, error) {
+	var events []*graph.ChangeEvent
+	err := queryRows(tx, func(rows *sql.Rows) error {
+		link, err := scanLink(rows)
+		if err != nil {
+			return err
+		}
+		events = append(events, &graph.ChangeEvent{Type: evType, Link: link})
+		return nil
+	}, query, args...)
+	return events, err
+}
+
+// queryEdgeEvents executes a query that returns edge rows and converts each
+// row into a change event of the specified type.
+func queryEdgeEvents(tx *sql.Tx, evType graph.ChangeType, query string, args ...interface{}) ([]*graph.ChangeEvent, error) {
+	var events []*graph.ChangeEvent
+	err := queryRows(tx, func(rows *sql.Rows) error {
+		edge, err := scanEdge(rows)
+		if err != nil {
+			return err
+		}
+		events = append(events, &graph.ChangeEvent{Type: evType, Edge: edge})
+		return nil
+	}, query, args...)
+	return events, err
+}
+
+// marshalChangeData serializes the link or edge affected by evt.
+func marshalChangeData(evt *graph.ChangeEvent) ([]byte, error) {
+	if evt.Link != nil {
+		return json.Marshal(evt.Link)
+	}
+	return json.Marshal(evt.Edge)
+}
+
+// unmarshalChangeData populates the link or edge of evt, depending on its
+// type, from data.
+func unmarshalChangeData(evt *graph.ChangeEvent, data []byte) error {
+	switch evt.Type {
+	case graph.LinkUpserted, graph.LinkRemoved:
+		evt.Link = new(graph.Link)
+		if err := json.Unmarshal(data, evt.Link); err != nil {
+			return xerrors.Errorf("unmarshal link: %w", err)
+		}
+		evt.Link.RetrievedAt = evt.Link.RetrievedAt.UTC()
+	default:
+		evt.Edge = new(graph.Edge)
+		if err := json.Unmarshal(data, evt.Edge); err != nil {
+			return xerrors.Errorf("unmarshal edge: %w", err)
+		}
+		evt.Edge.UpdatedAt = evt.Edge.UpdatedAt.UTC()
+	}
+	return nil
+}
+
+// scanLinkChange reads a LinkUpserted event from a row with the (id, url,
+// retrieved_at, http_status, content_type, content_hash, failure_count,
+// updated_at) columns.
+func scanLinkChange(rows *sql.Rows) (*graph.ChangeEvent, error) {
+	evt := &graph.ChangeEvent{Type: graph.LinkUpserted, Link: new(graph.Link)}
+	l := evt.Link
+	if err := rows.Scan(&l.ID, &l.URL, &l.RetrievedAt, &l.HTTPStatus, &l.ContentType, &l.ContentHash, &l.FailureCount, &evt.Timestamp); err != nil {
+		return nil, err
+	}
+	l.RetrievedAt = l.RetrievedAt.UTC()
+	evt.Timestamp = evt.Timestamp.UTC()
+	return evt, nil
+}
+
+// scanEdgeChange reads an EdgeUpserted event from a row with the (id, src,
+// dst, updated_at) columns.
+func scanEdgeChange(rows *sql.Rows) (*graph.ChangeEvent, error) {
+	edge, err := scanEdge(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &graph.ChangeEvent{Type: graph.EdgeUpserted, Timestamp: edge.UpdatedAt, Edge: edge}, nil
+}
+
+// scanTombstone reads a LinkRemoved or EdgeRemoved event from a row with the
+// (type, data, removed_at) columns.
+func scanTombstone(rows *sql.Rows) (*graph.ChangeEvent, error) {
+	var (
+		evt  = new(graph.ChangeEvent)
+		data []byte
+	)
+	if err := rows.Scan(&evt.Type, &data, &evt.Timestamp); err != nil {
+		return nil, err
+	}
+	evt.Timestamp = evt.Timestamp.UTC()
+	if err := unmarshalChangeData(evt, data); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// isRetryableTxError returns true if err indicates that a transaction was
+// aborted due to contention and can be retried.
+func isRetryableTxError(err error) bool {
+	var pqErr *pq.Error
+	return xerrors.As(err, &pqErr) && pqErr.Code == "40001"
+}
+
 // isForeignKeyViolationError returns true if err indicates a foreign key
 // constraint violation.
 func isForeignKeyViolationError(err error) bool {
@@ -138,3 +825,30 @@ func isForeignKeyViolationError(err error) bool {
 
 	return pqErr.Code.Name() == "foreign_key_violation"
 }
+
+// valueTuples returns a comma-delimited list of numRows VALUES tuples for a
+// multi-row INSERT statement. Each tuple contains numArgs sequentially
+// numbered placeholders followed by any specified extra column expressions.
+func valueTuples(numRows, numArgs int, extraCols ...string) string {
+	var sb strings.Builder
+	for row := 0; row < numRows; row++ {
+		if row > 0 {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteByte('(')
+		for col := 0; col < numArgs; col++ {
+			if col > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "$%d", row*numArgs+col+1)
+		}
+		for _, extraCol := range extraCols {
+			sb.WriteString(", ")
+			sb.WriteString(extraCol)
+		}
+		sb.WriteByte(')')
+	}
+
+	return sb.String()
+}
@@ -26,6 +26,8 @@ func (s *CockroachDbGraphTestSuite) SetUpSuite(c *gc.C) {
 
 	g, err := NewCockroachDbGraph(dsn)
 	c.Assert(err, gc.IsNil)
+	// Report changes as soon as they are committed.
+	g.watchSettleDelay = 0
 	s.SetGraph(g)
 	s.db = g.db
 }
@@ -46,4 +48,6 @@ func (s *CockroachDbGraphTestSuite) flushDB(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	_, err = s.db.Exec("DELETE FROM edges")
 	c.Assert(err, gc.IsNil)
+	_, err = s.db.Exec("DELETE FROM tombstones")
+	c.Assert(err, gc.IsNil)
 }
@@ -20,15 +20,8 @@ func (i *linkIterator) Next() bool {
 		return false
 	}
 
-	l := new(graph.Link)
-	i.lastErr = i.rows.Scan(&l.ID, &l.URL, &l.RetrievedAt)
-	if i.lastErr != nil {
-		return false
-	}
-	l.RetrievedAt = l.RetrievedAt.UTC()
-
-	i.latchedLink = l
-	return true
+	i.latchedLink, i.lastErr = scanLink(i.rows)
+	return i.lastErr == nil
 }
 
 // Error implements graph.LinkIterator.
@@ -63,15 +56,8 @@ func (i *edgeIterator) Next() bool {
 		return false
 	}
 
-	e := new(graph.Edge)
-	i.lastErr = i.rows.Scan(&e.ID, &e.Src, &e.Dst, &e.UpdatedAt)
-	if i.lastErr != nil {
-		return false
-	}
-	e.UpdatedAt = e.UpdatedAt.UTC()
-
-	i.latchedEdge = e
-	return true
+	i.latchedEdge, i.lastErr = scanEdge(i.rows)
+	return i.lastErr == nil
 }
 
 // Error implements graph.EdgeIterator.
@@ -92,3 +78,61 @@ func (i *edgeIterator) Close() error {
 func (i *edgeIterator) Edge() *graph.Edge {
 	return i.latchedEdge
 }
+
+// changeEventIterator is a graph.ChangeEventIterator implementation for the
+// cdb graph.
+type changeEventIterator struct {
+	events   []*graph.ChangeEvent
+	curIndex int
+}
+
+// Next implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Next() bool {
+	if i.curIndex >= len(i.events) {
+		return false
+	}
+	i.curIndex++
+	return true
+}
+
+// Error implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Error() error {
+	return nil
+}
+
+// Close implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Close() error {
+	return nil
+}
+
+// Event implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Event() *graph.ChangeEvent {
+	return i.events[i.curIndex-1]
+}
+
+// rowScanner is implemented by sql.Row and sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanLink reads a link from a row with the (id, url, retrieved_at,
+// http_status, content_type, content_hash, failure_count) columns.
+func scanLink(row rowScanner) (*graph.Link, error) {
+	l := new(graph.Link)
+	if err := row.Scan(&l.ID, &l.URL, &l.RetrievedAt, &l.HTTPStatus, &l.ContentType, &l.ContentHash, &l.FailureCount); err != nil {
+		return nil, err
+	}
+	l.RetrievedAt = l.RetrievedAt.UTC()
+	return l, nil
+}
+
+// scanEdge reads an edge from a row with the (id, src, dst, updated_at)
+// columns.
+func scanEdge(row rowScanner) (*graph.Edge, error) {
+	e := new(graph.Edge)
+	if err := row.Scan(&e.ID, &e.Src, &e.Dst, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	e.UpdatedAt = e.UpdatedAt.UTC()
+	return e, nil
+}
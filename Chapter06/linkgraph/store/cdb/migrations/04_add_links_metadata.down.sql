@@ -0,0 +1,4 @@
+ALTER TABLE links DROP COLUMN IF EXISTS failure_count;
+ALTER TABLE links DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE links DROP COLUMN IF EXISTS content_type;
+ALTER TABLE links DROP COLUMN IF EXISTS http_status;
@@ -0,0 +1,4 @@
+ALTER TABLE links ADD COLUMN IF NOT EXISTS http_status INT NOT NULL DEFAULT 0;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS content_type STRING NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS content_hash STRING NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS failure_count INT NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tombstones;
+DROP INDEX IF EXISTS edges@edges_updated_at_idx;
+DROP INDEX IF EXISTS links@links_updated_at_idx;
+ALTER TABLE links DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,10 @@
+ALTER TABLE links ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT now();
+CREATE INDEX IF NOT EXISTS links_updated_at_idx ON links (updated_at);
+CREATE INDEX IF NOT EXISTS edges_updated_at_idx ON edges (updated_at);
+CREATE TABLE IF NOT EXISTS tombstones (
+	removed_at TIMESTAMP NOT NULL,
+	type INT NOT NULL,
+	id UUID NOT NULL,
+	data JSONB NOT NULL,
+	PRIMARY KEY (removed_at, type, id)
+);
@@ -77,3 +77,45 @@ func (i *edgeIterator) Edge() *graph.Edge {
 	i.s.mu.RUnlock()
 	return edge
 }
+
+// changeEventIterator is a graph.ChangeEventIterator implementation for the
+// in-memory graph.
+type changeEventIterator struct {
+	events   []*graph.ChangeEvent
+	curIndex int
+}
+
+// Next implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Next() bool {
+	if i.curIndex >= len(i.events) {
+		return false
+	}
+	i.curIndex++
+	return true
+}
+
+// Error implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Error() error {
+	return nil
+}
+
+// Close implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Close() error {
+	return nil
+}
+
+// Event implements graph.ChangeEventIterator.
+func (i *changeEventIterator) Event() *graph.ChangeEvent {
+	// Change log entries are never modified once appended to the log so
+	// we only need to clone the event to protect them from the caller.
+	ev := *i.events[i.curIndex-1]
+	if ev.Link != nil {
+		lCopy := *ev.Link
+		ev.Link = &lCopy
+	}
+	if ev.Edge != nil {
+		eCopy := *ev.Edge
+		ev.Edge = &eCopy
+	}
+	return &ev
+}
@@ -1,6 +1,10 @@
 package memory
 
 import (
+	"bytes"
+	"net/url"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,7 +16,16 @@ import (
 // Compile-time check for ensuring InMemoryGraph implements Graph.
 var _ graph.Graph = (*InMemoryGraph)(nil)
 
-// edgeList contains the slice of edge UUIDs that originate from a link in the graph.
+// defaultMaxChangeLogSize is the default number of change events retained by
+// the in-memory graph for serving Watch requests.
+const defaultMaxChangeLogSize = 10000
+
+// maxWatchBatchSize is the maximum number of change events returned by a
+// single Watch call.
+const maxWatchBatchSize = 1000
+
+// edgeList contains the slice of edge UUIDs that originate from or terminate
+// at a link in the graph.
 type edgeList []uuid.UUID
 
 // InMemoryGraph implements an in-memory link graph that can be concurrently
@@ -23,17 +36,34 @@ type InMemoryGraph struct {
 	links map[uuid.UUID]*graph.Link
 	edges map[uuid.UUID]*graph.Edge
 
-	linkURLIndex map[string]*graph.Link
-	linkEdgeMap  map[uuid.UUID]edgeList
+	linkURLIndex       map[string]*graph.Link
+	linkEdgeMap        map[uuid.UUID]edgeList
+	linkInboundEdgeMap map[uuid.UUID]edgeList
+
+	// changeLog is a bounded, chronologically ordered log of the most
+	// recent graph mutations. Once it reaches twice maxChangeLogSize
+	// entries, only the maxChangeLogSize most recent entries are kept and
+	// changeLogTruncatedAt is set to the timestamp of the last dropped
+	// entry.
+	changeLog            []graph.ChangeEvent
+	maxChangeLogSize     int
+	changeLogTruncatedAt time.Time
+
+	// lastChangeAt is the timestamp of the most recently recorded change
+	// event. It ensures that event timestamps are strictly increasing
+	// even if the wall clock does not advance between mutations.
+	lastChangeAt time.Time
 }
 
 // NewInMemoryGraph creates a new in-memory link graph.
 func NewInMemoryGraph() *InMemoryGraph {
 	return &InMemoryGraph{
-		links:        make(map[uuid.UUID]*graph.Link),
-		edges:        make(map[uuid.UUID]*graph.Edge),
-		linkURLIndex: make(map[string]*graph.Link),
-		linkEdgeMap:  make(map[uuid.UUID]edgeList),
+		links:              make(map[uuid.UUID]*graph.Link),
+		edges:              make(map[uuid.UUID]*graph.Edge),
+		linkURLIndex:       make(map[string]*graph.Link),
+		linkEdgeMap:        make(map[uuid.UUID]edgeList),
+		linkInboundEdgeMap: make(map[uuid.UUID]edgeList),
+		maxChangeLogSize:   defaultMaxChangeLogSize,
 	}
 }
 
@@ -42,16 +72,34 @@ func (s *InMemoryGraph) UpsertLink(link *graph.Link) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.upsertLink(link)
+	return nil
+}
+
+// UpsertLinks creates or updates a batch of links.
+func (s *InMemoryGraph) UpsertLinks(links []*graph.Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range links {
+		s.upsertLink(link)
+	}
+	return nil
+}
+
+// upsertLink creates a new link or updates an existing link. It must be
+// called while holding a write lock.
+func (s *InMemoryGraph) upsertLink(link *graph.Link) {
 	// Check if a link with the same URL already exists. If so, convert
 	// this into an update and point the link ID to the existing link.
 	if existing := s.linkURLIndex[link.URL]; existing != nil {
 		link.ID = existing.ID
-		origTs := existing.RetrievedAt
-		*existing = *link
-		if origTs.After(existing.RetrievedAt) {
-			existing.RetrievedAt = origTs
+		// Never overwrite the link details with stale information.
+		if !existing.RetrievedAt.After(link.RetrievedAt) {
+			*existing = *link
+			s.recordChange(graph.LinkUpserted, existing, nil)
 		}
-		return nil
+		return
 	}
 
 	// Assign new ID and insert link
@@ -66,7 +114,7 @@ func (s *InMemoryGraph) UpsertLink(link *graph.Link) error {
 	*lCopy = *link
 	s.linkURLIndex[lCopy.URL] = lCopy
 	s.links[lCopy.ID] = lCopy
-	return nil
+	s.recordChange(graph.LinkUpserted, lCopy, nil)
 }
 
 // FindLink looks up a link by its ID.
@@ -98,6 +146,9 @@ func (s *InMemoryGraph) Links(fromID, toID uuid.UUID, retrievedBefore time.Time)
 	}
 	s.mu.RUnlock()
 
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i].ID[:], list[j].ID[:]) < 0
+	})
 	return &linkIterator{s: s, links: list}, nil
 }
 
@@ -106,19 +157,49 @@ func (s *InMemoryGraph) UpsertEdge(edge *graph.Edge) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, srcExists := s.links[edge.Src]
-	_, dstExists := s.links[edge.Dst]
-	if !srcExists || !dstExists {
+	if !s.edgeLinksExist(edge) {
 		return xerrors.Errorf("upsert edge: %w", graph.ErrUnknownEdgeLinks)
 	}
 
+	s.upsertEdge(edge)
+	return nil
+}
+
+// UpsertEdges creates or updates a batch of edges. If any of the edges refers
+// to an unknown link, none of the edges in the batch will be upserted.
+func (s *InMemoryGraph) UpsertEdges(edges []*graph.Edge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, edge := range edges {
+		if !s.edgeLinksExist(edge) {
+			return xerrors.Errorf("upsert edges: %w", graph.ErrUnknownEdgeLinks)
+		}
+	}
+
+	for _, edge := range edges {
+		s.upsertEdge(edge)
+	}
+	return nil
+}
+
+func (s *InMemoryGraph) edgeLinksExist(edge *graph.Edge) bool {
+	_, srcExists := s.links[edge.Src]
+	_, dstExists := s.links[edge.Dst]
+	return srcExists && dstExists
+}
+
+// upsertEdge creates a new edge or updates an existing edge. It must be
+// called while holding a write lock.
+func (s *InMemoryGraph) upsertEdge(edge *graph.Edge) {
 	// Scan edge list from source
 	for _, edgeID := range s.linkEdgeMap[edge.Src] {
 		existingEdge := s.edges[edgeID]
 		if existingEdge.Src == edge.Src && existingEdge.Dst == edge.Dst {
 			existingEdge.UpdatedAt = time.Now()
 			*edge = *existingEdge
-			return nil
+			s.recordChange(graph.EdgeUpserted, nil, existingEdge)
+			return
 		}
 	}
 
@@ -138,7 +219,11 @@ func (s *InMemoryGraph) UpsertEdge(edge *graph.Edge) error {
 	// Append the edge ID to the list of edges originating from the
 	// edge's source link.
 	s.linkEdgeMap[edge.Src] = append(s.linkEdgeMap[edge.Src], eCopy.ID)
-	return nil
+
+	// Append the edge ID to the list of edges terminating at the edge's
+	// destination link.
+	s.linkInboundEdgeMap[edge.Dst] = append(s.linkInboundEdgeMap[edge.Dst], eCopy.ID)
+	s.recordChange(graph.EdgeUpserted, nil, eCopy)
 }
 
 // Edges returns an iterator for the set of edges whose source vertex IDs
@@ -165,6 +250,42 @@ func (s *InMemoryGraph) Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (
 	return &edgeIterator{s: s, edges: list}, nil
 }
 
+// InboundEdges returns an iterator for the set of edges that terminate at the
+// specified link ID.
+func (s *InMemoryGraph) InboundEdges(dstID uuid.UUID) (graph.EdgeIterator, error) {
+	s.mu.RLock()
+	list := make([]*graph.Edge, 0, len(s.linkInboundEdgeMap[dstID]))
+	for _, edgeID := range s.linkInboundEdgeMap[dstID] {
+		list = append(list, s.edges[edgeID])
+	}
+	s.mu.RUnlock()
+
+	return &edgeIterator{s: s, edges: list}, nil
+}
+
+// InDegree returns the number of edges that terminate at the specified link ID.
+func (s *InMemoryGraph) InDegree(id uuid.UUID) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exists := s.links[id]; !exists {
+		return 0, xerrors.Errorf("in degree: %w", graph.ErrNotFound)
+	}
+	return len(s.linkInboundEdgeMap[id]), nil
+}
+
+// OutDegree returns the number of edges that originate from the specified link
+// ID.
+func (s *InMemoryGraph) OutDegree(id uuid.UUID) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exists := s.links[id]; !exists {
+		return 0, xerrors.Errorf("out degree: %w", graph.ErrNotFound)
+	}
+	return len(s.linkEdgeMap[id]), nil
+}
+
 // RemoveStaleEdges removes any edge that originates from the specified link ID
 // and was updated before the specified timestamp.
 func (s *InMemoryGraph) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error {
@@ -175,7 +296,8 @@ func (s *InMemoryGraph) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Ti
 	for _, edgeID := range s.linkEdgeMap[fromID] {
 		edge := s.edges[edgeID]
 		if edge.UpdatedAt.Before(updatedBefore) {
-			delete(s.edges, edgeID)
+			s.removeInboundEdge(edge)
+			s.deleteEdge(edge)
 			continue
 		}
 
@@ -186,3 +308,159 @@ func (s *InMemoryGraph) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Ti
 	s.linkEdgeMap[fromID] = newEdgeList
 	return nil
 }
+
+// RemoveLink removes the link with the specified ID as well as any edge that
+// originates from or terminates at it.
+func (s *InMemoryGraph) RemoveLink(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.links[id]; !exists {
+		return xerrors.Errorf("remove link: %w", graph.ErrNotFound)
+	}
+
+	s.removeLinks(map[uuid.UUID]struct{}{id: {}})
+	return nil
+}
+
+// RemoveLinksByHost removes all links whose URL points to the specified host
+// as well as any edges that originate from or terminate at them.
+func (s *InMemoryGraph) RemoveLinksByHost(host string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	toRemove := make(map[uuid.UUID]struct{})
+	for linkID, link := range s.links {
+		if linkMatchesHost(link, host) {
+			toRemove[linkID] = struct{}{}
+		}
+	}
+
+	s.removeLinks(toRemove)
+	return nil
+}
+
+// removeLinks deletes the specified set of links and purges any edges that
+// reference them. Callers must hold the write lock.
+func (s *InMemoryGraph) removeLinks(toRemove map[uuid.UUID]struct{}) {
+	for linkID := range toRemove {
+		// Drop outgoing edges and remove them from the inbound edge lists
+		// of their destinations.
+		for _, edgeID := range s.linkEdgeMap[linkID] {
+			s.removeInboundEdge(s.edges[edgeID])
+			s.deleteEdge(s.edges[edgeID])
+		}
+		delete(s.linkEdgeMap, linkID)
+
+		// Drop incoming edges and remove them from the outgoing edge lists
+		// of their sources.
+		for _, edgeID := range s.linkInboundEdgeMap[linkID] {
+			s.removeOutboundEdge(s.edges[edgeID])
+			s.deleteEdge(s.edges[edgeID])
+		}
+		delete(s.linkInboundEdgeMap, linkID)
+
+		link := s.links[linkID]
+		delete(s.linkURLIndex, link.URL)
+		delete(s.links, linkID)
+		s.recordChange(graph.LinkRemoved, link, nil)
+	}
+}
+
+// deleteEdge deletes an edge whose ID has already been removed from the
+// edge lists of its source and destination links. Callers must hold the
+// write lock.
+func (s *InMemoryGraph) deleteEdge(edge *graph.Edge) {
+	delete(s.edges, edge.ID)
+	s.recordChange(graph.EdgeRemoved, nil, edge)
+}
+
+// Watch returns an iterator for the link and edge mutations that took place
+// after the provided timestamp, ordered by their timestamps. At most
+// maxWatchBatchSize events are returned by each call.
+func (s *InMemoryGraph) Watch(since time.Time) (graph.ChangeEventIterator, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if since.Before(s.changeLogTruncatedAt) {
+		return nil, xerrors.Errorf("watch: %w", graph.ErrChangeLogTruncated)
+	}
+
+	first := sort.Search(len(s.changeLog), func(i int) bool {
+		return s.changeLog[i].Timestamp.After(since)
+	})
+
+	last := first + maxWatchBatchSize
+	if last > len(s.changeLog) {
+		last = len(s.changeLog)
+	}
+
+	list := make([]*graph.ChangeEvent, 0, last-first)
+	for i := first; i < last; i++ {
+		list = append(list, &s.changeLog[i])
+	}
+	return &changeEventIterator{events: list}, nil
+}
+
+// recordChange appends a change event for the provided link or edge to the
+// change log. Callers must hold the write lock.
+func (s *InMemoryGraph) recordChange(evType graph.ChangeType, link *graph.Link, edge *graph.Edge) {
+	// Strip the monotonic clock reading so that timestamps are compared
+	// by their wall clock value, which is all that survives once they
+	// are handed to clients.
+	now := time.Now().Round(0)
+	if !now.After(s.lastChangeAt) {
+		now = s.lastChangeAt.Add(time.Nanosecond)
+	}
+	s.lastChangeAt = now
+
+	ev := graph.ChangeEvent{Type: evType, Timestamp: now}
+	if link != nil {
+		lCopy := *link
+		ev.Link = &lCopy
+	}
+	if edge != nil {
+		eCopy := *edge
+		ev.Edge = &eCopy
+	}
+	s.changeLog = append(s.changeLog, ev)
+
+	// Drop the oldest events once the log grows too large.
+	if len(s.changeLog) >= 2*s.maxChangeLogSize {
+		dropCount := len(s.changeLog) - s.maxChangeLogSize
+		s.changeLogTruncatedAt = s.changeLog[dropCount-1].Timestamp
+		s.changeLog = append([]graph.ChangeEvent(nil), s.changeLog[dropCount:]...)
+	}
+}
+
+// removeInboundEdge removes edge from the inbound edge list of its
+// destination link. Callers must hold the write lock.
+func (s *InMemoryGraph) removeInboundEdge(edge *graph.Edge) {
+	s.linkInboundEdgeMap[edge.Dst] = withoutEdge(s.linkInboundEdgeMap[edge.Dst], edge.ID)
+}
+
+// removeOutboundEdge removes edge from the outgoing edge list of its source
+// link. Callers must hold the write lock.
+func (s *InMemoryGraph) removeOutboundEdge(edge *graph.Edge) {
+	s.linkEdgeMap[edge.Src] = withoutEdge(s.linkEdgeMap[edge.Src], edge.ID)
+}
+
+// withoutEdge returns a copy of list with edgeID filtered out.
+func withoutEdge(list edgeList, edgeID uuid.UUID) edgeList {
+	var newEdgeList edgeList
+	for _, id := range list {
+		if id != edgeID {
+			newEdgeList = append(newEdgeList, id)
+		}
+	}
+	return newEdgeList
+}
+
+// linkMatchesHost returns true if the link URL points to the specified host.
+func linkMatchesHost(link *graph.Link, host string) bool {
+	u, err := url.Parse(link.URL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Hostname(), host)
+}
@@ -1,9 +1,13 @@
 package memory
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph/graphtest"
+	"golang.org/x/xerrors"
 	gc "gopkg.in/check.v1"
 )
 
@@ -18,3 +22,71 @@ type InMemoryGraphTestSuite struct {
 func (s *InMemoryGraphTestSuite) SetUpTest(c *gc.C) {
 	s.SetGraph(NewInMemoryGraph())
 }
+
+func (s *InMemoryGraphTestSuite) TestWatchRemovals(c *gc.C) {
+	g := NewInMemoryGraph()
+	src := &graph.Link{URL: "https://example.com/a"}
+	dst := &graph.Link{URL: "https://example.com/b"}
+	c.Assert(g.UpsertLink(src), gc.IsNil)
+	c.Assert(g.UpsertLink(dst), gc.IsNil)
+	c.Assert(g.UpsertEdge(&graph.Edge{Src: src.ID, Dst: dst.ID}), gc.IsNil)
+
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	c.Assert(g.RemoveLink(dst.ID), gc.IsNil)
+
+	it, err := g.Watch(since)
+	c.Assert(err, gc.IsNil)
+	var types []graph.ChangeType
+	for it.Next() {
+		types = append(types, it.Event().Type)
+	}
+	c.Assert(it.Close(), gc.IsNil)
+	c.Assert(types, gc.DeepEquals, []graph.ChangeType{graph.EdgeRemoved, graph.LinkRemoved})
+}
+
+func (s *InMemoryGraphTestSuite) TestWatchTruncatedChangeLog(c *gc.C) {
+	g := NewInMemoryGraph()
+	g.maxChangeLogSize = 2
+
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 4; i++ {
+		c.Assert(g.UpsertLink(&graph.Link{URL: fmt.Sprint(i)}), gc.IsNil)
+	}
+
+	_, err := g.Watch(since)
+	c.Assert(xerrors.Is(err, graph.ErrChangeLogTruncated), gc.Equals, true)
+
+	_, err = g.Watch(time.Now())
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *InMemoryGraphTestSuite) TestWatchBatchSize(c *gc.C) {
+	g := NewInMemoryGraph()
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < maxWatchBatchSize+1; i++ {
+		c.Assert(g.UpsertLink(&graph.Link{URL: fmt.Sprint(i)}), gc.IsNil)
+	}
+
+	var count int
+	for {
+		it, err := g.Watch(since)
+		c.Assert(err, gc.IsNil)
+
+		var batch int
+		for it.Next() {
+			since = it.Event().Timestamp
+			batch++
+		}
+		c.Assert(it.Close(), gc.IsNil)
+		c.Assert(batch <= maxWatchBatchSize, gc.Equals, true)
+
+		if batch == 0 {
+			break
+		}
+		count += batch
+	}
+	c.Assert(count, gc.Equals, maxWatchBatchSize+1)
+}
@@ -0,0 +1,40 @@
+package index
+
+import "strings"
+
+// MaxAnchorTexts is the maximum number of distinct anchor texts retained for
+// each document.
+const MaxAnchorTexts = 32
+
+// AppendAnchorText appends text to a list of anchor texts unless the list
+// already contains text or has reached MaxAnchorTexts entries. Anchor texts
+// are compared case-insensitively. It returns the updated list and a flag
+// indicating whether text was appended.
+func AppendAnchorText(list []string, text string) ([]string, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" || len(list) >= MaxAnchorTexts {
+		return list, false
+	}
+	for _, existing := range list {
+		if strings.EqualFold(existing, text) {
+			return list, false
+		}
+	}
+	return append(list, text), true
+}
+
+// AppendAnchorTexts appends each one of texts to a copy of list using the same
+// rules as AppendAnchorText. It returns the updated list and a flag indicating
+// whether any text was appended.
+func AppendAnchorTexts(list []string, texts []string) ([]string, bool) {
+	var (
+		updated = append([]string(nil), list...)
+		changed bool
+	)
+	for _, text := range texts {
+		var appended bool
+		updated, appended = AppendAnchorText(updated, text)
+		changed = changed || appended
+	}
+	return updated, changed
+}
@@ -0,0 +1,47 @@
+package index
+
+import (
+	"fmt"
+
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(AnchorTextTestSuite))
+
+type AnchorTextTestSuite struct{}
+
+func (s *AnchorTextTestSuite) TestAppendAnchorText(c *gc.C) {
+	list, appended := AppendAnchorText(nil, " Lorem ipsum ")
+	c.Assert(appended, gc.Equals, true)
+	c.Assert(list, gc.DeepEquals, []string{"Lorem ipsum"})
+
+	list, appended = AppendAnchorText(list, "LOREM IPSUM")
+	c.Assert(appended, gc.Equals, false)
+	c.Assert(list, gc.HasLen, 1)
+
+	list, appended = AppendAnchorText(list, "   ")
+	c.Assert(appended, gc.Equals, false)
+	c.Assert(list, gc.HasLen, 1)
+
+	for i := len(list); i < MaxAnchorTexts; i++ {
+		list, _ = AppendAnchorText(list, fmt.Sprint(i))
+	}
+	list, appended = AppendAnchorText(list, "dolor")
+	c.Assert(appended, gc.Equals, false)
+	c.Assert(list, gc.HasLen, MaxAnchorTexts)
+}
+
+func (s *AnchorTextTestSuite) TestAppendAnchorTexts(c *gc.C) {
+	orig := make([]string, 1, 4)
+	orig[0] = "Lorem ipsum"
+
+	list, appended := AppendAnchorTexts(orig, []string{"lorem IPSUM", "dolor", " ", "DOLOR", "sit amet"})
+	c.Assert(appended, gc.Equals, true)
+	c.Assert(list, gc.DeepEquals, []string{"Lorem ipsum", "dolor", "sit amet"})
+
+	// The original list must not be modified.
+	c.Assert(orig[:cap(orig)], gc.DeepEquals, []string{"Lorem ipsum", "", "", ""})
+
+	_, appended = AppendAnchorTexts(list, []string{"DOLOR", "  "})
+	c.Assert(appended, gc.Equals, false)
+}
@@ -0,0 +1,94 @@
+package index
+
+import (
+	"sort"
+	"strings"
+)
+
+// MaxCompletions is the maximum number of completions returned by the
+// indexer implementations.
+const MaxCompletions = 8
+
+// PrefixFunc returns the indexed terms that start with a lower-cased prefix
+// together with the number of documents that contain each term.
+type PrefixFunc func(prefix string) ([]TermCandidate, error)
+
+// TitleFunc returns the titles of up to limit documents whose titles contain
+// all of the specified lower-cased words as well as a term that starts with
+// prefix. If prefix is empty, only the words need to be matched.
+type TitleFunc func(words []string, prefix string, limit int) ([]string, error)
+
+// Complete returns up to MaxCompletions completions for a partially typed
+// search expression.
+//
+// If expression does not end with a space, its last word is treated as a
+// prefix and term completions are built by replacing it with the indexed
+// terms returned by termsFn, ordered by the number of documents that contain
+// them. Term completions are followed by up to MaxCompletions/2 document
+// titles returned by titlesFn.
+func Complete(expression string, termsFn PrefixFunc, titlesFn TitleFunc) ([]string, error) {
+	words := spellCheckedWords(expression)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	// The last word is only treated as a prefix if the user is still
+	// typing it.
+	var (
+		prefixLoc  []int
+		titleWords []string
+	)
+	if last := words[len(words)-1]; last[1] == len(expression) {
+		prefixLoc = last
+		words = words[:len(words)-1]
+	}
+	for _, loc := range words {
+		titleWords = append(titleWords, strings.ToLower(expression[loc[0]:loc[1]]))
+	}
+
+	var prefix string
+	if prefixLoc != nil {
+		prefix = strings.ToLower(expression[prefixLoc[0]:prefixLoc[1]])
+	}
+
+	titles, err := titlesFn(titleWords, prefix, MaxCompletions/2)
+	if err != nil {
+		return nil, err
+	}
+
+	var terms []TermCandidate
+	if prefix != "" {
+		if terms, err = termsFn(prefix); err != nil {
+			return nil, err
+		}
+		sort.Slice(terms, func(i, j int) bool {
+			if terms[i].DocCount != terms[j].DocCount {
+				return terms[i].DocCount > terms[j].DocCount
+			}
+			return terms[i].Term < terms[j].Term
+		})
+	}
+
+	var (
+		completions []string
+		seen        = map[string]bool{strings.ToLower(strings.TrimSpace(expression)): true}
+		maxTerms    = MaxCompletions - len(titles)
+	)
+	for _, term := range terms {
+		if len(completions) == maxTerms {
+			break
+		}
+		completion := expression[:prefixLoc[0]] + term.Term
+		if key := strings.ToLower(completion); !seen[key] {
+			seen[key] = true
+			completions = append(completions, completion)
+		}
+	}
+	for _, title := range titles {
+		if key := strings.ToLower(strings.TrimSpace(title)); key != "" && !seen[key] {
+			seen[key] = true
+			completions = append(completions, title)
+		}
+	}
+	return completions, nil
+}
@@ -0,0 +1,63 @@
+package index
+
+import (
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(CompleteTestSuite))
+
+type CompleteTestSuite struct{}
+
+func (s *CompleteTestSuite) TestComplete(c *gc.C) {
+	var (
+		gotPrefix string
+		gotWords  []string
+	)
+	termsFn := func(prefix string) ([]TermCandidate, error) {
+		gotPrefix = prefix
+		return []TermCandidate{
+			{Term: "ipsam", DocCount: 1},
+			{Term: "ipsum", DocCount: 10},
+			{Term: "ips", DocCount: 3},
+		}, nil
+	}
+	titlesFn := func(words []string, prefix string, limit int) ([]string, error) {
+		gotWords = words
+		c.Assert(prefix, gc.Equals, "ips")
+		c.Assert(limit, gc.Equals, MaxCompletions/2)
+		return []string{"Lorem Ipsum Dolor", "lorem ipsum"}, nil
+	}
+
+	got, err := Complete("+Lorem ips", termsFn, titlesFn)
+	c.Assert(err, gc.IsNil)
+	c.Assert(gotPrefix, gc.Equals, "ips")
+	c.Assert(gotWords, gc.DeepEquals, []string{"lorem"})
+	c.Assert(got, gc.DeepEquals, []string{
+		"+Lorem ipsum",
+		"+Lorem ipsam",
+		"Lorem Ipsum Dolor",
+		"lorem ipsum",
+	})
+}
+
+func (s *CompleteTestSuite) TestCompleteAfterSpace(c *gc.C) {
+	termsFn := func(string) ([]TermCandidate, error) {
+		c.Fatal("unexpected call to termsFn")
+		return nil, nil
+	}
+	titlesFn := func(words []string, prefix string, _ int) ([]string, error) {
+		c.Assert(words, gc.DeepEquals, []string{"lorem", "ipsum"})
+		c.Assert(prefix, gc.Equals, "")
+		return []string{"Lorem ipsum dolor"}, nil
+	}
+
+	got, err := Complete("lorem AND ipsum ", termsFn, titlesFn)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.DeepEquals, []string{"Lorem ipsum dolor"})
+}
+
+func (s *CompleteTestSuite) TestCompleteWithoutWords(c *gc.C) {
+	got, err := Complete(` "" `, nil, nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.HasLen, 0)
+}
@@ -0,0 +1,97 @@
+package index
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// Cursor identifies the position of a document within the ranked results of
+// a search query. Cursors are exchanged with clients in an opaque string form
+// obtained via the String method.
+//
+// Search results are ordered by descending ranking score; ties are broken by
+// ordering documents by their link IDs. As cursors capture the sort keys of
+// the document they point to, resuming a search from a cursor does not
+// require the indexer to skip over the preceding results and is less
+// sensitive to changes in the result set between requests than specifying
+// an offset.
+//
+// As ranking scores may decay with the age of documents, cursors also capture
+// the reference time that was used for calculating the scores. Searches that
+// resume from a cursor calculate the scores of the results for the same
+// reference time so that they remain comparable to the captured score.
+type Cursor struct {
+	// The 0-based position of the document within the results at the time
+	// the cursor was created. It is only used for display purposes.
+	Position uint64
+
+	// The ranking score of the document.
+	Score float64
+
+	// The reference time used for calculating the ranking score.
+	Now time.Time
+
+	// The link ID of the document.
+	LinkID uuid.UUID
+}
+
+// String returns the opaque representation of c.
+func (c Cursor) String() string {
+	var nowNanos int64
+	if !c.Now.IsZero() {
+		nowNanos = c.Now.UnixNano()
+	}
+
+	raw := strconv.FormatUint(c.Position, 10) + ":" +
+		strconv.FormatFloat(c.Score, 'g', -1, 64) + ":" +
+		strconv.FormatInt(nowNanos, 10) + ":" +
+		c.LinkID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ReferenceTime returns the reference time for calculating ranking scores
+// when resuming a search from c. Cursors that do not capture a reference
+// time yield the current time.
+func (c Cursor) ReferenceTime() time.Time {
+	if c.Now.IsZero() {
+		return time.Now()
+	}
+	return c.Now
+}
+
+// ParseCursor decodes a cursor from the opaque representation returned by
+// its String method.
+func ParseCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, xerrors.Errorf("parse cursor: %w", ErrInvalidCursor)
+	}
+
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 {
+		return Cursor{}, xerrors.Errorf("parse cursor: %w", ErrInvalidCursor)
+	}
+
+	var c Cursor
+	if c.Position, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return Cursor{}, xerrors.Errorf("parse cursor: %w", ErrInvalidCursor)
+	}
+	if c.Score, err = strconv.ParseFloat(parts[1], 64); err != nil {
+		return Cursor{}, xerrors.Errorf("parse cursor: %w", ErrInvalidCursor)
+	}
+	nowNanos, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Cursor{}, xerrors.Errorf("parse cursor: %w", ErrInvalidCursor)
+	} else if nowNanos != 0 {
+		c.Now = time.Unix(0, nowNanos).UTC()
+	}
+	if c.LinkID, err = uuid.Parse(parts[3]); err != nil {
+		return Cursor{}, xerrors.Errorf("parse cursor: %w", ErrInvalidCursor)
+	}
+	return c, nil
+}
@@ -0,0 +1,35 @@
+package index
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(CursorTestSuite))
+
+type CursorTestSuite struct{}
+
+func (s *CursorTestSuite) TestCursorRoundTrip(c *gc.C) {
+	exp := Cursor{Position: 42, Score: 0.1 + 0.2, Now: time.Unix(0, 1234567890123456789).UTC(), LinkID: uuid.New()}
+
+	got, err := ParseCursor(exp.String())
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.Equals, exp)
+}
+
+func (s *CursorTestSuite) TestParseInvalidCursor(c *gc.C) {
+	for _, cursor := range []string{
+		"",
+		"not base64!",
+		Cursor{}.String()[:10],
+		"MTo6", // "1::"
+		base64.RawURLEncoding.EncodeToString([]byte("7:1.5:" + uuid.New().String())),
+	} {
+		_, err := ParseCursor(cursor)
+		c.Assert(xerrors.Is(err, ErrInvalidCursor), gc.Equals, true, gc.Commentf("cursor %q", cursor))
+	}
+}
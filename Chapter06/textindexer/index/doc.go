@@ -20,6 +20,22 @@ type Document struct {
 	// The document body
 	Content string
 
+	// The contents of the description meta tag (if available).
+	Description string
+
+	// The text of the top-level (h1-h3) headings in the document.
+	Headings []string
+
+	// The distinct texts of links pointing to this document from other
+	// crawled pages. Anchor texts are recorded via the AddAnchorText and
+	// AddAnchorTexts methods of the indexer and are preserved when the
+	// document is re-indexed.
+	AnchorText []string
+
+	// The ISO 639-1 code of the document language or an empty string if
+	// the language is unknown.
+	Language string
+
 	// The last time this document was indexed.
 	IndexedAt time.Time
 
@@ -10,4 +10,12 @@ var (
 	// ErrMissingLinkID is returned when attempting to index a document
 	// that does not specify a valid link ID.
 	ErrMissingLinkID = xerrors.New("document does not provide a valid linkID")
+
+	// ErrInvalidQuery is returned by ParseQuery when the provided search
+	// expression cannot be parsed.
+	ErrInvalidQuery = xerrors.New("invalid query")
+
+	// ErrInvalidCursor is returned when a search query specifies a cursor
+	// that cannot be decoded.
+	ErrInvalidCursor = xerrors.New("invalid cursor")
 )
@@ -0,0 +1,178 @@
+package index
+
+import (
+	"strconv"
+	"time"
+)
+
+// DefaultFacetSize is the number of buckets returned for host and IndexedAt
+// facets that do not specify an explicit size.
+const DefaultFacetSize = 10
+
+// FacetType describes the types of facets supported by the indexer
+// implementations.
+type FacetType uint8
+
+const (
+	// FacetTypeHost counts the matching documents for each URL host.
+	FacetTypeHost FacetType = iota
+
+	// FacetTypeIndexedAt counts the matching documents indexed within
+	// consecutive calendar intervals.
+	FacetTypeIndexedAt
+
+	// FacetTypePageRank counts the matching documents whose PageRank score
+	// falls within a set of ranges.
+	FacetTypePageRank
+)
+
+// DateInterval describes the width of the buckets for IndexedAt facets.
+type DateInterval uint8
+
+const (
+	// DateIntervalDay groups documents by the day they were indexed.
+	DateIntervalDay DateInterval = iota
+
+	// DateIntervalMonth groups documents by the month they were indexed.
+	DateIntervalMonth
+
+	// DateIntervalYear groups documents by the year they were indexed.
+	DateIntervalYear
+)
+
+// FacetRequest describes a set of counts to be calculated over the documents
+// that match a query.
+type FacetRequest struct {
+	// A name for identifying the facet in the search results.
+	Name string
+
+	// The type of facet to calculate.
+	Type FacetType
+
+	// For host facets, the maximum number of hosts to return. For IndexedAt
+	// facets, the number of intervals to return, ending with the interval
+	// that contains the current time. If zero, DefaultFacetSize is used.
+	Size int
+
+	// The bucket width for IndexedAt facets.
+	Interval DateInterval
+
+	// The ascending list of range boundaries for PageRank facets. N
+	// boundaries yield N+1 buckets where the first and last buckets are
+	// open-ended.
+	Ranges []float64
+}
+
+// FacetResult contains the bucket counts for a requested facet.
+type FacetResult struct {
+	// The name of the facet request.
+	Name string
+
+	// The calculated buckets. Host buckets are sorted by count in descending
+	// order while IndexedAt and PageRank buckets are sorted by their ranges
+	// in ascending order.
+	Buckets []FacetBucket
+}
+
+// FacetBucket contains the number of matching documents for a facet value.
+type FacetBucket struct {
+	// The bucket key. Depending on the facet type this is a host name, the
+	// start of an IndexedAt interval (formatted as YYYY-MM-DD, YYYY-MM or
+	// YYYY) or a PageRank range (formatted as FROM..TO).
+	Key string
+
+	// The number of matching documents in the bucket.
+	Count uint64
+}
+
+// DateRange describes a bucket for an IndexedAt facet. It covers the
+// [From, To) interval.
+type DateRange struct {
+	Key  string
+	From time.Time
+	To   time.Time
+}
+
+// NumericRange describes a bucket for a PageRank facet. It covers the
+// [From, To) interval; a nil bound leaves that end of the range open.
+type NumericRange struct {
+	Key  string
+	From *float64
+	To   *float64
+}
+
+// Limit returns the number of buckets to calculate for host and IndexedAt
+// facets.
+func (r FacetRequest) Limit() int {
+	if r.Size <= 0 {
+		return DefaultFacetSize
+	}
+	return r.Size
+}
+
+// DateRanges returns the list of buckets for an IndexedAt facet in ascending
+// order. The last bucket contains now.
+func (r FacetRequest) DateRanges(now time.Time) []DateRange {
+	var (
+		start  time.Time
+		layout string
+		next   func(time.Time, int) time.Time
+	)
+
+	now = now.UTC()
+	switch r.Interval {
+	case DateIntervalYear:
+		start = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		layout = "2006"
+		next = func(t time.Time, n int) time.Time { return t.AddDate(n, 0, 0) }
+	case DateIntervalMonth:
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		layout = "2006-01"
+		next = func(t time.Time, n int) time.Time { return t.AddDate(0, n, 0) }
+	default:
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		layout = "2006-01-02"
+		next = func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) }
+	}
+
+	limit := r.Limit()
+	ranges := make([]DateRange, limit)
+	for i := 0; i < limit; i++ {
+		from := next(start, i-limit+1)
+		ranges[i] = DateRange{
+			Key:  from.Format(layout),
+			From: from,
+			To:   next(from, 1),
+		}
+	}
+	return ranges
+}
+
+// NumericRanges returns the list of buckets for a PageRank facet in
+// ascending order.
+func (r FacetRequest) NumericRanges() []NumericRange {
+	if len(r.Ranges) == 0 {
+		return nil
+	}
+
+	ranges := make([]NumericRange, 0, len(r.Ranges)+1)
+	var from *float64
+	for i := range r.Ranges {
+		to := &r.Ranges[i]
+		ranges = append(ranges, NumericRange{Key: numericRangeKey(from, to), From: from, To: to})
+		from = to
+	}
+	return append(ranges, NumericRange{Key: numericRangeKey(from, nil), From: from})
+}
+
+func numericRangeKey(from, to *float64) string {
+	var key string
+	if from != nil {
+		key = strconv.FormatFloat(*from, 'g', -1, 64)
+	}
+	key += ".."
+	if to != nil {
+		key += strconv.FormatFloat(*to, 'g', -1, 64)
+	}
+	return key
+}
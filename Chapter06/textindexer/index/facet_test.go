@@ -0,0 +1,75 @@
+package index
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(FacetTestSuite))
+
+type FacetTestSuite struct{}
+
+func (s *FacetTestSuite) TestDateRanges(c *gc.C) {
+	now := time.Date(2019, 2, 14, 13, 37, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	specs := []struct {
+		descr string
+		req   FacetRequest
+		exp   []DateRange
+	}{
+		{
+			descr: "days",
+			req:   FacetRequest{Interval: DateIntervalDay, Size: 2},
+			exp: []DateRange{
+				{Key: "2019-02-13", From: day(2019, 2, 13), To: day(2019, 2, 14)},
+				{Key: "2019-02-14", From: day(2019, 2, 14), To: day(2019, 2, 15)},
+			},
+		},
+		{
+			descr: "months across a year boundary",
+			req:   FacetRequest{Interval: DateIntervalMonth, Size: 3},
+			exp: []DateRange{
+				{Key: "2018-12", From: day(2018, 12, 1), To: day(2019, 1, 1)},
+				{Key: "2019-01", From: day(2019, 1, 1), To: day(2019, 2, 1)},
+				{Key: "2019-02", From: day(2019, 2, 1), To: day(2019, 3, 1)},
+			},
+		},
+		{
+			descr: "years",
+			req:   FacetRequest{Interval: DateIntervalYear, Size: 1},
+			exp: []DateRange{
+				{Key: "2019", From: day(2019, 1, 1), To: day(2020, 1, 1)},
+			},
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		c.Assert(spec.req.DateRanges(now), gc.DeepEquals, spec.exp)
+	}
+
+	c.Assert(FacetRequest{}.DateRanges(now), gc.HasLen, DefaultFacetSize)
+}
+
+func (s *FacetTestSuite) TestNumericRanges(c *gc.C) {
+	req := FacetRequest{Ranges: []float64{0.25, 0.5}}
+	got := req.NumericRanges()
+	c.Assert(got, gc.HasLen, 3)
+
+	expKeys := []string{"..0.25", "0.25..0.5", "0.5.."}
+	expBounds := [][2]interface{}{{nil, 0.25}, {0.25, 0.5}, {0.5, nil}}
+	for i, r := range got {
+		c.Assert(r.Key, gc.Equals, expKeys[i])
+		for j, bound := range []*float64{r.From, r.To} {
+			if expBounds[i][j] == nil {
+				c.Assert(bound, gc.IsNil)
+			} else {
+				c.Assert(*bound, gc.Equals, expBounds[i][j])
+			}
+		}
+	}
+
+	c.Assert(FacetRequest{}.NumericRanges(), gc.IsNil)
+}
@@ -1,6 +1,10 @@
 package index
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Indexer is implemented by objects that can index and search documents
 // discovered by the Links 'R' Us crawler.
@@ -20,6 +24,38 @@ type Indexer interface {
 	// specified link ID. If no such document exists, a placeholder
 	// document with the provided score will be created.
 	UpdateScore(linkID uuid.UUID, score float64) error
+
+	// AddAnchorText records the text of a link pointing to the document
+	// with the specified link ID. If no such document exists, a placeholder
+	// document with the provided anchor text will be created. Duplicate
+	// texts are ignored and at most MaxAnchorTexts texts are retained for
+	// each document.
+	AddAnchorText(linkID uuid.UUID, text string) error
+
+	// AddAnchorTexts works like AddAnchorText but records the texts of
+	// links pointing to several documents, keyed by their link IDs, in a
+	// single operation.
+	AddAnchorTexts(texts map[uuid.UUID][]string) error
+
+	// Delete removes the document with the specified link ID from the
+	// index. Deleting a document that does not exist is not an error.
+	Delete(linkID uuid.UUID) error
+
+	// DeleteOlderThan removes all documents that were last indexed before
+	// the specified timestamp. Placeholder documents created by UpdateScore
+	// for links that have not been indexed yet are retained.
+	DeleteOlderThan(t time.Time) error
+
+	// Suggest returns up to MaxSuggestions alternative spellings for the
+	// terms of a search expression that are not present in the index. The
+	// suggestions are ordered by their edit distance from expression.
+	Suggest(expression string) ([]string, error)
+
+	// Complete returns up to MaxCompletions completions for a partially
+	// typed search expression. Completions either replace the last word of
+	// expression with an indexed term that starts with it or consist of the
+	// title of a matching document.
+	Complete(expression string) ([]string, error)
 }
 
 // Iterator is implemented by objects that can paginate search results.
@@ -39,6 +75,15 @@ type Iterator interface {
 
 	// TotalCount returns the approximate number of search results.
 	TotalCount() uint64
+
+	// Facets returns the bucket counts for the facets requested by the
+	// search query.
+	Facets() []FacetResult
+
+	// Cursor returns the opaque cursor for the current document. Searches
+	// can be resumed from the current document by specifying the cursor
+	// in the Cursor field of a Query.
+	Cursor() string
 }
 
 // QueryType describes the types of queries supported by the indexer
@@ -62,6 +107,39 @@ type Query struct {
 	// The search expression.
 	Expression string
 
-	// The number of search results to skip.
+	// An optional structured query tree. If specified, the indexer
+	// ignores the Type and Expression fields.
+	Tree QueryNode
+
+	// The number of search results to skip. Offset is ignored if a Cursor
+	// is specified.
 	Offset uint64
+
+	// An optional cursor obtained via the Cursor method of an Iterator. If
+	// specified, the search results start with the document following the
+	// one that the cursor points to.
+	Cursor string
+
+	// If set together with Cursor, the search yields the documents that
+	// precede the one that the cursor points to, in reverse ranked order.
+	Backward bool
+
+	// An optional list of facets to calculate over the matching documents.
+	Facets []FacetRequest
+
+	// An optional ranking model for ordering the search results. If not
+	// specified, DefaultRanking is used.
+	Ranking *Ranking
+
+	// An optional language preference for filtering or boosting documents
+	// based on their language.
+	Language *LanguagePreference
+}
+
+// RankingModel returns the ranking model for ordering the results of q.
+func (q Query) RankingModel() Ranking {
+	if q.Ranking == nil {
+		return DefaultRanking
+	}
+	return *q.Ranking
 }
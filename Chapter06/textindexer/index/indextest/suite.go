@@ -2,6 +2,7 @@ package indextest
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
@@ -228,6 +229,111 @@ func (s *SuiteBase) TestMatchSearchWithOffset(c *gc.C) {
 	c.Assert(iterateDocs(c, it), gc.HasLen, 0)
 }
 
+// TestMatchSearchWithCursor verifies the document search logic when resuming
+// searches from a cursor.
+func (s *SuiteBase) TestMatchSearchWithCursor(c *gc.C) {
+	var (
+		numDocs = 50
+		expIDs  []uuid.UUID
+	)
+	for i := 0; i < numDocs; i++ {
+		id := uuid.New()
+		expIDs = append(expIDs, id)
+		doc := &index.Document{
+			LinkID:  id,
+			Title:   fmt.Sprintf("doc with ID %s", id.String()),
+			Content: "Ovidius poeta in terra pontica",
+		}
+
+		err := s.idx.Index(doc)
+		c.Assert(err, gc.IsNil)
+
+		err = s.idx.UpdateScore(id, float64(numDocs-i))
+		c.Assert(err, gc.IsNil)
+	}
+
+	// Obtain the cursor for the 20th result.
+	it, err := s.idx.Search(index.Query{Expression: "poeta", Offset: 19})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Document().LinkID, gc.Equals, expIDs[19])
+	cursor := it.Cursor()
+	c.Assert(it.Close(), gc.IsNil)
+
+	parsed, err := index.ParseCursor(cursor)
+	c.Assert(err, gc.IsNil)
+	c.Assert(parsed.Position, gc.Equals, uint64(19))
+	c.Assert(parsed.LinkID, gc.Equals, expIDs[19])
+
+	// The offset is ignored when a cursor is specified.
+	it, err = s.idx.Search(index.Query{Expression: "poeta", Cursor: cursor, Offset: 42})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, expIDs[20:])
+
+	// Iterate backwards from the cursor position.
+	it, err = s.idx.Search(index.Query{Expression: "poeta", Cursor: cursor, Backward: true})
+	c.Assert(err, gc.IsNil)
+	var gotIDs []uuid.UUID
+	for it.Next() {
+		gotIDs = append(gotIDs, it.Document().LinkID)
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+	c.Assert(gotIDs, gc.DeepEquals, reverse(append([]uuid.UUID(nil), expIDs[:19]...)))
+
+	// Search with a cursor that cannot be decoded.
+	_, err = s.idx.Search(index.Query{Expression: "poeta", Cursor: "bogus"})
+	c.Assert(xerrors.Is(err, index.ErrInvalidCursor), gc.Equals, true)
+}
+
+// TestMatchSearchWithCursorFreshnessDecay verifies that searches resumed from
+// a cursor return the correct results when the freshness of the matching
+// documents has decayed since the cursor was obtained.
+func (s *SuiteBase) TestMatchSearchWithCursorFreshnessDecay(c *gc.C) {
+	var (
+		numDocs = 20
+		expIDs  []uuid.UUID
+	)
+	for i := 0; i < numDocs; i++ {
+		id := uuid.New()
+		expIDs = append(expIDs, id)
+		doc := &index.Document{
+			LinkID:  id,
+			Title:   fmt.Sprintf("doc with ID %s", id.String()),
+			Content: "Ovidius poeta in terra pontica",
+		}
+
+		err := s.idx.Index(doc)
+		c.Assert(err, gc.IsNil)
+
+		err = s.idx.UpdateScore(id, float64(numDocs-i))
+		c.Assert(err, gc.IsNil)
+	}
+
+	// Use a half-life long enough for the ranking order to remain stable;
+	// any amount of decay changes the scores of the results.
+	ranking := &index.Ranking{PageRankWeight: 1, FreshnessHalfLife: time.Hour}
+
+	// Obtain the cursor for the 10th result.
+	it, err := s.idx.Search(index.Query{Expression: "poeta", Ranking: ranking, Offset: 9})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Document().LinkID, gc.Equals, expIDs[9])
+	cursor := it.Cursor()
+	c.Assert(it.Close(), gc.IsNil)
+
+	// Allow the scores of the documents to decay before resuming the search.
+	time.Sleep(100 * time.Millisecond)
+
+	it, err = s.idx.Search(index.Query{Expression: "poeta", Ranking: ranking, Cursor: cursor})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, expIDs[10:])
+
+	it, err = s.idx.Search(index.Query{Expression: "poeta", Ranking: ranking, Cursor: cursor, Backward: true})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, reverse(append([]uuid.UUID(nil), expIDs[:9]...)))
+}
+
 // TestUpdateScore checks that PageRank score updates work as expected.
 func (s *SuiteBase) TestUpdateScore(c *gc.C) {
 	var (
@@ -289,6 +395,513 @@ func (s *SuiteBase) TestUpdateScoreForUnknownDocument(c *gc.C) {
 	c.Assert(doc.PageRank, gc.Equals, 0.5)
 }
 
+// TestAddAnchorText verifies that anchor texts are recorded for both known and
+// unknown documents and that they are preserved when documents are
+// re-indexed.
+func (s *SuiteBase) TestAddAnchorText(c *gc.C) {
+	linkID := uuid.New()
+	c.Assert(s.idx.AddAnchorText(linkID, "Ovidius poeta"), gc.IsNil)
+	c.Assert(s.idx.AddAnchorText(linkID, "ovidius POETA"), gc.IsNil)
+	c.Assert(s.idx.AddAnchorText(linkID, "   "), gc.IsNil)
+
+	doc, err := s.idx.FindByID(linkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(doc.AnchorText, gc.DeepEquals, []string{"Ovidius poeta"})
+	c.Assert(doc.IndexedAt.IsZero(), gc.Equals, true)
+
+	// Placeholder documents must not be returned by searches.
+	it, err := s.idx.Search(index.Query{Expression: "poeta"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.HasLen, 0)
+
+	err = s.idx.Index(&index.Document{
+		LinkID:  linkID,
+		URL:     "http://example.com",
+		Title:   "Tristia",
+		Content: "Lorem ipsum dolor",
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.idx.AddAnchorText(linkID, "terra pontica"), gc.IsNil)
+
+	doc, err = s.idx.FindByID(linkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(doc.Title, gc.Equals, "Tristia")
+	c.Assert(doc.AnchorText, gc.DeepEquals, []string{"Ovidius poeta", "terra pontica"})
+
+	it, err = s.idx.Search(index.Query{Expression: "poeta"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, []uuid.UUID{linkID})
+}
+
+// TestAddAnchorTexts verifies that anchor texts for several documents can be
+// recorded with a single call.
+func (s *SuiteBase) TestAddAnchorTexts(c *gc.C) {
+	known := &index.Document{
+		LinkID:  uuid.New(),
+		URL:     "http://example.com",
+		Title:   "Tristia",
+		Content: "Lorem ipsum dolor",
+	}
+	c.Assert(s.idx.Index(known), gc.IsNil)
+	c.Assert(s.idx.AddAnchorText(known.LinkID, "Ovidius poeta"), gc.IsNil)
+
+	unknownID := uuid.New()
+	err := s.idx.AddAnchorTexts(map[uuid.UUID][]string{
+		known.LinkID: {"ovidius POETA", "terra pontica"},
+		unknownID:    {"Epistulae ex Ponto", "   ", "epistulae ex ponto"},
+	})
+	c.Assert(err, gc.IsNil)
+
+	doc, err := s.idx.FindByID(known.LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(doc.Title, gc.Equals, "Tristia")
+	c.Assert(doc.AnchorText, gc.DeepEquals, []string{"Ovidius poeta", "terra pontica"})
+
+	doc, err = s.idx.FindByID(unknownID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(doc.AnchorText, gc.DeepEquals, []string{"Epistulae ex Ponto"})
+	c.Assert(doc.IndexedAt.IsZero(), gc.Equals, true)
+
+	// Recording anchor texts that are already known is a no-op.
+	c.Assert(s.idx.AddAnchorTexts(map[uuid.UUID][]string{unknownID: {"EPISTULAE EX PONTO"}}), gc.IsNil)
+	c.Assert(s.idx.AddAnchorTexts(nil), gc.IsNil)
+
+	doc, err = s.idx.FindByID(unknownID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(doc.AnchorText, gc.DeepEquals, []string{"Epistulae ex Ponto"})
+}
+
+// TestMultiFieldSearch verifies that the description, headings and anchor
+// text of documents are matched by search queries and that matches in these
+// fields are boosted relative to content matches.
+func (s *SuiteBase) TestMultiFieldSearch(c *gc.C) {
+	docs := []*index.Document{
+		{Title: "Doc 1", Content: "Lorem ipsum dolor sit amet"},
+		{Title: "Doc 2", Content: "Lorem ipsum", Description: "Dolor sit amet"},
+		{Title: "Doc 3", Content: "Lorem ipsum", Headings: []string{"Consectetur", "Dolor sit amet"}},
+		{Title: "Doc 4", Content: "Lorem ipsum"},
+	}
+	for _, doc := range docs {
+		doc.LinkID = uuid.New()
+		c.Assert(s.idx.Index(doc), gc.IsNil)
+	}
+	c.Assert(s.idx.AddAnchorText(docs[3].LinkID, "Dolor sit amet"), gc.IsNil)
+
+	got, err := s.idx.FindByID(docs[2].LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.Headings, gc.DeepEquals, docs[2].Headings)
+	got, err = s.idx.FindByID(docs[1].LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.Description, gc.Equals, docs[1].Description)
+
+	it, err := s.idx.Search(index.Query{Expression: "consectetur"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, []uuid.UUID{docs[2].LinkID})
+
+	// All documents match; the one matching only by content must be ranked
+	// last.
+	it, err = s.idx.Search(index.Query{Expression: "dolor"})
+	c.Assert(err, gc.IsNil)
+	ids := iterateDocs(c, it)
+	c.Assert(ids, gc.HasLen, len(docs))
+	c.Assert(ids[len(ids)-1], gc.Equals, docs[0].LinkID)
+}
+
+// TestDelete verifies that documents can be removed from the index.
+func (s *SuiteBase) TestDelete(c *gc.C) {
+	ids := s.indexQueryTreeDocs(c)
+
+	err := s.idx.Delete(ids[1])
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.idx.FindByID(ids[1])
+	c.Assert(xerrors.Is(err, index.ErrNotFound), gc.Equals, true)
+	s.assertQueryTreeResults(c, "lorem", ids[0], ids[2])
+
+	// Deleting an unknown document is not an error.
+	err = s.idx.Delete(uuid.New())
+	c.Assert(err, gc.IsNil)
+}
+
+// TestDeleteOlderThan verifies that stale documents can be removed from the
+// index while placeholder documents for links that have not been indexed
+// yet are retained.
+func (s *SuiteBase) TestDeleteOlderThan(c *gc.C) {
+	placeholderID := uuid.New()
+	err := s.idx.UpdateScore(placeholderID, 0.5)
+	c.Assert(err, gc.IsNil)
+
+	var docs [2]*index.Document
+	for i := range docs {
+		docs[i] = &index.Document{
+			LinkID:    uuid.New(),
+			URL:       fmt.Sprintf("http://example.com/%d", i),
+			Content:   "Lorem ipsum dolor",
+			IndexedAt: time.Now().UTC(),
+		}
+		err = s.idx.Index(docs[i])
+		c.Assert(err, gc.IsNil)
+
+		// Ensure that the documents get distinct IndexedAt values.
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// Delete the documents indexed before the midpoint of the two
+	// indexing timestamps.
+	cutoff := docs[0].IndexedAt.Add(docs[1].IndexedAt.Sub(docs[0].IndexedAt) / 2)
+	err = s.idx.DeleteOlderThan(cutoff)
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.idx.FindByID(docs[0].LinkID)
+	c.Assert(xerrors.Is(err, index.ErrNotFound), gc.Equals, true)
+	_, err = s.idx.FindByID(docs[1].LinkID)
+	c.Assert(err, gc.IsNil)
+	_, err = s.idx.FindByID(placeholderID)
+	c.Assert(err, gc.IsNil)
+}
+
+// TestBooleanSearch verifies the handling of required, excluded and
+// alternative terms in structured queries.
+func (s *SuiteBase) TestBooleanSearch(c *gc.C) {
+	ids := s.indexQueryTreeDocs(c)
+	s.assertQueryTreeResults(c, "+lorem -ipsum", ids[1])
+	s.assertQueryTreeResults(c, "lorem AND dolor", ids[0], ids[1], ids[2])
+	s.assertQueryTreeResults(c, "adipiscing OR amet", ids[0], ids[2], ids[3])
+	s.assertQueryTreeResults(c, "NOT lorem", ids[3])
+	s.assertQueryTreeResults(c, "(sit OR elit) AND NOT amet", ids[1], ids[3])
+}
+
+// TestTitleSearch verifies that structured queries can match terms and
+// phrases against document titles.
+func (s *SuiteBase) TestTitleSearch(c *gc.C) {
+	ids := s.indexQueryTreeDocs(c)
+	s.assertQueryTreeResults(c, "intitle:ipsum", ids[0])
+	s.assertQueryTreeResults(c, `intitle:"dolor sit"`, ids[1])
+	s.assertQueryTreeResults(c, `intitle:"sit dolor"`)
+}
+
+// TestSiteSearch verifies that structured queries can restrict results to
+// documents from a particular host and its sub-domains.
+func (s *SuiteBase) TestSiteSearch(c *gc.C) {
+	ids := s.indexQueryTreeDocs(c)
+	s.assertQueryTreeResults(c, "site:example.com", ids[0], ids[1])
+	s.assertQueryTreeResults(c, "site:blog.example.com", ids[1])
+	s.assertQueryTreeResults(c, "+lorem -site:example.com", ids[2])
+}
+
+// TestDateRangeSearch verifies that structured queries can restrict results
+// to documents indexed within a particular time range.
+func (s *SuiteBase) TestDateRangeSearch(c *gc.C) {
+	ids := s.indexQueryTreeDocs(c)
+	yesterday := time.Now().Add(-24 * time.Hour).UTC().Format("2006-01-02")
+	tomorrow := time.Now().Add(48 * time.Hour).UTC().Format("2006-01-02")
+
+	s.assertQueryTreeResults(c, "+lorem +after:"+yesterday, ids[0], ids[1], ids[2])
+	s.assertQueryTreeResults(c, "+lorem +before:"+yesterday)
+	s.assertQueryTreeResults(c, "indexed:"+yesterday+".."+tomorrow, ids...)
+	s.assertQueryTreeResults(c, "indexed:"+tomorrow+"..")
+}
+
+// TestSuggest verifies that spelling suggestions are built from the indexed
+// terms.
+func (s *SuiteBase) TestSuggest(c *gc.C) {
+	docs := []*index.Document{
+		{Title: "Lorem ipsum", Content: "dolor sit amet consectetur"},
+		{Title: "Dolor", Content: "lorem adipiscing elit"},
+	}
+	for _, doc := range docs {
+		doc.LinkID = uuid.New()
+		c.Assert(s.idx.Index(doc), gc.IsNil)
+	}
+
+	specs := []struct {
+		expr     string
+		expFirst string
+	}{
+		{expr: "lorm ipsun", expFirst: "lorem ipsum"},
+		{expr: `+adipiscing -"elot"`, expFirst: `+adipiscing -"elit"`},
+		{expr: "intitle:Dolr", expFirst: "intitle:dolor"},
+	}
+	for _, spec := range specs {
+		got, err := s.idx.Suggest(spec.expr)
+		c.Assert(err, gc.IsNil)
+		c.Assert(len(got) > 0, gc.Equals, true, gc.Commentf("expected suggestions for %q", spec.expr))
+		c.Assert(got[0], gc.Equals, spec.expFirst)
+	}
+
+	// Expressions whose terms are all present in the index should not
+	// yield any suggestions.
+	got, err := s.idx.Suggest("Lorem ipsum AND dolor")
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.HasLen, 0)
+}
+
+// TestComplete verifies the completions for partially typed search
+// expressions.
+func (s *SuiteBase) TestComplete(c *gc.C) {
+	docs := []*index.Document{
+		{Title: "Lorem ipsum", Content: "dolor sit amet"},
+		{Title: "Lorem dolor", Content: "ipsum ipsam adipiscing"},
+	}
+	for _, doc := range docs {
+		doc.LinkID = uuid.New()
+		c.Assert(s.idx.Index(doc), gc.IsNil)
+	}
+
+	// Term completions are ordered by the number of documents containing
+	// each term and are followed by the titles of matching documents.
+	got, err := s.idx.Complete("lorem ips")
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.DeepEquals, []string{"lorem ipsum", "lorem ipsam"})
+
+	got, err = s.idx.Complete("Lor")
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.HasLen, 3)
+	c.Assert(got[0], gc.Equals, "lorem")
+	sort.Strings(got[1:])
+	c.Assert(got[1:], gc.DeepEquals, []string{"Lorem dolor", "Lorem ipsum"})
+
+	// Once the last word has been typed, only titles are returned.
+	got, err = s.idx.Complete("dolor ")
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.DeepEquals, []string{"Lorem dolor"})
+
+	got, err = s.idx.Complete("xyz")
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.HasLen, 0)
+}
+
+// TestRanking verifies that search results are ordered according to the
+// ranking model of the query.
+func (s *SuiteBase) TestRanking(c *gc.C) {
+	docs := []*index.Document{
+		{URL: "http://example.com/a", Title: "Lorem", Content: "dolor sit amet"},
+		{URL: "http://example.com/b", Title: "Dolor", Content: "lorem sit amet"},
+	}
+	for i, doc := range docs {
+		doc.LinkID = uuid.New()
+		c.Assert(s.idx.Index(doc), gc.IsNil)
+		c.Assert(s.idx.UpdateScore(doc.LinkID, float64(i+1)), gc.IsNil)
+	}
+
+	// Index two documents with identical content that were indexed at
+	// different times. The older document has a higher PageRank score.
+	var freshDocs [2]*index.Document
+	for i := range freshDocs {
+		freshDocs[i] = &index.Document{
+			LinkID:    uuid.New(),
+			URL:       fmt.Sprintf("http://example.com/fresh/%d", i),
+			Content:   "consectetur adipiscing elit",
+			IndexedAt: time.Now().UTC(),
+		}
+		c.Assert(s.idx.Index(freshDocs[i]), gc.IsNil)
+		c.Assert(s.idx.UpdateScore(freshDocs[i].LinkID, 1/float64(i+1)), gc.IsNil)
+
+		if i == 0 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	specs := []struct {
+		descr   string
+		expr    string
+		ranking index.Ranking
+		exp     []uuid.UUID
+	}{
+		{
+			descr:   "PageRank only",
+			expr:    "lorem",
+			ranking: index.Ranking{PageRankWeight: 1},
+			exp:     []uuid.UUID{docs[1].LinkID, docs[0].LinkID},
+		},
+		{
+			descr:   "title boost",
+			expr:    "lorem",
+			ranking: index.Ranking{RelevanceWeight: 1, TitleBoost: 10},
+			exp:     []uuid.UUID{docs[0].LinkID, docs[1].LinkID},
+		},
+		{
+			descr:   "no freshness decay",
+			expr:    "consectetur",
+			ranking: index.Ranking{RelevanceWeight: 1, PageRankWeight: 1},
+			exp:     []uuid.UUID{freshDocs[0].LinkID, freshDocs[1].LinkID},
+		},
+		{
+			descr:   "freshness decay",
+			expr:    "consectetur",
+			ranking: index.Ranking{RelevanceWeight: 1, PageRankWeight: 1, FreshnessHalfLife: 100 * time.Millisecond},
+			exp:     []uuid.UUID{freshDocs[1].LinkID, freshDocs[0].LinkID},
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		ranking := spec.ranking
+		it, err := s.idx.Search(index.Query{Expression: spec.expr, Ranking: &ranking})
+		c.Assert(err, gc.IsNil)
+		c.Assert(iterateDocs(c, it), gc.DeepEquals, spec.exp)
+	}
+}
+
+// TestLanguage verifies that documents are analyzed using the analyzer for
+// their language and that search results can be filtered or boosted by
+// language.
+func (s *SuiteBase) TestLanguage(c *gc.C) {
+	docs := []*index.Document{
+		{Title: "Berlin", Content: "Die Häuser der Stadt sind alt", Language: "de"},
+		{Title: "Berlin", Content: "The houses of the city are old", Language: "en"},
+		{Title: "Berlin", Content: "Lorem ipsum dolor sit amet"},
+	}
+	for _, doc := range docs {
+		doc.LinkID = uuid.New()
+		c.Assert(s.idx.Index(doc), gc.IsNil)
+	}
+
+	got, err := s.idx.FindByID(docs[0].LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.Language, gc.Equals, "de")
+
+	specs := []struct {
+		descr string
+		expr  string
+		lang  *index.LanguagePreference
+		exp   []uuid.UUID
+	}{
+		{
+			descr: "German stemming",
+			expr:  "Haus",
+			exp:   []uuid.UUID{docs[0].LinkID},
+		},
+		{
+			descr: "English stemming",
+			expr:  "house",
+			exp:   []uuid.UUID{docs[1].LinkID},
+		},
+		{
+			descr: "language filter",
+			expr:  "berlin",
+			lang:  &index.LanguagePreference{Language: "en"},
+			exp:   []uuid.UUID{docs[1].LinkID},
+		},
+		{
+			descr: "language boost",
+			expr:  "berlin",
+			lang:  &index.LanguagePreference{Language: "de", Boost: 10},
+			exp:   []uuid.UUID{docs[0].LinkID},
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		it, err := s.idx.Search(index.Query{Expression: spec.expr, Language: spec.lang})
+		c.Assert(err, gc.IsNil)
+		got := iterateDocs(c, it)
+		if spec.lang != nil && !spec.lang.IsFilter() {
+			// Boosting does not exclude documents in other languages.
+			c.Assert(got, gc.HasLen, len(docs))
+			got = got[:1]
+		}
+		c.Assert(got, gc.DeepEquals, spec.exp)
+	}
+}
+
+// TestFacets verifies the calculation of facets over the documents that
+// match a search query.
+func (s *SuiteBase) TestFacets(c *gc.C) {
+	docs := []struct {
+		url      string
+		content  string
+		pageRank float64
+	}{
+		{"http://a.example.com/1", "lorem ipsum", 0.9},
+		{"http://c.example.com/1", "dolor sit", 0.7},
+		{"http://a.example.com/2", "lorem dolor", 0.5},
+		{"https://b.example.com", "lorem sit", 0.2},
+		{"http://A.Example.com:8080/3", "lorem amet", 0.05},
+	}
+	for _, d := range docs {
+		linkID := uuid.New()
+		err := s.idx.Index(&index.Document{LinkID: linkID, URL: d.url, Content: d.content})
+		c.Assert(err, gc.IsNil)
+		err = s.idx.UpdateScore(linkID, d.pageRank)
+		c.Assert(err, gc.IsNil)
+	}
+
+	facetReqs := []index.FacetRequest{
+		{Name: "sites", Type: index.FacetTypeHost},
+		{Name: "months", Type: index.FacetTypeIndexedAt, Interval: index.DateIntervalMonth, Size: 2},
+		{Name: "rank", Type: index.FacetTypePageRank, Ranges: []float64{0.1, 0.5}},
+	}
+	it, err := s.idx.Search(index.Query{
+		Type:       index.QueryTypeMatch,
+		Expression: "lorem",
+		Facets:     facetReqs,
+	})
+	c.Assert(err, gc.IsNil)
+
+	monthRanges := facetReqs[1].DateRanges(time.Now())
+	c.Assert(it.Facets(), gc.DeepEquals, []index.FacetResult{
+		{
+			Name: "sites",
+			Buckets: []index.FacetBucket{
+				{Key: "a.example.com", Count: 3},
+				{Key: "b.example.com", Count: 1},
+			},
+		},
+		{
+			Name: "months",
+			Buckets: []index.FacetBucket{
+				{Key: monthRanges[0].Key, Count: 0},
+				{Key: monthRanges[1].Key, Count: 4},
+			},
+		},
+		{
+			Name: "rank",
+			Buckets: []index.FacetBucket{
+				{Key: "..0.1", Count: 1},
+				{Key: "0.1..0.5", Count: 1},
+				{Key: "0.5..", Count: 2},
+			},
+		},
+	})
+	c.Assert(iterateDocs(c, it), gc.HasLen, 4)
+}
+
+// indexQueryTreeDocs populates the index with a set of documents for
+// testing structured queries. The returned IDs are sorted by PageRank in
+// descending order.
+func (s *SuiteBase) indexQueryTreeDocs(c *gc.C) []uuid.UUID {
+	docs := []*index.Document{
+		{URL: "http://example.com/a", Title: "Lorem ipsum", Content: "dolor sit amet"},
+		{URL: "https://blog.example.com:8443/b", Title: "Dolor sit", Content: "lorem consectetur"},
+		{URL: "http://other.org/example.com", Title: "Amet", Content: "lorem ipsum dolor"},
+		{URL: "https://example.com.other.org?a=b", Title: "Consectetur", Content: "adipiscing elit"},
+	}
+
+	var ids []uuid.UUID
+	for i, doc := range docs {
+		doc.LinkID = uuid.New()
+		ids = append(ids, doc.LinkID)
+
+		err := s.idx.Index(doc)
+		c.Assert(err, gc.IsNil)
+
+		err = s.idx.UpdateScore(doc.LinkID, float64(len(docs)-i))
+		c.Assert(err, gc.IsNil)
+	}
+
+	return ids
+}
+
+func (s *SuiteBase) assertQueryTreeResults(c *gc.C, expr string, expIDs ...uuid.UUID) {
+	tree, err := index.ParseQuery(expr)
+	c.Assert(err, gc.IsNil, gc.Commentf("query %q", expr))
+
+	it, err := s.idx.Search(index.Query{Tree: tree})
+	c.Assert(err, gc.IsNil, gc.Commentf("query %q", expr))
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, expIDs, gc.Commentf("query %q", expr))
+}
+
 func iterateDocs(c *gc.C, it index.Iterator) []uuid.UUID {
 	var seen []uuid.UUID
 	for it.Next() {
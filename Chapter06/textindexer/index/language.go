@@ -0,0 +1,45 @@
+package index
+
+// SupportedLanguages lists the ISO 639-1 codes of the languages for which
+// the indexer implementations apply language-specific text analysis such as
+// stemming and stop word removal.
+var SupportedLanguages = []string{"de", "en", "es", "fr", "it", "nl", "pt"}
+
+// IsSupportedLanguage returns true if lang is one of the SupportedLanguages.
+func IsSupportedLanguage(lang string) bool {
+	for _, supported := range SupportedLanguages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// LanguagePreference describes how the language of documents affects the
+// results of a search query.
+type LanguagePreference struct {
+	// The ISO 639-1 code of the preferred language.
+	Language string
+
+	// A multiplier for the relevance score of documents in the preferred
+	// language. Values less than or equal to 1 exclude documents in other
+	// languages from the search results.
+	Boost float64
+}
+
+// IsFilter returns true if documents in languages other than the preferred
+// one should be excluded from the search results.
+func (p LanguagePreference) IsFilter() bool {
+	return p.Boost <= 1
+}
+
+// SearchLanguages returns the languages whose language-specific analysis
+// should be used for matching the terms of a search query with the language
+// preference p. If p is nil or refers to a language that is not supported,
+// all SupportedLanguages are returned.
+func (p *LanguagePreference) SearchLanguages() []string {
+	if p == nil || !IsSupportedLanguage(p.Language) {
+		return SupportedLanguages
+	}
+	return []string{p.Language}
+}
@@ -0,0 +1,22 @@
+package index
+
+import (
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(LanguageTestSuite))
+
+type LanguageTestSuite struct{}
+
+func (s *LanguageTestSuite) TestSearchLanguages(c *gc.C) {
+	var pref *LanguagePreference
+	c.Assert(pref.SearchLanguages(), gc.DeepEquals, SupportedLanguages)
+
+	pref = &LanguagePreference{Language: "de"}
+	c.Assert(pref.SearchLanguages(), gc.DeepEquals, []string{"de"})
+	c.Assert(pref.IsFilter(), gc.Equals, true)
+
+	pref = &LanguagePreference{Language: "ja", Boost: 2}
+	c.Assert(pref.SearchLanguages(), gc.DeepEquals, SupportedLanguages)
+	c.Assert(pref.IsFilter(), gc.Equals, false)
+}
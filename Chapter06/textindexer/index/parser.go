@@ -0,0 +1,369 @@
+package index
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/xerrors"
+)
+
+// ParseQuery converts a search expression into a structured query tree.
+//
+// The supported syntax is modelled after the one offered by popular web
+// search engines:
+//   - Whitespace-separated terms match documents containing any of the terms.
+//   - "quoted text" matches an exact phrase.
+//   - A leading '+' marks a term as required; a leading '-' or the NOT
+//     keyword marks it as excluded.
+//   - The AND and OR keywords combine expressions; AND binds tighter than
+//     OR. Parentheses can be used for grouping.
+//   - site:example.com restricts results to a host and its sub-domains.
+//   - intitle:term or intitle:"some phrase" matches against document titles.
+//   - after:DATE, before:DATE and indexed:FROM..TO restrict results to
+//     documents indexed within a time range. Dates use the YYYY-MM-DD or the
+//     RFC3339 format; after and FROM are inclusive while before and TO are
+//     exclusive.
+//
+// Unknown field prefixes (e.g. foo:bar) are treated as plain terms.
+func ParseQuery(expr string) (QueryNode, error) {
+	toks, err := lexQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{toks: toks}
+	if p.peek().typ == tokenEOF {
+		return nil, xerrors.Errorf("empty query: %w", ErrInvalidQuery)
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != tokenEOF {
+		return nil, xerrors.Errorf("unexpected %q at offset %d: %w", tok.text, tok.pos, ErrInvalidQuery)
+	}
+	return node, nil
+}
+
+type tokenType uint8
+
+const (
+	tokenEOF tokenType = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenRequire
+	tokenExclude
+	tokenWord
+	tokenPhrase
+)
+
+type token struct {
+	typ tokenType
+	pos int
+
+	// The field prefix (if any) for word and phrase tokens.
+	field string
+	text  string
+}
+
+// The set of field prefixes recognized by the lexer.
+var queryFields = map[string]bool{
+	"site":    true,
+	"intitle": true,
+	"after":   true,
+	"before":  true,
+	"indexed": true,
+}
+
+// lexQuery splits a search expression into a list of tokens.
+func lexQuery(expr string) ([]token, error) {
+	var (
+		toks []token
+		rs   = []rune(expr)
+	)
+
+	for pos := 0; pos < len(rs); {
+		r := rs[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case r == '(':
+			toks = append(toks, token{typ: tokenLParen, pos: pos, text: "("})
+			pos++
+		case r == ')':
+			toks = append(toks, token{typ: tokenRParen, pos: pos, text: ")"})
+			pos++
+		case (r == '+' || r == '-') && pos+1 < len(rs) && !unicode.IsSpace(rs[pos+1]):
+			typ := tokenRequire
+			if r == '-' {
+				typ = tokenExclude
+			}
+			toks = append(toks, token{typ: typ, pos: pos, text: string(r)})
+			pos++
+		case r == '"':
+			phrase, next, err := lexPhrase(rs, pos)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, token{typ: tokenPhrase, pos: pos, text: phrase})
+			pos = next
+		default:
+			start := pos
+			for pos < len(rs) && !unicode.IsSpace(rs[pos]) && rs[pos] != '(' && rs[pos] != ')' && rs[pos] != '"' {
+				pos++
+			}
+			word := string(rs[start:pos])
+
+			switch word {
+			case "AND":
+				toks = append(toks, token{typ: tokenAnd, pos: start, text: word})
+				continue
+			case "OR":
+				toks = append(toks, token{typ: tokenOr, pos: start, text: word})
+				continue
+			case "NOT":
+				toks = append(toks, token{typ: tokenNot, pos: start, text: word})
+				continue
+			}
+
+			tok := token{typ: tokenWord, pos: start, text: word}
+			if sepIndex := strings.IndexRune(word, ':'); sepIndex > 0 && queryFields[strings.ToLower(word[:sepIndex])] {
+				tok.field, tok.text = strings.ToLower(word[:sepIndex]), word[sepIndex+1:]
+
+				// Support quoted field values (e.g. intitle:"foo bar").
+				if tok.text == "" && pos < len(rs) && rs[pos] == '"' {
+					phrase, next, err := lexPhrase(rs, pos)
+					if err != nil {
+						return nil, err
+					}
+					tok.typ, tok.text = tokenPhrase, phrase
+					pos = next
+				}
+			}
+			toks = append(toks, tok)
+		}
+	}
+
+	return append(toks, token{typ: tokenEOF, pos: len(rs)}), nil
+}
+
+// lexPhrase extracts the quoted phrase that starts at offset pos and returns
+// it together with the offset of the first rune after the closing quote.
+func lexPhrase(rs []rune, pos int) (string, int, error) {
+	for end := pos + 1; end < len(rs); end++ {
+		if rs[end] == '"' {
+			return string(rs[pos+1 : end]), end + 1, nil
+		}
+	}
+	return "", 0, xerrors.Errorf("unterminated phrase at offset %d: %w", pos, ErrInvalidQuery)
+}
+
+type queryParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *queryParser) peek() token { return p.toks[p.pos] }
+
+func (p *queryParser) next() token {
+	tok := p.toks[p.pos]
+	if tok.typ != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseOr parses: andExpr { OR andExpr }
+func (p *queryParser) parseOr() (QueryNode, error) {
+	var operands []QueryNode
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		// Flatten nested disjunctions.
+		if b, ok := node.(*BoolNode); ok && len(b.Must) == 0 && len(b.MustNot) == 0 {
+			operands = append(operands, b.Should...)
+		} else {
+			operands = append(operands, node)
+		}
+
+		if p.peek().typ != tokenOr {
+			break
+		}
+		p.next()
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &BoolNode{Should: operands}, nil
+}
+
+// parseAnd parses: sequence { AND sequence }
+func (p *queryParser) parseAnd() (QueryNode, error) {
+	var (
+		res      BoolNode
+		operands int
+	)
+	for {
+		node, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		operands++
+
+		// Hoist required and excluded clauses of nested conjunctions.
+		if b, ok := node.(*BoolNode); ok && len(b.Should) == 0 {
+			res.Must = append(res.Must, b.Must...)
+			res.MustNot = append(res.MustNot, b.MustNot...)
+		} else {
+			res.Must = append(res.Must, node)
+		}
+
+		if p.peek().typ != tokenAnd {
+			break
+		}
+		p.next()
+	}
+
+	if operands == 1 && len(res.Must) == 1 && len(res.MustNot) == 0 {
+		return res.Must[0], nil
+	}
+	return &res, nil
+}
+
+// parseSequence parses: clause { clause }
+//
+// Each clause is optional unless it is prefixed by '+' (required) or by '-'
+// or NOT (excluded).
+func (p *queryParser) parseSequence() (QueryNode, error) {
+	var res BoolNode
+	for {
+		switch p.peek().typ {
+		case tokenEOF, tokenRParen, tokenAnd, tokenOr:
+			return simplifySequence(&res, p.peek())
+		}
+
+		var list = &res.Should
+		switch p.peek().typ {
+		case tokenRequire:
+			p.next()
+			list = &res.Must
+		case tokenExclude, tokenNot:
+			p.next()
+			list = &res.MustNot
+		}
+
+		node, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		*list = append(*list, node)
+	}
+}
+
+func simplifySequence(res *BoolNode, stopTok token) (QueryNode, error) {
+	switch numClauses := len(res.Must) + len(res.Should) + len(res.MustNot); {
+	case numClauses == 0 && stopTok.typ == tokenEOF:
+		return nil, xerrors.Errorf("unexpected end of query: %w", ErrInvalidQuery)
+	case numClauses == 0:
+		return nil, xerrors.Errorf("unexpected %q at offset %d: %w", stopTok.text, stopTok.pos, ErrInvalidQuery)
+	case numClauses == 1 && len(res.Should) == 1:
+		return res.Should[0], nil
+	case numClauses == 1 && len(res.Must) == 1:
+		return res.Must[0], nil
+	}
+	return res, nil
+}
+
+// parsePrimary parses a term, a phrase, a field expression or a
+// parenthesized expression.
+func (p *queryParser) parsePrimary() (QueryNode, error) {
+	tok := p.next()
+	switch tok.typ {
+	case tokenLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closeTok := p.next(); closeTok.typ != tokenRParen {
+			return nil, xerrors.Errorf("missing closing parenthesis for group at offset %d: %w", tok.pos, ErrInvalidQuery)
+		}
+		return node, nil
+	case tokenWord, tokenPhrase:
+		if tok.field != "" {
+			return parseFieldExpr(tok)
+		}
+		if tok.typ == tokenPhrase && strings.TrimSpace(tok.text) == "" {
+			return nil, xerrors.Errorf("empty phrase at offset %d: %w", tok.pos, ErrInvalidQuery)
+		}
+		return &TermNode{Field: FieldAny, Text: tok.text, Phrase: tok.typ == tokenPhrase}, nil
+	case tokenEOF:
+		return nil, xerrors.Errorf("unexpected end of query: %w", ErrInvalidQuery)
+	default:
+		return nil, xerrors.Errorf("unexpected %q at offset %d: %w", tok.text, tok.pos, ErrInvalidQuery)
+	}
+}
+
+// parseFieldExpr converts a field-prefixed token into a query node.
+func parseFieldExpr(tok token) (QueryNode, error) {
+	if strings.TrimSpace(tok.text) == "" {
+		return nil, xerrors.Errorf("missing value for %q at offset %d: %w", tok.field, tok.pos, ErrInvalidQuery)
+	}
+
+	switch tok.field {
+	case "site":
+		return &SiteNode{Host: strings.TrimSuffix(strings.ToLower(tok.text), "/")}, nil
+	case "intitle":
+		return &TermNode{Field: FieldTitle, Text: tok.text, Phrase: tok.typ == tokenPhrase}, nil
+	case "after":
+		from, err := parseQueryDate(tok, tok.text)
+		if err != nil {
+			return nil, err
+		}
+		return &DateRangeNode{From: from}, nil
+	case "before":
+		to, err := parseQueryDate(tok, tok.text)
+		if err != nil {
+			return nil, err
+		}
+		return &DateRangeNode{To: to}, nil
+	default: // indexed
+		bounds := strings.SplitN(tok.text, "..", 2)
+		if len(bounds) != 2 || (bounds[0] == "" && bounds[1] == "") {
+			return nil, xerrors.Errorf("expected a FROM..TO date range at offset %d: %w", tok.pos, ErrInvalidQuery)
+		}
+
+		var (
+			res DateRangeNode
+			err error
+		)
+		if bounds[0] != "" {
+			if res.From, err = parseQueryDate(tok, bounds[0]); err != nil {
+				return nil, err
+			}
+		}
+		if bounds[1] != "" {
+			if res.To, err = parseQueryDate(tok, bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+		return &res, nil
+	}
+}
+
+func parseQueryDate(tok token, v string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, xerrors.Errorf("invalid date %q for %q at offset %d: %w", v, tok.field, tok.pos, ErrInvalidQuery)
+}
@@ -0,0 +1,161 @@
+package index
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(ParserTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type ParserTestSuite struct{}
+
+func (s *ParserTestSuite) TestParseQuery(c *gc.C) {
+	day := func(v string) time.Time {
+		t, err := time.Parse("2006-01-02", v)
+		c.Assert(err, gc.IsNil)
+		return t
+	}
+
+	specs := []struct {
+		descr string
+		in    string
+		exp   QueryNode
+	}{
+		{
+			descr: "single term",
+			in:    "lorem",
+			exp:   &TermNode{Text: "lorem"},
+		},
+		{
+			descr: "optional terms",
+			in:    "lorem  ipsum",
+			exp: &BoolNode{Should: []QueryNode{
+				&TermNode{Text: "lorem"},
+				&TermNode{Text: "ipsum"},
+			}},
+		},
+		{
+			descr: "phrase",
+			in:    `"lorem ipsum"`,
+			exp:   &TermNode{Text: "lorem ipsum", Phrase: true},
+		},
+		{
+			descr: "required and excluded terms",
+			in:    `+lorem -ipsum NOT "dolor sit" amet`,
+			exp: &BoolNode{
+				Must:    []QueryNode{&TermNode{Text: "lorem"}},
+				Should:  []QueryNode{&TermNode{Text: "amet"}},
+				MustNot: []QueryNode{&TermNode{Text: "ipsum"}, &TermNode{Text: "dolor sit", Phrase: true}},
+			},
+		},
+		{
+			descr: "AND binds tighter than OR",
+			in:    "lorem OR ipsum AND dolor",
+			exp: &BoolNode{Should: []QueryNode{
+				&TermNode{Text: "lorem"},
+				&BoolNode{Must: []QueryNode{&TermNode{Text: "ipsum"}, &TermNode{Text: "dolor"}}},
+			}},
+		},
+		{
+			descr: "AND NOT",
+			in:    "lorem AND NOT ipsum",
+			exp: &BoolNode{
+				Must:    []QueryNode{&TermNode{Text: "lorem"}},
+				MustNot: []QueryNode{&TermNode{Text: "ipsum"}},
+			},
+		},
+		{
+			descr: "grouping",
+			in:    "(lorem OR ipsum) AND dolor",
+			exp: &BoolNode{Must: []QueryNode{
+				&BoolNode{Should: []QueryNode{&TermNode{Text: "lorem"}, &TermNode{Text: "ipsum"}}},
+				&TermNode{Text: "dolor"},
+			}},
+		},
+		{
+			descr: "lower-case operators are plain terms",
+			in:    "lorem or ipsum",
+			exp: &BoolNode{Should: []QueryNode{
+				&TermNode{Text: "lorem"},
+				&TermNode{Text: "or"},
+				&TermNode{Text: "ipsum"},
+			}},
+		},
+		{
+			descr: "site filter",
+			in:    "+lorem site:Example.COM/",
+			exp: &BoolNode{
+				Must:   []QueryNode{&TermNode{Text: "lorem"}},
+				Should: []QueryNode{&SiteNode{Host: "example.com"}},
+			},
+		},
+		{
+			descr: "title term",
+			in:    "intitle:lorem",
+			exp:   &TermNode{Field: FieldTitle, Text: "lorem"},
+		},
+		{
+			descr: "title phrase",
+			in:    `intitle:"lorem ipsum"`,
+			exp:   &TermNode{Field: FieldTitle, Text: "lorem ipsum", Phrase: true},
+		},
+		{
+			descr: "date range bounds",
+			in:    "after:2019-01-02 AND before:2019-02-03T04:05:06Z",
+			exp: &BoolNode{Must: []QueryNode{
+				&DateRangeNode{From: day("2019-01-02")},
+				&DateRangeNode{To: time.Date(2019, 2, 3, 4, 5, 6, 0, time.UTC)},
+			}},
+		},
+		{
+			descr: "date range",
+			in:    "indexed:2019-01-02..2019-02-03",
+			exp:   &DateRangeNode{From: day("2019-01-02"), To: day("2019-02-03")},
+		},
+		{
+			descr: "open-ended date range",
+			in:    "indexed:..2019-02-03",
+			exp:   &DateRangeNode{To: day("2019-02-03")},
+		},
+		{
+			descr: "unknown field",
+			in:    "foo:bar",
+			exp:   &TermNode{Text: "foo:bar"},
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		got, err := ParseQuery(spec.in)
+		c.Assert(err, gc.IsNil)
+		c.Assert(got, gc.DeepEquals, spec.exp)
+	}
+}
+
+func (s *ParserTestSuite) TestParseInvalidQuery(c *gc.C) {
+	specs := []string{
+		"",
+		"   ",
+		`"lorem ipsum`,
+		`""`,
+		"(lorem",
+		"lorem)",
+		"()",
+		"lorem AND",
+		"OR lorem",
+		"site:",
+		"after:yesterday",
+		"indexed:2019-01-02",
+		"indexed:..",
+	}
+
+	for _, in := range specs {
+		_, err := ParseQuery(in)
+		c.Assert(xerrors.Is(err, ErrInvalidQuery), gc.Equals, true, gc.Commentf("input %q; got %v", in, err))
+	}
+}
@@ -0,0 +1,63 @@
+package index
+
+import "time"
+
+// QueryNode is implemented by the nodes of a structured query tree. The
+// supported node types are TermNode, SiteNode, DateRangeNode and BoolNode.
+type QueryNode interface {
+	queryNode()
+}
+
+// Field describes the document field(s) that a TermNode is matched against.
+type Field uint8
+
+const (
+	// FieldAny matches terms against both the title and the content of
+	// documents.
+	FieldAny Field = iota
+
+	// FieldTitle matches terms against the title of documents.
+	FieldTitle
+)
+
+// TermNode matches documents that contain one or more keywords.
+type TermNode struct {
+	// The field to match the terms against.
+	Field Field
+
+	// The terms to search for.
+	Text string
+
+	// If set, Text is treated as an exact phrase.
+	Phrase bool
+}
+
+// SiteNode matches documents whose URL points to a particular host or any
+// of its sub-domains.
+type SiteNode struct {
+	// The host name to match.
+	Host string
+}
+
+// DateRangeNode matches documents whose IndexedAt value falls within a
+// [From, To) range. A zero value for either bound leaves that end of the
+// range open.
+type DateRangeNode struct {
+	From time.Time
+	To   time.Time
+}
+
+// BoolNode combines a set of sub-queries. Documents must match all Must
+// clauses and none of the MustNot clauses. If no Must clauses are present,
+// documents must also match at least one of the Should clauses; otherwise,
+// Should clauses only affect the relevance of the matched documents.
+type BoolNode struct {
+	Must    []QueryNode
+	Should  []QueryNode
+	MustNot []QueryNode
+}
+
+func (*TermNode) queryNode()      {}
+func (*SiteNode) queryNode()      {}
+func (*DateRangeNode) queryNode() {}
+func (*BoolNode) queryNode()      {}
@@ -0,0 +1,75 @@
+package index
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultRanking is the ranking model used for queries that do not specify
+// one. It blends the text relevance and PageRank scores with equal weights
+// and does not apply any freshness decay or title boost.
+var DefaultRanking = Ranking{
+	RelevanceWeight: 1,
+	PageRankWeight:  1,
+}
+
+// The relevance multipliers applied to matches in the secondary document
+// fields for terms that are matched against both the title and the content
+// of documents.
+const (
+	DescriptionBoost = 1.5
+	HeadingsBoost    = 2
+	AnchorTextBoost  = 2
+)
+
+// Ranking describes how the indexer implementations calculate the final
+// score used for ordering search results. The score of each matching
+// document is calculated as:
+//
+//	(RelevanceWeight * relevance + PageRankWeight * PageRank) * freshness
+//
+// where freshness halves every FreshnessHalfLife since the document was
+// last indexed.
+type Ranking struct {
+	// The weight of the text relevance score.
+	RelevanceWeight float64
+
+	// The weight of the PageRank score.
+	PageRankWeight float64
+
+	// A multiplier for the relevance of terms that match document titles.
+	// It only applies to terms that are matched against both the title and
+	// the content of documents. Values less than or equal to 1 disable the
+	// boost.
+	TitleBoost float64
+
+	// The age after which the score of a document is halved. If zero,
+	// the score does not depend on the document age.
+	FreshnessHalfLife time.Duration
+}
+
+// Score returns the final ranking score for a document with the specified
+// relevance and PageRank scores that was indexed at indexedAt.
+func (r Ranking) Score(relevance, pageRank float64, indexedAt, now time.Time) float64 {
+	score := r.RelevanceWeight*relevance + r.PageRankWeight*pageRank
+	return score * r.Freshness(indexedAt, now)
+}
+
+// Freshness returns the decay factor for a document that was indexed at
+// indexedAt. Documents that have never been indexed do not decay.
+func (r Ranking) Freshness(indexedAt, now time.Time) float64 {
+	if r.FreshnessHalfLife <= 0 || indexedAt.IsZero() {
+		return 1
+	}
+
+	age := now.Sub(indexedAt)
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, float64(age)/float64(r.FreshnessHalfLife))
+}
+
+// HasTitleBoost returns true if the ranking model boosts title matches.
+func (r Ranking) HasTitleBoost() bool {
+	return r.TitleBoost > 1
+}
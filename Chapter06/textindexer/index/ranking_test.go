@@ -0,0 +1,64 @@
+package index
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(RankingTestSuite))
+
+type RankingTestSuite struct{}
+
+func (s *RankingTestSuite) TestScore(c *gc.C) {
+	now := time.Date(2019, 2, 14, 13, 37, 0, 0, time.UTC)
+
+	specs := []struct {
+		descr     string
+		ranking   Ranking
+		indexedAt time.Time
+		exp       float64
+	}{
+		{
+			descr:     "default ranking",
+			ranking:   DefaultRanking,
+			indexedAt: now.Add(-24 * time.Hour),
+			exp:       2.5,
+		},
+		{
+			descr:     "weighted scores",
+			ranking:   Ranking{RelevanceWeight: 0.5, PageRankWeight: 2},
+			indexedAt: now,
+			exp:       2,
+		},
+		{
+			descr:     "freshness decay",
+			ranking:   Ranking{RelevanceWeight: 1, PageRankWeight: 1, FreshnessHalfLife: time.Hour},
+			indexedAt: now.Add(-2 * time.Hour),
+			exp:       0.625,
+		},
+		{
+			descr:   "placeholder documents do not decay",
+			ranking: Ranking{RelevanceWeight: 1, PageRankWeight: 1, FreshnessHalfLife: time.Hour},
+			exp:     2.5,
+		},
+		{
+			descr:     "documents from the future do not get a boost",
+			ranking:   Ranking{RelevanceWeight: 1, PageRankWeight: 1, FreshnessHalfLife: time.Hour},
+			indexedAt: now.Add(time.Hour),
+			exp:       2.5,
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		c.Assert(spec.ranking.Score(2, 0.5, spec.indexedAt, now), gc.Equals, spec.exp)
+	}
+}
+
+func (s *RankingTestSuite) TestRankingModel(c *gc.C) {
+	c.Assert(Query{}.RankingModel(), gc.DeepEquals, DefaultRanking)
+
+	r := Ranking{PageRankWeight: 1, TitleBoost: 2}
+	c.Assert(Query{Ranking: &r}.RankingModel(), gc.DeepEquals, r)
+}
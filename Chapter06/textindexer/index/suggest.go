@@ -0,0 +1,239 @@
+package index
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MaxSuggestions is the maximum number of spelling suggestions returned by
+// the indexer implementations.
+const MaxSuggestions = 5
+
+// MaxSuggestEditDistance is the maximum number of single-character edits
+// between a search term and any of its suggested corrections.
+const MaxSuggestEditDistance = 2
+
+// TermCandidate is an indexed term that may replace a misspelled search
+// term.
+type TermCandidate struct {
+	// The indexed term.
+	Term string
+
+	// The number of documents that contain the term.
+	DocCount uint64
+}
+
+// CandidateFunc returns the correction candidates for each one of a list of
+// lower-cased search terms. Terms that are present in the index must be
+// omitted from the returned map.
+type CandidateFunc func(terms []string) (map[string][]TermCandidate, error)
+
+// The runs of characters in a search expression that are checked for
+// spelling errors.
+var wordRegex = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// The keywords of the query syntax supported by ParseQuery.
+var queryKeywords = map[string]bool{"AND": true, "OR": true, "NOT": true}
+
+// Suggest returns up to MaxSuggestions alternative spellings for the terms
+// in a search expression using the correction candidates provided by
+// candidatesFn. Any query syntax in expression is preserved.
+//
+// The first suggestion replaces each misspelled term with its closest
+// candidate while the remaining suggestions differ from the first one by a
+// single term. Suggestions are ordered by their total edit distance from
+// expression and, for equal distances, by the number of documents that
+// contain the replacement terms.
+func Suggest(expression string, candidatesFn CandidateFunc) ([]string, error) {
+	var (
+		words = spellCheckedWords(expression)
+		terms []string
+		seen  = make(map[string]bool)
+	)
+	for _, loc := range words {
+		term := strings.ToLower(expression[loc[0]:loc[1]])
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	candidates, err := candidatesFn(terms)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rank the candidates for each term and discard terms that cannot be
+	// corrected.
+	var misspelled []string
+	ranked := make(map[string][]rankedCandidate)
+	for _, term := range terms {
+		if list := rankCandidates(term, candidates[term]); len(list) != 0 {
+			misspelled = append(misspelled, term)
+			ranked[term] = list
+		}
+	}
+	if len(misspelled) == 0 {
+		return nil, nil
+	}
+
+	// Start with the best candidate for each term and then vary one term
+	// at a time.
+	best := make(map[string]int, len(misspelled))
+	for _, term := range misspelled {
+		best[term] = 0
+	}
+	corrections := []correction{newCorrection(best, ranked)}
+	for _, term := range misspelled {
+		for i := 1; i < len(ranked[term]); i++ {
+			choice := make(map[string]int, len(best))
+			for t := range best {
+				choice[t] = 0
+			}
+			choice[term] = i
+			corrections = append(corrections, newCorrection(choice, ranked))
+		}
+	}
+	sort.SliceStable(corrections, func(i, j int) bool {
+		if corrections[i].distance != corrections[j].distance {
+			return corrections[i].distance < corrections[j].distance
+		}
+		return corrections[i].docCount > corrections[j].docCount
+	})
+
+	var suggestions []string
+	for _, corr := range corrections {
+		if len(suggestions) == MaxSuggestions {
+			break
+		}
+		suggestions = append(suggestions, corr.apply(expression, words))
+	}
+	return suggestions, nil
+}
+
+// spellCheckedWords returns the locations of the words in expression that
+// should be checked for spelling errors. Query keywords and field prefixes
+// are ignored.
+func spellCheckedWords(expression string) [][]int {
+	var words [][]int
+	for _, loc := range wordRegex.FindAllStringIndex(expression, -1) {
+		if queryKeywords[expression[loc[0]:loc[1]]] {
+			continue
+		} else if loc[1] < len(expression) && expression[loc[1]] == ':' && queryFields[strings.ToLower(expression[loc[0]:loc[1]])] {
+			continue
+		}
+		words = append(words, loc)
+	}
+	return words
+}
+
+type rankedCandidate struct {
+	TermCandidate
+	distance int
+}
+
+// rankCandidates filters out candidates that are too far from term and
+// sorts the remaining ones by edit distance and document count. If any of
+// the candidates matches term, rankCandidates returns nil.
+func rankCandidates(term string, candidates []TermCandidate) []rankedCandidate {
+	var list []rankedCandidate
+	for _, cand := range candidates {
+		dist := EditDistance(term, cand.Term)
+		if dist == 0 {
+			return nil
+		} else if dist <= MaxSuggestEditDistance {
+			list = append(list, rankedCandidate{TermCandidate: cand, distance: dist})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		switch {
+		case list[i].distance != list[j].distance:
+			return list[i].distance < list[j].distance
+		case list[i].DocCount != list[j].DocCount:
+			return list[i].DocCount > list[j].DocCount
+		default:
+			return list[i].Term < list[j].Term
+		}
+	})
+	if len(list) > MaxSuggestions {
+		list = list[:MaxSuggestions]
+	}
+	return list
+}
+
+// correction describes a set of replacements for the misspelled terms of a
+// search expression.
+type correction struct {
+	replacements map[string]string
+	distance     int
+	docCount     uint64
+}
+
+// apply replaces the misspelled words at the specified locations of
+// expression.
+func (corr correction) apply(expression string, words [][]int) string {
+	var (
+		sb   strings.Builder
+		last int
+	)
+	for _, loc := range words {
+		replacement, found := corr.replacements[strings.ToLower(expression[loc[0]:loc[1]])]
+		if !found {
+			continue
+		}
+		sb.WriteString(expression[last:loc[0]])
+		sb.WriteString(replacement)
+		last = loc[1]
+	}
+	sb.WriteString(expression[last:])
+	return sb.String()
+}
+
+func newCorrection(choice map[string]int, ranked map[string][]rankedCandidate) correction {
+	corr := correction{replacements: make(map[string]string, len(choice))}
+	for term, index := range choice {
+		cand := ranked[term][index]
+		corr.replacements[term] = cand.Term
+		corr.distance += cand.distance
+		corr.docCount += cand.DocCount
+	}
+	return corr
+}
+
+// EditDistance returns the Levenshtein distance between a and b, i.e. the
+// minimum number of single-character insertions, deletions and
+// substitutions required for converting a into b.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
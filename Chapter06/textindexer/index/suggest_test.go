@@ -0,0 +1,70 @@
+package index
+
+import (
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(SuggestTestSuite))
+
+type SuggestTestSuite struct{}
+
+func (s *SuggestTestSuite) TestEditDistance(c *gc.C) {
+	specs := []struct {
+		a, b string
+		exp  int
+	}{
+		{"", "", 0},
+		{"lorem", "lorem", 0},
+		{"", "lorem", 5},
+		{"lorme", "lorem", 2},
+		{"lorm", "lorem", 1},
+		{"kitten", "sitting", 3},
+		{"καλημέρα", "καλιμέρα", 1},
+	}
+
+	for _, spec := range specs {
+		c.Assert(EditDistance(spec.a, spec.b), gc.Equals, spec.exp, gc.Commentf("%q -> %q", spec.a, spec.b))
+	}
+}
+
+func (s *SuggestTestSuite) TestSuggest(c *gc.C) {
+	candidates := map[string][]TermCandidate{
+		"lorme": {
+			{Term: "lorem", DocCount: 2},
+			{Term: "lore", DocCount: 10},
+			{Term: "lorelei", DocCount: 100},
+		},
+		"ipsm": {
+			{Term: "ipsum", DocCount: 5},
+		},
+		"dolr": {
+			{Term: "dolor", DocCount: 1},
+		},
+		// Terms that are too far from any candidate cannot be corrected.
+		"xyz": {
+			{Term: "amet", DocCount: 1},
+		},
+	}
+
+	var gotTerms []string
+	got, err := Suggest(`+Lorme -ipsm AND intitle:"dolr xyz" site:lorme.com`, func(terms []string) (map[string][]TermCandidate, error) {
+		gotTerms = terms
+		return candidates, nil
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(gotTerms, gc.DeepEquals, []string{"lorme", "ipsm", "dolr", "xyz", "com"})
+	c.Assert(got, gc.DeepEquals, []string{
+		`+lore -ipsum AND intitle:"dolor xyz" site:lore.com`,
+		`+lorem -ipsum AND intitle:"dolor xyz" site:lorem.com`,
+	})
+}
+
+func (s *SuggestTestSuite) TestSuggestWithoutMisspelledTerms(c *gc.C) {
+	got, err := Suggest("lorem ipsum", func(terms []string) (map[string][]TermCandidate, error) {
+		return map[string][]TermCandidate{
+			"lorem": {{Term: "lorem", DocCount: 1}, {Term: "lore", DocCount: 1}},
+		}, nil
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.IsNil)
+}
@@ -0,0 +1,81 @@
+package blevequery
+
+import (
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// Complete returns completions for a partially typed search expression
+// using the terms in the dictionary of the default search field of idx and
+// the titles of the matching documents. Indexers must store the Title field
+// of each document.
+func Complete(idx bleve.Index, expression string) ([]string, error) {
+	return index.Complete(
+		expression,
+		func(prefix string) ([]index.TermCandidate, error) {
+			return prefixTerms(idx, prefix)
+		},
+		func(words []string, prefix string, limit int) ([]string, error) {
+			return matchingTitles(idx, words, prefix, limit)
+		},
+	)
+}
+
+// prefixTerms returns the terms in the dictionary of the default search
+// field of idx that start with prefix.
+func prefixTerms(idx bleve.Index, prefix string) ([]index.TermCandidate, error) {
+	dict, err := idx.FieldDictPrefix(idx.Mapping().DefaultSearchField(), []byte(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = dict.Close() }()
+
+	var terms []index.TermCandidate
+	for {
+		entry, err := dict.Next()
+		if err != nil {
+			return nil, err
+		} else if entry == nil {
+			return terms, nil
+		} else if entry.Count != 0 {
+			terms = append(terms, index.TermCandidate{Term: entry.Term, DocCount: entry.Count})
+		}
+	}
+}
+
+// matchingTitles returns the titles of up to limit documents whose titles
+// contain all words and a term that starts with prefix. Documents with a
+// higher PageRank score are preferred.
+func matchingTitles(idx bleve.Index, words []string, prefix string, limit int) ([]string, error) {
+	var conjuncts []query.Query
+	for _, word := range words {
+		mq := bleve.NewMatchQuery(word)
+		mq.SetField(TitleField)
+		conjuncts = append(conjuncts, mq)
+	}
+	if prefix != "" {
+		pq := bleve.NewPrefixQuery(prefix)
+		pq.SetField(TitleField)
+		conjuncts = append(conjuncts, pq)
+	}
+	if len(conjuncts) == 0 {
+		return nil, nil
+	}
+
+	searchReq := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(conjuncts...), limit, 0, false)
+	searchReq.Fields = []string{TitleField}
+	searchReq.SortBy([]string{"-" + PageRankField, "-_score", "_id"})
+	rs, err := idx.Search(searchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make([]string, 0, len(rs.Hits))
+	for _, hit := range rs.Hits {
+		if title, ok := hit.Fields[TitleField].(string); ok && title != "" {
+			titles = append(titles, title)
+		}
+	}
+	return titles, nil
+}
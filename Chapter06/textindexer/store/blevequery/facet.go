@@ -0,0 +1,138 @@
+package blevequery
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// The names of the document fields that are used for calculating facets.
+// Indexers must map the Host field as a keyword and the PageRank field as
+// a number.
+const (
+	HostField     = "Host"
+	PageRankField = "PageRank"
+)
+
+// URLHost returns the lower-cased host name for rawURL or an empty string if
+// rawURL cannot be parsed.
+func URLHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// Facets calculates the facets described by reqs over the documents in idx
+// that match q. The buckets for IndexedAt facets are calculated relative to
+// now.
+//
+// Facets are calculated by a separate, unsorted search request. When the
+// sort and facet fields of a request overlap, bleve visits the doc values of
+// the shared fields twice for persisted segments and double-counts them.
+func Facets(idx bleve.Index, q query.Query, reqs []index.FacetRequest, now time.Time) ([]index.FacetResult, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	fs := newFacetSet(reqs, now)
+	searchReq := bleve.NewSearchRequestOptions(q, 0, 0, false)
+	for name, fr := range fs.facetReqs {
+		searchReq.AddFacet(name, fr)
+	}
+
+	rs, err := idx.Search(searchReq)
+	if err != nil {
+		return nil, err
+	}
+	return fs.results(rs.Facets), nil
+}
+
+// facetSet translates a list of index.FacetRequest values into bleve facet
+// requests and the calculated bleve facets back into index.FacetResult
+// values.
+type facetSet struct {
+	reqs []index.FacetRequest
+
+	// The ordered bucket keys for each range-based facet request.
+	rangeKeys [][]string
+
+	facetReqs map[string]*bleve.FacetRequest
+}
+
+func newFacetSet(reqs []index.FacetRequest, now time.Time) *facetSet {
+	fs := &facetSet{
+		reqs:      reqs,
+		rangeKeys: make([][]string, len(reqs)),
+		facetReqs: make(map[string]*bleve.FacetRequest, len(reqs)),
+	}
+
+	for i, req := range reqs {
+		switch req.Type {
+		case index.FacetTypeHost:
+			fs.facetReqs[req.Name] = bleve.NewFacetRequest(HostField, req.Limit())
+		case index.FacetTypeIndexedAt:
+			ranges := req.DateRanges(now)
+			fr := bleve.NewFacetRequest(IndexedAtField, len(ranges))
+			for _, r := range ranges {
+				fr.AddDateTimeRange(r.Key, r.From, r.To)
+				fs.rangeKeys[i] = append(fs.rangeKeys[i], r.Key)
+			}
+			fs.facetReqs[req.Name] = fr
+		case index.FacetTypePageRank:
+			ranges := req.NumericRanges()
+			if len(ranges) == 0 {
+				continue
+			}
+			fr := bleve.NewFacetRequest(PageRankField, len(ranges))
+			for _, r := range ranges {
+				fr.AddNumericRange(r.Key, r.From, r.To)
+				fs.rangeKeys[i] = append(fs.rangeKeys[i], r.Key)
+			}
+			fs.facetReqs[req.Name] = fr
+		}
+	}
+
+	return fs
+}
+
+// results converts the facets calculated by bleve into a list of
+// index.FacetResult values with the same order as the facet requests.
+func (fs *facetSet) results(facets search.FacetResults) []index.FacetResult {
+	results := make([]index.FacetResult, len(fs.reqs))
+	for i, req := range fs.reqs {
+		results[i].Name = req.Name
+
+		res := facets[req.Name]
+		if req.Type == index.FacetTypeHost {
+			if res != nil && res.Terms != nil {
+				for _, term := range res.Terms.Terms() {
+					results[i].Buckets = append(results[i].Buckets, index.FacetBucket{Key: term.Term, Count: uint64(term.Count)})
+				}
+			}
+			continue
+		}
+
+		// Range-based facets only include non-empty ranges in their results.
+		counts := make(map[string]int)
+		if res != nil {
+			for _, r := range res.DateRanges {
+				counts[r.Name] = r.Count
+			}
+			for _, r := range res.NumericRanges {
+				counts[r.Name] = r.Count
+			}
+		}
+		for _, key := range fs.rangeKeys[i] {
+			results[i].Buckets = append(results[i].Buckets, index.FacetBucket{Key: key, Count: uint64(counts[key])})
+		}
+	}
+
+	return results
+}
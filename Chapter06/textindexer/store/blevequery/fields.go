@@ -0,0 +1,18 @@
+package blevequery
+
+import (
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+)
+
+// AddSecondaryFieldMappings adds to docMapping text mappings for the
+// Description, Headings and AnchorText fields. The fields are excluded from
+// the default search field as translated queries match them separately so
+// that field-specific boosts can be applied.
+func AddSecondaryFieldMappings(docMapping *mapping.DocumentMapping) {
+	for _, field := range []string{DescriptionField, HeadingsField, AnchorTextField} {
+		fieldMapping := bleve.NewTextFieldMapping()
+		fieldMapping.IncludeInAll = false
+		docMapping.AddFieldMappingsAt(field, fieldMapping)
+	}
+}
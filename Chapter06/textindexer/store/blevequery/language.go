@@ -0,0 +1,59 @@
+package blevequery
+
+import (
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+
+	// Register the analyzers for the supported languages. The name of each
+	// analyzer matches the ISO 639-1 code of its language.
+	_ "github.com/blevesearch/bleve/v2/analysis/lang/de"
+	_ "github.com/blevesearch/bleve/v2/analysis/lang/en"
+	_ "github.com/blevesearch/bleve/v2/analysis/lang/es"
+	_ "github.com/blevesearch/bleve/v2/analysis/lang/fr"
+	_ "github.com/blevesearch/bleve/v2/analysis/lang/it"
+	_ "github.com/blevesearch/bleve/v2/analysis/lang/nl"
+	_ "github.com/blevesearch/bleve/v2/analysis/lang/pt"
+)
+
+// The names of the fields that hold the language of each document and its
+// content analyzed with the analyzer for that language.
+const (
+	LanguageField        = "Language"
+	LanguageContentField = "LanguageContent"
+)
+
+// LanguageContentPath returns the path of the field that holds the content
+// of documents in lang.
+func LanguageContentPath(lang string) string {
+	return LanguageContentField + "." + lang
+}
+
+// LanguageContent returns the value of the LanguageContent field for doc.
+// It returns nil if the language of doc is not supported.
+func LanguageContent(doc *index.Document) map[string]string {
+	if !index.IsSupportedLanguage(doc.Language) {
+		return nil
+	}
+	return map[string]string{doc.Language: doc.Content}
+}
+
+// AddLanguageMappings adds to docMapping a keyword mapping for the Language
+// field and a text mapping with the appropriate analyzer for the content of
+// each supported language. The language-specific content fields are not
+// stored and are excluded from the default search field.
+func AddLanguageMappings(docMapping *mapping.DocumentMapping) {
+	langMapping := bleve.NewKeywordFieldMapping()
+	langMapping.IncludeInAll = false
+	docMapping.AddFieldMappingsAt(LanguageField, langMapping)
+
+	contentMapping := bleve.NewDocumentStaticMapping()
+	for _, lang := range index.SupportedLanguages {
+		fieldMapping := bleve.NewTextFieldMapping()
+		fieldMapping.Analyzer = lang
+		fieldMapping.Store = false
+		fieldMapping.IncludeInAll = false
+		contentMapping.AddFieldMappingsAt(lang, fieldMapping)
+	}
+	docMapping.AddSubDocumentMapping(LanguageContentField, contentMapping)
+}
@@ -0,0 +1,191 @@
+package blevequery
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/google/uuid"
+)
+
+// The number of hits fetched by each search request issued by Results.
+const batchSize = 10
+
+// Results iterates the hits of a RankedQuery in ranked order, honoring the
+// Offset, Cursor and Backward settings of an index.Query. Hits are fetched
+// in batches; each batch after the first resumes from the sort keys of the
+// last fetched hit via a SearchAfter (or SearchBefore when iterating
+// backwards) request.
+type Results struct {
+	idx      bleve.Index
+	req      *bleve.SearchRequest
+	now      time.Time
+	total    uint64
+	backward bool
+
+	// The current batch of hits in iteration order.
+	batch     search.DocumentMatchCollection
+	batchIdx  int
+	exhausted bool
+
+	latchedHit *search.DocumentMatch
+	latchedPos uint64
+	nextPos    uint64
+	lastErr    error
+}
+
+// Search executes bq against idx and returns an iterator for the matching
+// hits, ordered by the ranking model of q. The stored fields listed in
+// fields are loaded for each hit.
+//
+// When q specifies a cursor, the hits are ranked using the reference time
+// captured by the cursor so that the scores of the hits are comparable to
+// the score stored in the cursor.
+func Search(idx bleve.Index, bq query.Query, q index.Query, fields []string) (*Results, error) {
+	res := &Results{idx: idx, now: time.Now()}
+
+	var cursor index.Cursor
+	if q.Cursor != "" {
+		var err error
+		if cursor, err = index.ParseCursor(q.Cursor); err != nil {
+			return nil, err
+		}
+		res.now = cursor.ReferenceTime()
+	}
+
+	res.req = bleve.NewSearchRequestOptions(RankedQuery(bq, q.RankingModel(), q.Language, res.now), batchSize, 0, false)
+	res.req.SortBy(RankedSortOrder)
+	res.req.Fields = fields
+
+	switch {
+	case q.Cursor == "":
+		res.req.From = int(q.Offset)
+		res.nextPos = q.Offset
+	case !q.Backward:
+		res.req.SetSearchAfter(cursorSortKeys(cursor))
+		res.nextPos = cursor.Position + 1
+	default:
+		res.req.SetSearchBefore(cursorSortKeys(cursor))
+		res.backward = true
+		if cursor.Position > 0 {
+			res.nextPos = cursor.Position - 1
+		}
+	}
+
+	// Fetch the first batch so that the total hit count becomes available.
+	if err := res.fetchBatch(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Next advances the iterator to the next hit. It returns false if no more
+// hits are available or an error occurred.
+func (r *Results) Next() bool {
+	if r.lastErr != nil {
+		return false
+	}
+
+	if r.batchIdx >= len(r.batch) {
+		if r.exhausted {
+			return false
+		}
+		if r.lastErr = r.fetchBatch(); r.lastErr != nil || len(r.batch) == 0 {
+			return false
+		}
+	}
+
+	r.latchedHit, r.latchedPos = r.batch[r.batchIdx], r.nextPos
+	r.batchIdx++
+	if r.backward {
+		if r.nextPos > 0 {
+			r.nextPos--
+		}
+	} else {
+		r.nextPos++
+	}
+	return true
+}
+
+// fetchBatch loads the next batch of hits.
+func (r *Results) fetchBatch() error {
+	rs, err := r.idx.Search(r.req)
+	if err != nil {
+		return err
+	}
+
+	if r.total == 0 {
+		r.total = rs.Total
+	}
+	r.exhausted = len(rs.Hits) < r.req.Size
+
+	r.batch, r.batchIdx = rs.Hits, 0
+	if r.backward {
+		for i, j := 0, len(r.batch)-1; i < j; i, j = i+1, j-1 {
+			r.batch[i], r.batch[j] = r.batch[j], r.batch[i]
+		}
+	}
+
+	// Resume the next batch after the last hit of this one.
+	if len(r.batch) != 0 {
+		keys := hitSortKeys(r.batch[len(r.batch)-1])
+		r.req.From = 0
+		if r.backward {
+			r.req.SetSearchBefore(keys)
+		} else {
+			r.req.SetSearchAfter(keys)
+		}
+	}
+	return nil
+}
+
+// Error returns the last error encountered by the iterator.
+func (r *Results) Error() error {
+	return r.lastErr
+}
+
+// Hit returns the current hit.
+func (r *Results) Hit() *search.DocumentMatch {
+	return r.latchedHit
+}
+
+// Cursor returns the opaque cursor for the current hit.
+func (r *Results) Cursor() string {
+	linkID, _ := uuid.Parse(r.latchedHit.ID)
+	return index.Cursor{
+		Position: r.latchedPos,
+		Score:    r.latchedHit.Score,
+		Now:      r.now,
+		LinkID:   linkID,
+	}.String()
+}
+
+// Total returns the total number of hits matching the query.
+func (r *Results) Total() uint64 {
+	return r.total
+}
+
+// Now returns the reference time used for calculating the ranking scores of
+// the hits.
+func (r *Results) Now() time.Time {
+	return r.now
+}
+
+// Close releases the resources held by the iterator.
+func (r *Results) Close() {
+	r.idx, r.batch, r.exhausted = nil, nil, true
+}
+
+// cursorSortKeys returns the RankedSortOrder keys for the hit that cursor
+// points to.
+func cursorSortKeys(cursor index.Cursor) []string {
+	return []string{strconv.FormatFloat(cursor.Score, 'g', -1, 64), cursor.LinkID.String()}
+}
+
+// hitSortKeys returns the RankedSortOrder keys for hit.
+func hitSortKeys(hit *search.DocumentMatch) []string {
+	return []string{strconv.FormatFloat(hit.Score, 'g', -1, 64), hit.ID}
+}
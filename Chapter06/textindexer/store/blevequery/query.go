@@ -0,0 +1,159 @@
+// Package blevequery translates index.Query values into queries that can be
+// executed by the bleve-backed indexer implementations.
+package blevequery
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// The names of the document fields that are referenced by translated
+// queries. Indexers must map the URL field as a keyword and the IndexedAt
+// field as a datetime.
+const (
+	TitleField       = "Title"
+	ContentField     = "Content"
+	DescriptionField = "Description"
+	HeadingsField    = "Headings"
+	AnchorTextField  = "AnchorText"
+	URLField         = "URL"
+	IndexedAtField   = "IndexedAt"
+)
+
+// Translate converts q into a bleve query. If the ranking model of q boosts
+// title matches, terms that are matched against both the title and the
+// content of documents are translated into a disjunction of a boosted title
+// query and a content query.
+//
+// Terms that are matched against the content of documents are also matched
+// against the language-specific content fields of the languages returned by
+// the SearchLanguages method of the language preference of q. If the
+// preference excludes documents in other languages, the translated query is
+// combined with a filter on the Language field. Placeholder documents for
+// links that have not been indexed yet are never matched.
+func Translate(q index.Query) query.Query {
+	t := translator{
+		ranking:   q.RankingModel(),
+		languages: q.Language.SearchLanguages(),
+	}
+
+	var bq query.Query
+	if q.Tree != nil {
+		bq = t.translateNode(q.Tree)
+	} else {
+		bq = t.translateTerm(&index.TermNode{
+			Text:   q.Expression,
+			Phrase: q.Type == index.QueryTypePhrase,
+		})
+	}
+
+	if q.Language != nil && q.Language.IsFilter() {
+		tq := bleve.NewTermQuery(q.Language.Language)
+		tq.SetField(LanguageField)
+		bq = bleve.NewConjunctionQuery(bq, tq)
+	}
+	return bleve.NewConjunctionQuery(bq, indexedDocsQuery())
+}
+
+// indexedDocsQuery returns a query that matches all documents with an
+// IndexedAt field. Placeholder documents lack a (valid) indexing timestamp
+// and are therefore never matched.
+func indexedDocsQuery() query.Query {
+	inclusive := true
+	dq := bleve.NewDateRangeInclusiveQuery(time.Unix(0, 0), time.Time{}, &inclusive, nil)
+	dq.SetField(IndexedAtField)
+	return dq
+}
+
+type translator struct {
+	ranking   index.Ranking
+	languages []string
+}
+
+func (t translator) translateNode(node index.QueryNode) query.Query {
+	switch n := node.(type) {
+	case *index.TermNode:
+		return t.translateTerm(n)
+	case *index.SiteNode:
+		rq := bleve.NewRegexpQuery(siteRegexp(n.Host))
+		rq.SetField(URLField)
+		return rq
+	case *index.DateRangeNode:
+		if n.From.IsZero() && n.To.IsZero() {
+			return bleve.NewMatchAllQuery()
+		}
+		inclusive, exclusive := true, false
+		dq := bleve.NewDateRangeInclusiveQuery(n.From, n.To, &inclusive, &exclusive)
+		dq.SetField(IndexedAtField)
+		return dq
+	case *index.BoolNode:
+		bq := bleve.NewBooleanQuery()
+		for _, sub := range n.Must {
+			bq.AddMust(t.translateNode(sub))
+		}
+		for _, sub := range n.Should {
+			bq.AddShould(t.translateNode(sub))
+		}
+		for _, sub := range n.MustNot {
+			bq.AddMustNot(t.translateNode(sub))
+		}
+		return bq
+	default:
+		return bleve.NewMatchNoneQuery()
+	}
+}
+
+func (t translator) translateTerm(n *index.TermNode) query.Query {
+	if n.Field == index.FieldTitle {
+		return matchQuery(n, TitleField, 1)
+	}
+
+	var disjuncts []query.Query
+	if t.ranking.HasTitleBoost() {
+		disjuncts = append(disjuncts,
+			matchQuery(n, TitleField, t.ranking.TitleBoost),
+			matchQuery(n, ContentField, 1),
+		)
+	} else {
+		disjuncts = append(disjuncts, matchQuery(n, "", 1))
+	}
+	disjuncts = append(disjuncts,
+		matchQuery(n, DescriptionField, index.DescriptionBoost),
+		matchQuery(n, HeadingsField, index.HeadingsBoost),
+		matchQuery(n, AnchorTextField, index.AnchorTextBoost),
+	)
+	for _, lang := range t.languages {
+		disjuncts = append(disjuncts, matchQuery(n, LanguageContentPath(lang), 1))
+	}
+	return bleve.NewDisjunctionQuery(disjuncts...)
+}
+
+// matchQuery returns a match or phrase query for the text of n. If field is
+// empty, the query is matched against the default search field.
+func matchQuery(n *index.TermNode, field string, boost float64) query.Query {
+	if n.Phrase {
+		pq := bleve.NewMatchPhraseQuery(n.Text)
+		pq.SetField(field)
+		if boost != 1 {
+			pq.SetBoost(boost)
+		}
+		return pq
+	}
+
+	mq := bleve.NewMatchQuery(n.Text)
+	mq.SetField(field)
+	if boost != 1 {
+		mq.SetBoost(boost)
+	}
+	return mq
+}
+
+// siteRegexp returns a regular expression that matches URLs pointing to
+// host or any of its sub-domains.
+func siteRegexp(host string) string {
+	return `https?://([^/?]*\.)?` + regexp.QuoteMeta(host) + `(:[0-9]+)?([/?].*)?`
+}
@@ -0,0 +1,133 @@
+package blevequery
+
+import (
+	"context"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+	bleveindex "github.com/blevesearch/bleve_index_api"
+)
+
+// RankedSortOrder orders the hits of a RankedQuery by descending ranking
+// score, breaking ties by ordering documents by their IDs so that the result
+// order is stable across paginated requests.
+var RankedSortOrder = []string{"-_score", "_id"}
+
+// RankedQuery returns a query that matches the same documents as q and sets
+// the score of each hit to the score calculated by the ranking model r for
+// the reference time now. If lang specifies a language boost, the relevance
+// score of documents in the preferred language is multiplied by the boost
+// before calculating the ranking score.
+//
+// The ranking score is calculated while bleve collects the matching
+// documents so that bleve itself can order the hits by their ranking score.
+// The PageRank, IndexedAt and Language values of each document are read from
+// the document values of the respective fields which, unlike stored fields,
+// retain the full precision of the indexed values.
+func RankedQuery(q query.Query, r index.Ranking, lang *index.LanguagePreference, now time.Time) query.Query {
+	return &rankedQuery{
+		q:       q,
+		ranking: r,
+		lang:    lang,
+		now:     now,
+	}
+}
+
+type rankedQuery struct {
+	q       query.Query
+	ranking index.Ranking
+	lang    *index.LanguagePreference
+	now     time.Time
+}
+
+// Searcher implements query.Query.
+func (q *rankedQuery) Searcher(ctx context.Context, i bleveindex.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	searcher, err := q.q.Searcher(ctx, i, m, options)
+	if err != nil {
+		return nil, err
+	}
+
+	dvReader, err := i.DocValueReader([]string{PageRankField, IndexedAtField, LanguageField})
+	if err != nil {
+		_ = searcher.Close()
+		return nil, err
+	}
+
+	rs := &rankedSearcher{Searcher: searcher, query: q, dvReader: dvReader}
+	rs.visitor = rs.visitDocValue
+	return rs, nil
+}
+
+// rankedSearcher replaces the relevance score of the hits returned by the
+// wrapped searcher with their ranking score.
+type rankedSearcher struct {
+	search.Searcher
+
+	query    *rankedQuery
+	dvReader bleveindex.DocValueReader
+	visitor  bleveindex.DocValueVisitor
+
+	// The document values of the hit being ranked.
+	pageRank  float64
+	indexedAt time.Time
+	language  string
+}
+
+// Next implements search.Searcher.
+func (s *rankedSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	dm, err := s.Searcher.Next(ctx)
+	if err != nil || dm == nil {
+		return dm, err
+	}
+	return dm, s.rank(dm)
+}
+
+// Advance implements search.Searcher.
+func (s *rankedSearcher) Advance(ctx *search.SearchContext, id bleveindex.IndexInternalID) (*search.DocumentMatch, error) {
+	dm, err := s.Searcher.Advance(ctx, id)
+	if err != nil || dm == nil {
+		return dm, err
+	}
+	return dm, s.rank(dm)
+}
+
+func (s *rankedSearcher) rank(dm *search.DocumentMatch) error {
+	s.pageRank, s.indexedAt, s.language = 0, time.Time{}, ""
+	if err := s.dvReader.VisitDocValues(dm.IndexInternalID, s.visitor); err != nil {
+		return err
+	}
+
+	relevance := dm.Score
+	if lang := s.query.lang; lang != nil && !lang.IsFilter() && s.language == lang.Language {
+		relevance *= lang.Boost
+	}
+	dm.Score = s.query.ranking.Score(relevance, s.pageRank, s.indexedAt, s.query.now)
+	return nil
+}
+
+func (s *rankedSearcher) visitDocValue(field string, term []byte) {
+	switch field {
+	case PageRankField, IndexedAtField:
+		// Numeric values are indexed as a set of prefix-coded terms with
+		// different shifts; only the term with a zero shift holds the
+		// full-precision value.
+		if valid, shift := numeric.ValidPrefixCodedTermBytes(term); !valid || shift != 0 {
+			return
+		}
+		v, err := numeric.PrefixCoded(term).Int64()
+		if err != nil {
+			return
+		}
+		if field == PageRankField {
+			s.pageRank = numeric.Int64ToFloat64(v)
+		} else {
+			s.indexedAt = time.Unix(0, v)
+		}
+	case LanguageField:
+		s.language = string(term)
+	}
+}
@@ -0,0 +1,80 @@
+package blevequery
+
+import (
+	"unicode/utf8"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/blevesearch/bleve/v2"
+)
+
+// Suggest returns spelling suggestions for expression using the terms in
+// the dictionary of the default search field of idx.
+func Suggest(idx bleve.Index, expression string) ([]string, error) {
+	return index.Suggest(expression, func(terms []string) (map[string][]index.TermCandidate, error) {
+		return dictCandidates(idx, terms)
+	})
+}
+
+// dictCandidates scans the term dictionary of the default search field of
+// idx and collects the terms that are within index.MaxSuggestEditDistance
+// edits of each search term.
+func dictCandidates(idx bleve.Index, terms []string) (map[string][]index.TermCandidate, error) {
+	m := idx.Mapping()
+	field := m.DefaultSearchField()
+	analyzer := m.AnalyzerNamed(m.AnalyzerNameForPath(field))
+
+	// Index terms are stored in their analyzed form. Terms that are
+	// dropped by the analyzer (e.g. stop words) are never indexed and
+	// therefore cannot be corrected.
+	analyzedTerms := make(map[string]string, len(terms))
+	for _, term := range terms {
+		if tokens := analyzer.Analyze([]byte(term)); len(tokens) == 1 {
+			analyzedTerms[string(tokens[0].Term)] = term
+		}
+	}
+	if len(analyzedTerms) == 0 {
+		return nil, nil
+	}
+
+	dict, err := idx.FieldDict(field)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = dict.Close() }()
+
+	var (
+		candidates = make(map[string][]index.TermCandidate)
+		indexed    = make(map[string]bool)
+	)
+	for {
+		entry, err := dict.Next()
+		if err != nil {
+			return nil, err
+		} else if entry == nil {
+			break
+		} else if entry.Count == 0 {
+			continue
+		}
+
+		for analyzed, term := range analyzedTerms {
+			if entry.Term == analyzed {
+				indexed[term] = true
+				continue
+			}
+
+			// Skip the edit distance calculation for terms whose length
+			// difference exceeds the maximum distance.
+			if lenDiff := utf8.RuneCountInString(entry.Term) - utf8.RuneCountInString(analyzed); lenDiff > index.MaxSuggestEditDistance || -lenDiff > index.MaxSuggestEditDistance {
+				continue
+			}
+			if index.EditDistance(analyzed, entry.Term) <= index.MaxSuggestEditDistance {
+				candidates[term] = append(candidates[term], index.TermCandidate{Term: entry.Term, DocCount: entry.Count})
+			}
+		}
+	}
+
+	for term := range indexed {
+		delete(candidates, term)
+	}
+	return candidates, nil
+}
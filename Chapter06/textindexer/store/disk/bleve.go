@@ -0,0 +1,408 @@
+package disk
+
+import (
+	"sync"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/blevequery"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// The number of documents removed by each batch operation when deleting
+// stale documents.
+const deleteBatchSize = 1000
+
+// The list of stored fields that need to be loaded for reconstructing an
+// index.Document from a search hit.
+var storedFields = []string{"URL", "Title", "Content", "Description", "Headings", "AnchorText", "Language", "IndexedAtNano", "PageRank"}
+
+// Compile-time check to ensure OnDiskBleveIndexer implements Indexer.
+var _ index.Indexer = (*OnDiskBleveIndexer)(nil)
+
+type bleveDoc struct {
+	URL         string
+	Host        string
+	Title       string
+	Content     string
+	Description string
+	Headings    []string
+	AnchorText  []string
+
+	Language        string
+	LanguageContent map[string]string
+
+	// bleve only retains the second-level precision of datetime fields so
+	// the exact indexing timestamp is stored in a separate field.
+	IndexedAt     time.Time
+	IndexedAtNano string
+
+	PageRank float64
+}
+
+// OnDiskBleveIndexer is an Indexer implementation that uses a bleve instance
+// persisted to a local directory to catalogue and search documents. Unlike
+// the in-memory indexer, all document fields are stored in the bleve index
+// itself so that the indexed documents survive process restarts.
+type OnDiskBleveIndexer struct {
+	// Serializes read-modify-write operations on documents.
+	mu sync.Mutex
+
+	idx bleve.Index
+}
+
+// NewOnDiskBleveIndexer opens the bleve index stored in dir or creates a new
+// one if dir does not contain an index.
+func NewOnDiskBleveIndexer(dir string) (*OnDiskBleveIndexer, error) {
+	return newOnDiskBleveIndexer(dir, nil)
+}
+
+// newOnDiskBleveIndexer works like NewOnDiskBleveIndexer but passes the
+// provided store config to bleve when a new index needs to be created.
+func newOnDiskBleveIndexer(dir string, kvconfig map[string]interface{}) (*OnDiskBleveIndexer, error) {
+	idx, err := bleve.Open(dir)
+	if err == bleve.ErrorIndexPathDoesNotExist || err == bleve.ErrorIndexMetaMissing {
+		idx, err = bleve.NewUsing(dir, indexMapping(), bleve.Config.DefaultIndexType, bleve.Config.DefaultKVStore, kvconfig)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("open bleve index: %w", err)
+	}
+
+	return &OnDiskBleveIndexer{idx: idx}, nil
+}
+
+// indexMapping returns the mapping for documents stored in the index. The
+// URL, Host and IndexedAt fields are excluded from the default search field
+// so that they can only be matched by site and date range queries or used
+// for calculating facets. The description, headings and anchor text fields
+// are matched separately by translated queries. The content of documents in
+// a supported language is additionally indexed using the analyzer for that
+// language.
+func indexMapping() mapping.IndexMapping {
+	urlMapping := bleve.NewKeywordFieldMapping()
+	urlMapping.IncludeInAll = false
+	hostMapping := bleve.NewKeywordFieldMapping()
+	hostMapping.IncludeInAll = false
+	hostMapping.Store = false
+	indexedAtMapping := bleve.NewDateTimeFieldMapping()
+	indexedAtMapping.IncludeInAll = false
+	indexedAtMapping.Store = false
+	indexedAtNanoMapping := bleve.NewKeywordFieldMapping()
+	indexedAtNanoMapping.Index = false
+	indexedAtNanoMapping.IncludeInAll = false
+
+	docMapping := bleve.NewDocumentStaticMapping()
+	docMapping.AddFieldMappingsAt(blevequery.URLField, urlMapping)
+	docMapping.AddFieldMappingsAt(blevequery.HostField, hostMapping)
+	docMapping.AddFieldMappingsAt(blevequery.IndexedAtField, indexedAtMapping)
+	docMapping.AddFieldMappingsAt("IndexedAtNano", indexedAtNanoMapping)
+	docMapping.AddFieldMappingsAt("Title", bleve.NewTextFieldMapping())
+	docMapping.AddFieldMappingsAt("Content", bleve.NewTextFieldMapping())
+	docMapping.AddFieldMappingsAt(blevequery.PageRankField, bleve.NewNumericFieldMapping())
+	blevequery.AddSecondaryFieldMappings(docMapping)
+	blevequery.AddLanguageMappings(docMapping)
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = docMapping
+	return m
+}
+
+// Close the indexer and release any allocated resources.
+func (i *OnDiskBleveIndexer) Close() error {
+	return i.idx.Close()
+}
+
+// Index inserts a new document to the index or updates the index entry
+// for and existing document.
+func (i *OnDiskBleveIndexer) Index(doc *index.Document) error {
+	if doc.LinkID == uuid.Nil {
+		return xerrors.Errorf("index: %w", index.ErrMissingLinkID)
+	}
+
+	doc.IndexedAt = time.Now().UTC()
+	dcopy := copyDoc(doc)
+	key := dcopy.LinkID.String()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// If updating, preserve existing PageRank score and anchor texts
+	orig, err := i.findByID(key)
+	if err == nil {
+		dcopy.PageRank = orig.PageRank
+		dcopy.AnchorText = orig.AnchorText
+	} else if !xerrors.Is(err, index.ErrNotFound) {
+		return xerrors.Errorf("index: %w", err)
+	}
+
+	if err := i.idx.Index(key, makeBleveDoc(dcopy)); err != nil {
+		return xerrors.Errorf("index: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID looks up a document by its link ID.
+func (i *OnDiskBleveIndexer) FindByID(linkID uuid.UUID) (*index.Document, error) {
+	return i.findByID(linkID.String())
+}
+
+// findByID looks up a document by its link UUID expressed as a string.
+func (i *OnDiskBleveIndexer) findByID(linkID string) (*index.Document, error) {
+	searchReq := bleve.NewSearchRequest(bleve.NewDocIDQuery([]string{linkID}))
+	searchReq.Fields = storedFields
+	rs, err := i.idx.Search(searchReq)
+	if err != nil {
+		return nil, xerrors.Errorf("find by ID: %w", err)
+	} else if len(rs.Hits) == 0 {
+		return nil, xerrors.Errorf("find by ID: %w", index.ErrNotFound)
+	}
+
+	doc, err := docFromHit(rs.Hits[0])
+	if err != nil {
+		return nil, xerrors.Errorf("find by ID: %w", err)
+	}
+	return doc, nil
+}
+
+// Search the index for a particular query and return back a result
+// iterator.
+func (i *OnDiskBleveIndexer) Search(q index.Query) (index.Iterator, error) {
+	bq := blevequery.Translate(q)
+	res, err := blevequery.Search(i.idx, bq, q, storedFields)
+	if err != nil {
+		return nil, xerrors.Errorf("search: %w", err)
+	}
+
+	facets, err := blevequery.Facets(i.idx, bq, q.Facets, res.Now())
+	if err != nil {
+		return nil, xerrors.Errorf("search: %w", err)
+	}
+
+	return &bleveIterator{
+		res:    res,
+		facets: facets,
+	}, nil
+}
+
+// UpdateScore updates the PageRank score for a document with the specified
+// link ID. If no such document exists, a placeholder document with the
+// provided score will be created.
+func (i *OnDiskBleveIndexer) UpdateScore(linkID uuid.UUID, score float64) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	key := linkID.String()
+	doc, err := i.findByID(key)
+	if xerrors.Is(err, index.ErrNotFound) {
+		doc = &index.Document{LinkID: linkID}
+	} else if err != nil {
+		return xerrors.Errorf("update score: %w", err)
+	}
+
+	doc.PageRank = score
+	if err := i.idx.Index(key, makeBleveDoc(doc)); err != nil {
+		return xerrors.Errorf("update score: %w", err)
+	}
+
+	return nil
+}
+
+// AddAnchorText records the text of a link pointing to the document with the
+// specified link ID. If no such document exists, a placeholder document with
+// the provided anchor text will be created.
+func (i *OnDiskBleveIndexer) AddAnchorText(linkID uuid.UUID, text string) error {
+	if err := i.addAnchorTexts(map[uuid.UUID][]string{linkID: {text}}); err != nil {
+		return xerrors.Errorf("add anchor text: %w", err)
+	}
+	return nil
+}
+
+// AddAnchorTexts records the texts of links pointing to the documents with the
+// specified link IDs using a single bleve batch. If any of the documents does
+// not exist, a placeholder document with the provided anchor texts will be
+// created.
+func (i *OnDiskBleveIndexer) AddAnchorTexts(texts map[uuid.UUID][]string) error {
+	if err := i.addAnchorTexts(texts); err != nil {
+		return xerrors.Errorf("add anchor texts: %w", err)
+	}
+	return nil
+}
+
+func (i *OnDiskBleveIndexer) addAnchorTexts(texts map[uuid.UUID][]string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	batch := i.idx.NewBatch()
+	for linkID, list := range texts {
+		key := linkID.String()
+		doc, err := i.findByID(key)
+		if xerrors.Is(err, index.ErrNotFound) {
+			doc = &index.Document{LinkID: linkID}
+		} else if err != nil {
+			return err
+		}
+
+		var changed bool
+		if doc.AnchorText, changed = index.AppendAnchorTexts(doc.AnchorText, list); !changed {
+			continue
+		}
+		if err := batch.Index(key, makeBleveDoc(doc)); err != nil {
+			return err
+		}
+	}
+
+	if batch.Size() == 0 {
+		return nil
+	}
+	return i.idx.Batch(batch)
+}
+
+// Delete removes the document with the specified link ID from the index.
+// Deleting a document that does not exist is not an error.
+func (i *OnDiskBleveIndexer) Delete(linkID uuid.UUID) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if err := i.idx.Delete(linkID.String()); err != nil {
+		return xerrors.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
+// DeleteOlderThan removes all documents that were last indexed before the
+// specified timestamp. Placeholder documents created by UpdateScore for
+// links that have not been indexed yet are retained.
+func (i *OnDiskBleveIndexer) DeleteOlderThan(t time.Time) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// Placeholder documents do not have an IndexedAt field and are
+	// therefore never matched by the range query.
+	exclusive := false
+	staleQuery := bleve.NewDateRangeInclusiveQuery(time.Time{}, t, nil, &exclusive)
+	staleQuery.SetField(blevequery.IndexedAtField)
+
+	for {
+		rs, err := i.idx.Search(bleve.NewSearchRequestOptions(staleQuery, deleteBatchSize, 0, false))
+		if err != nil {
+			return xerrors.Errorf("delete older than: %w", err)
+		} else if len(rs.Hits) == 0 {
+			return nil
+		}
+
+		batch := i.idx.NewBatch()
+		for _, hit := range rs.Hits {
+			batch.Delete(hit.ID)
+		}
+		if err = i.idx.Batch(batch); err != nil {
+			return xerrors.Errorf("delete older than: %w", err)
+		}
+	}
+}
+
+// Suggest returns up to index.MaxSuggestions alternative spellings for the
+// terms of a search expression that are not present in the index.
+func (i *OnDiskBleveIndexer) Suggest(expression string) ([]string, error) {
+	suggestions, err := blevequery.Suggest(i.idx, expression)
+	if err != nil {
+		return nil, xerrors.Errorf("suggest: %w", err)
+	}
+	return suggestions, nil
+}
+
+// Complete returns up to index.MaxCompletions completions for a partially
+// typed search expression.
+func (i *OnDiskBleveIndexer) Complete(expression string) ([]string, error) {
+	completions, err := blevequery.Complete(i.idx, expression)
+	if err != nil {
+		return nil, xerrors.Errorf("complete: %w", err)
+	}
+	return completions, nil
+}
+
+func copyDoc(d *index.Document) *index.Document {
+	dcopy := new(index.Document)
+	*dcopy = *d
+	return dcopy
+}
+
+func makeBleveDoc(d *index.Document) bleveDoc {
+	var indexedAtNano string
+	if !d.IndexedAt.IsZero() {
+		indexedAtNano = d.IndexedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return bleveDoc{
+		URL:             d.URL,
+		Host:            blevequery.URLHost(d.URL),
+		Title:           d.Title,
+		Content:         d.Content,
+		Description:     d.Description,
+		Headings:        d.Headings,
+		AnchorText:      d.AnchorText,
+		Language:        d.Language,
+		LanguageContent: blevequery.LanguageContent(d),
+		IndexedAt:       d.IndexedAt,
+		IndexedAtNano:   indexedAtNano,
+		PageRank:        d.PageRank,
+	}
+}
+
+// docFromHit reconstructs an index.Document from the stored fields of a
+// search hit.
+func docFromHit(hit *search.DocumentMatch) (*index.Document, error) {
+	linkID, err := uuid.Parse(hit.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &index.Document{
+		LinkID:      linkID,
+		URL:         stringField(hit, "URL"),
+		Title:       stringField(hit, "Title"),
+		Content:     stringField(hit, "Content"),
+		Description: stringField(hit, "Description"),
+		Headings:    stringsField(hit, "Headings"),
+		AnchorText:  stringsField(hit, "AnchorText"),
+		Language:    stringField(hit, "Language"),
+	}
+	if pageRank, ok := hit.Fields["PageRank"].(float64); ok {
+		doc.PageRank = pageRank
+	}
+	if indexedAt := stringField(hit, "IndexedAtNano"); indexedAt != "" {
+		if doc.IndexedAt, err = time.Parse(time.RFC3339Nano, indexedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+func stringField(hit *search.DocumentMatch, name string) string {
+	v, _ := hit.Fields[name].(string)
+	return v
+}
+
+// stringsField returns the values of a stored field that holds a list of
+// strings. bleve returns a single string for lists with one element.
+func stringsField(hit *search.DocumentMatch, name string) []string {
+	switch v := hit.Fields[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
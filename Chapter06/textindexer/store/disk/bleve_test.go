@@ -0,0 +1,79 @@
+package disk
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index/indextest"
+	"github.com/google/uuid"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(OnDiskBleveTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type OnDiskBleveTestSuite struct {
+	indextest.SuiteBase
+	dir string
+	idx *OnDiskBleveIndexer
+}
+
+func (s *OnDiskBleveTestSuite) SetUpTest(c *gc.C) {
+	dir, err := ioutil.TempDir("", "bleve-indexer-test")
+	c.Assert(err, gc.IsNil)
+	s.dir = dir
+
+	// Don't wait for each write to be synced to disk as this makes the
+	// test suite painfully slow. TestPersistenceAcrossReopen covers the
+	// default settings.
+	s.idx, err = newOnDiskBleveIndexer(dir, map[string]interface{}{"unsafe_batch": true})
+	c.Assert(err, gc.IsNil)
+	s.SetIndexer(s.idx)
+}
+
+func (s *OnDiskBleveTestSuite) TearDownTest(c *gc.C) {
+	c.Assert(s.idx.Close(), gc.IsNil)
+	c.Assert(os.RemoveAll(s.dir), gc.IsNil)
+}
+
+func (s *OnDiskBleveTestSuite) TestPersistenceAcrossReopen(c *gc.C) {
+	// Replace the index created by SetUpTest with one that uses the
+	// default settings.
+	c.Assert(s.idx.Close(), gc.IsNil)
+	c.Assert(os.RemoveAll(s.dir), gc.IsNil)
+	var err error
+	s.idx, err = NewOnDiskBleveIndexer(s.dir)
+	c.Assert(err, gc.IsNil)
+	s.SetIndexer(s.idx)
+
+	doc := &index.Document{
+		LinkID:  uuid.New(),
+		URL:     "http://example.com",
+		Title:   "Illustrious examples",
+		Content: "Lorem ipsum dolor",
+	}
+	c.Assert(s.idx.Index(doc), gc.IsNil)
+	c.Assert(s.idx.UpdateScore(doc.LinkID, 0.5), gc.IsNil)
+	doc.PageRank = 0.5
+
+	// Reopen the index and check that the document is still there.
+	c.Assert(s.idx.Close(), gc.IsNil)
+	s.idx, err = NewOnDiskBleveIndexer(s.dir)
+	c.Assert(err, gc.IsNil)
+	s.SetIndexer(s.idx)
+
+	got, err := s.idx.FindByID(doc.LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.DeepEquals, doc)
+
+	it, err := s.idx.Search(index.Query{Type: index.QueryTypeMatch, Expression: "ipsum"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Document(), gc.DeepEquals, doc)
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
@@ -0,0 +1,65 @@
+package disk
+
+import (
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/blevequery"
+)
+
+// bleveIterator implements index.Iterator.
+type bleveIterator struct {
+	// The ranked hits to iterate.
+	res *blevequery.Results
+
+	latchedDoc *index.Document
+	lastErr    error
+
+	facets []index.FacetResult
+}
+
+// Close the iterator and release any allocated resources.
+func (it *bleveIterator) Close() error {
+	it.res.Close()
+	return nil
+}
+
+// Next loads the next document matching the search query.
+// It returns false if no more documents are available.
+func (it *bleveIterator) Next() bool {
+	if it.lastErr != nil {
+		return false
+	}
+
+	if !it.res.Next() {
+		it.lastErr = it.res.Error()
+		return false
+	}
+
+	it.latchedDoc, it.lastErr = docFromHit(it.res.Hit())
+	return it.lastErr == nil
+}
+
+// Error returns the last error encountered by the iterator.
+func (it *bleveIterator) Error() error {
+	return it.lastErr
+}
+
+// Document returns the current document from the result set.
+func (it *bleveIterator) Document() *index.Document {
+	return it.latchedDoc
+}
+
+// TotalCount returns the approximate number of search results.
+func (it *bleveIterator) TotalCount() uint64 {
+	return it.res.Total()
+}
+
+// Facets returns the bucket counts for the facets requested by the search
+// query.
+func (it *bleveIterator) Facets() []index.FacetResult {
+	return it.facets
+}
+
+// Cursor returns the opaque cursor for the current document.
+func (it *bleveIterator) Cursor() string {
+	return it.res.Cursor()
+}
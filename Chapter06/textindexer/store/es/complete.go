@@ -0,0 +1,114 @@
+package es
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+)
+
+// The number of matching documents that are sampled for collecting term
+// completions. As text fields do not expose their term dictionaries, the
+// completions for a prefix are extracted from the highlighted fragments of
+// the best matching documents.
+const completeSampleSize = 50
+
+// The tags that wrap the highlighted terms in each fragment.
+const (
+	highlightPreTag  = "<em>"
+	highlightPostTag = "</em>"
+)
+
+var highlightedTermRegex = regexp.MustCompile(regexp.QuoteMeta(highlightPreTag) + `(.*?)` + regexp.QuoteMeta(highlightPostTag))
+
+// prefixTermsQuery returns a search request that highlights the terms
+// starting with prefix in the suggestFields of a sample of the documents
+// that contain such terms.
+func prefixTermsQuery(prefix string) map[string]interface{} {
+	should := make([]map[string]interface{}, len(suggestFields))
+	highlightFields := make(map[string]interface{}, len(suggestFields))
+	for i, field := range suggestFields {
+		should[i] = map[string]interface{}{
+			"prefix": map[string]interface{}{field: prefix},
+		}
+		highlightFields[field] = map[string]interface{}{}
+	}
+
+	return map[string]interface{}{
+		"size":    completeSampleSize,
+		"_source": false,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{"should": should},
+		},
+		"highlight": map[string]interface{}{
+			"pre_tags":            []string{highlightPreTag},
+			"post_tags":           []string{highlightPostTag},
+			"fragment_size":       20,
+			"number_of_fragments": index.MaxCompletions,
+			"fields":              highlightFields,
+		},
+	}
+}
+
+// prefixTerms extracts the terms that start with prefix from the response
+// to a request built by prefixTermsQuery. The document count of each term
+// refers to the sampled documents.
+func prefixTerms(prefix string, res *esSearchRes) []index.TermCandidate {
+	var (
+		docCounts = make(map[string]uint64)
+		terms     []string
+	)
+	for _, hit := range res.Hits.HitList {
+		inDoc := make(map[string]bool)
+		for _, field := range suggestFields {
+			for _, fragment := range hit.Highlight[field] {
+				for _, match := range highlightedTermRegex.FindAllStringSubmatch(fragment, -1) {
+					term := strings.ToLower(match[1])
+					if inDoc[term] || !strings.HasPrefix(term, prefix) {
+						continue
+					}
+					inDoc[term] = true
+					if docCounts[term] == 0 {
+						terms = append(terms, term)
+					}
+					docCounts[term]++
+				}
+			}
+		}
+	}
+
+	candidates := make([]index.TermCandidate, len(terms))
+	for i, term := range terms {
+		candidates[i] = index.TermCandidate{Term: term, DocCount: docCounts[term]}
+	}
+	return candidates
+}
+
+// titlesQuery returns a search request for the titles of up to limit
+// documents whose titles contain all words and a term that starts with
+// prefix. Documents with a higher PageRank score are preferred.
+func titlesQuery(words []string, prefix string, limit int) map[string]interface{} {
+	var must []map[string]interface{}
+	for _, word := range words {
+		must = append(must, map[string]interface{}{
+			"match": map[string]interface{}{"Title": word},
+		})
+	}
+	if prefix != "" {
+		must = append(must, map[string]interface{}{
+			"prefix": map[string]interface{}{"Title": prefix},
+		})
+	}
+
+	return map[string]interface{}{
+		"size":    limit,
+		"_source": []string{"Title"},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{"must": must},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"PageRank": map[string]interface{}{"order": "desc", "missing": "_last"}},
+			"_score",
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package es
+
+import (
+	"encoding/json"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(CompleteTestSuite))
+
+type CompleteTestSuite struct{}
+
+func (s *CompleteTestSuite) TestPrefixTermsQuery(c *gc.C) {
+	got, err := json.Marshal(prefixTermsQuery("ips"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, `{`+
+		`"_source":false,`+
+		`"highlight":{"fields":{"Content":{},"Title":{}},"fragment_size":20,"number_of_fragments":8,"post_tags":["\u003c/em\u003e"],"pre_tags":["\u003cem\u003e"]},`+
+		`"query":{"bool":{"should":[{"prefix":{"Title":"ips"}},{"prefix":{"Content":"ips"}}]}},`+
+		`"size":50}`,
+	)
+}
+
+func (s *CompleteTestSuite) TestPrefixTerms(c *gc.C) {
+	var res esSearchRes
+	err := json.Unmarshal([]byte(`{
+  "hits": {
+    "hits": [
+      {"highlight": {"Title": ["<em>Ipsum</em> dolor"], "Content": ["lorem <em>ipsum</em> <em>ipsam</em>"]}},
+      {"highlight": {"Content": ["<em>ipsum</em> sit"]}},
+      {"highlight": {"Content": ["<em>other</em>"]}}
+    ]
+  }
+}`), &res)
+	c.Assert(err, gc.IsNil)
+
+	got := prefixTerms("ips", &res)
+	c.Assert(got, gc.DeepEquals, []index.TermCandidate{
+		{Term: "ipsum", DocCount: 2},
+		{Term: "ipsam", DocCount: 1},
+	})
+}
+
+func (s *CompleteTestSuite) TestTitlesQuery(c *gc.C) {
+	got, err := json.Marshal(titlesQuery([]string{"lorem"}, "ips", 4))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, `{`+
+		`"_source":["Title"],`+
+		`"query":{"bool":{"must":[{"match":{"Title":"lorem"}},{"prefix":{"Title":"ips"}}]}},`+
+		`"size":4,`+
+		`"sort":[{"PageRank":{"missing":"_last","order":"desc"}},"_score"]}`,
+	)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -27,8 +28,24 @@ var esMappings = `
     "properties": {
       "LinkID": {"type": "keyword"},
       "URL": {"type": "keyword"},
+      "Host": {"type": "keyword"},
       "Content": {"type": "text"},
       "Title": {"type": "text"},
+      "Description": {"type": "text"},
+      "Headings": {"type": "text"},
+      "AnchorText": {"type": "text"},
+      "Language": {"type": "keyword"},
+      "LanguageContent": {
+        "properties": {
+          "de": {"type": "text", "analyzer": "german"},
+          "en": {"type": "text", "analyzer": "english"},
+          "es": {"type": "text", "analyzer": "spanish"},
+          "fr": {"type": "text", "analyzer": "french"},
+          "it": {"type": "text", "analyzer": "italian"},
+          "nl": {"type": "text", "analyzer": "dutch"},
+          "pt": {"type": "text", "analyzer": "portuguese"}
+        }
+      },
       "IndexedAt": {"type": "date"},
       "PageRank": {"type": "double"}
     }
@@ -36,7 +53,9 @@ var esMappings = `
 }`
 
 type esSearchRes struct {
-	Hits esSearchResHits `json:"hits"`
+	Hits         esSearchResHits             `json:"hits"`
+	Aggregations map[string]esAggregation    `json:"aggregations"`
+	Suggest      map[string][]esSuggestEntry `json:"suggest"`
 }
 
 type esSearchResHits struct {
@@ -49,22 +68,55 @@ type esTotal struct {
 }
 
 type esHitWrapper struct {
-	DocSource esDoc `json:"_source"`
+	DocSource esDoc               `json:"_source"`
+	Highlight map[string][]string `json:"highlight"`
+	Sort      []interface{}       `json:"sort"`
 }
 
 type esDoc struct {
-	LinkID    string    `json:"LinkID"`
-	URL       string    `json:"URL"`
-	Title     string    `json:"Title"`
-	Content   string    `json:"Content"`
-	IndexedAt time.Time `json:"IndexedAt"`
-	PageRank  float64   `json:"PageRank,omitempty"`
+	LinkID      string    `json:"LinkID"`
+	URL         string    `json:"URL"`
+	Host        string    `json:"Host"`
+	Title       string    `json:"Title"`
+	Content     string    `json:"Content"`
+	Description string    `json:"Description"`
+	Headings    []string  `json:"Headings"`
+	AnchorText  []string  `json:"AnchorText,omitempty"`
+	Language    string    `json:"Language"`
+	IndexedAt   time.Time `json:"IndexedAt"`
+	PageRank    float64   `json:"PageRank,omitempty"`
+
+	LanguageContent map[string]interface{} `json:"LanguageContent,omitempty"`
 }
 
 type esUpdateRes struct {
 	Result string `json:"result"`
 }
 
+type esBulkRes struct {
+	Errors bool                       `json:"errors"`
+	Items  []map[string]esBulkResItem `json:"items"`
+}
+
+type esBulkResItem struct {
+	Error *esError `json:"error"`
+}
+
+// firstError returns the error for the first failed item of a bulk request.
+func (r esBulkRes) firstError() error {
+	if !r.Errors {
+		return nil
+	}
+	for _, item := range r.Items {
+		for _, res := range item {
+			if res.Error != nil {
+				return *res.Error
+			}
+		}
+	}
+	return nil
+}
+
 type esErrorRes struct {
 	Error esError `json:"error"`
 }
@@ -84,8 +136,9 @@ var _ index.Indexer = (*ElasticSearchIndexer)(nil)
 // ElasticSearchIndexer is an Indexer implementation that uses an elastic search
 // instance to catalogue and search documents.
 type ElasticSearchIndexer struct {
-	es         *elasticsearch.Client
-	refreshOpt func(*esapi.UpdateRequest)
+	es          *elasticsearch.Client
+	refreshOpt  func(*esapi.UpdateRequest)
+	syncUpdates bool
 }
 
 // NewElasticSearchIndexer creates a text indexer that uses an in-memory
@@ -109,8 +162,9 @@ func NewElasticSearchIndexer(esNodes []string, syncUpdates bool) (*ElasticSearch
 	}
 
 	return &ElasticSearchIndexer{
-		es:         es,
-		refreshOpt: refreshOpt,
+		es:          es,
+		refreshOpt:  refreshOpt,
+		syncUpdates: syncUpdates,
 	}, nil
 }
 
@@ -177,41 +231,37 @@ func (i *ElasticSearchIndexer) FindByID(linkID uuid.UUID) (*index.Document, erro
 // Search the index for a particular query and return back a result
 // iterator.
 func (i *ElasticSearchIndexer) Search(q index.Query) (index.Iterator, error) {
-	var qtype string
-	switch q.Type {
-	case index.QueryTypePhrase:
-		qtype = "phrase"
-	default:
-		qtype = "best_fields"
-	}
-
 	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"function_score": map[string]interface{}{
-				"query": map[string]interface{}{
-					"multi_match": map[string]interface{}{
-						"type":   qtype,
-						"query":  q.Expression,
-						"fields": []string{"Title", "Content"},
-					},
-				},
-				"script_score": map[string]interface{}{
-					"script": map[string]interface{}{
-						"source": "_score + doc['PageRank'].value",
-					},
-				},
-			},
-		},
-		"from": q.Offset,
 		"size": batchSize,
 	}
+	firstPos, now, err := applyPagination(query, q)
+	if err != nil {
+		return nil, xerrors.Errorf("search: %w", err)
+	}
+	query["query"] = rankedQuery(q, now)
+
+	if aggs := facetAggregations(q.Facets, now); len(aggs) != 0 {
+		query["aggs"] = aggs
+	}
 
 	searchRes, err := runSearch(i.es, query)
 	if err != nil {
 		return nil, xerrors.Errorf("search: %w", err)
 	}
 
-	return &esIterator{es: i.es, searchReq: query, rs: searchRes, cumIdx: q.Offset}, nil
+	// Aggregations only need to be calculated for the first page of results.
+	delete(query, "aggs")
+
+	return &esIterator{
+		es:        i.es,
+		searchReq: query,
+		rs:        searchRes,
+		total:     searchRes.Hits.Total.Count,
+		nextPos:   firstPos,
+		backward:  q.Cursor != "" && q.Backward,
+		now:       now,
+		facets:    facetResults(q.Facets, searchRes.Aggregations, now),
+	}, nil
 }
 
 // UpdateScore updates the PageRank score for a document with the
@@ -243,6 +293,217 @@ func (i *ElasticSearchIndexer) UpdateScore(linkID uuid.UUID, score float64) erro
 	return nil
 }
 
+// The painless script used by AddAnchorText and AddAnchorTexts for appending
+// anchor texts to an existing document.
+const addAnchorTextsScript = `
+if (ctx._source.AnchorText == null) {
+  ctx._source.AnchorText = [];
+}
+boolean changed = false;
+for (String text : params.texts) {
+  if (ctx._source.AnchorText.size() >= params.maxAnchorTexts) {
+    break;
+  }
+  boolean exists = false;
+  for (String existing : ctx._source.AnchorText) {
+    if (existing.equalsIgnoreCase(text)) {
+      exists = true;
+      break;
+    }
+  }
+  if (!exists) {
+    ctx._source.AnchorText.add(text);
+    changed = true;
+  }
+}
+if (!changed) {
+  ctx.op = 'noop';
+}`
+
+// AddAnchorText records the text of a link pointing to the document with the
+// specified link ID. If no such document exists, a placeholder document with
+// the provided anchor text will be created.
+func (i *ElasticSearchIndexer) AddAnchorText(linkID uuid.UUID, text string) error {
+	if err := i.addAnchorTexts(map[uuid.UUID][]string{linkID: {text}}); err != nil {
+		return xerrors.Errorf("add anchor text: %w", err)
+	}
+	return nil
+}
+
+// AddAnchorTexts records the texts of links pointing to the documents with the
+// specified link IDs using a single bulk request. If any of the documents does
+// not exist, a placeholder document with the provided anchor texts will be
+// created.
+func (i *ElasticSearchIndexer) AddAnchorTexts(texts map[uuid.UUID][]string) error {
+	if err := i.addAnchorTexts(texts); err != nil {
+		return xerrors.Errorf("add anchor texts: %w", err)
+	}
+	return nil
+}
+
+func (i *ElasticSearchIndexer) addAnchorTexts(texts map[uuid.UUID][]string) error {
+	var (
+		buf bytes.Buffer
+		enc = json.NewEncoder(&buf)
+	)
+	for linkID, list := range texts {
+		// Drop blank and duplicate texts before sending them over.
+		list, _ = index.AppendAnchorTexts(nil, list)
+		if len(list) == 0 {
+			continue
+		}
+
+		action := map[string]interface{}{
+			"update": map[string]interface{}{"_index": indexName, "_id": linkID.String()},
+		}
+		update := map[string]interface{}{
+			"script": map[string]interface{}{
+				"source": addAnchorTextsScript,
+				"lang":   "painless",
+				"params": map[string]interface{}{
+					"texts":          list,
+					"maxAnchorTexts": index.MaxAnchorTexts,
+				},
+			},
+			"upsert": map[string]interface{}{
+				"LinkID":     linkID.String(),
+				"AnchorText": list,
+			},
+		}
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+		if err := enc.Encode(update); err != nil {
+			return err
+		}
+	}
+
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	refresh := "false"
+	if i.syncUpdates {
+		refresh = "true"
+	}
+	res, err := i.es.Bulk(&buf, i.es.Bulk.WithRefresh(refresh))
+	if err != nil {
+		return err
+	}
+
+	var bulkRes esBulkRes
+	if err = unmarshalResponse(res, &bulkRes); err != nil {
+		return err
+	}
+	return bulkRes.firstError()
+}
+
+// Delete removes the document with the specified link ID from the index.
+// Deleting a document that does not exist is not an error.
+func (i *ElasticSearchIndexer) Delete(linkID uuid.UUID) error {
+	refresh := "false"
+	if i.syncUpdates {
+		refresh = "true"
+	}
+
+	res, err := i.es.Delete(indexName, linkID.String(), i.es.Delete.WithRefresh(refresh))
+	if err != nil {
+		return xerrors.Errorf("delete: %w", err)
+	} else if res.StatusCode == http.StatusNotFound {
+		_ = res.Body.Close()
+		return nil
+	}
+
+	var updateRes esUpdateRes
+	if err = unmarshalResponse(res, &updateRes); err != nil {
+		return xerrors.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteOlderThan removes all documents that were last indexed before the
+// specified timestamp. Placeholder documents created by UpdateScore for
+// links that have not been indexed yet are retained.
+func (i *ElasticSearchIndexer) DeleteOlderThan(t time.Time) error {
+	var buf bytes.Buffer
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"range": map[string]interface{}{
+				"IndexedAt": map[string]interface{}{
+					"lt": t.UTC().Format(time.RFC3339Nano),
+				},
+			},
+		},
+	}
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return xerrors.Errorf("delete older than: %w", err)
+	}
+
+	res, err := i.es.DeleteByQuery(
+		[]string{indexName},
+		&buf,
+		i.es.DeleteByQuery.WithConflicts("proceed"),
+		i.es.DeleteByQuery.WithRefresh(i.syncUpdates),
+	)
+	if err != nil {
+		return xerrors.Errorf("delete older than: %w", err)
+	}
+
+	var deleteRes map[string]interface{}
+	if err = unmarshalResponse(res, &deleteRes); err != nil {
+		return xerrors.Errorf("delete older than: %w", err)
+	}
+
+	return nil
+}
+
+// Suggest returns up to index.MaxSuggestions alternative spellings for the
+// terms of a search expression that are not present in the index.
+func (i *ElasticSearchIndexer) Suggest(expression string) ([]string, error) {
+	suggestions, err := index.Suggest(expression, func(terms []string) (map[string][]index.TermCandidate, error) {
+		searchRes, err := runSearch(i.es, suggestQuery(terms))
+		if err != nil {
+			return nil, err
+		}
+		return suggestCandidates(terms, searchRes), nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("suggest: %w", err)
+	}
+	return suggestions, nil
+}
+
+// Complete returns up to index.MaxCompletions completions for a partially
+// typed search expression.
+func (i *ElasticSearchIndexer) Complete(expression string) ([]string, error) {
+	completions, err := index.Complete(
+		expression,
+		func(prefix string) ([]index.TermCandidate, error) {
+			searchRes, err := runSearch(i.es, prefixTermsQuery(prefix))
+			if err != nil {
+				return nil, err
+			}
+			return prefixTerms(prefix, searchRes), nil
+		},
+		func(words []string, prefix string, limit int) ([]string, error) {
+			searchRes, err := runSearch(i.es, titlesQuery(words, prefix, limit))
+			if err != nil {
+				return nil, err
+			}
+			titles := make([]string, len(searchRes.Hits.HitList))
+			for j, hit := range searchRes.Hits.HitList {
+				titles[j] = hit.DocSource.Title
+			}
+			return titles, nil
+		},
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("complete: %w", err)
+	}
+	return completions, nil
+}
+
 func ensureIndex(es *elasticsearch.Client) error {
 	mappingsReader := strings.NewReader(esMappings)
 	res, err := es.Indices.Create(indexName, es.Indices.Create.WithBody(mappingsReader))
@@ -251,12 +512,36 @@ func ensureIndex(es *elasticsearch.Client) error {
 	} else if res.IsError() {
 		err := unmarshalError(res)
 		if esErr, valid := err.(esError); valid && esErr.Type == "resource_already_exists_exception" {
-			return nil
+			return updateMapping(es)
 		}
 		return xerrors.Errorf("cannot create ES index: %w", err)
 	}
 
-	return nil
+	return res.Body.Close()
+}
+
+// updateMapping applies esMappings to an existing index so that any fields
+// added after the index was created get mapped before documents are indexed.
+// Elasticsearch rejects the update if the existing mapping of a field
+// conflicts with esMappings, e.g. because the field was dynamically mapped
+// with a different type or its analyzer has changed. In that case the index
+// needs to be re-created and its documents re-indexed.
+func updateMapping(es *elasticsearch.Client) error {
+	var body struct {
+		Mappings json.RawMessage `json:"mappings"`
+	}
+	if err := json.Unmarshal([]byte(esMappings), &body); err != nil {
+		return xerrors.Errorf("cannot update ES index mapping: %w", err)
+	}
+
+	res, err := es.Indices.PutMapping(bytes.NewReader(body.Mappings), es.Indices.PutMapping.WithIndex(indexName))
+	if err != nil {
+		return xerrors.Errorf("cannot update ES index mapping: %w", err)
+	} else if res.IsError() {
+		return xerrors.Errorf("ES index %q is incompatible with the expected mapping and must be re-created: %w", indexName, unmarshalError(res))
+	}
+
+	return res.Body.Close()
 }
 
 func runSearch(es *elasticsearch.Client, searchQuery map[string]interface{}) (*esSearchRes, error) {
@@ -304,23 +589,33 @@ func unmarshalResponse(res *esapi.Response, to interface{}) error {
 
 func mapEsDoc(d *esDoc) *index.Document {
 	return &index.Document{
-		LinkID:    uuid.MustParse(d.LinkID),
-		URL:       d.URL,
-		Title:     d.Title,
-		Content:   d.Content,
-		IndexedAt: d.IndexedAt.UTC(),
-		PageRank:  d.PageRank,
+		LinkID:      uuid.MustParse(d.LinkID),
+		URL:         d.URL,
+		Title:       d.Title,
+		Content:     d.Content,
+		Description: d.Description,
+		Headings:    d.Headings,
+		AnchorText:  d.AnchorText,
+		Language:    d.Language,
+		IndexedAt:   d.IndexedAt.UTC(),
+		PageRank:    d.PageRank,
 	}
 }
 
 func makeEsDoc(d *index.Document) esDoc {
-	// Note: we intentionally skip PageRank as we don't want updates to
-	// overwrite existing PageRank values.
+	// Note: we intentionally skip PageRank and AnchorText as we don't want
+	// updates to overwrite existing PageRank values and anchor texts.
 	return esDoc{
-		LinkID:    d.LinkID.String(),
-		URL:       d.URL,
-		Title:     d.Title,
-		Content:   d.Content,
-		IndexedAt: d.IndexedAt.UTC(),
+		LinkID:      d.LinkID.String(),
+		URL:         d.URL,
+		Host:        urlHost(d.URL),
+		Title:       d.Title,
+		Content:     d.Content,
+		Description: d.Description,
+		Headings:    d.Headings,
+		Language:    d.Language,
+		IndexedAt:   d.IndexedAt.UTC(),
+
+		LanguageContent: languageContent(d),
 	}
 }
@@ -38,3 +38,37 @@ func (s *ElasticSearchTestSuite) SetUpTest(c *gc.C) {
 		c.Assert(err, gc.IsNil)
 	}
 }
+
+func (s *ElasticSearchTestSuite) TestEnsureIndexUpdatesMapping(c *gc.C) {
+	// Simulate an index created before the Host field was introduced.
+	_, err := s.idx.es.Indices.Delete([]string{indexName})
+	c.Assert(err, gc.IsNil)
+	res, err := s.idx.es.Indices.Create(indexName, s.idx.es.Indices.Create.WithBody(strings.NewReader(
+		`{"mappings": {"properties": {"URL": {"type": "keyword"}}}}`,
+	)))
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.IsError(), gc.Equals, false, gc.Commentf(res.String()))
+	c.Assert(ensureIndex(s.idx.es), gc.IsNil)
+
+	res, err = s.idx.es.Indices.GetFieldMapping([]string{"Host"}, s.idx.es.Indices.GetFieldMapping.WithIndex(indexName))
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.IsError(), gc.Equals, false, gc.Commentf(res.String()))
+	c.Assert(strings.Contains(res.String(), `"type":"keyword"`), gc.Equals, true, gc.Commentf(res.String()))
+
+	// Ensuring an index that is already up to date is a no-op.
+	c.Assert(ensureIndex(s.idx.es), gc.IsNil)
+}
+
+func (s *ElasticSearchTestSuite) TestEnsureIndexWithConflictingMapping(c *gc.C) {
+	// Simulate an index where the Host field was dynamically mapped.
+	_, err := s.idx.es.Indices.Delete([]string{indexName})
+	c.Assert(err, gc.IsNil)
+	res, err := s.idx.es.Indices.Create(indexName, s.idx.es.Indices.Create.WithBody(strings.NewReader(
+		`{"mappings": {"properties": {"Host": {"type": "text"}}}}`,
+	)))
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.IsError(), gc.Equals, false, gc.Commentf(res.String()))
+
+	err = ensureIndex(s.idx.es)
+	c.Assert(err, gc.ErrorMatches, ".*incompatible with the expected mapping.*")
+}
@@ -0,0 +1,124 @@
+package es
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+)
+
+type esAggregation struct {
+	Buckets []esBucket `json:"buckets"`
+}
+
+type esBucket struct {
+	Key      string `json:"key"`
+	DocCount uint64 `json:"doc_count"`
+}
+
+// facetAggregations converts a list of facet requests into elasticsearch
+// aggregations. The buckets for IndexedAt facets are calculated relative to
+// now.
+func facetAggregations(reqs []index.FacetRequest, now time.Time) map[string]interface{} {
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	aggs := make(map[string]interface{}, len(reqs))
+	for _, req := range reqs {
+		switch req.Type {
+		case index.FacetTypeHost:
+			aggs[req.Name] = map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "Host",
+					"size":  req.Limit(),
+				},
+			}
+		case index.FacetTypeIndexedAt:
+			var ranges []map[string]interface{}
+			for _, r := range req.DateRanges(now) {
+				ranges = append(ranges, map[string]interface{}{
+					"key":  r.Key,
+					"from": r.From.Format(time.RFC3339),
+					"to":   r.To.Format(time.RFC3339),
+				})
+			}
+			aggs[req.Name] = map[string]interface{}{
+				"date_range": map[string]interface{}{
+					"field":  "IndexedAt",
+					"ranges": ranges,
+				},
+			}
+		case index.FacetTypePageRank:
+			var ranges []map[string]interface{}
+			for _, r := range req.NumericRanges() {
+				spec := map[string]interface{}{"key": r.Key}
+				if r.From != nil {
+					spec["from"] = *r.From
+				}
+				if r.To != nil {
+					spec["to"] = *r.To
+				}
+				ranges = append(ranges, spec)
+			}
+			if len(ranges) == 0 {
+				continue
+			}
+			aggs[req.Name] = map[string]interface{}{
+				"range": map[string]interface{}{
+					"field":  "PageRank",
+					"ranges": ranges,
+				},
+			}
+		}
+	}
+
+	return aggs
+}
+
+// facetResults converts the aggregations returned by elasticsearch into a
+// list of index.FacetResult values with the same order as the facet
+// requests.
+func facetResults(reqs []index.FacetRequest, aggs map[string]esAggregation, now time.Time) []index.FacetResult {
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	results := make([]index.FacetResult, len(reqs))
+	for i, req := range reqs {
+		results[i].Name = req.Name
+
+		counts := make(map[string]uint64)
+		for _, bucket := range aggs[req.Name].Buckets {
+			if req.Type == index.FacetTypeHost {
+				results[i].Buckets = append(results[i].Buckets, index.FacetBucket{Key: bucket.Key, Count: bucket.DocCount})
+				continue
+			}
+			counts[bucket.Key] = bucket.DocCount
+		}
+
+		switch req.Type {
+		case index.FacetTypeIndexedAt:
+			for _, r := range req.DateRanges(now) {
+				results[i].Buckets = append(results[i].Buckets, index.FacetBucket{Key: r.Key, Count: counts[r.Key]})
+			}
+		case index.FacetTypePageRank:
+			for _, r := range req.NumericRanges() {
+				results[i].Buckets = append(results[i].Buckets, index.FacetBucket{Key: r.Key, Count: counts[r.Key]})
+			}
+		}
+	}
+
+	return results
+}
+
+// urlHost returns the lower-cased host name for rawURL or an empty string if
+// rawURL cannot be parsed.
+func urlHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -0,0 +1,62 @@
+package es
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(FacetTestSuite))
+
+type FacetTestSuite struct{}
+
+func (s *FacetTestSuite) TestFacetAggregations(c *gc.C) {
+	now := time.Date(2019, 2, 14, 13, 37, 0, 0, time.UTC)
+	reqs := []index.FacetRequest{
+		{Name: "sites", Type: index.FacetTypeHost, Size: 5},
+		{Name: "years", Type: index.FacetTypeIndexedAt, Interval: index.DateIntervalYear, Size: 1},
+		{Name: "rank", Type: index.FacetTypePageRank, Ranges: []float64{0.5}},
+	}
+
+	got, err := json.Marshal(facetAggregations(reqs, now))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, `{`+
+		`"rank":{"range":{"field":"PageRank","ranges":[{"key":"..0.5","to":0.5},{"from":0.5,"key":"0.5.."}]}},`+
+		`"sites":{"terms":{"field":"Host","size":5}},`+
+		`"years":{"date_range":{"field":"IndexedAt","ranges":[{"from":"2019-01-01T00:00:00Z","key":"2019","to":"2020-01-01T00:00:00Z"}]}}}`,
+	)
+}
+
+func (s *FacetTestSuite) TestFacetResults(c *gc.C) {
+	now := time.Date(2019, 2, 14, 13, 37, 0, 0, time.UTC)
+	reqs := []index.FacetRequest{
+		{Name: "sites", Type: index.FacetTypeHost},
+		{Name: "rank", Type: index.FacetTypePageRank, Ranges: []float64{0.5}},
+	}
+
+	var res esSearchRes
+	err := json.Unmarshal([]byte(`{"aggregations": {
+		"sites": {"buckets": [{"key": "a.example.com", "doc_count": 3}, {"key": "b.example.com", "doc_count": 1}]},
+		"rank": {"buckets": [{"key": "0.5..", "from": 0.5, "doc_count": 2}]}
+	}}`), &res)
+	c.Assert(err, gc.IsNil)
+
+	c.Assert(facetResults(reqs, res.Aggregations, now), gc.DeepEquals, []index.FacetResult{
+		{
+			Name: "sites",
+			Buckets: []index.FacetBucket{
+				{Key: "a.example.com", Count: 3},
+				{Key: "b.example.com", Count: 1},
+			},
+		},
+		{
+			Name: "rank",
+			Buckets: []index.FacetBucket{
+				{Key: "..0.5", Count: 0},
+				{Key: "0.5..", Count: 2},
+			},
+		},
+	})
+}
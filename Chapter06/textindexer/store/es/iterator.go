@@ -1,6 +1,8 @@
 package es
 
 import (
+	"time"
+
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
 	"github.com/elastic/go-elasticsearch"
 )
@@ -10,42 +12,68 @@ type esIterator struct {
 	es        *elasticsearch.Client
 	searchReq map[string]interface{}
 
-	cumIdx uint64
-	rsIdx  int
-	rs     *esSearchRes
+	rsIdx int
+	rs    *esSearchRes
+	total uint64
+
+	// The position of the next document and the direction of iteration.
+	nextPos  uint64
+	backward bool
+
+	// The reference time for the ranking scores of the results.
+	now time.Time
+
+	latchedDoc    *index.Document
+	latchedCursor string
+	lastErr       error
 
-	latchedDoc *index.Document
-	lastErr    error
+	facets []index.FacetResult
 }
 
 // Close the iterator and release any allocated resources.
 func (it *esIterator) Close() error {
 	it.es = nil
 	it.searchReq = nil
-	it.cumIdx = it.rs.Hits.Total.Count
+	it.rs = nil
 	return nil
 }
 
 // Next loads the next document matching the search query.
 // It returns false if no more documents are available.
 func (it *esIterator) Next() bool {
-	if it.lastErr != nil || it.rs == nil || it.cumIdx >= it.rs.Hits.Total.Count {
+	if it.lastErr != nil || it.rs == nil {
 		return false
 	}
 
-	// Do we need to fetch the next batch?
+	// Do we need to fetch the next batch? Subsequent batches are requested
+	// using the sort keys of the last hit so that deep pagination does not
+	// require elasticsearch to skip over the previous batches.
 	if it.rsIdx >= len(it.rs.Hits.HitList) {
-		it.searchReq["from"] = it.searchReq["from"].(uint64) + batchSize
+		if len(it.rs.Hits.HitList) < batchSize {
+			return false
+		}
+
+		delete(it.searchReq, "from")
+		it.searchReq["search_after"] = it.rs.Hits.HitList[len(it.rs.Hits.HitList)-1].Sort
 		if it.rs, it.lastErr = runSearch(it.es, it.searchReq); it.lastErr != nil {
 			return false
 		}
 
 		it.rsIdx = 0
+		if len(it.rs.Hits.HitList) == 0 {
+			return false
+		}
 	}
 
-	it.latchedDoc = mapEsDoc(&it.rs.Hits.HitList[it.rsIdx].DocSource)
-	it.cumIdx++
+	hit := &it.rs.Hits.HitList[it.rsIdx]
+	it.latchedDoc = mapEsDoc(&hit.DocSource)
+	it.latchedCursor = hitCursor(hit, it.nextPos, it.now)
 	it.rsIdx++
+	if !it.backward {
+		it.nextPos++
+	} else if it.nextPos > 0 {
+		it.nextPos--
+	}
 	return true
 }
 
@@ -61,5 +89,16 @@ func (it *esIterator) Document() *index.Document {
 
 // TotalCount returns the approximate number of search results.
 func (it *esIterator) TotalCount() uint64 {
-	return it.rs.Hits.Total.Count
+	return it.total
+}
+
+// Facets returns the bucket counts for the facets requested by the search
+// query.
+func (it *esIterator) Facets() []index.FacetResult {
+	return it.facets
+}
+
+// Cursor returns the opaque cursor for the current document.
+func (it *esIterator) Cursor() string {
+	return it.latchedCursor
 }
@@ -0,0 +1,66 @@
+package es
+
+import (
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+)
+
+// languageContent returns the value of the LanguageContent field for doc. The
+// field holds one sub-field for each supported language whose mapping
+// applies the built-in elasticsearch analyzer for that language.
+//
+// As partial document updates are merged with the existing document, the
+// returned map includes a nil value for each language other than the
+// language of doc so that content indexed under a previously detected
+// language is cleared.
+func languageContent(doc *index.Document) map[string]interface{} {
+	content := make(map[string]interface{}, len(index.SupportedLanguages))
+	for _, lang := range index.SupportedLanguages {
+		content[lang] = nil
+		if lang == doc.Language {
+			content[lang] = doc.Content
+		}
+	}
+	return content
+}
+
+// languageContentFields returns the names of the language-specific content
+// fields for langs.
+func languageContentFields(langs []string) []string {
+	fields := make([]string, len(langs))
+	for i, lang := range langs {
+		fields[i] = "LanguageContent." + lang
+	}
+	return fields
+}
+
+// applyLanguagePreference filters or boosts the documents matched by the
+// query clause q according to the language preference pref.
+func applyLanguagePreference(q map[string]interface{}, pref *index.LanguagePreference) map[string]interface{} {
+	if pref == nil {
+		return q
+	}
+
+	langFilter := map[string]interface{}{
+		"term": map[string]interface{}{"Language": pref.Language},
+	}
+	if pref.IsFilter() {
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":   q,
+				"filter": langFilter,
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"function_score": map[string]interface{}{
+			"query": q,
+			"functions": []map[string]interface{}{
+				{"filter": langFilter, "weight": pref.Boost},
+			},
+			// Documents that do not match the filter keep their original
+			// relevance score.
+			"boost_mode": "multiply",
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package es
+
+import (
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/google/uuid"
+)
+
+// resultSort returns the sort clause that orders search results by their
+// ranking score in descending order, breaking ties by ordering documents by
+// their link IDs. If backward is set, the order is reversed.
+func resultSort(backward bool) []map[string]interface{} {
+	scoreOrder, linkIDOrder := "desc", "asc"
+	if backward {
+		scoreOrder, linkIDOrder = "asc", "desc"
+	}
+	return []map[string]interface{}{
+		{"_score": scoreOrder},
+		{"LinkID": linkIDOrder},
+	}
+}
+
+// applyPagination adds to searchReq the clauses for the Offset, Cursor and
+// Backward settings of q. Cursors are translated into search_after clauses
+// that use the sort keys captured by the cursor. applyPagination returns the
+// position of the first search result and the reference time for
+// calculating the ranking scores of the results, which is captured by the
+// cursor when resuming a search.
+func applyPagination(searchReq map[string]interface{}, q index.Query) (uint64, time.Time, error) {
+	if q.Cursor == "" {
+		searchReq["sort"] = resultSort(false)
+		searchReq["from"] = q.Offset
+		return q.Offset, time.Now(), nil
+	}
+
+	cursor, err := index.ParseCursor(q.Cursor)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	searchReq["sort"] = resultSort(q.Backward)
+	searchReq["search_after"] = []interface{}{cursor.Score, cursor.LinkID.String()}
+	if !q.Backward {
+		return cursor.Position + 1, cursor.ReferenceTime(), nil
+	} else if cursor.Position == 0 {
+		return 0, cursor.ReferenceTime(), nil
+	}
+	return cursor.Position - 1, cursor.ReferenceTime(), nil
+}
+
+// hitCursor returns the opaque cursor for a search hit at the specified
+// position whose score was calculated for the reference time now.
+func hitCursor(hit *esHitWrapper, pos uint64, now time.Time) string {
+	cursor := index.Cursor{Position: pos, Now: now}
+	if len(hit.Sort) != 0 {
+		cursor.Score, _ = hit.Sort[0].(float64)
+	}
+	cursor.LinkID, _ = uuid.Parse(hit.DocSource.LinkID)
+	return cursor.String()
+}
@@ -0,0 +1,83 @@
+package es
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(PaginationTestSuite))
+
+type PaginationTestSuite struct{}
+
+func (s *PaginationTestSuite) TestApplyOffset(c *gc.C) {
+	searchReq := make(map[string]interface{})
+	firstPos, now, err := applyPagination(searchReq, index.Query{Offset: 20})
+	c.Assert(err, gc.IsNil)
+	c.Assert(firstPos, gc.Equals, uint64(20))
+	c.Assert(now.IsZero(), gc.Equals, false)
+
+	got, err := json.Marshal(searchReq)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, `{"from":20,"sort":[{"_score":"desc"},{"LinkID":"asc"}]}`)
+}
+
+func (s *PaginationTestSuite) TestApplyCursor(c *gc.C) {
+	linkID := uuid.MustParse("0bcd3a9c-6d2a-4a06-9a5b-5e7c10ff46ee")
+	cursorNow := time.Unix(1600000000, 0).UTC()
+	cursor := index.Cursor{Position: 9, Score: 1.5, Now: cursorNow, LinkID: linkID}.String()
+
+	specs := []struct {
+		descr       string
+		backward    bool
+		expFirstPos uint64
+		expReq      string
+	}{
+		{
+			descr:       "forward",
+			expFirstPos: 10,
+			expReq:      `{"search_after":[1.5,"0bcd3a9c-6d2a-4a06-9a5b-5e7c10ff46ee"],"sort":[{"_score":"desc"},{"LinkID":"asc"}]}`,
+		},
+		{
+			descr:       "backward",
+			backward:    true,
+			expFirstPos: 8,
+			expReq:      `{"search_after":[1.5,"0bcd3a9c-6d2a-4a06-9a5b-5e7c10ff46ee"],"sort":[{"_score":"asc"},{"LinkID":"desc"}]}`,
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		searchReq := make(map[string]interface{})
+		firstPos, now, err := applyPagination(searchReq, index.Query{Offset: 20, Cursor: cursor, Backward: spec.backward})
+		c.Assert(err, gc.IsNil)
+		c.Assert(firstPos, gc.Equals, spec.expFirstPos)
+		c.Assert(now, gc.Equals, cursorNow, gc.Commentf("scores should be calculated for the reference time of the cursor"))
+
+		got, err := json.Marshal(searchReq)
+		c.Assert(err, gc.IsNil)
+		c.Assert(string(got), gc.Equals, spec.expReq)
+	}
+}
+
+func (s *PaginationTestSuite) TestApplyInvalidCursor(c *gc.C) {
+	_, _, err := applyPagination(make(map[string]interface{}), index.Query{Cursor: "bogus"})
+	c.Assert(xerrors.Is(err, index.ErrInvalidCursor), gc.Equals, true)
+}
+
+func (s *PaginationTestSuite) TestHitCursor(c *gc.C) {
+	linkID := uuid.New()
+	hit := &esHitWrapper{
+		DocSource: esDoc{LinkID: linkID.String()},
+		Sort:      []interface{}{0.25, linkID.String()},
+	}
+
+	now := time.Unix(1600000000, 0).UTC()
+	got, err := index.ParseCursor(hitCursor(hit, 3, now))
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.Equals, index.Cursor{Position: 3, Score: 0.25, Now: now, LinkID: linkID})
+}
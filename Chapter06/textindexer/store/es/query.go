@@ -0,0 +1,131 @@
+package es
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+)
+
+// searchQuery converts q into an elasticsearch query clause. If the ranking
+// model of q boosts title matches, the boost is applied to the Title field
+// of terms that are matched against both the title and the content of
+// documents. Such terms are also matched against the description, headings
+// and anchor text fields using the boosts defined by the index package.
+//
+// Terms that are matched against the content of documents are also matched
+// against the language-specific content fields of the languages returned by
+// the SearchLanguages method of the language preference of q. The query
+// clause is then wrapped in a clause that applies the preference.
+func searchQuery(q index.Query) map[string]interface{} {
+	t := translator{
+		ranking:   q.RankingModel(),
+		languages: q.Language.SearchLanguages(),
+	}
+
+	var clause map[string]interface{}
+	if q.Tree != nil {
+		clause = t.translateNode(q.Tree)
+	} else {
+		clause = t.translateTerm(&index.TermNode{
+			Text:   q.Expression,
+			Phrase: q.Type == index.QueryTypePhrase,
+		})
+	}
+	return applyLanguagePreference(clause, q.Language)
+}
+
+// The secondary document fields that are matched together with the title and
+// content of documents, along with their boosts.
+var (
+	descriptionField = "Description^" + strconv.FormatFloat(index.DescriptionBoost, 'g', -1, 64)
+	headingsField    = "Headings^" + strconv.FormatFloat(index.HeadingsBoost, 'g', -1, 64)
+	anchorTextField  = "AnchorText^" + strconv.FormatFloat(index.AnchorTextBoost, 'g', -1, 64)
+)
+
+type translator struct {
+	ranking   index.Ranking
+	languages []string
+}
+
+func (t translator) translateNode(node index.QueryNode) map[string]interface{} {
+	switch n := node.(type) {
+	case *index.TermNode:
+		return t.translateTerm(n)
+	case *index.SiteNode:
+		return map[string]interface{}{
+			"regexp": map[string]interface{}{
+				"URL": siteRegexp(n.Host),
+			},
+		}
+	case *index.DateRangeNode:
+		bounds := make(map[string]interface{})
+		if !n.From.IsZero() {
+			bounds["gte"] = n.From.UTC().Format(time.RFC3339Nano)
+		}
+		if !n.To.IsZero() {
+			bounds["lt"] = n.To.UTC().Format(time.RFC3339Nano)
+		}
+		return map[string]interface{}{
+			"range": map[string]interface{}{
+				"IndexedAt": bounds,
+			},
+		}
+	case *index.BoolNode:
+		clauses := make(map[string]interface{})
+		for name, list := range map[string][]index.QueryNode{"must": n.Must, "should": n.Should, "must_not": n.MustNot} {
+			if len(list) == 0 {
+				continue
+			}
+			translated := make([]map[string]interface{}, 0, len(list))
+			for _, sub := range list {
+				translated = append(translated, t.translateNode(sub))
+			}
+			clauses[name] = translated
+		}
+		if len(clauses) == 0 {
+			return map[string]interface{}{"match_none": map[string]interface{}{}}
+		}
+		return map[string]interface{}{"bool": clauses}
+	default:
+		return map[string]interface{}{"match_none": map[string]interface{}{}}
+	}
+}
+
+func (t translator) translateTerm(n *index.TermNode) map[string]interface{} {
+	if n.Field == index.FieldTitle {
+		qtype := "match"
+		if n.Phrase {
+			qtype = "match_phrase"
+		}
+		return map[string]interface{}{
+			qtype: map[string]interface{}{
+				"Title": n.Text,
+			},
+		}
+	}
+
+	qtype := "best_fields"
+	if n.Phrase {
+		qtype = "phrase"
+	}
+	titleField := "Title"
+	if t.ranking.HasTitleBoost() {
+		titleField += "^" + strconv.FormatFloat(t.ranking.TitleBoost, 'g', -1, 64)
+	}
+	return map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"type":   qtype,
+			"query":  n.Text,
+			"fields": append([]string{titleField, "Content", descriptionField, headingsField, anchorTextField}, languageContentFields(t.languages)...),
+		},
+	}
+}
+
+// siteRegexp returns a regular expression that matches URLs pointing to
+// host or any of its sub-domains. Elasticsearch regular expressions are
+// always anchored to the start and end of the matched term.
+func siteRegexp(host string) string {
+	return `https?://([^/?]*\.)?` + regexp.QuoteMeta(host) + `(:[0-9]+)?([/?].*)?`
+}
@@ -0,0 +1,69 @@
+package es
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(QueryTranslationTestSuite))
+
+type QueryTranslationTestSuite struct{}
+
+func (s *QueryTranslationTestSuite) TestTranslateQueryTree(c *gc.C) {
+	q := index.Query{
+		Tree: &index.BoolNode{
+			Must: []index.QueryNode{
+				&index.TermNode{Field: index.FieldTitle, Text: "lorem ipsum", Phrase: true},
+				&index.DateRangeNode{From: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)},
+			},
+			Should: []index.QueryNode{
+				&index.TermNode{Text: "dolor"},
+			},
+			MustNot: []index.QueryNode{
+				&index.SiteNode{Host: "example.com"},
+			},
+		},
+	}
+
+	got, err := json.Marshal(searchQuery(q))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, `{"bool":{`+
+		`"must":[{"match_phrase":{"Title":"lorem ipsum"}},{"range":{"IndexedAt":{"gte":"2019-01-02T00:00:00Z"}}}],`+
+		`"must_not":[{"regexp":{"URL":"https?://([^/?]*\\.)?example\\.com(:[0-9]+)?([/?].*)?"}}],`+
+		`"should":[{"multi_match":{"fields":["Title","Content","Description^1.5","Headings^2","AnchorText^2","LanguageContent.de","LanguageContent.en","LanguageContent.es","LanguageContent.fr","LanguageContent.it","LanguageContent.nl","LanguageContent.pt"],"query":"dolor","type":"best_fields"}}]}}`,
+	)
+}
+
+func (s *QueryTranslationTestSuite) TestTranslateLegacyQuery(c *gc.C) {
+	got, err := json.Marshal(searchQuery(index.Query{Type: index.QueryTypePhrase, Expression: "lorem ipsum"}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, `{"multi_match":{"fields":["Title","Content","Description^1.5","Headings^2","AnchorText^2","LanguageContent.de","LanguageContent.en","LanguageContent.es","LanguageContent.fr","LanguageContent.it","LanguageContent.nl","LanguageContent.pt"],"query":"lorem ipsum","type":"phrase"}}`)
+}
+
+func (s *QueryTranslationTestSuite) TestTranslateQueryWithLanguageFilter(c *gc.C) {
+	got, err := json.Marshal(searchQuery(index.Query{
+		Expression: "haus",
+		Language:   &index.LanguagePreference{Language: "de"},
+	}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, `{"bool":{`+
+		`"filter":{"term":{"Language":"de"}},`+
+		`"must":{"multi_match":{"fields":["Title","Content","Description^1.5","Headings^2","AnchorText^2","LanguageContent.de"],"query":"haus","type":"best_fields"}}}}`,
+	)
+}
+
+func (s *QueryTranslationTestSuite) TestTranslateQueryWithLanguageBoost(c *gc.C) {
+	got, err := json.Marshal(searchQuery(index.Query{
+		Expression: "haus",
+		Language:   &index.LanguagePreference{Language: "de", Boost: 2},
+	}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, `{"function_score":{`+
+		`"boost_mode":"multiply",`+
+		`"functions":[{"filter":{"term":{"Language":"de"}},"weight":2}],`+
+		`"query":{"multi_match":{"fields":["Title","Content","Description^1.5","Headings^2","AnchorText^2","LanguageContent.de"],"query":"haus","type":"best_fields"}}}}`,
+	)
+}
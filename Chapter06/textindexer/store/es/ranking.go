@@ -0,0 +1,52 @@
+package es
+
+import (
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+)
+
+// rankingScript is a painless port of index.Ranking.Score. Documents without
+// a PageRank or an IndexedAt value are scored as if they had a zero PageRank
+// and did not decay.
+const rankingScript = `double pageRank = doc['PageRank'].size() == 0 ? 0 : doc['PageRank'].value;
+double score = params.relevanceWeight * _score + params.pageRankWeight * pageRank;
+if (params.halfLifeMillis > 0 && doc['IndexedAt'].size() != 0) {
+  double age = Math.max(0, params.nowMillis - doc['IndexedAt'].value.toInstant().toEpochMilli());
+  score *= Math.pow(0.5, age / params.halfLifeMillis);
+}
+return score;`
+
+// rankedQuery wraps the query clause for q in a function_score query that
+// orders the matching documents using the ranking model of q. Placeholder
+// documents for links that have not been indexed yet lack an IndexedAt field
+// and are excluded from the results.
+func rankedQuery(q index.Query, now time.Time) map[string]interface{} {
+	r := q.RankingModel()
+	return map[string]interface{}{
+		"function_score": map[string]interface{}{
+			"query": map[string]interface{}{
+				"bool": map[string]interface{}{
+					"must": searchQuery(q),
+					"filter": map[string]interface{}{
+						"exists": map[string]interface{}{"field": "IndexedAt"},
+					},
+				},
+			},
+			"script_score": map[string]interface{}{
+				"script": map[string]interface{}{
+					"source": rankingScript,
+					"params": map[string]interface{}{
+						"relevanceWeight": r.RelevanceWeight,
+						"pageRankWeight":  r.PageRankWeight,
+						"halfLifeMillis":  r.FreshnessHalfLife.Milliseconds(),
+						"nowMillis":       now.UnixMilli(),
+					},
+				},
+			},
+			// The script calculates the final score so the relevance score
+			// must not be applied a second time.
+			"boost_mode": "replace",
+		},
+	}
+}
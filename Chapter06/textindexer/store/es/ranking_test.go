@@ -0,0 +1,50 @@
+package es
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(RankingTestSuite))
+
+type RankingTestSuite struct{}
+
+func (s *RankingTestSuite) TestRankedQuery(c *gc.C) {
+	now := time.Date(2019, 2, 14, 13, 37, 0, 0, time.UTC)
+	q := index.Query{
+		Expression: "lorem",
+		Ranking: &index.Ranking{
+			RelevanceWeight:   0.5,
+			PageRankWeight:    2,
+			TitleBoost:        3,
+			FreshnessHalfLife: time.Hour,
+		},
+	}
+
+	got := rankedQuery(q, now)["function_score"].(map[string]interface{})
+	c.Assert(got["boost_mode"], gc.Equals, "replace")
+
+	encQuery, err := json.Marshal(got["query"])
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(encQuery), gc.Equals, `{"bool":{"filter":{"exists":{"field":"IndexedAt"}},"must":{"multi_match":{"fields":["Title^3","Content","Description^1.5","Headings^2","AnchorText^2","LanguageContent.de","LanguageContent.en","LanguageContent.es","LanguageContent.fr","LanguageContent.it","LanguageContent.nl","LanguageContent.pt"],"query":"lorem","type":"best_fields"}}}}`)
+
+	encParams, err := json.Marshal(got["script_score"].(map[string]interface{})["script"].(map[string]interface{})["params"])
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(encParams), gc.Equals, `{"halfLifeMillis":3600000,"nowMillis":1550151420000,"pageRankWeight":2,"relevanceWeight":0.5}`)
+}
+
+func (s *RankingTestSuite) TestRankedQueryWithDefaultRanking(c *gc.C) {
+	got := rankedQuery(index.Query{Expression: "lorem"}, time.Now())["function_score"].(map[string]interface{})
+
+	encQuery, err := json.Marshal(got["query"])
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(encQuery), gc.Equals, `{"bool":{"filter":{"exists":{"field":"IndexedAt"}},"must":{"multi_match":{"fields":["Title","Content","Description^1.5","Headings^2","AnchorText^2","LanguageContent.de","LanguageContent.en","LanguageContent.es","LanguageContent.fr","LanguageContent.it","LanguageContent.nl","LanguageContent.pt"],"query":"lorem","type":"best_fields"}}}}`)
+
+	params := got["script_score"].(map[string]interface{})["script"].(map[string]interface{})["params"].(map[string]interface{})
+	c.Assert(params["relevanceWeight"], gc.Equals, 1.0)
+	c.Assert(params["pageRankWeight"], gc.Equals, 1.0)
+	c.Assert(params["halfLifeMillis"], gc.Equals, int64(0))
+}
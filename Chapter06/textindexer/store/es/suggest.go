@@ -0,0 +1,102 @@
+package es
+
+import (
+	"strings"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+)
+
+// The fields whose term dictionaries are used for building spelling
+// suggestions.
+var suggestFields = []string{"Title", "Content"}
+
+type esSuggestEntry struct {
+	Text    string            `json:"text"`
+	Options []esSuggestOption `json:"options"`
+}
+
+type esSuggestOption struct {
+	Text string `json:"text"`
+	Freq uint64 `json:"freq"`
+}
+
+// suggestQuery returns a search request that runs a term suggester over each
+// one of the suggestFields for the provided search terms. The request also
+// includes a filters aggregation that counts the documents containing each
+// term so that terms that are present in the index are not corrected.
+func suggestQuery(terms []string) map[string]interface{} {
+	suggest := map[string]interface{}{
+		"text": strings.Join(terms, " "),
+	}
+	for _, field := range suggestFields {
+		suggest[field] = map[string]interface{}{
+			"term": map[string]interface{}{
+				"field":        field,
+				"suggest_mode": "always",
+				"max_edits":    index.MaxSuggestEditDistance,
+				"size":         index.MaxSuggestions,
+			},
+		}
+	}
+
+	filters := make([]map[string]interface{}, len(terms))
+	for i, term := range terms {
+		filters[i] = map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  term,
+				"fields": suggestFields,
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"size":    0,
+		"suggest": suggest,
+		"aggs": map[string]interface{}{
+			"indexed": map[string]interface{}{
+				"filters": map[string]interface{}{
+					"filters": filters,
+				},
+			},
+		},
+	}
+}
+
+// suggestCandidates extracts the correction candidates for the provided
+// search terms from the response to a request built by suggestQuery.
+func suggestCandidates(terms []string, res *esSearchRes) map[string][]index.TermCandidate {
+	indexed := make(map[string]bool, len(terms))
+	for i, bucket := range res.Aggregations["indexed"].Buckets {
+		if i < len(terms) && bucket.DocCount != 0 {
+			indexed[terms[i]] = true
+		}
+	}
+
+	// Merge the options for each term across all suggested fields. The
+	// number of documents containing a term in any of the fields is at
+	// least as large as its highest per-field frequency.
+	docCounts := make(map[string]map[string]uint64)
+	for _, field := range suggestFields {
+		for _, entry := range res.Suggest[field] {
+			if indexed[entry.Text] {
+				continue
+			}
+			for _, opt := range entry.Options {
+				if docCounts[entry.Text] == nil {
+					docCounts[entry.Text] = make(map[string]uint64)
+				}
+				if opt.Freq > docCounts[entry.Text][opt.Text] {
+					docCounts[entry.Text][opt.Text] = opt.Freq
+				}
+			}
+		}
+	}
+
+	candidates := make(map[string][]index.TermCandidate, len(docCounts))
+	for term, options := range docCounts {
+		for optTerm, docCount := range options {
+			candidates[term] = append(candidates[term], index.TermCandidate{Term: optTerm, DocCount: docCount})
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,56 @@
+package es
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(SuggestTestSuite))
+
+type SuggestTestSuite struct{}
+
+func (s *SuggestTestSuite) TestSuggestQuery(c *gc.C) {
+	got, err := json.Marshal(suggestQuery([]string{"lorme", "ipsum"}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, `{`+
+		`"aggs":{"indexed":{"filters":{"filters":[`+
+		`{"multi_match":{"fields":["Title","Content"],"query":"lorme"}},`+
+		`{"multi_match":{"fields":["Title","Content"],"query":"ipsum"}}]}}},`+
+		`"size":0,`+
+		`"suggest":{`+
+		`"Content":{"term":{"field":"Content","max_edits":2,"size":5,"suggest_mode":"always"}},`+
+		`"Title":{"term":{"field":"Title","max_edits":2,"size":5,"suggest_mode":"always"}},`+
+		`"text":"lorme ipsum"}}`,
+	)
+}
+
+func (s *SuggestTestSuite) TestSuggestCandidates(c *gc.C) {
+	var res esSearchRes
+	err := json.Unmarshal([]byte(`{
+  "aggregations": {
+    "indexed": {"buckets": [{"doc_count": 0}, {"doc_count": 3}]}
+  },
+  "suggest": {
+    "Title": [
+      {"text": "lorme", "options": [{"text": "lorem", "freq": 2}]},
+      {"text": "ipsum", "options": [{"text": "ipsam", "freq": 1}]}
+    ],
+    "Content": [
+      {"text": "lorme", "options": [{"text": "lorem", "freq": 5}, {"text": "lore", "freq": 1}]},
+      {"text": "ipsum", "options": []}
+    ]
+  }
+}`), &res)
+	c.Assert(err, gc.IsNil)
+
+	got := suggestCandidates([]string{"lorme", "ipsum"}, &res)
+	c.Assert(got, gc.HasLen, 1)
+	sort.Slice(got["lorme"], func(i, j int) bool { return got["lorme"][i].Term < got["lorme"][j].Term })
+	c.Assert(got["lorme"], gc.DeepEquals, []index.TermCandidate{
+		{Term: "lore", DocCount: 1},
+		{Term: "lorem", DocCount: 5},
+	})
+}
@@ -5,22 +5,29 @@ import (
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/blevequery"
 	"github.com/blevesearch/bleve/v2"
-	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 )
 
-// The size of each page of results that is cached locally by the iterator.
-const batchSize = 10
-
 // Compile-time check to ensure InMemoryBleveIndexer implements Indexer.
 var _ index.Indexer = (*InMemoryBleveIndexer)(nil)
 
 type bleveDoc struct {
-	Title    string
-	Content  string
-	PageRank float64
+	URL         string
+	Host        string
+	Title       string
+	Content     string
+	Description string
+	Headings    []string
+	AnchorText  []string
+	IndexedAt   time.Time
+	PageRank    float64
+
+	Language        string
+	LanguageContent map[string]string
 }
 
 // InMemoryBleveIndexer is an Indexer implementation that uses an in-memory
@@ -35,8 +42,7 @@ type InMemoryBleveIndexer struct {
 // NewInMemoryBleveIndexer creates a text indexer that uses an in-memory
 // bleve instance for indexing documents.
 func NewInMemoryBleveIndexer() (*InMemoryBleveIndexer, error) {
-	mapping := bleve.NewIndexMapping()
-	idx, err := bleve.NewMemOnly(mapping)
+	idx, err := bleve.NewMemOnly(indexMapping())
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +53,30 @@ func NewInMemoryBleveIndexer() (*InMemoryBleveIndexer, error) {
 	}, nil
 }
 
+// indexMapping returns the mapping for documents stored in the index. The
+// URL, Host and IndexedAt fields are excluded from the default search field
+// so that they can only be matched by site and date range queries or used
+// for calculating facets. The description, headings and anchor text fields
+// are matched separately by translated queries. The content of documents in
+// a supported language is additionally indexed using the analyzer for that
+// language.
+func indexMapping() mapping.IndexMapping {
+	urlMapping := bleve.NewKeywordFieldMapping()
+	urlMapping.IncludeInAll = false
+	hostMapping := bleve.NewKeywordFieldMapping()
+	hostMapping.IncludeInAll = false
+	indexedAtMapping := bleve.NewDateTimeFieldMapping()
+	indexedAtMapping.IncludeInAll = false
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping.AddFieldMappingsAt(blevequery.URLField, urlMapping)
+	m.DefaultMapping.AddFieldMappingsAt(blevequery.HostField, hostMapping)
+	m.DefaultMapping.AddFieldMappingsAt(blevequery.IndexedAtField, indexedAtMapping)
+	blevequery.AddSecondaryFieldMappings(m.DefaultMapping)
+	blevequery.AddLanguageMappings(m.DefaultMapping)
+	return m
+}
+
 // Close the indexer and release any allocated resources.
 func (i *InMemoryBleveIndexer) Close() error {
 	return i.idx.Close()
@@ -66,9 +96,10 @@ func (i *InMemoryBleveIndexer) Index(doc *index.Document) error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	// If updating, preserve existing PageRank score
+	// If updating, preserve existing PageRank score and anchor texts
 	if orig, exists := i.docs[key]; exists {
 		dcopy.PageRank = orig.PageRank
+		dcopy.AnchorText = orig.AnchorText
 	}
 
 	if err := i.idx.Index(key, makeBleveDoc(dcopy)); err != nil {
@@ -99,24 +130,22 @@ func (i *InMemoryBleveIndexer) findByID(linkID string) (*index.Document, error)
 // Search the index for a particular query and return back a result
 // iterator.
 func (i *InMemoryBleveIndexer) Search(q index.Query) (index.Iterator, error) {
-	var bq query.Query
-	switch q.Type {
-	case index.QueryTypePhrase:
-		bq = bleve.NewMatchPhraseQuery(q.Expression)
-	default:
-		bq = bleve.NewMatchQuery(q.Expression)
-	}
-
-	searchReq := bleve.NewSearchRequest(bq)
-	searchReq.SortBy([]string{"-PageRank", "-_score"})
-	searchReq.Size = batchSize
-	searchReq.From = int(q.Offset)
-	rs, err := i.idx.Search(searchReq)
+	bq := blevequery.Translate(q)
+	res, err := blevequery.Search(i.idx, bq, q, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("search: %w", err)
+	}
+
+	facets, err := blevequery.Facets(i.idx, bq, q.Facets, res.Now())
 	if err != nil {
 		return nil, xerrors.Errorf("search: %w", err)
 	}
 
-	return &bleveIterator{idx: i, searchReq: searchReq, rs: rs, cumIdx: q.Offset}, nil
+	return &bleveIterator{
+		idx:    i,
+		res:    res,
+		facets: facets,
+	}, nil
 }
 
 // UpdateScore updates the PageRank score for a document with the specified
@@ -141,6 +170,130 @@ func (i *InMemoryBleveIndexer) UpdateScore(linkID uuid.UUID, score float64) erro
 	return nil
 }
 
+// AddAnchorText records the text of a link pointing to the document with the
+// specified link ID. If no such document exists, a placeholder document with
+// the provided anchor text will be created.
+func (i *InMemoryBleveIndexer) AddAnchorText(linkID uuid.UUID, text string) error {
+	if err := i.addAnchorTexts(map[uuid.UUID][]string{linkID: {text}}); err != nil {
+		return xerrors.Errorf("add anchor text: %w", err)
+	}
+	return nil
+}
+
+// AddAnchorTexts records the texts of links pointing to the documents with the
+// specified link IDs using a single bleve batch. If any of the documents does
+// not exist, a placeholder document with the provided anchor texts will be
+// created.
+func (i *InMemoryBleveIndexer) AddAnchorTexts(texts map[uuid.UUID][]string) error {
+	if err := i.addAnchorTexts(texts); err != nil {
+		return xerrors.Errorf("add anchor texts: %w", err)
+	}
+	return nil
+}
+
+func (i *InMemoryBleveIndexer) addAnchorTexts(texts map[uuid.UUID][]string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var (
+		batch   = i.idx.NewBatch()
+		updated = make(map[string]*index.Document)
+	)
+	for linkID, list := range texts {
+		key := linkID.String()
+		doc := &index.Document{LinkID: linkID}
+		if orig, found := i.docs[key]; found {
+			doc = copyDoc(orig)
+		}
+
+		var changed bool
+		if doc.AnchorText, changed = index.AppendAnchorTexts(doc.AnchorText, list); !changed {
+			continue
+		}
+		if err := batch.Index(key, makeBleveDoc(doc)); err != nil {
+			return err
+		}
+		updated[key] = doc
+	}
+
+	if batch.Size() == 0 {
+		return nil
+	}
+	if err := i.idx.Batch(batch); err != nil {
+		return err
+	}
+
+	for key, doc := range updated {
+		i.docs[key] = doc
+	}
+	return nil
+}
+
+// Delete removes the document with the specified link ID from the index.
+// Deleting a document that does not exist is not an error.
+func (i *InMemoryBleveIndexer) Delete(linkID uuid.UUID) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	key := linkID.String()
+	if _, found := i.docs[key]; !found {
+		return nil
+	}
+
+	if err := i.idx.Delete(key); err != nil {
+		return xerrors.Errorf("delete: %w", err)
+	}
+
+	delete(i.docs, key)
+	return nil
+}
+
+// DeleteOlderThan removes all documents that were last indexed before the
+// specified timestamp. Placeholder documents created by UpdateScore for
+// links that have not been indexed yet are retained.
+func (i *InMemoryBleveIndexer) DeleteOlderThan(t time.Time) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var staleKeys []string
+	batch := i.idx.NewBatch()
+	for key, doc := range i.docs {
+		if !doc.IndexedAt.IsZero() && doc.IndexedAt.Before(t) {
+			staleKeys = append(staleKeys, key)
+			batch.Delete(key)
+		}
+	}
+
+	if err := i.idx.Batch(batch); err != nil {
+		return xerrors.Errorf("delete older than: %w", err)
+	}
+
+	for _, key := range staleKeys {
+		delete(i.docs, key)
+	}
+	return nil
+}
+
+// Suggest returns up to index.MaxSuggestions alternative spellings for the
+// terms of a search expression that are not present in the index.
+func (i *InMemoryBleveIndexer) Suggest(expression string) ([]string, error) {
+	suggestions, err := blevequery.Suggest(i.idx, expression)
+	if err != nil {
+		return nil, xerrors.Errorf("suggest: %w", err)
+	}
+	return suggestions, nil
+}
+
+// Complete returns up to index.MaxCompletions completions for a partially
+// typed search expression.
+func (i *InMemoryBleveIndexer) Complete(expression string) ([]string, error) {
+	completions, err := blevequery.Complete(i.idx, expression)
+	if err != nil {
+		return nil, xerrors.Errorf("complete: %w", err)
+	}
+	return completions, nil
+}
+
 func copyDoc(d *index.Document) *index.Document {
 	dcopy := new(index.Document)
 	*dcopy = *d
@@ -149,8 +302,17 @@ func copyDoc(d *index.Document) *index.Document {
 
 func makeBleveDoc(d *index.Document) bleveDoc {
 	return bleveDoc{
-		Title:    d.Title,
-		Content:  d.Content,
-		PageRank: d.PageRank,
+		URL:         d.URL,
+		Host:        blevequery.URLHost(d.URL),
+		Title:       d.Title,
+		Content:     d.Content,
+		Description: d.Description,
+		Headings:    d.Headings,
+		AnchorText:  d.AnchorText,
+		IndexedAt:   d.IndexedAt,
+		PageRank:    d.PageRank,
+
+		Language:        d.Language,
+		LanguageContent: blevequery.LanguageContent(d),
 	}
 }
@@ -2,57 +2,43 @@ package memory
 
 import (
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
-	"github.com/blevesearch/bleve/v2"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/blevequery"
 )
 
 // bleveIterator implements index.Iterator.
 type bleveIterator struct {
-	idx       *InMemoryBleveIndexer
-	searchReq *bleve.SearchRequest
+	idx *InMemoryBleveIndexer
 
-	cumIdx uint64
-	rsIdx  int
-	rs     *bleve.SearchResult
+	// The ranked hits to iterate.
+	res *blevequery.Results
 
 	latchedDoc *index.Document
 	lastErr    error
+
+	facets []index.FacetResult
 }
 
 // Close the iterator and release any allocated resources.
 func (it *bleveIterator) Close() error {
 	it.idx = nil
-	it.searchReq = nil
-	if it.rs != nil {
-		it.cumIdx = it.rs.Total
-	}
+	it.res.Close()
 	return nil
 }
 
 // Next loads the next document matching the search query.
 // It returns false if no more documents are available.
 func (it *bleveIterator) Next() bool {
-	if it.lastErr != nil || it.rs == nil || it.cumIdx >= it.rs.Total {
+	if it.lastErr != nil {
 		return false
 	}
 
-	// Do we need to fetch the next batch?
-	if it.rsIdx >= it.rs.Hits.Len() {
-		it.searchReq.From += it.searchReq.Size
-		if it.rs, it.lastErr = it.idx.idx.Search(it.searchReq); it.lastErr != nil {
-			return false
-		}
-
-		it.rsIdx = 0
-	}
-
-	nextID := it.rs.Hits[it.rsIdx].ID
-	if it.latchedDoc, it.lastErr = it.idx.findByID(nextID); it.lastErr != nil {
+	if !it.res.Next() {
+		it.lastErr = it.res.Error()
 		return false
 	}
 
-	it.cumIdx++
-	it.rsIdx++
-	return true
+	it.latchedDoc, it.lastErr = it.idx.findByID(it.res.Hit().ID)
+	return it.lastErr == nil
 }
 
 // Error returns the last error encountered by the iterator.
@@ -67,8 +53,16 @@ func (it *bleveIterator) Document() *index.Document {
 
 // TotalCount returns the approximate number of search results.
 func (it *bleveIterator) TotalCount() uint64 {
-	if it.rs == nil {
-		return 0
-	}
-	return it.rs.Total
+	return it.res.Total()
+}
+
+// Facets returns the bucket counts for the facets requested by the search
+// query.
+func (it *bleveIterator) Facets() []index.FacetResult {
+	return it.facets
+}
+
+// Cursor returns the opaque cursor for the current document.
+func (it *bleveIterator) Cursor() string {
+	return it.res.Cursor()
 }
@@ -1,14 +1,18 @@
 package crawler
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"time"
 
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/canonical"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
 )
 
 //go:generate mockgen -package mocks -destination mocks/mocks.go github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/crawler URLGetter,PrivateNetworkDetector,Graph,Indexer
@@ -24,15 +28,27 @@ type PrivateNetworkDetector interface {
 	IsPrivate(host string) (bool, error)
 }
 
+// URLCanonicalizer is implemented by objects that can convert URLs into a
+// canonical form.
+type URLCanonicalizer interface {
+	Canonicalize(rawURL string) (string, error)
+}
+
 // Graph is implemented by objects that can upsert links and edges into a link
 // graph instance.
 type Graph interface {
 	// UpsertLink creates a new link or updates an existing link.
 	UpsertLink(link *graph.Link) error
 
+	// UpsertLinks creates or updates a batch of links.
+	UpsertLinks(links []*graph.Link) error
+
 	// UpsertEdge creates a new edge or updates an existing edge.
 	UpsertEdge(edge *graph.Edge) error
 
+	// UpsertEdges creates or updates a batch of edges.
+	UpsertEdges(edges []*graph.Edge) error
+
 	// RemoveStaleEdges removes any edge that originates from the specified
 	// link ID and was updated before the specified timestamp.
 	RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error
@@ -44,6 +60,14 @@ type Indexer interface {
 	// Index inserts a new document to the index or updates the index entry
 	// for and existing document.
 	Index(doc *index.Document) error
+
+	// Delete removes the document with the specified link ID from the
+	// index.
+	Delete(linkID uuid.UUID) error
+
+	// AddAnchorTexts records the texts of links pointing to the documents
+	// with the specified link IDs in a single batch.
+	AddAnchorTexts(texts map[uuid.UUID][]string) error
 }
 
 // Config encapsulates the configuration options for creating a new Crawler.
@@ -62,6 +86,54 @@ type Config struct {
 
 	// The number of concurrent workers used for retrieving links.
 	FetchWorkers int
+
+	// The delay before retrying a link that could not be retrieved by a
+	// previous crawler pass. The delay doubles with each consecutive
+	// failure. If zero, failed links are retried on every pass.
+	FailureBackoff time.Duration
+
+	// The maximum number of concurrent requests to the same host. If
+	// greater than zero, links are queued by host and the fetch workers
+	// retrieve links from different hosts in a round-robin fashion while
+	// honoring the MinHostDelay and MaxCrawlDelay settings. Links for a
+	// host that already has a large number of queued links are skipped
+	// and will be retrieved by a later crawler pass. Otherwise, links are
+	// retrieved in the order they are received without any per-host
+	// limits.
+	MaxHostConns int
+
+	// The minimum time between the start of two requests to the same host.
+	MinHostDelay time.Duration
+
+	// The maximum Crawl-delay value from the robots.txt file of a host that
+	// will be honored; longer delays are capped to this value. If zero,
+	// robots.txt files are not consulted.
+	MaxCrawlDelay time.Duration
+
+	// The user agent to match against the User-agent lines of robots.txt
+	// files. If not specified, only the rules for all user agents ("*")
+	// are considered.
+	RobotsUserAgent string
+
+	// A URLCanonicalizer instance for converting extracted links into a
+	// canonical form before they are added to the link graph. If not
+	// specified, a canonical.URLCanonicalizer that strips the default set of
+	// tracking parameters will be used instead.
+	URLCanonicalizer URLCanonicalizer
+
+	// An optional pipeline.Observer for monitoring the flow of links
+	// through each stage of the crawler pipeline. The names returned by
+	// PipelineStageNames can be used to identify each stage.
+	Observer pipeline.Observer
+
+	// An optional pipeline.CheckpointStore for persisting the progress of
+	// calls to CrawlWithCheckpoint so that interrupted crawls can be resumed.
+	Checkpoints pipeline.CheckpointStore
+
+	// An optional logger for reporting errors that do not abort the crawl,
+	// such as failures to record the metadata of links that could not be
+	// retrieved. If not specified, such errors are silently ignored.
+	Logger *logrus.Entry
 }
 
 // Crawler implements a web-page crawling pipeline consisting of the following
@@ -69,33 +141,55 @@ type Config struct {
 //
 //   - Given a URL, retrieve the web-page contents from the remote server.
 //   - Extract and resolve absolute and relative links from the retrieved page.
-//   - Extract page title and text content from the retrieved page.
+//   - Extract page title, description, headings and text content from the
+//     retrieved page.
 //   - Update the link graph: add new links and create edges between the crawled
-//     page and the links within it.
-//   - Index crawled page title and text content.
+//     page and the links within it. Record the anchor text of each link with
+//     the text indexer.
+//   - Index crawled page title, description, headings and text content.
 type Crawler struct {
-	p *pipeline.Pipeline
+	p           *pipeline.Pipeline
+	checkpoints pipeline.CheckpointStore
 }
 
 // NewCrawler returns a new crawler instance.
 func NewCrawler(cfg Config) *Crawler {
 	return &Crawler{
-		p: assembleCrawlerPipeline(cfg),
+		p:           assembleCrawlerPipeline(cfg),
+		checkpoints: cfg.Checkpoints,
 	}
 }
 
+// PipelineStageNames returns a short name for each stage of the crawler
+// pipeline followed by the name of the pipeline sink.
+func PipelineStageNames() []string {
+	return []string{"link_fetcher", "link_extractor", "text_extractor", "graph_and_text_indexer", "sink"}
+}
+
 // assembleCrawlerPipeline creates the various stages of a crawler pipeline
 // using the options in cfg and assembles them into a pipeline instance.
 func assembleCrawlerPipeline(cfg Config) *pipeline.Pipeline {
-	return pipeline.New(
-		pipeline.FixedWorkerPool(
-			newLinkFetcher(cfg.URLGetter, cfg.PrivateNetworkDetector),
-			cfg.FetchWorkers,
-		),
-		pipeline.FIFO(newLinkExtractor(cfg.PrivateNetworkDetector)),
+	if cfg.URLCanonicalizer == nil {
+		cfg.URLCanonicalizer = canonical.NewURLCanonicalizer()
+	}
+
+	fetcher := newLinkFetcher(cfg.URLGetter, cfg.PrivateNetworkDetector, cfg.Graph, cfg.Indexer)
+	fetcher.failureBackoff = cfg.FailureBackoff
+	if cfg.Logger != nil {
+		fetcher.logger = cfg.Logger
+	}
+	fetchStage := pipeline.FixedWorkerPool(fetcher, cfg.FetchWorkers)
+	if cfg.MaxHostConns > 0 {
+		fetchStage = newPoliteFetcher(fetcher, newHostScheduler(cfg), cfg.FetchWorkers)
+	}
+
+	return pipeline.NewWithObserver(
+		cfg.Observer,
+		fetchStage,
+		pipeline.FIFO(newLinkExtractor(cfg.PrivateNetworkDetector, cfg.URLCanonicalizer)),
 		pipeline.FIFO(newTextExtractor()),
 		pipeline.Broadcast(
-			newGraphUpdater(cfg.Graph),
+			newGraphUpdater(cfg.Graph, cfg.Indexer),
 			newTextIndexer(cfg.Indexer),
 		),
 	)
@@ -111,6 +205,54 @@ func (c *Crawler) Crawl(ctx context.Context, linkIt graph.LinkIterator) (int, er
 	return sink.getCount(), err
 }
 
+// CrawlWithCheckpoint behaves like Crawl but persists its progress under the
+// specified key using the CheckpointStore from the crawler configuration.
+// Once a link has gone through the pipeline, its ID and the IDs of all links
+// preceding it are recorded as crawled. If a previous call with the same key
+// was interrupted, links up to and including the last recorded link ID are
+// skipped. The checkpoint is cleared once linkIt is exhausted.
+//
+// As link IDs are compared to the recorded position, linkIt must yield links
+// in ascending link ID order. If the crawler was not configured with a
+// CheckpointStore, CrawlWithCheckpoint is equivalent to Crawl.
+func (c *Crawler) CrawlWithCheckpoint(ctx context.Context, key string, linkIt graph.LinkIterator) (int, error) {
+	if c.checkpoints == nil {
+		return c.Crawl(ctx, linkIt)
+	}
+
+	tracker := pipeline.NewCheckpointTracker(c.checkpoints, key, pipeline.CheckpointConfig{})
+	resumeFrom, err := tracker.ResumePosition()
+	if err != nil {
+		return 0, err
+	}
+
+	src := &checkpointedLinkSource{
+		linkSource: linkSource{linkIt: linkIt},
+		tracker:    tracker,
+	}
+	if resumeFrom != "" {
+		if src.resumeAfter, err = uuid.Parse(resumeFrom); err != nil {
+			return 0, xerrors.Errorf("parse checkpoint: %w", err)
+		}
+		src.resume = true
+	}
+
+	sink := new(countingSink)
+	if err = c.p.Process(ctx, src, sink); err != nil {
+		// Persist the progress made so far so the next call can
+		// resume from it.
+		_ = tracker.Flush()
+		return sink.getCount(), err
+	}
+
+	// Don't clear the checkpoint if we were interrupted before exhausting
+	// the iterator.
+	if ctx.Err() != nil {
+		return sink.getCount(), tracker.Flush()
+	}
+	return sink.getCount(), tracker.Complete()
+}
+
 type linkSource struct {
 	linkIt graph.LinkIterator
 }
@@ -124,6 +266,47 @@ func (ls *linkSource) Payload() pipeline.Payload {
 	p.LinkID = link.ID
 	p.URL = link.URL
 	p.RetrievedAt = link.RetrievedAt
+	p.ContentHash = link.ContentHash
+	p.FailureCount = link.FailureCount
+	return p
+}
+
+// checkpointedLinkSource is a linkSource that skips links that have been
+// crawled by a previous run and registers each emitted link with a
+// checkpoint tracker.
+type checkpointedLinkSource struct {
+	linkSource
+	tracker *pipeline.CheckpointTracker
+
+	resume      bool
+	resumeAfter uuid.UUID
+}
+
+func (ls *checkpointedLinkSource) Error() error {
+	if err := ls.linkIt.Error(); err != nil {
+		return err
+	}
+	return ls.tracker.Err()
+}
+
+func (ls *checkpointedLinkSource) Next(context.Context) bool {
+	// Stop emitting links if the tracker cannot persist its progress.
+	if ls.tracker.Err() != nil {
+		return false
+	}
+
+	for ls.linkIt.Next() {
+		if ls.resume && bytes.Compare(ls.linkIt.Link().ID[:], ls.resumeAfter[:]) <= 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (ls *checkpointedLinkSource) Payload() pipeline.Payload {
+	p := ls.linkSource.Payload().(*crawlerPayload)
+	p.setAck(ls.tracker.Track(p.LinkID.String()))
 	return p
 }
 
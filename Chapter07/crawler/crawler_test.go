@@ -0,0 +1,138 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(CheckpointTestSuite))
+
+type CheckpointTestSuite struct {
+	store *pipeline.FileCheckpointStore
+	links []*graph.Link
+}
+
+func (s *CheckpointTestSuite) SetUpTest(c *gc.C) {
+	var err error
+	s.store, err = pipeline.NewFileCheckpointStore(c.MkDir())
+	c.Assert(err, gc.IsNil)
+
+	s.links = make([]*graph.Link, 5)
+	for i := range s.links {
+		s.links[i] = &graph.Link{ID: uuid.New(), URL: "http://example.com"}
+	}
+	sort.Slice(s.links, func(i, j int) bool {
+		return bytes.Compare(s.links[i].ID[:], s.links[j].ID[:]) < 0
+	})
+}
+
+func (s *CheckpointTestSuite) TestPayloadAckAfterAllClonesProcessed(c *gc.C) {
+	var acks int
+	p := new(crawlerPayload)
+	p.setAck(func() { acks++ })
+
+	clone := p.Clone()
+	p.MarkAsProcessed()
+	c.Assert(acks, gc.Equals, 0)
+
+	clone.MarkAsProcessed()
+	c.Assert(acks, gc.Equals, 1)
+}
+
+func (s *CheckpointTestSuite) TestCrawlWithCheckpointResumes(c *gc.C) {
+	// Simulate a previous run that was interrupted after crawling the
+	// first two links.
+	c.Assert(s.store.SaveCheckpoint("test", s.links[1].ID.String()), gc.IsNil)
+
+	rec := new(recordingProcessor)
+	crawler := s.newCrawler(rec)
+	count, err := crawler.CrawlWithCheckpoint(context.TODO(), "test", &sliceLinkIterator{links: s.links})
+	c.Assert(err, gc.IsNil)
+	c.Assert(count, gc.Equals, 3)
+	c.Assert(rec.ids, gc.DeepEquals, []uuid.UUID{s.links[2].ID, s.links[3].ID, s.links[4].ID})
+
+	// The checkpoint should be cleared after a complete run.
+	pos, err := s.store.LoadCheckpoint("test")
+	c.Assert(err, gc.IsNil)
+	c.Assert(pos, gc.Equals, "")
+}
+
+func (s *CheckpointTestSuite) TestCrawlWithCheckpointError(c *gc.C) {
+	rec := &recordingProcessor{failOn: s.links[2].ID}
+	crawler := s.newCrawler(rec)
+	_, err := crawler.CrawlWithCheckpoint(context.TODO(), "test", &sliceLinkIterator{links: s.links})
+	c.Assert(err, gc.ErrorMatches, "(?s).*crawl failed.*")
+
+	// Depending on how far the first links got before the pipeline was
+	// shut down, the checkpoint may point to any of the links preceding
+	// the one that failed.
+	pos, err := s.store.LoadCheckpoint("test")
+	c.Assert(err, gc.IsNil)
+	c.Assert(pos, gc.Not(gc.Equals), s.links[2].ID.String())
+	c.Assert(pos, gc.Not(gc.Equals), s.links[3].ID.String())
+	c.Assert(pos, gc.Not(gc.Equals), s.links[4].ID.String())
+}
+
+func (s *CheckpointTestSuite) TestCrawlWithoutCheckpointStore(c *gc.C) {
+	rec := new(recordingProcessor)
+	crawler := s.newCrawler(rec)
+	crawler.checkpoints = nil
+	count, err := crawler.CrawlWithCheckpoint(context.TODO(), "test", &sliceLinkIterator{links: s.links})
+	c.Assert(err, gc.IsNil)
+	c.Assert(count, gc.Equals, len(s.links))
+}
+
+// newCrawler returns a Crawler whose pipeline broadcasts each payload to proc
+// and a no-op processor, mirroring the fan-out of the real crawler pipeline.
+func (s *CheckpointTestSuite) newCrawler(proc pipeline.Processor) *Crawler {
+	noop := pipeline.ProcessorFunc(func(_ context.Context, p pipeline.Payload) (pipeline.Payload, error) {
+		return p, nil
+	})
+	return &Crawler{
+		p:           pipeline.New(pipeline.Broadcast(proc, noop)),
+		checkpoints: s.store,
+	}
+}
+
+type recordingProcessor struct {
+	mu     sync.Mutex
+	ids    []uuid.UUID
+	failOn uuid.UUID
+}
+
+func (p *recordingProcessor) Process(_ context.Context, payload pipeline.Payload) (pipeline.Payload, error) {
+	linkID := payload.(*crawlerPayload).LinkID
+	if linkID == p.failOn {
+		return nil, xerrors.New("crawl failed")
+	}
+
+	p.mu.Lock()
+	p.ids = append(p.ids, linkID)
+	p.mu.Unlock()
+	return payload, nil
+}
+
+type sliceLinkIterator struct {
+	links []*graph.Link
+	index int
+}
+
+func (it *sliceLinkIterator) Next() bool {
+	if it.index >= len(it.links) {
+		return false
+	}
+	it.index++
+	return true
+}
+
+func (it *sliceLinkIterator) Link() *graph.Link { return it.links[it.index-1] }
+func (it *sliceLinkIterator) Error() error      { return nil }
+func (it *sliceLinkIterator) Close() error      { return nil }
@@ -6,15 +6,18 @@ import (
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"github.com/google/uuid"
 )
 
 type graphUpdater struct {
 	updater Graph
+	indexer Indexer
 }
 
-func newGraphUpdater(updater Graph) *graphUpdater {
+func newGraphUpdater(updater Graph, indexer Indexer) *graphUpdater {
 	return &graphUpdater{
 		updater: updater,
+		indexer: indexer,
 	}
 }
 
@@ -22,34 +25,57 @@ func (u *graphUpdater) Process(ctx context.Context, p pipeline.Payload) (pipelin
 	payload := p.(*crawlerPayload)
 
 	src := &graph.Link{
-		ID:          payload.LinkID,
-		URL:         payload.URL,
-		RetrievedAt: time.Now(),
+		ID:           payload.LinkID,
+		URL:          payload.URL,
+		RetrievedAt:  time.Now(),
+		HTTPStatus:   payload.HTTPStatus,
+		ContentType:  payload.ContentType,
+		ContentHash:  payload.ContentHash,
+		FailureCount: payload.FailureCount,
 	}
 	if err := u.updater.UpsertLink(src); err != nil {
 		return nil, err
 	}
 
-	// Upsert discovered no-follow links without creating an edge
+	// Upsert discovered no-follow links and links in a single batch.
+	// Only the latter get edges created for them.
+	dstLinks := make([]*graph.Link, 0, len(payload.NoFollowLinks)+len(payload.Links))
 	for _, dstLink := range payload.NoFollowLinks {
-		dst := &graph.Link{URL: dstLink}
-		if err := u.updater.UpsertLink(dst); err != nil {
+		dstLinks = append(dstLinks, &graph.Link{URL: dstLink})
+	}
+	for _, dstLink := range payload.Links {
+		dstLinks = append(dstLinks, &graph.Link{URL: dstLink})
+	}
+	if len(dstLinks) != 0 {
+		if err := u.updater.UpsertLinks(dstLinks); err != nil {
 			return nil, err
 		}
 	}
 
-	// Upsert discovered links and create edges for them. Keep track of
-	// the current time so we can drop stale edges that have not been
-	// updated after this loop.
-	removeEdgesOlderThan := time.Now()
-	for _, dstLink := range payload.Links {
-		dst := &graph.Link{URL: dstLink}
-
-		if err := u.updater.UpsertLink(dst); err != nil {
+	// Record the anchor texts for the discovered links in a single batch.
+	// The link IDs are only known after the batch upsert.
+	anchorTexts := make(map[uuid.UUID][]string)
+	for _, dst := range dstLinks[len(payload.NoFollowLinks):] {
+		if text, found := payload.AnchorText[dst.URL]; found {
+			anchorTexts[dst.ID] = append(anchorTexts[dst.ID], text)
+		}
+	}
+	if len(anchorTexts) != 0 {
+		if err := u.indexer.AddAnchorTexts(anchorTexts); err != nil {
 			return nil, err
 		}
+	}
 
-		if err := u.updater.UpsertEdge(&graph.Edge{Src: src.ID, Dst: dst.ID}); err != nil {
+	// Create edges for the discovered links. Keep track of the current
+	// time so we can drop stale edges that have not been updated by the
+	// batch upsert.
+	removeEdgesOlderThan := time.Now()
+	edges := make([]*graph.Edge, 0, len(payload.Links))
+	for _, dst := range dstLinks[len(payload.NoFollowLinks):] {
+		edges = append(edges, &graph.Edge{Src: src.ID, Dst: dst.ID})
+	}
+	if len(edges) != 0 {
+		if err := u.updater.UpsertEdges(edges); err != nil {
 			return nil, err
 		}
 	}
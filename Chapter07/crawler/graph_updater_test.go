@@ -15,13 +15,15 @@ import (
 var _ = gc.Suite(new(GraphUpdaterTestSuite))
 
 type GraphUpdaterTestSuite struct {
-	graph *mocks.MockGraph
+	graph   *mocks.MockGraph
+	indexer *mocks.MockIndexer
 }
 
 func (s *GraphUpdaterTestSuite) TestGraphUpdater(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
 	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
 
 	payload := &crawlerPayload{
 		LinkID: uuid.New(),
@@ -33,23 +35,34 @@ func (s *GraphUpdaterTestSuite) TestGraphUpdater(c *gc.C) {
 			"http://example.com/foo",
 			"http://example.com/bar",
 		},
+		AnchorText: map[string]string{
+			"http://example.com/bar": "Bar",
+		},
 	}
 
 	exp := s.graph.EXPECT()
 
 	// We expect the original link to be upserted with a new timestamp and
-	// two additional insert calls for the discovered links.
+	// a batch upsert call for the discovered links.
 	exp.UpsertLink(linkMatcher{id: payload.LinkID, url: payload.URL, notBefore: time.Now()}).Return(nil)
 
 	id0, id1, id2 := uuid.New(), uuid.New(), uuid.New()
-	exp.UpsertLink(linkMatcher{url: "http://forum.com", notBefore: time.Time{}}).DoAndReturn(setLinkID(id0))
-	exp.UpsertLink(linkMatcher{url: "http://example.com/foo", notBefore: time.Time{}}).DoAndReturn(setLinkID(id1))
-	exp.UpsertLink(linkMatcher{url: "http://example.com/bar", notBefore: time.Time{}}).DoAndReturn(setLinkID(id2))
-
-	// We then expect two edges to be created from the origin link to the
-	// two links we just created.
-	exp.UpsertEdge(edgeMatcher{src: payload.LinkID, dst: id1}).Return(nil)
-	exp.UpsertEdge(edgeMatcher{src: payload.LinkID, dst: id2}).Return(nil)
+	exp.UpsertLinks(linkBatchMatcher{
+		linkMatcher{url: "http://forum.com", notBefore: time.Time{}},
+		linkMatcher{url: "http://example.com/foo", notBefore: time.Time{}},
+		linkMatcher{url: "http://example.com/bar", notBefore: time.Time{}},
+	}).DoAndReturn(setLinkIDs(id0, id1, id2))
+
+	// The anchor text of the followed links is recorded using the IDs
+	// assigned by the batch upsert.
+	s.indexer.EXPECT().AddAnchorTexts(map[uuid.UUID][]string{id2: {"Bar"}}).Return(nil)
+
+	// We then expect a batch call to create two edges from the origin
+	// link to the two followed links we just created.
+	exp.UpsertEdges(edgeBatchMatcher{
+		edgeMatcher{src: payload.LinkID, dst: id1},
+		edgeMatcher{src: payload.LinkID, dst: id2},
+	}).Return(nil)
 
 	// Finally we expect a call to drop stale edges whose source is the origin link.
 	exp.RemoveStaleEdges(payload.LinkID, gomock.Any()).Return(nil)
@@ -59,7 +72,7 @@ func (s *GraphUpdaterTestSuite) TestGraphUpdater(c *gc.C) {
 }
 
 func (s *GraphUpdaterTestSuite) updateGraph(c *gc.C, p *crawlerPayload) *crawlerPayload {
-	out, err := newGraphUpdater(s.graph).Process(context.TODO(), p)
+	out, err := newGraphUpdater(s.graph, s.indexer).Process(context.TODO(), p)
 	c.Assert(err, gc.IsNil)
 	if out != nil {
 		c.Assert(out, gc.FitsTypeOf, p)
@@ -69,9 +82,11 @@ func (s *GraphUpdaterTestSuite) updateGraph(c *gc.C, p *crawlerPayload) *crawler
 	return nil
 }
 
-func setLinkID(id uuid.UUID) func(*graph.Link) error {
-	return func(link *graph.Link) error {
-		link.ID = id
+func setLinkIDs(ids ...uuid.UUID) func([]*graph.Link) error {
+	return func(links []*graph.Link) error {
+		for i, link := range links {
+			link.ID = ids[i]
+		}
 		return nil
 	}
 }
@@ -106,3 +121,41 @@ func (em edgeMatcher) Matches(x interface{}) bool {
 func (em edgeMatcher) String() string {
 	return fmt.Sprintf("has Src=%q and Dst=%q", em.src, em.dst)
 }
+
+type linkBatchMatcher []linkMatcher
+
+func (m linkBatchMatcher) Matches(x interface{}) bool {
+	links := x.([]*graph.Link)
+	if len(links) != len(m) {
+		return false
+	}
+	for i, link := range links {
+		if !m[i].Matches(link) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m linkBatchMatcher) String() string {
+	return fmt.Sprintf("is a batch of %d links matching %v", len(m), []linkMatcher(m))
+}
+
+type edgeBatchMatcher []edgeMatcher
+
+func (m edgeBatchMatcher) Matches(x interface{}) bool {
+	edges := x.([]*graph.Edge)
+	if len(edges) != len(m) {
+		return false
+	}
+	for i, edge := range edges {
+		if !m[i].Matches(edge) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m edgeBatchMatcher) String() string {
+	return fmt.Sprintf("is a batch of %d edges matching %v", len(m), []edgeMatcher(m))
+}
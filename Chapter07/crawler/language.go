@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var htmlLangRegex = regexp.MustCompile(`(?is)<html\b[^>]*?\s(?:xml:)?lang\s*=\s*["']?([a-z]{2,3})\b`)
+
+// The minimum number of stop words that must be present in a document for
+// its language to be detected from its text content.
+const minLanguageStopWords = 3
+
+// The maximum number of words that are inspected when detecting the
+// language of a document.
+const maxLanguageDetectionWords = 1000
+
+// languageStopWords maps the ISO 639-1 code of each language that can be
+// detected from the text content of a document to a list of frequently used
+// words in that language.
+var languageStopWords = map[string]map[string]bool{
+	"de": wordSet("der die und das ist nicht ein eine zu den von mit sich auf für dem des auch es wird im werden bei oder wie"),
+	"en": wordSet("the and of to is in that it was for with are this be on not have you they which from by at as or an"),
+	"es": wordSet("el la los las de que y en del se por un una con no es para al lo como más pero sus le"),
+	"fr": wordSet("le la les et des est une un du dans que qui pour pas sur au avec ce il sont par plus ne se aux"),
+	"it": wordSet("il di che e la per un non una del della sono le con si da al gli nel anche come più questo ha"),
+	"nl": wordSet("de het een en van is dat niet op te zijn voor met die er aan ook als bij wordt door maar naar heeft"),
+	"pt": wordSet("o a os de que e do da em um uma para com não no na por se dos das mais como ao ou é"),
+}
+
+// detectLanguage returns the ISO 639-1 code for the language of a document
+// given its raw HTML content and the text extracted from it. The language
+// declared by the lang attribute of the html element takes precedence over
+// the language detected from the text. If the language cannot be determined,
+// detectLanguage returns an empty string.
+func detectLanguage(rawContent, textContent string) string {
+	if match := htmlLangRegex.FindStringSubmatch(rawContent); len(match) == 2 {
+		return strings.ToLower(match[1])
+	}
+
+	counts := make(map[string]int, len(languageStopWords))
+	words := strings.FieldsFunc(strings.ToLower(textContent), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) > maxLanguageDetectionWords {
+		words = words[:maxLanguageDetectionWords]
+	}
+	for _, word := range words {
+		for lang, stopWords := range languageStopWords {
+			if stopWords[word] {
+				counts[lang]++
+			}
+		}
+	}
+
+	// Pick the language with the most stop words; ties are ambiguous.
+	var bestLang string
+	var bestCount, runnerUpCount int
+	for lang, count := range counts {
+		switch {
+		case count > bestCount:
+			bestLang, bestCount, runnerUpCount = lang, count, bestCount
+		case count > runnerUpCount:
+			runnerUpCount = count
+		}
+	}
+	if bestCount < minLanguageStopWords || bestCount == runnerUpCount {
+		return ""
+	}
+	return bestLang
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
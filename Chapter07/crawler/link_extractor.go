@@ -2,8 +2,10 @@ package crawler
 
 import (
 	"context"
+	"html"
 	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
 )
@@ -13,15 +15,26 @@ var (
 	baseHrefRegex  = regexp.MustCompile(`(?i)<base.*?href\s*?=\s*?"(.*?)\s*?"`)
 	findLinkRegex  = regexp.MustCompile(`(?i)<a.*?href\s*?=\s*?"\s*?(.*?)\s*?".*?>`)
 	nofollowRegex  = regexp.MustCompile(`(?i)rel\s*?=\s*?"?nofollow"?`)
+	closeLinkRegex = regexp.MustCompile(`(?i)</a\s*>`)
+	htmlTagRegex   = regexp.MustCompile(`<[^>]*>`)
 )
 
+// The maximum number of bytes following an opening anchor tag that are
+// scanned for the matching closing tag.
+const maxAnchorTextScanLen = 1024
+
+// The maximum length (in runes) of extracted anchor texts.
+const maxAnchorTextLen = 128
+
 type linkExtractor struct {
-	netDetector PrivateNetworkDetector
+	netDetector   PrivateNetworkDetector
+	canonicalizer URLCanonicalizer
 }
 
-func newLinkExtractor(netDetector PrivateNetworkDetector) *linkExtractor {
+func newLinkExtractor(netDetector PrivateNetworkDetector, canonicalizer URLCanonicalizer) *linkExtractor {
 	return &linkExtractor{
-		netDetector: netDetector,
+		netDetector:   netDetector,
+		canonicalizer: canonicalizer,
 	}
 }
 
@@ -43,15 +56,19 @@ func (le *linkExtractor) Process(ctx context.Context, p pipeline.Payload) (pipel
 	// Find the unique set of links from the document, resolve them and
 	// add them to the payload.
 	seenMap := make(map[string]struct{})
-	for _, match := range findLinkRegex.FindAllStringSubmatch(content, -1) {
-		link := resolveURL(relTo, match[1])
+	for _, matchIndex := range findLinkRegex.FindAllStringSubmatchIndex(content, -1) {
+		match := content[matchIndex[0]:matchIndex[1]]
+		link := resolveURL(relTo, content[matchIndex[2]:matchIndex[3]])
 		if !le.retainLink(relTo.Hostname(), link) {
 			continue
 		}
 
-		// Truncate anchors and drop duplicates
-		link.Fragment = ""
-		linkStr := link.String()
+		// Canonicalize links (which also truncates anchors) and drop
+		// duplicates
+		linkStr, err := le.canonicalizer.Canonicalize(link.String())
+		if err != nil {
+			continue
+		}
 		if _, seen := seenMap[linkStr]; seen {
 			continue
 		}
@@ -62,10 +79,17 @@ func (le *linkExtractor) Process(ctx context.Context, p pipeline.Payload) (pipel
 		}
 
 		seenMap[linkStr] = struct{}{}
-		if nofollowRegex.MatchString(match[0]) {
+		if nofollowRegex.MatchString(match) {
 			payload.NoFollowLinks = append(payload.NoFollowLinks, linkStr)
-		} else {
-			payload.Links = append(payload.Links, linkStr)
+			continue
+		}
+
+		payload.Links = append(payload.Links, linkStr)
+		if text := extractAnchorText(content[matchIndex[1]:]); text != "" {
+			if payload.AnchorText == nil {
+				payload.AnchorText = make(map[string]string)
+			}
+			payload.AnchorText[linkStr] = text
 		}
 	}
 
@@ -96,6 +120,28 @@ func (le *linkExtractor) retainLink(srcHost string, link *url.URL) bool {
 	return true
 }
 
+// extractAnchorText returns the text of an anchor element given the content
+// that follows its opening tag. Any nested tags are stripped from the
+// returned text. If the closing tag cannot be located, extractAnchorText
+// returns an empty string.
+func extractAnchorText(content string) string {
+	if len(content) > maxAnchorTextScanLen {
+		content = content[:maxAnchorTextScanLen]
+	}
+	closeIndex := closeLinkRegex.FindStringIndex(content)
+	if closeIndex == nil {
+		return ""
+	}
+
+	text := strings.TrimSpace(repeatedSpaceRegex.ReplaceAllString(
+		html.UnescapeString(htmlTagRegex.ReplaceAllString(content[:closeIndex[0]], " ")), " ",
+	))
+	if runes := []rune(text); len(runes) > maxAnchorTextLen {
+		text = strings.TrimSpace(string(runes[:maxAnchorTextLen]))
+	}
+	return text
+}
+
 func ensureHasTrailingSlash(s string) string {
 	if s[len(s)-1] != '/' {
 		return s + "/"
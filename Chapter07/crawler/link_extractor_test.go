@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"sort"
 
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/canonical"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/crawler/mocks"
 	"github.com/golang/mock/gomock"
 	gc "gopkg.in/check.v1"
@@ -190,6 +191,32 @@ func (s *LinkExtractorTestSuite) TestLinkExtractorWithBaseTag(c *gc.C) {
 	}, nil)
 }
 
+func (s *LinkExtractorTestSuite) TestLinkExtractorCanonicalizesLinks(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+
+	exp := s.privNetDetector.EXPECT()
+	exp.IsPrivate("Example.com").Return(false, nil)
+	exp.IsPrivate("example.com:443").Return(false, nil)
+	exp.IsPrivate("example.com").Return(false, nil)
+
+	content := `
+<html>
+<body>
+<a href="https://Example.com/a/"/>
+<a href="https://example.com:443/a"/>
+<a href="https://example.com/b/../a?utm_source=newsletter"/>
+<a href="/c/./d/?b=2&a=1"/>
+</body>
+</html>
+`
+	s.assertExtractedLinks(c, "https://test.com", content, []string{
+		"https://example.com/a",
+		"https://test.com/c/d?a=1&b=2",
+	}, nil)
+}
+
 func (s *LinkExtractorTestSuite) TestLinkExtractorWithPrivateNetworkLinks(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -212,12 +239,44 @@ func (s *LinkExtractorTestSuite) TestLinkExtractorWithPrivateNetworkLinks(c *gc.
 	}, nil)
 }
 
+func (s *LinkExtractorTestSuite) TestLinkExtractorWithAnchorText(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
+
+	content := `
+<html>
+<body>
+<a href="https://example.com">Link to
+   <b>example</b> &amp; co</a>
+<a href="/no-text"><img src="/logo.png"/></a>
+<a href="/unclosed">Lorem ipsum
+<a href="/nofollow" rel="nofollow">Dolor sit amet</a>
+</body>
+</html>
+`
+	p := &crawlerPayload{URL: "http://test.com"}
+	_, err := p.RawContent.WriteString(content)
+	c.Assert(err, gc.IsNil)
+
+	le := newLinkExtractor(s.privNetDetector, canonical.NewURLCanonicalizer())
+	_, err = le.Process(context.TODO(), p)
+	c.Assert(err, gc.IsNil)
+
+	// The unclosed anchor spans up to the closing tag of the next anchor.
+	c.Assert(p.AnchorText, gc.DeepEquals, map[string]string{
+		"https://example.com":      "Link to example & co",
+		"http://test.com/unclosed": "Lorem ipsum Dolor sit amet",
+	})
+}
+
 func (s *LinkExtractorTestSuite) assertExtractedLinks(c *gc.C, url, content string, expLinks []string, expNoFollowLinks []string) {
 	p := &crawlerPayload{URL: url}
 	_, err := p.RawContent.WriteString(content)
 	c.Assert(err, gc.IsNil)
 
-	le := newLinkExtractor(s.privNetDetector)
+	le := newLinkExtractor(s.privNetDetector, canonical.NewURLCanonicalizer())
 	ret, err := le.Process(context.TODO(), p)
 	c.Assert(err, gc.IsNil)
 	c.Assert(ret, gc.DeepEquals, p)
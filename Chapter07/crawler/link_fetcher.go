@@ -2,11 +2,18 @@ package crawler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
+	"io/ioutil"
+	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"github.com/sirupsen/logrus"
 )
 
 var _ pipeline.Processor = (*linkFetcher)(nil)
@@ -14,12 +21,28 @@ var _ pipeline.Processor = (*linkFetcher)(nil)
 type linkFetcher struct {
 	urlGetter   URLGetter
 	netDetector PrivateNetworkDetector
+	updater     Graph
+	indexer     Indexer
+
+	// The base delay before retrying a link that could not be retrieved.
+	// If zero, links are retried regardless of their failure count.
+	failureBackoff time.Duration
+
+	// A logger for reporting errors that occur while recording the
+	// metadata of links that are not processed further.
+	logger *logrus.Entry
 }
 
-func newLinkFetcher(urlGetter URLGetter, netDetector PrivateNetworkDetector) *linkFetcher {
+// The maximum number of times the failure backoff gets doubled.
+const maxFailureBackoffShift = 10
+
+func newLinkFetcher(urlGetter URLGetter, netDetector PrivateNetworkDetector, updater Graph, indexer Indexer) *linkFetcher {
 	return &linkFetcher{
 		urlGetter:   urlGetter,
 		netDetector: netDetector,
+		updater:     updater,
+		indexer:     indexer,
+		logger:      logrus.NewEntry(&logrus.Logger{Out: ioutil.Discard}),
 	}
 }
 
@@ -31,6 +54,11 @@ func (lf *linkFetcher) Process(ctx context.Context, p pipeline.Payload) (pipelin
 		return nil, nil
 	}
 
+	// Back off from links that could not be retrieved by previous passes.
+	if lf.shouldBackOff(payload) {
+		return nil, nil
+	}
+
 	// Never crawl links in private networks (e.g. link-local addresses).
 	// This is a security risk!
 	if isPrivate, err := lf.isPrivate(payload.URL); err != nil || isPrivate {
@@ -39,6 +67,8 @@ func (lf *linkFetcher) Process(ctx context.Context, p pipeline.Payload) (pipelin
 
 	res, err := lf.urlGetter.Get(payload.URL)
 	if err != nil {
+		payload.FailureCount++
+		lf.recordMetadata(payload)
 		return nil, nil
 	}
 	_, err = io.Copy(&payload.RawContent, res.Body)
@@ -47,19 +77,82 @@ func (lf *linkFetcher) Process(ctx context.Context, p pipeline.Payload) (pipelin
 		return nil, err
 	}
 
+	payload.HTTPStatus = res.StatusCode
+	payload.ContentType = res.Header.Get("Content-Type")
+
 	// Skip payloads for invalid http status codes.
 	if res.StatusCode < 200 || res.StatusCode > 299 {
+		payload.FailureCount++
+
+		// Evict pages that no longer exist from the index. The content
+		// hash is cleared so the page gets re-indexed if it reappears.
+		if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone {
+			payload.ContentHash = ""
+			lf.recordMetadata(payload)
+			if err = lf.indexer.Delete(payload.LinkID); err != nil {
+				lf.logger.WithField("link_id", payload.LinkID).WithError(err).Warn("unable to remove missing page from the index")
+			}
+			return nil, nil
+		}
+		lf.recordMetadata(payload)
 		return nil, nil
 	}
 
+	prevContentHash := payload.ContentHash
+	contentHash := sha256.Sum256(payload.RawContent.Bytes())
+	payload.ContentHash = hex.EncodeToString(contentHash[:])
+	payload.FailureCount = 0
+
 	// Skip payloads for non-html payloads
-	if contentType := res.Header.Get("Content-Type"); !strings.Contains(contentType, "html") {
+	if !strings.Contains(payload.ContentType, "html") {
+		lf.recordMetadata(payload)
+		return nil, nil
+	}
+
+	// Pages whose content has not changed since they were last crawled
+	// do not need to be processed and indexed again.
+	if payload.ContentHash == prevContentHash {
+		lf.recordMetadata(payload)
 		return nil, nil
 	}
 
 	return payload, nil
 }
 
+// shouldBackOff returns true if the payload refers to a link that failed to
+// be retrieved and not enough time has elapsed since the last attempt. The
+// delay between attempts doubles with each consecutive failure.
+func (lf *linkFetcher) shouldBackOff(payload *crawlerPayload) bool {
+	if lf.failureBackoff <= 0 || payload.FailureCount == 0 {
+		return false
+	}
+
+	shift := payload.FailureCount - 1
+	if shift > maxFailureBackoffShift {
+		shift = maxFailureBackoffShift
+	}
+	return time.Since(payload.RetrievedAt) < lf.failureBackoff<<uint(shift)
+}
+
+// recordMetadata updates the retrieval metadata for a link that will not be
+// processed further by the pipeline and hence will not reach the graph
+// updater stage. As the link is dropped either way, errors are logged
+// instead of being returned so that they do not abort the crawl pass.
+func (lf *linkFetcher) recordMetadata(payload *crawlerPayload) {
+	err := lf.updater.UpsertLink(&graph.Link{
+		ID:           payload.LinkID,
+		URL:          payload.URL,
+		RetrievedAt:  time.Now(),
+		HTTPStatus:   payload.HTTPStatus,
+		ContentType:  payload.ContentType,
+		ContentHash:  payload.ContentHash,
+		FailureCount: payload.FailureCount,
+	})
+	if err != nil {
+		lf.logger.WithField("link_id", payload.LinkID).WithError(err).Warn("unable to record link metadata")
+	}
+}
+
 func (lf *linkFetcher) isPrivate(URL string) (bool, error) {
 	u, err := url.Parse(URL)
 	if err != nil {
@@ -5,9 +5,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/crawler/mocks"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 	gc "gopkg.in/check.v1"
 )
 
@@ -16,6 +20,8 @@ var _ = gc.Suite(new(LinkFetcherTestSuite))
 type LinkFetcherTestSuite struct {
 	urlGetter       *mocks.MockURLGetter
 	privNetDetector *mocks.MockPrivateNetworkDetector
+	graph           *mocks.MockGraph
+	indexer         *mocks.MockIndexer
 }
 
 func (s *LinkFetcherTestSuite) SetUpTest(c *gc.C) {
@@ -26,6 +32,8 @@ func (s *LinkFetcherTestSuite) TestLinkFetcherWithExcludedExtension(c *gc.C) {
 	defer ctrl.Finish()
 	s.urlGetter = mocks.NewMockURLGetter(ctrl)
 	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
 
 	p := s.fetchLink(c, "http://example.com/foo.png")
 	c.Assert(p, gc.IsNil)
@@ -36,6 +44,8 @@ func (s *LinkFetcherTestSuite) TestLinkFetcher(c *gc.C) {
 	defer ctrl.Finish()
 	s.urlGetter = mocks.NewMockURLGetter(ctrl)
 	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
 
 	s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
 	s.urlGetter.EXPECT().Get("http://example.com/index.html").Return(
@@ -45,6 +55,10 @@ func (s *LinkFetcherTestSuite) TestLinkFetcher(c *gc.C) {
 
 	p := s.fetchLink(c, "http://example.com/index.html")
 	c.Assert(p.RawContent.String(), gc.Equals, "hello")
+	c.Assert(p.HTTPStatus, gc.Equals, 200)
+	c.Assert(p.ContentType, gc.Equals, "application/xhtml")
+	c.Assert(p.ContentHash, gc.Equals, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")
+	c.Assert(p.FailureCount, gc.Equals, 0)
 }
 
 func (s *LinkFetcherTestSuite) TestLinkFetcherForLinkWithPortNumber(c *gc.C) {
@@ -52,6 +66,8 @@ func (s *LinkFetcherTestSuite) TestLinkFetcherForLinkWithPortNumber(c *gc.C) {
 	defer ctrl.Finish()
 	s.urlGetter = mocks.NewMockURLGetter(ctrl)
 	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
 
 	s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
 	s.urlGetter.EXPECT().Get("http://example.com:1234").Return(
@@ -68,28 +84,188 @@ func (s *LinkFetcherTestSuite) TestLinkFetcherWithWrongStatusCode(c *gc.C) {
 	defer ctrl.Finish()
 	s.urlGetter = mocks.NewMockURLGetter(ctrl)
 	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
 
 	s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
 	s.urlGetter.EXPECT().Get("http://example.com/index.html").Return(
 		makeResponse(400, `{"error":"something went wrong"}`, "application/json"),
 		nil,
 	)
+	s.graph.EXPECT().UpsertLink(gomock.Any()).DoAndReturn(func(link *graph.Link) error {
+		c.Assert(link.URL, gc.Equals, "http://example.com/index.html")
+		c.Assert(link.HTTPStatus, gc.Equals, 400)
+		c.Assert(link.ContentType, gc.Equals, "application/json")
+		c.Assert(link.ContentHash, gc.Equals, "")
+		c.Assert(link.FailureCount, gc.Equals, 1)
+		return nil
+	})
 
 	p := s.fetchLink(c, "http://example.com/index.html")
 	c.Assert(p, gc.IsNil)
 }
 
+func (s *LinkFetcherTestSuite) TestLinkFetcherEvictsMissingPages(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	s.urlGetter = mocks.NewMockURLGetter(ctrl)
+	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
+
+	for _, status := range []int{404, 410} {
+		linkID := uuid.New()
+		s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
+		s.urlGetter.EXPECT().Get("http://example.com/index.html").Return(
+			makeResponse(status, "", "text/html"),
+			nil,
+		)
+		s.graph.EXPECT().UpsertLink(gomock.Any()).DoAndReturn(func(link *graph.Link) error {
+			c.Assert(link.ID, gc.Equals, linkID)
+			c.Assert(link.HTTPStatus, gc.Equals, status)
+			c.Assert(link.ContentHash, gc.Equals, "", gc.Commentf("content hash of missing page was not cleared"))
+			return nil
+		})
+		s.indexer.EXPECT().Delete(linkID).Return(nil)
+
+		p := &crawlerPayload{LinkID: linkID, URL: "http://example.com/index.html", ContentHash: "deadbeef"}
+		out, err := newLinkFetcher(s.urlGetter, s.privNetDetector, s.graph, s.indexer).Process(context.TODO(), p)
+		c.Assert(err, gc.IsNil)
+		c.Assert(out, gc.IsNil)
+	}
+}
+
+func (s *LinkFetcherTestSuite) TestLinkFetcherSkipsUnchangedContent(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	s.urlGetter = mocks.NewMockURLGetter(ctrl)
+	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
+
+	s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
+	s.urlGetter.EXPECT().Get("http://example.com/index.html").Return(
+		makeResponse(200, "hello", "text/html"),
+		nil,
+	)
+
+	// The page content matches the stored hash so only the retrieval
+	// metadata should be updated.
+	helloHash := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	s.graph.EXPECT().UpsertLink(gomock.Any()).DoAndReturn(func(link *graph.Link) error {
+		c.Assert(link.ContentHash, gc.Equals, helloHash)
+		c.Assert(link.FailureCount, gc.Equals, 0)
+		c.Assert(link.RetrievedAt.IsZero(), gc.Equals, false)
+		return nil
+	})
+
+	p := &crawlerPayload{URL: "http://example.com/index.html", ContentHash: helloHash}
+	out, err := newLinkFetcher(s.urlGetter, s.privNetDetector, s.graph, s.indexer).Process(context.TODO(), p)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.IsNil)
+}
+
+func (s *LinkFetcherTestSuite) TestLinkFetcherBacksOffFailedLinks(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	s.urlGetter = mocks.NewMockURLGetter(ctrl)
+	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
+
+	fetcher := newLinkFetcher(s.urlGetter, s.privNetDetector, s.graph, s.indexer)
+	fetcher.failureBackoff = time.Hour
+
+	// After 3 failures the backoff is 4h so a link last tried 3h ago
+	// should be skipped without being retrieved.
+	p := &crawlerPayload{
+		URL:          "http://example.com/index.html",
+		FailureCount: 3,
+		RetrievedAt:  time.Now().Add(-3 * time.Hour),
+	}
+	out, err := fetcher.Process(context.TODO(), p)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.IsNil)
+
+	// Once the backoff expires, the link should be retrieved again.
+	p.RetrievedAt = time.Now().Add(-5 * time.Hour)
+	s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
+	s.urlGetter.EXPECT().Get("http://example.com/index.html").Return(
+		makeResponse(200, "hello", "text/html"),
+		nil,
+	)
+	out, err = fetcher.Process(context.TODO(), p)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Not(gc.IsNil))
+	c.Assert(out.(*crawlerPayload).FailureCount, gc.Equals, 0)
+}
+
+func (s *LinkFetcherTestSuite) TestLinkFetcherWithFetchError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	s.urlGetter = mocks.NewMockURLGetter(ctrl)
+	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
+
+	s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
+	s.urlGetter.EXPECT().Get("http://example.com/index.html").Return(nil, xerrors.New("connection refused"))
+	s.graph.EXPECT().UpsertLink(gomock.Any()).DoAndReturn(func(link *graph.Link) error {
+		c.Assert(link.URL, gc.Equals, "http://example.com/index.html")
+		c.Assert(link.HTTPStatus, gc.Equals, 0)
+		c.Assert(link.ContentHash, gc.Equals, "abcd", gc.Commentf("expected previous content hash to be retained"))
+		c.Assert(link.FailureCount, gc.Equals, 3)
+		return nil
+	})
+
+	p := &crawlerPayload{URL: "http://example.com/index.html", ContentHash: "abcd", FailureCount: 2}
+	out, err := newLinkFetcher(s.urlGetter, s.privNetDetector, s.graph, s.indexer).Process(context.TODO(), p)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.IsNil)
+}
+
+func (s *LinkFetcherTestSuite) TestLinkFetcherDropsMetadataErrors(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	s.urlGetter = mocks.NewMockURLGetter(ctrl)
+	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
+
+	s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
+	s.urlGetter.EXPECT().Get("http://example.com/gone.html").Return(
+		makeResponse(404, "not found", "text/html"),
+		nil,
+	)
+	s.graph.EXPECT().UpsertLink(gomock.Any()).Return(xerrors.New("graph unavailable"))
+	s.indexer.EXPECT().Delete(gomock.Any()).Return(xerrors.New("indexer unavailable"))
+
+	// Failing to record the metadata of a dropped link must not abort the
+	// crawl pass.
+	p := s.fetchLink(c, "http://example.com/gone.html")
+	c.Assert(p, gc.IsNil)
+}
+
 func (s *LinkFetcherTestSuite) TestLinkFetcherWithNonHTMLContentType(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
 	s.urlGetter = mocks.NewMockURLGetter(ctrl)
 	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
 
 	s.privNetDetector.EXPECT().IsPrivate("example.com").Return(false, nil)
 	s.urlGetter.EXPECT().Get("http://example.com/list/products").Return(
 		makeResponse(200, `["a", "b", "c"]`, "application/json"),
 		nil,
 	)
+	s.graph.EXPECT().UpsertLink(gomock.Any()).DoAndReturn(func(link *graph.Link) error {
+		c.Assert(link.HTTPStatus, gc.Equals, 200)
+		c.Assert(link.ContentType, gc.Equals, "application/json")
+		c.Assert(link.ContentHash, gc.Not(gc.Equals), "")
+		c.Assert(link.FailureCount, gc.Equals, 0)
+		return nil
+	})
 
 	p := s.fetchLink(c, "http://example.com/list/products")
 	c.Assert(p, gc.IsNil)
@@ -100,6 +276,8 @@ func (s *LinkFetcherTestSuite) TestLinkFetcherWithLinkThatResolvesToPrivateNetwo
 	defer ctrl.Finish()
 	s.urlGetter = mocks.NewMockURLGetter(ctrl)
 	s.privNetDetector = mocks.NewMockPrivateNetworkDetector(ctrl)
+	s.graph = mocks.NewMockGraph(ctrl)
+	s.indexer = mocks.NewMockIndexer(ctrl)
 
 	s.privNetDetector.EXPECT().IsPrivate("169.254.169.254").Return(true, nil)
 
@@ -109,7 +287,7 @@ func (s *LinkFetcherTestSuite) TestLinkFetcherWithLinkThatResolvesToPrivateNetwo
 
 func (s *LinkFetcherTestSuite) fetchLink(c *gc.C, url string) *crawlerPayload {
 	p := &crawlerPayload{URL: url}
-	out, err := newLinkFetcher(s.urlGetter, s.privNetDetector).Process(context.TODO(), p)
+	out, err := newLinkFetcher(s.urlGetter, s.privNetDetector, s.graph, s.indexer).Process(context.TODO(), p)
 	c.Assert(err, gc.IsNil)
 	if out != nil {
 		c.Assert(out, gc.FitsTypeOf, p)
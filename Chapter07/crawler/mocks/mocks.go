@@ -141,6 +141,20 @@ func (mr *MockGraphMockRecorder) UpsertEdge(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertEdge", reflect.TypeOf((*MockGraph)(nil).UpsertEdge), arg0)
 }
 
+// UpsertEdges mocks base method
+func (m *MockGraph) UpsertEdges(arg0 []*graph.Edge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertEdges", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertEdges indicates an expected call of UpsertEdges
+func (mr *MockGraphMockRecorder) UpsertEdges(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertEdges", reflect.TypeOf((*MockGraph)(nil).UpsertEdges), arg0)
+}
+
 // UpsertLink mocks base method
 func (m *MockGraph) UpsertLink(arg0 *graph.Link) error {
 	m.ctrl.T.Helper()
@@ -155,6 +169,20 @@ func (mr *MockGraphMockRecorder) UpsertLink(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLink", reflect.TypeOf((*MockGraph)(nil).UpsertLink), arg0)
 }
 
+// UpsertLinks mocks base method
+func (m *MockGraph) UpsertLinks(arg0 []*graph.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLinks", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertLinks indicates an expected call of UpsertLinks
+func (mr *MockGraphMockRecorder) UpsertLinks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLinks", reflect.TypeOf((*MockGraph)(nil).UpsertLinks), arg0)
+}
+
 // MockIndexer is a mock of Indexer interface
 type MockIndexer struct {
 	ctrl     *gomock.Controller
@@ -178,6 +206,34 @@ func (m *MockIndexer) EXPECT() *MockIndexerMockRecorder {
 	return m.recorder
 }
 
+// AddAnchorTexts mocks base method
+func (m *MockIndexer) AddAnchorTexts(arg0 map[uuid.UUID][]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAnchorTexts", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAnchorTexts indicates an expected call of AddAnchorTexts
+func (mr *MockIndexerMockRecorder) AddAnchorTexts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAnchorTexts", reflect.TypeOf((*MockIndexer)(nil).AddAnchorTexts), arg0)
+}
+
+// Delete mocks base method
+func (m *MockIndexer) Delete(arg0 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockIndexerMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIndexer)(nil).Delete), arg0)
+}
+
 // Index mocks base method
 func (m *MockIndexer) Index(arg0 *index.Document) error {
 	m.ctrl.T.Helper()
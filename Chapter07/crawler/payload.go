@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
@@ -24,6 +25,13 @@ type crawlerPayload struct {
 	URL         string
 	RetrievedAt time.Time
 
+	// Retrieval metadata for the link. ContentHash and FailureCount are
+	// populated from the link graph and get updated by the link fetcher.
+	HTTPStatus   int
+	ContentType  string
+	ContentHash  string
+	FailureCount int
+
 	RawContent bytes.Buffer
 
 	// NoFollowLinks are still added to the graph but no outgoing edges
@@ -33,6 +41,33 @@ type crawlerPayload struct {
 	Links       []string
 	Title       string
 	TextContent string
+	Language    string
+
+	// The contents of the description meta tag and the text of the h1-h3
+	// headings of the page.
+	Description string
+	Headings    []string
+
+	// AnchorText maps each link in Links to the text of the first anchor
+	// element that points to it (if any).
+	AnchorText map[string]string
+
+	// ack is shared by a payload and all its clones and is invoked once
+	// all of them have been marked as processed.
+	ack *payloadAck
+}
+
+// payloadAck keeps track of the number of live copies of a payload and
+// invokes fn once the last copy has been marked as processed.
+type payloadAck struct {
+	refs int32
+	fn   func()
+}
+
+// setAck registers fn to be invoked once the payload and all its clones have
+// been marked as processed.
+func (p *crawlerPayload) setAck(fn func()) {
+	p.ack = &payloadAck{refs: 1, fn: fn}
 }
 
 // Clone implements pipeline.Payload.
@@ -41,10 +76,28 @@ func (p *crawlerPayload) Clone() pipeline.Payload {
 	newP.LinkID = p.LinkID
 	newP.URL = p.URL
 	newP.RetrievedAt = p.RetrievedAt
+	newP.HTTPStatus = p.HTTPStatus
+	newP.ContentType = p.ContentType
+	newP.ContentHash = p.ContentHash
+	newP.FailureCount = p.FailureCount
 	newP.NoFollowLinks = append([]string(nil), p.NoFollowLinks...)
 	newP.Links = append([]string(nil), p.Links...)
 	newP.Title = p.Title
 	newP.TextContent = p.TextContent
+	newP.Language = p.Language
+	newP.Description = p.Description
+	newP.Headings = append([]string(nil), p.Headings...)
+	if len(p.AnchorText) != 0 {
+		newP.AnchorText = make(map[string]string, len(p.AnchorText))
+		for link, text := range p.AnchorText {
+			newP.AnchorText[link] = text
+		}
+	}
+
+	if p.ack != nil {
+		atomic.AddInt32(&p.ack.refs, 1)
+		newP.ack = p.ack
+	}
 
 	_, err := io.Copy(&newP.RawContent, &p.RawContent)
 	if err != nil {
@@ -56,10 +109,26 @@ func (p *crawlerPayload) Clone() pipeline.Payload {
 // MarkAsProcessed implements pipeline.Payload
 func (p *crawlerPayload) MarkAsProcessed() {
 	p.URL = p.URL[:0]
+	p.HTTPStatus = 0
+	p.ContentType = p.ContentType[:0]
+	p.ContentHash = p.ContentHash[:0]
+	p.FailureCount = 0
 	p.RawContent.Reset()
 	p.NoFollowLinks = p.NoFollowLinks[:0]
 	p.Links = p.Links[:0]
 	p.Title = p.Title[:0]
 	p.TextContent = p.TextContent[:0]
+	p.Language = p.Language[:0]
+	p.Description = p.Description[:0]
+	p.Headings = p.Headings[:0]
+	for link := range p.AnchorText {
+		delete(p.AnchorText, link)
+	}
+	if p.ack != nil {
+		if atomic.AddInt32(&p.ack.refs, -1) == 0 {
+			p.ack.fn()
+		}
+		p.ack = nil
+	}
 	payloadPool.Put(p)
 }
@@ -0,0 +1,437 @@
+package crawler
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+)
+
+var _ pipeline.StageRunner = (*politeFetcher)(nil)
+
+const (
+	// The default number of links that the politeness scheduler buffers
+	// while waiting for their hosts to become available.
+	defaultHostQueueCapacity = 1000
+
+	// The default number of links that the politeness scheduler buffers
+	// for any single host. Links for a host whose queue is full are
+	// deferred to a later crawler pass.
+	defaultPerHostQueueCapacity = 100
+
+	// The maximum number of robots.txt files that the politeness scheduler
+	// retrieves concurrently.
+	maxConcurrentRobotsFetches = 16
+
+	// The minimum number of hosts tracked by hostScheduler before it
+	// attempts to evict idle hosts.
+	minHostsBeforeEviction = 1024
+)
+
+// politeFetcher is a pipeline stage that fetches links using a pool of
+// workers while ensuring that the load placed on each host is kept within
+// the limits enforced by a hostScheduler.
+type politeFetcher struct {
+	sched *hostScheduler
+	pool  pipeline.StageRunner
+}
+
+func newPoliteFetcher(fetcher *linkFetcher, sched *hostScheduler, numWorkers int) *politeFetcher {
+	// Release the host slot acquired by the scheduler once the fetcher is
+	// done with each link.
+	releasingFetcher := pipeline.ProcessorFunc(func(ctx context.Context, p pipeline.Payload) (pipeline.Payload, error) {
+		defer sched.release(hostKey(p.(*crawlerPayload).URL))
+		return fetcher.Process(ctx, p)
+	})
+
+	return &politeFetcher{
+		sched: sched,
+		pool:  pipeline.FixedWorkerPool(releasingFetcher, numWorkers),
+	}
+}
+
+// Run implements pipeline.StageRunner.
+func (f *politeFetcher) Run(ctx context.Context, params pipeline.StageParams) {
+	var wg sync.WaitGroup
+	dispatchCh := make(chan pipeline.Payload)
+
+	wg.Add(1)
+	go func() {
+		f.pool.Run(ctx, &dispatchParams{StageParams: params, inCh: dispatchCh})
+		wg.Done()
+	}()
+
+	f.dispatch(ctx, params.Input(), dispatchCh)
+
+	// Signal the worker pool that no more data is available.
+	close(dispatchCh)
+	wg.Wait()
+}
+
+// dispatch queues the links read from inCh by host and forwards them to
+// outCh in the order determined by the scheduler. It returns once inCh has
+// been closed and all queued links have been dispatched or ctx expires. In
+// the latter case, the links that have not been dispatched are marked as
+// processed.
+//
+// Links whose host queue is full are marked as processed without being
+// dispatched so that a single host with a large number of links cannot stop
+// dispatch from reading the links for other hosts. As such links are not
+// retrieved, they will be picked up again by a later crawler pass.
+func (f *politeFetcher) dispatch(ctx context.Context, inCh <-chan pipeline.Payload, outCh chan<- pipeline.Payload) {
+	var pending *crawlerPayload
+	for {
+		var wakeAt time.Time
+		if pending == nil {
+			pending, wakeAt = f.sched.next(time.Now())
+		}
+		if pending == nil && inCh == nil && f.sched.empty() {
+			return
+		}
+
+		// Only enable the select cases that can make progress.
+		var (
+			selInCh  = inCh
+			selOutCh chan<- pipeline.Payload
+			timer    *time.Timer
+			timerCh  <-chan time.Time
+		)
+		if f.sched.full() {
+			selInCh = nil
+		}
+		if pending != nil {
+			selOutCh = outCh
+		}
+		if !wakeAt.IsZero() {
+			timer = time.NewTimer(time.Until(wakeAt))
+			timerCh = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			// Asked to cleanly shut down; release the links that
+			// will never reach the worker pool.
+			if timer != nil {
+				timer.Stop()
+			}
+			if pending != nil {
+				f.sched.release(hostKey(pending.URL))
+				pending.MarkAsProcessed()
+			}
+			for _, p := range f.sched.drain() {
+				p.MarkAsProcessed()
+			}
+			return
+		case selOutCh <- pending:
+			pending = nil
+		case payload, ok := <-selInCh:
+			if !ok {
+				inCh = nil
+				break
+			}
+			if p := payload.(*crawlerPayload); !f.sched.enqueue(p) {
+				p.MarkAsProcessed()
+			}
+		case <-f.sched.wakeCh:
+			// A host slot has been released or the robots.txt
+			// file of a host has been retrieved.
+		case <-timerCh:
+			// A host delay has expired.
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// dispatchParams overrides the input channel of a pipeline.StageParams.
+type dispatchParams struct {
+	pipeline.StageParams
+	inCh <-chan pipeline.Payload
+}
+
+func (p *dispatchParams) Input() <-chan pipeline.Payload { return p.inCh }
+
+// hostScheduler groups links into per-host queues and decides which link
+// should be fetched next. Hosts are visited in round-robin order so that a
+// host with a large number of links cannot starve the others. A link is only
+// handed out if fetching it would not exceed the maximum number of concurrent
+// requests to its host and the required delay since the last request to its
+// host has elapsed.
+//
+// The delay for each host is the larger of the configured minimum delay and
+// the Crawl-delay directive in the host's robots.txt file. The robots.txt
+// file is retrieved in the background when the first link for a host is
+// queued and no links for that host are handed out until it has been
+// processed.
+type hostScheduler struct {
+	maxConns      int
+	minDelay      time.Duration
+	maxCrawlDelay time.Duration
+	userAgent     string
+	urlGetter     URLGetter
+	capacity      int
+	hostCapacity  int
+
+	// wakeCh is signalled each time a host slot is released or the
+	// robots.txt file for a host has been processed.
+	wakeCh chan struct{}
+
+	// robotsSem limits the number of concurrent robots.txt requests.
+	robotsSem chan struct{}
+
+	mu        sync.Mutex
+	hosts     map[string]*hostState
+	ring      []*hostState
+	cursor    int
+	queued    int
+	evictSize int
+}
+
+// hostState tracks the queued links and the requests in progress for a
+// single host.
+type hostState struct {
+	queue          []*crawlerPayload
+	active         int
+	lastStart      time.Time
+	crawlDelay     time.Duration
+	robotsResolved bool
+	fetchingRobots bool
+}
+
+func newHostScheduler(cfg Config) *hostScheduler {
+	return &hostScheduler{
+		maxConns:      cfg.MaxHostConns,
+		minDelay:      cfg.MinHostDelay,
+		maxCrawlDelay: cfg.MaxCrawlDelay,
+		userAgent:     cfg.RobotsUserAgent,
+		urlGetter:     cfg.URLGetter,
+		capacity:      defaultHostQueueCapacity,
+		hostCapacity:  defaultPerHostQueueCapacity,
+		wakeCh:        make(chan struct{}, 1),
+		robotsSem:     make(chan struct{}, maxConcurrentRobotsFetches),
+		hosts:         make(map[string]*hostState),
+		evictSize:     minHostsBeforeEviction,
+	}
+}
+
+// hostKey returns the key used for grouping links by host.
+func hostKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// enqueue appends a link to the queue of its host. It returns false without
+// queueing the link if the queue for its host is full.
+func (s *hostScheduler) enqueue(p *crawlerPayload) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.host(hostKey(p.URL))
+	if len(h.queue) >= s.hostCapacity {
+		return false
+	}
+	h.queue = append(h.queue, p)
+	if len(h.queue) == 1 {
+		s.ring = append(s.ring, h)
+	}
+	s.queued++
+
+	if !h.robotsResolved && !h.fetchingRobots {
+		h.fetchingRobots = true
+		go s.resolveRobots(h, p.URL)
+	}
+	return true
+}
+
+// full returns true if the number of queued links has reached the capacity
+// of the scheduler.
+func (s *hostScheduler) full() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queued >= s.capacity
+}
+
+// empty returns true if no links are queued.
+func (s *hostScheduler) empty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queued == 0
+}
+
+// drain removes and returns all queued links.
+func (s *hostScheduler) drain() []*crawlerPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	drained := make([]*crawlerPayload, 0, s.queued)
+	for _, h := range s.ring {
+		drained = append(drained, h.queue...)
+		h.queue = nil
+	}
+	s.ring, s.cursor, s.queued = nil, 0, 0
+	return drained
+}
+
+// next removes and returns the next link that can be fetched at time now and
+// acquires a slot for its host. The slot must be returned via a call to
+// release once the link has been fetched. If no link can be fetched, next
+// returns nil and the earliest time at which the delay for one of the hosts
+// with queued links expires. A zero time indicates that the caller needs to
+// wait for a host slot to be released or for the robots.txt file of a host
+// to be processed.
+func (s *hostScheduler) next(now time.Time) (*crawlerPayload, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var wakeAt time.Time
+	for i := 0; i < len(s.ring); i++ {
+		idx := (s.cursor + i) % len(s.ring)
+		h := s.ring[idx]
+		if !h.robotsResolved || h.active >= s.maxConns {
+			continue
+		}
+		if readyAt := h.lastStart.Add(s.delay(h)); now.Before(readyAt) {
+			if wakeAt.IsZero() || readyAt.Before(wakeAt) {
+				wakeAt = readyAt
+			}
+			continue
+		}
+
+		p := h.queue[0]
+		h.queue[0] = nil
+		h.queue = h.queue[1:]
+		h.active++
+		h.lastStart = now
+		s.queued--
+
+		// Continue from the host following this one on the next call.
+		// If the queue for this host has been drained, remove it from
+		// the ring; the next host will then occupy its slot.
+		if len(h.queue) == 0 {
+			h.queue = nil
+			s.ring = append(s.ring[:idx], s.ring[idx+1:]...)
+			s.cursor = idx
+		} else {
+			s.cursor = idx + 1
+		}
+		if len(s.ring) != 0 {
+			s.cursor %= len(s.ring)
+		} else {
+			s.cursor = 0
+		}
+		return p, time.Time{}
+	}
+
+	return nil, wakeAt
+}
+
+// release returns a slot acquired by a call to next for the specified host.
+func (s *hostScheduler) release(host string) {
+	s.mu.Lock()
+	if h, exists := s.hosts[host]; exists && h.active > 0 {
+		h.active--
+	}
+	s.mu.Unlock()
+	s.wake()
+}
+
+// wake signals wakeCh without blocking.
+func (s *hostScheduler) wake() {
+	select {
+	case s.wakeCh <- struct{}{}:
+	default: // a wake-up is already pending.
+	}
+}
+
+// resolveRobots retrieves the robots.txt file for the host of rawURL and
+// records the Crawl-delay directive that applies to the crawler, if any. Once
+// it returns, the links queued for h can be handed out by next.
+func (s *hostScheduler) resolveRobots(h *hostState, rawURL string) {
+	s.robotsSem <- struct{}{}
+	defer func() { <-s.robotsSem }()
+
+	// Skip the request if the queued links have been drained while
+	// waiting for the semaphore; it will be retried when a new link
+	// for the host gets queued.
+	s.mu.Lock()
+	if len(h.queue) == 0 {
+		h.fetchingRobots = false
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	var crawlDelay time.Duration
+	if u, err := url.Parse(rawURL); err == nil {
+		crawlDelay, _ = s.fetchCrawlDelay(u)
+	}
+	if crawlDelay > s.maxCrawlDelay {
+		crawlDelay = s.maxCrawlDelay
+	}
+
+	s.mu.Lock()
+	h.crawlDelay = crawlDelay
+	h.robotsResolved, h.fetchingRobots = true, false
+	s.mu.Unlock()
+	s.wake()
+}
+
+func (s *hostScheduler) fetchCrawlDelay(u *url.URL) (time.Duration, bool) {
+	robotsURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	res, err := s.urlGetter.Get(robotsURL.String())
+	if err != nil {
+		return 0, false
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return 0, false
+	}
+	return parseCrawlDelay(res.Body, s.userAgent)
+}
+
+// delay returns the minimum time between the start of two requests to h.
+func (s *hostScheduler) delay(h *hostState) time.Duration {
+	if h.crawlDelay > s.minDelay {
+		return h.crawlDelay
+	}
+	return s.minDelay
+}
+
+// host returns the state for the specified host, creating it if required.
+// It must be invoked while holding s.mu.
+func (s *hostScheduler) host(name string) *hostState {
+	if h, exists := s.hosts[name]; exists {
+		return h
+	}
+
+	// Hosts without queued links or requests in progress whose delay has
+	// expired behave exactly like newly created ones (except for having
+	// to retrieve their robots.txt again) so we can safely drop them to
+	// keep the number of tracked hosts bounded.
+	if len(s.hosts) >= s.evictSize {
+		now := time.Now()
+		for hostName, h := range s.hosts {
+			if h.active == 0 && len(h.queue) == 0 && !now.Before(h.lastStart.Add(s.delay(h))) {
+				delete(s.hosts, hostName)
+			}
+		}
+		if s.evictSize = 2 * len(s.hosts); s.evictSize < minHostsBeforeEviction {
+			s.evictSize = minHostsBeforeEviction
+		}
+	}
+
+	// The robots.txt files are only consulted if a maximum Crawl-delay
+	// has been configured.
+	h := &hostState{robotsResolved: s.maxCrawlDelay <= 0}
+	s.hosts[name] = h
+	return h
+}
@@ -0,0 +1,291 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/crawler/mocks"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"github.com/golang/mock/gomock"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(PolitenessTestSuite))
+
+type PolitenessTestSuite struct{}
+
+func (s *PolitenessTestSuite) TestSchedulerInterleavesHosts(c *gc.C) {
+	sched := newHostScheduler(Config{MaxHostConns: 1})
+	for _, link := range []string{
+		"http://a.com/1", "http://a.com/2", "http://a.com/3",
+		"http://b.com/1",
+		"http://c.com/1",
+	} {
+		sched.enqueue(&crawlerPayload{URL: link})
+	}
+
+	// Each host allows a single request in progress.
+	now := time.Now()
+	c.Assert(s.nextURL(c, sched, now), gc.Equals, "http://a.com/1")
+	c.Assert(s.nextURL(c, sched, now), gc.Equals, "http://b.com/1")
+	c.Assert(s.nextURL(c, sched, now), gc.Equals, "http://c.com/1")
+	p, wakeAt := sched.next(now)
+	c.Assert(p, gc.IsNil)
+	c.Assert(wakeAt.IsZero(), gc.Equals, true)
+
+	sched.release("a.com")
+	c.Assert(s.nextURL(c, sched, now), gc.Equals, "http://a.com/2")
+	sched.release("a.com")
+	c.Assert(s.nextURL(c, sched, now), gc.Equals, "http://a.com/3")
+	c.Assert(sched.empty(), gc.Equals, true)
+}
+
+func (s *PolitenessTestSuite) TestSchedulerRoundRobin(c *gc.C) {
+	sched := newHostScheduler(Config{MaxHostConns: 10})
+	for i := 0; i < 3; i++ {
+		sched.enqueue(&crawlerPayload{URL: fmt.Sprintf("http://a.com/%d", i)})
+	}
+	for i := 0; i < 2; i++ {
+		sched.enqueue(&crawlerPayload{URL: fmt.Sprintf("http://b.com/%d", i)})
+	}
+
+	var got []string
+	for !sched.empty() {
+		got = append(got, s.nextURL(c, sched, time.Now()))
+	}
+	c.Assert(got, gc.DeepEquals, []string{
+		"http://a.com/0", "http://b.com/0",
+		"http://a.com/1", "http://b.com/1",
+		"http://a.com/2",
+	})
+}
+
+func (s *PolitenessTestSuite) TestSchedulerMinHostDelay(c *gc.C) {
+	sched := newHostScheduler(Config{MaxHostConns: 2, MinHostDelay: time.Second})
+	sched.enqueue(&crawlerPayload{URL: "http://a.com/1"})
+	sched.enqueue(&crawlerPayload{URL: "http://a.com/2"})
+
+	now := time.Now()
+	c.Assert(s.nextURL(c, sched, now), gc.Equals, "http://a.com/1")
+
+	p, wakeAt := sched.next(now.Add(500 * time.Millisecond))
+	c.Assert(p, gc.IsNil)
+	c.Assert(wakeAt, gc.Equals, now.Add(time.Second))
+
+	c.Assert(s.nextURL(c, sched, now.Add(time.Second)), gc.Equals, "http://a.com/2")
+}
+
+func (s *PolitenessTestSuite) TestSchedulerCrawlDelay(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	urlGetter := mocks.NewMockURLGetter(ctrl)
+
+	// The robots.txt file is only retrieved once per host and its
+	// Crawl-delay is capped to MaxCrawlDelay.
+	robotsReqCh := make(chan struct{})
+	urlGetter.EXPECT().Get("https://a.com/robots.txt").DoAndReturn(func(string) (*http.Response, error) {
+		<-robotsReqCh
+		return makeResponse(200, "User-agent: *\nCrawl-delay: 60\n", "text/plain"), nil
+	})
+
+	sched := newHostScheduler(Config{
+		MaxHostConns:  1,
+		MinHostDelay:  time.Second,
+		MaxCrawlDelay: 5 * time.Second,
+		URLGetter:     urlGetter,
+	})
+	sched.enqueue(&crawlerPayload{URL: "https://a.com/1"})
+	sched.enqueue(&crawlerPayload{URL: "https://a.com/2"})
+
+	// No links are handed out until the robots.txt file for the host has
+	// been processed.
+	now := time.Now()
+	p, wakeAt := sched.next(now)
+	c.Assert(p, gc.IsNil)
+	c.Assert(wakeAt.IsZero(), gc.Equals, true)
+
+	close(robotsReqCh)
+	s.waitForWakeUp(c, sched)
+	c.Assert(s.nextURL(c, sched, now), gc.Equals, "https://a.com/1")
+	sched.release("a.com")
+
+	p, wakeAt = sched.next(now.Add(time.Second))
+	c.Assert(p, gc.IsNil)
+	c.Assert(wakeAt, gc.Equals, now.Add(5*time.Second))
+
+	c.Assert(s.nextURL(c, sched, now.Add(5*time.Second)), gc.Equals, "https://a.com/2")
+}
+
+func (s *PolitenessTestSuite) TestSchedulerPerHostQueueLimit(c *gc.C) {
+	sched := newHostScheduler(Config{MaxHostConns: 1})
+	sched.hostCapacity = 2
+
+	for i := 0; i < 2; i++ {
+		c.Assert(sched.enqueue(&crawlerPayload{URL: fmt.Sprintf("http://a.com/%d", i)}), gc.Equals, true)
+	}
+	c.Assert(sched.enqueue(&crawlerPayload{URL: "http://a.com/2"}), gc.Equals, false)
+	c.Assert(sched.enqueue(&crawlerPayload{URL: "http://b.com/0"}), gc.Equals, true)
+
+	// Dispatching a link frees up room in the queue for its host.
+	c.Assert(s.nextURL(c, sched, time.Now()), gc.Equals, "http://a.com/0")
+	c.Assert(sched.enqueue(&crawlerPayload{URL: "http://a.com/2"}), gc.Equals, true)
+}
+
+func (s *PolitenessTestSuite) TestPoliteFetcherLimitsConcurrentRequestsPerHost(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	urlGetter := mocks.NewMockURLGetter(ctrl)
+	privNetDetector := mocks.NewMockPrivateNetworkDetector(ctrl)
+
+	var (
+		mu        sync.Mutex
+		active    = make(map[string]int)
+		maxActive = make(map[string]int)
+	)
+	privNetDetector.EXPECT().IsPrivate(gomock.Any()).Return(false, nil).AnyTimes()
+	urlGetter.EXPECT().Get(gomock.Any()).DoAndReturn(func(link string) (*http.Response, error) {
+		host := hostKey(link)
+
+		mu.Lock()
+		active[host]++
+		if active[host] > maxActive[host] {
+			maxActive[host] = active[host]
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		active[host]--
+		mu.Unlock()
+		return makeResponse(200, "hello", "text/html"), nil
+	}).Times(10)
+
+	cfg := Config{
+		URLGetter:    urlGetter,
+		MaxHostConns: 2,
+	}
+	fetcher := newLinkFetcher(urlGetter, privNetDetector, nil, nil)
+	stage := newPoliteFetcher(fetcher, newHostScheduler(cfg), 8)
+
+	var links []string
+	for i := 0; i < 8; i++ {
+		links = append(links, fmt.Sprintf("http://a.com/%d", i))
+	}
+	links = append(links, "http://b.com/0", "http://b.com/1")
+
+	sink := new(countingSink)
+	err := pipeline.New(stage).Process(context.TODO(), &urlSource{links: links}, sink)
+	c.Assert(err, gc.IsNil)
+	c.Assert(sink.count, gc.Equals, len(links))
+	c.Assert(maxActive["a.com"], gc.Equals, 2)
+	c.Assert(maxActive["b.com"] <= 2, gc.Equals, true)
+}
+
+func (s *PolitenessTestSuite) TestPoliteFetcherDefersLinksOfSaturatedHosts(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	urlGetter := mocks.NewMockURLGetter(ctrl)
+	privNetDetector := mocks.NewMockPrivateNetworkDetector(ctrl)
+
+	ctx, cancelFn := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancelFn()
+
+	// Once a.com has been visited, its remaining links must wait for an
+	// hour. Its excess links should be deferred so that the link for
+	// b.com can still be read and fetched.
+	var (
+		mu      sync.Mutex
+		fetched []string
+	)
+	privNetDetector.EXPECT().IsPrivate(gomock.Any()).Return(false, nil).AnyTimes()
+	urlGetter.EXPECT().Get(gomock.Any()).DoAndReturn(func(link string) (*http.Response, error) {
+		mu.Lock()
+		fetched = append(fetched, link)
+		mu.Unlock()
+		if hostKey(link) == "b.com" {
+			cancelFn()
+		}
+		return makeResponse(200, "hello", "text/html"), nil
+	}).Times(2)
+
+	sched := newHostScheduler(Config{MaxHostConns: 1, MinHostDelay: time.Hour})
+	sched.capacity, sched.hostCapacity = 4, 2
+	fetcher := newLinkFetcher(urlGetter, privNetDetector, nil, nil)
+	stage := newPoliteFetcher(fetcher, sched, 2)
+
+	var links []string
+	for i := 0; i < 10; i++ {
+		links = append(links, fmt.Sprintf("http://a.com/%d", i))
+	}
+	links = append(links, "http://b.com/0")
+
+	_ = pipeline.New(stage).Process(ctx, &urlSource{links: links}, new(countingSink))
+	c.Assert(ctx.Err(), gc.Equals, context.Canceled, gc.Commentf("timed out waiting for the link to b.com to be fetched"))
+	c.Assert(fetched, gc.HasLen, 2)
+	c.Assert(fetched[1], gc.Equals, "http://b.com/0")
+}
+
+func (s *PolitenessTestSuite) TestPoliteFetcherReleasesLinksOnShutdown(c *gc.C) {
+	sched := newHostScheduler(Config{MaxHostConns: 1, MinHostDelay: time.Hour})
+	var acked []string
+	for _, link := range []string{"http://a.com/1", "http://a.com/2", "http://b.com/1"} {
+		link := link
+		p := &crawlerPayload{URL: link}
+		p.setAck(func() { acked = append(acked, link) })
+		sched.enqueue(p)
+	}
+
+	// Nobody reads the dispatched links and the context has already
+	// expired so dispatch should return while still holding a pending
+	// link and two queued links.
+	ctx, cancelFn := context.WithCancel(context.TODO())
+	cancelFn()
+	f := &politeFetcher{sched: sched}
+	f.dispatch(ctx, make(chan pipeline.Payload), make(chan pipeline.Payload))
+
+	c.Assert(acked, gc.HasLen, 3)
+	c.Assert(sched.empty(), gc.Equals, true)
+	for host, h := range sched.hosts {
+		c.Assert(h.active, gc.Equals, 0, gc.Commentf("expected the slot for host %q to be released", host))
+	}
+}
+
+func (s *PolitenessTestSuite) waitForWakeUp(c *gc.C, sched *hostScheduler) {
+	select {
+	case <-sched.wakeCh:
+	case <-time.After(5 * time.Second):
+		c.Fatal("timed out waiting for the scheduler to wake up")
+	}
+}
+
+func (s *PolitenessTestSuite) nextURL(c *gc.C, sched *hostScheduler, now time.Time) string {
+	p, _ := sched.next(now)
+	c.Assert(p, gc.Not(gc.IsNil))
+	return p.URL
+}
+
+// urlSource is a pipeline.Source that emits a payload for each link.
+type urlSource struct {
+	links []string
+	index int
+}
+
+func (s *urlSource) Next(context.Context) bool {
+	if s.index >= len(s.links) {
+		return false
+	}
+	s.index++
+	return true
+}
+
+func (s *urlSource) Payload() pipeline.Payload {
+	p := payloadPool.Get().(*crawlerPayload)
+	p.URL = s.links[s.index-1]
+	return p
+}
+
+func (s *urlSource) Error() error { return nil }
@@ -0,0 +1,81 @@
+package crawler
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRobotsSize is the maximum number of bytes of a robots.txt file that
+// will be parsed.
+const maxRobotsSize = 512 * 1024
+
+// parseCrawlDelay scans the contents of a robots.txt file and returns the
+// Crawl-delay value that applies to userAgent. Rules for a group whose
+// User-agent line matches userAgent take precedence over the rules of the
+// group for all user agents ("*"). The second return value is false if no
+// Crawl-delay applies.
+func parseCrawlDelay(r io.Reader, userAgent string) (time.Duration, bool) {
+	userAgent = strings.ToLower(userAgent)
+
+	var (
+		matchesAny       bool
+		matchesAgent     bool
+		inUserAgentLines bool
+
+		anyDelay, agentDelay         time.Duration
+		haveAnyDelay, haveAgentDelay bool
+	)
+
+	scanner := bufio.NewScanner(io.LimitReader(r, maxRobotsSize))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx != -1 {
+			line = line[:idx]
+		}
+		sepIdx := strings.IndexByte(line, ':')
+		if sepIdx == -1 {
+			continue
+		}
+		field := strings.ToLower(strings.TrimSpace(line[:sepIdx]))
+		value := strings.TrimSpace(line[sepIdx+1:])
+
+		if field == "user-agent" {
+			// Consecutive User-agent lines share the same group of rules.
+			if !inUserAgentLines {
+				matchesAny, matchesAgent = false, false
+				inUserAgentLines = true
+			}
+			agent := strings.ToLower(value)
+			if agent == "*" {
+				matchesAny = true
+			} else if userAgent != "" && strings.Contains(userAgent, agent) {
+				matchesAgent = true
+			}
+			continue
+		}
+		inUserAgentLines = false
+
+		if field != "crawl-delay" {
+			continue
+		}
+		secs, err := strconv.ParseFloat(value, 64)
+		if err != nil || secs < 0 {
+			continue
+		}
+		delay := time.Duration(secs * float64(time.Second))
+		if matchesAgent && !haveAgentDelay {
+			agentDelay, haveAgentDelay = delay, true
+		}
+		if matchesAny && !haveAnyDelay {
+			anyDelay, haveAnyDelay = delay, true
+		}
+	}
+
+	if haveAgentDelay {
+		return agentDelay, true
+	}
+	return anyDelay, haveAnyDelay
+}
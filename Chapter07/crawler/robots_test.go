@@ -0,0 +1,68 @@
+package crawler
+
+import (
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(RobotsTestSuite))
+
+type RobotsTestSuite struct{}
+
+func (s *RobotsTestSuite) TestParseCrawlDelay(c *gc.C) {
+	specs := []struct {
+		descr     string
+		robots    string
+		userAgent string
+		expDelay  time.Duration
+		expFound  bool
+	}{
+		{
+			descr:  "no crawl-delay",
+			robots: "User-agent: *\nDisallow: /private\n",
+		},
+		{
+			descr:    "crawl-delay for all user agents",
+			robots:   "User-agent: *\nDisallow: /private\nCrawl-delay: 2.5\n",
+			expDelay: 2500 * time.Millisecond,
+			expFound: true,
+		},
+		{
+			descr:    "case-insensitive fields and comments",
+			robots:   "# robots file\nUSER-AGENT: * # everyone\ncrawl-DELAY: 3 # seconds\n",
+			expDelay: 3 * time.Second,
+			expFound: true,
+		},
+		{
+			descr:     "specific user agent takes precedence",
+			robots:    "User-agent: *\nCrawl-delay: 10\n\nUser-agent: LinksRUs\nCrawl-delay: 1\n",
+			userAgent: "linksrus-crawler/1.0",
+			expDelay:  time.Second,
+			expFound:  true,
+		},
+		{
+			descr:     "crawl-delay for other user agents is ignored",
+			robots:    "User-agent: otherbot\nCrawl-delay: 10\n",
+			userAgent: "linksrus",
+		},
+		{
+			descr:    "consecutive user-agent lines share a group",
+			robots:   "User-agent: otherbot\nUser-agent: *\nCrawl-delay: 4\n",
+			expDelay: 4 * time.Second,
+			expFound: true,
+		},
+		{
+			descr:  "invalid crawl-delay values are ignored",
+			robots: "User-agent: *\nCrawl-delay: soon\nCrawl-delay: -1\n",
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		delay, found := parseCrawlDelay(strings.NewReader(spec.robots), spec.userAgent)
+		c.Assert(found, gc.Equals, spec.expFound)
+		c.Assert(delay, gc.Equals, spec.expDelay)
+	}
+}
@@ -12,8 +12,12 @@ import (
 )
 
 var (
-	titleRegex         = regexp.MustCompile(`(?i)<title.*?>(.*?)</title>`)
-	repeatedSpaceRegex = regexp.MustCompile(`\s+`)
+	titleRegex           = regexp.MustCompile(`(?i)<title.*?>(.*?)</title>`)
+	metaTagRegex         = regexp.MustCompile(`(?is)<meta\b[^>]*>`)
+	metaDescriptionRegex = regexp.MustCompile(`(?i)\bname\s*=\s*["']?description\b`)
+	metaContentRegex     = regexp.MustCompile(`(?is)\bcontent\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	headingRegex         = regexp.MustCompile(`(?is)<h[1-3]\b[^>]*>(.*?)</h[1-3]\s*>`)
+	repeatedSpaceRegex   = regexp.MustCompile(`\s+`)
 )
 
 type textExtractor struct {
@@ -34,10 +38,15 @@ func (te *textExtractor) Process(ctx context.Context, p pipeline.Payload) (pipel
 	payload := p.(*crawlerPayload)
 	policy := te.policyPool.Get().(*bluemonday.Policy)
 
-	if titleMatch := titleRegex.FindStringSubmatch(payload.RawContent.String()); len(titleMatch) == 2 {
-		payload.Title = strings.TrimSpace(html.UnescapeString(repeatedSpaceRegex.ReplaceAllString(
-			policy.Sanitize(titleMatch[1]), " ",
-		)))
+	rawContent := payload.RawContent.String()
+	if titleMatch := titleRegex.FindStringSubmatch(rawContent); len(titleMatch) == 2 {
+		payload.Title = sanitizeText(policy, titleMatch[1])
+	}
+	payload.Description = sanitizeText(policy, metaDescription(rawContent))
+	for _, headingMatch := range headingRegex.FindAllStringSubmatch(rawContent, -1) {
+		if heading := sanitizeText(policy, headingMatch[1]); heading != "" {
+			payload.Headings = append(payload.Headings, heading)
+		}
 	}
 
 	payload.TextContent = strings.TrimSpace(html.UnescapeString(repeatedSpaceRegex.ReplaceAllString(
@@ -45,5 +54,30 @@ func (te *textExtractor) Process(ctx context.Context, p pipeline.Payload) (pipel
 	)))
 	te.policyPool.Put(policy)
 
+	payload.Language = detectLanguage(rawContent, payload.TextContent)
+
 	return payload, nil
 }
+
+// metaDescription returns the raw value of the content attribute of the
+// description meta tag in rawContent or an empty string if the tag is not
+// present.
+func metaDescription(rawContent string) string {
+	for _, tag := range metaTagRegex.FindAllString(rawContent, -1) {
+		if !metaDescriptionRegex.MatchString(tag) {
+			continue
+		}
+		if contentMatch := metaContentRegex.FindStringSubmatch(tag); contentMatch != nil {
+			return contentMatch[1] + contentMatch[2]
+		}
+	}
+	return ""
+}
+
+// sanitizeText strips any HTML tags from s, unescapes any HTML entities and
+// collapses repeated whitespace.
+func sanitizeText(policy *bluemonday.Policy, s string) string {
+	return strings.TrimSpace(html.UnescapeString(repeatedSpaceRegex.ReplaceAllString(
+		policy.Sanitize(s), " ",
+	)))
+}
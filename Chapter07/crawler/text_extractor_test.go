@@ -30,6 +30,30 @@ func (s *ContentExtractorTestSuite) TestContentExtractorWithTitle(c *gc.C) {
 	assertExtractedContent(c, content, "Test title", `Some content`)
 }
 
+func (s *ContentExtractorTestSuite) TestContentExtractorWithDescriptionAndHeadings(c *gc.C) {
+	content := `<html>
+<head>
+<meta charset="utf-8">
+<meta content='Lorem &amp; ipsum' name="Description">
+</head>
+<body>
+<h1 class="title">Dolor <em>sit</em></h1>
+<h2>Amet</h2>
+<h3></h3>
+<h4>Consectetur</h4>
+</body>
+</html>
+`
+	p := new(crawlerPayload)
+	_, err := p.RawContent.WriteString(content)
+	c.Assert(err, gc.IsNil)
+
+	_, err = newTextExtractor().Process(context.TODO(), p)
+	c.Assert(err, gc.IsNil)
+	c.Assert(p.Description, gc.Equals, "Lorem & ipsum")
+	c.Assert(p.Headings, gc.DeepEquals, []string{"Dolor sit", "Amet"})
+}
+
 func assertExtractedContent(c *gc.C, content, expTitle, expText string) {
 	p := new(crawlerPayload)
 	_, err := p.RawContent.WriteString(content)
@@ -42,3 +66,48 @@ func assertExtractedContent(c *gc.C, content, expTitle, expText string) {
 	c.Assert(p.Title, gc.Equals, expTitle)
 	c.Assert(p.TextContent, gc.Equals, expText)
 }
+
+func (s *ContentExtractorTestSuite) TestLanguageDetection(c *gc.C) {
+	specs := []struct {
+		descr   string
+		content string
+		expLang string
+	}{
+		{
+			descr:   "html lang attribute",
+			content: `<html lang="de-DE"><body>The quick brown fox jumps over the lazy dog</body></html>`,
+			expLang: "de",
+		},
+		{
+			descr:   "English text",
+			content: `<div>The houses of the city are old and the streets are narrow.</div>`,
+			expLang: "en",
+		},
+		{
+			descr:   "German text",
+			content: `<div>Die Häuser der Stadt sind alt und die Straßen sind eng.</div>`,
+			expLang: "de",
+		},
+		{
+			descr:   "French text",
+			content: `<div>Les maisons de la ville sont vieilles et les rues sont étroites.</div>`,
+			expLang: "fr",
+		},
+		{
+			descr:   "not enough stop words",
+			content: `<div>Lorem ipsum dolor sit amet</div>`,
+			expLang: "",
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		p := new(crawlerPayload)
+		_, err := p.RawContent.WriteString(spec.content)
+		c.Assert(err, gc.IsNil)
+
+		_, err = newTextExtractor().Process(context.TODO(), p)
+		c.Assert(err, gc.IsNil)
+		c.Assert(p.Language, gc.Equals, spec.expLang)
+	}
+}
@@ -22,11 +22,14 @@ func (i *textIndexer) Process(ctx context.Context, p pipeline.Payload) (pipeline
 	payload := p.(*crawlerPayload)
 
 	doc := &index.Document{
-		LinkID:    payload.LinkID,
-		URL:       payload.URL,
-		Title:     payload.Title,
-		Content:   payload.TextContent,
-		IndexedAt: time.Now(),
+		LinkID:      payload.LinkID,
+		URL:         payload.URL,
+		Title:       payload.Title,
+		Content:     payload.TextContent,
+		Description: payload.Description,
+		Headings:    payload.Headings,
+		Language:    payload.Language,
+		IndexedAt:   time.Now(),
 	}
 	if err := i.indexer.Index(doc); err != nil {
 		return nil, err
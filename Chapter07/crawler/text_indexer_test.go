@@ -3,6 +3,7 @@ package crawler
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
@@ -28,15 +29,21 @@ func (s *TextIndexerTestSuite) TestTextIndexer(c *gc.C) {
 		URL:         "http://example.com",
 		Title:       "some title",
 		TextContent: "Lorem ipsum dolor",
+		Language:    "la",
+		Description: "sit amet",
+		Headings:    []string{"consectetur"},
 	}
 
 	exp := s.indexer.EXPECT()
 	exp.Index(docMatcher{
-		linkID:    payload.LinkID,
-		url:       payload.URL,
-		title:     payload.Title,
-		content:   payload.TextContent,
-		notBefore: time.Now(),
+		linkID:      payload.LinkID,
+		url:         payload.URL,
+		title:       payload.Title,
+		content:     payload.TextContent,
+		language:    payload.Language,
+		description: payload.Description,
+		headings:    payload.Headings,
+		notBefore:   time.Now(),
 	}).Return(nil)
 
 	p := s.updateIndex(c, payload)
@@ -55,11 +62,14 @@ func (s *TextIndexerTestSuite) updateIndex(c *gc.C, p *crawlerPayload) *crawlerP
 }
 
 type docMatcher struct {
-	linkID    uuid.UUID
-	url       string
-	title     string
-	content   string
-	notBefore time.Time
+	linkID      uuid.UUID
+	url         string
+	title       string
+	content     string
+	language    string
+	description string
+	headings    []string
+	notBefore   time.Time
 }
 
 func (dm docMatcher) Matches(x interface{}) bool {
@@ -68,9 +78,12 @@ func (dm docMatcher) Matches(x interface{}) bool {
 		dm.url == doc.URL &&
 		dm.title == doc.Title &&
 		dm.content == doc.Content &&
+		dm.language == doc.Language &&
+		dm.description == doc.Description &&
+		reflect.DeepEqual(dm.headings, doc.Headings) &&
 		!doc.IndexedAt.Before(dm.notBefore)
 }
 
 func (dm docMatcher) String() string {
-	return fmt.Sprintf("has LinkID=%q, URL=%q, Title=%q, Content=%q and IndexedAt not before %v", dm.linkID, dm.url, dm.title, dm.content, dm.notBefore)
+	return fmt.Sprintf("has LinkID=%q, URL=%q, Title=%q, Content=%q, Language=%q, Description=%q, Headings=%q and IndexedAt not before %v", dm.linkID, dm.url, dm.title, dm.content, dm.language, dm.description, dm.headings, dm.notBefore)
 }
@@ -0,0 +1,152 @@
+package pipeline
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+type batcher struct {
+	size      int
+	maxWait   time.Duration
+	batchProc BatchProcessor
+}
+
+// Batch returns a StageRunner that groups incoming payloads into batches of
+// up to size payloads and passes each batch to the specified batch processor.
+// A partial batch is flushed once maxWait has elapsed since its first payload
+// was received; if maxWait is not positive, partial batches are only flushed
+// when the stage input is exhausted.
+//
+// Each payload returned by the batch processor is emitted to the next stage.
+// Input payloads that are not part of the batch processor output are marked
+// as processed. As payloads are matched by identity, the stage only accepts
+// payloads whose underlying type is comparable (e.g. pointers to structs);
+// receiving any other payload type causes the stage to fail.
+func Batch(size int, maxWait time.Duration, batchProc BatchProcessor) StageRunner {
+	if size <= 0 {
+		panic("Batch: size must be > 0")
+	}
+
+	return &batcher{size: size, maxWait: maxWait, batchProc: batchProc}
+}
+
+// Run implements StageRunner.
+func (b *batcher) Run(ctx context.Context, params StageParams) {
+	var (
+		batch   []Payload
+		timer   *time.Timer
+		timerCh <-chan time.Time
+	)
+
+	stopTimer := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, timerCh = nil, nil
+		}
+	}
+	defer stopTimer()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Asked to cleanly shut down; the payloads in the pending
+			// batch will never reach the rest of the pipeline.
+			dropPayloads(params, batch)
+			return
+		case payloadIn, ok := <-params.Input():
+			if !ok {
+				if len(batch) != 0 {
+					b.processBatch(ctx, params, batch)
+				}
+				return
+			}
+
+			params.Observer().PayloadIn(params.StageIndex())
+			batch = append(batch, payloadIn)
+			if !reflect.TypeOf(payloadIn).Comparable() {
+				err := xerrors.Errorf("pipeline stage %d: batched payloads must be comparable; got %T", params.StageIndex(), payloadIn)
+				params.Observer().Error(params.StageIndex(), err)
+				maybeEmitError(err, params.Error())
+				dropPayloads(params, batch)
+				return
+			}
+			if len(batch) == 1 && b.maxWait > 0 {
+				timer = time.NewTimer(b.maxWait)
+				timerCh = timer.C
+			}
+			if len(batch) < b.size {
+				continue
+			}
+		case <-timerCh:
+			timer, timerCh = nil, nil
+		}
+
+		// Flush the batch either because it is full or because the
+		// timeout for the partial batch has expired.
+		stopTimer()
+		if !b.processBatch(ctx, params, batch) {
+			return
+		}
+		batch = nil
+	}
+}
+
+// processBatch passes batch to the batch processor and emits its output to
+// the next stage. It returns false if the stage should shut down, either
+// because an error occurred or because the context expired.
+func (b *batcher) processBatch(ctx context.Context, params StageParams, batch []Payload) bool {
+	obs, stage := params.Observer(), params.StageIndex()
+
+	startedAt := time.Now()
+	batchOut, err := b.batchProc.ProcessBatch(ctx, batch)
+	obs.PayloadProcessed(stage, time.Since(startedAt))
+	if err != nil {
+		obs.Error(stage, err)
+		wrappedErr := xerrors.Errorf("pipeline stage %d: %w", stage, err)
+		maybeEmitError(wrappedErr, params.Error())
+		return false
+	}
+
+	// Mark the input payloads that did not make it to the output as
+	// processed as they will not reach the rest of the pipeline.
+	for _, payloadIn := range batch {
+		if !containsPayload(batchOut, payloadIn) {
+			obs.PayloadDropped(stage)
+			payloadIn.MarkAsProcessed()
+		}
+	}
+
+	for i, payloadOut := range batchOut {
+		if !emitPayload(ctx, params, payloadOut) {
+			// The context expired; release the payloads that were
+			// not emitted.
+			dropPayloads(params, batchOut[i:])
+			return false
+		}
+	}
+	return true
+}
+
+// dropPayloads marks each payload in list as processed and notifies the
+// stage observer that it was dropped.
+func dropPayloads(params StageParams, list []Payload) {
+	obs, stage := params.Observer(), params.StageIndex()
+	for _, p := range list {
+		obs.PayloadDropped(stage)
+		p.MarkAsProcessed()
+	}
+}
+
+// containsPayload returns true if list contains target. Batch only accepts
+// comparable payloads so payloads can be compared for equality.
+func containsPayload(list []Payload, target Payload) bool {
+	for _, p := range list {
+		if p == target {
+			return true
+		}
+	}
+	return false
+}
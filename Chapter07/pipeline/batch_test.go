@@ -0,0 +1,181 @@
+package pipeline_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(BatchTestSuite))
+
+type BatchTestSuite struct{}
+
+func (s *BatchTestSuite) TestFlushOnSize(c *gc.C) {
+	proc := new(recordingBatchProcessor)
+	src := &sourceStub{data: stringPayloads(7)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.Batch(3, 0, proc))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.IsNil)
+	c.Assert(sink.data, gc.DeepEquals, src.data)
+	assertAllProcessed(c, src.data)
+	c.Assert(proc.batchSizes, gc.DeepEquals, []int{3, 3, 1})
+}
+
+func (s *BatchTestSuite) TestFlushOnTimeout(c *gc.C) {
+	proc := new(recordingBatchProcessor)
+	src := &delayedSourceStub{
+		sourceStub: sourceStub{data: stringPayloads(3)},
+		delayAt:    2,
+		delay:      250 * time.Millisecond,
+	}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.Batch(10, 50*time.Millisecond, proc))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.IsNil)
+	c.Assert(sink.data, gc.DeepEquals, src.data)
+	assertAllProcessed(c, src.data)
+	c.Assert(proc.batchSizes, gc.DeepEquals, []int{2, 1})
+}
+
+func (s *BatchTestSuite) TestDroppedPayloadsAreMarkedAsProcessed(c *gc.C) {
+	// Replace each batch with a single payload that joins the values of
+	// the batched payloads.
+	joinProc := pipeline.BatchProcessorFunc(func(_ context.Context, batch []pipeline.Payload) ([]pipeline.Payload, error) {
+		vals := make([]string, len(batch))
+		for i, p := range batch {
+			vals[i] = p.(*stringPayload).val
+		}
+		return []pipeline.Payload{&stringPayload{val: strings.Join(vals, ",")}}, nil
+	})
+
+	src := &sourceStub{data: stringPayloads(5)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.Batch(2, 0, joinProc))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.IsNil)
+	assertAllProcessed(c, src.data)
+	c.Assert(sink.data, gc.DeepEquals, []pipeline.Payload{
+		&stringPayload{val: "0,1", processed: true},
+		&stringPayload{val: "2,3", processed: true},
+		&stringPayload{val: "4", processed: true},
+	})
+}
+
+func (s *BatchTestSuite) TestBatchProcessorError(c *gc.C) {
+	errProc := pipeline.BatchProcessorFunc(func(context.Context, []pipeline.Payload) ([]pipeline.Payload, error) {
+		return nil, xerrors.New("some error")
+	})
+
+	src := &sourceStub{data: stringPayloads(3)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.Batch(2, 0, errProc))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.ErrorMatches, "(?s).*pipeline stage 0: some error.*")
+}
+
+func (s *BatchTestSuite) TestPendingBatchIsMarkedAsProcessedOnShutdown(c *gc.C) {
+	obs := newRecordingObserver()
+	params := &stageParamsStub{
+		inCh:  make(chan pipeline.Payload),
+		outCh: make(chan pipeline.Payload),
+		errCh: make(chan error, 1),
+		obs:   obs,
+	}
+
+	ctx, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	doneCh := make(chan struct{})
+	go func() {
+		pipeline.Batch(10, 0, new(recordingBatchProcessor)).Run(ctx, params)
+		close(doneCh)
+	}()
+
+	payloads := stringPayloads(3)
+	for _, p := range payloads {
+		params.inCh <- p
+	}
+	cancelFn()
+
+	select {
+	case <-doneCh:
+	case <-time.After(10 * time.Second):
+		c.Fatal("timed out waiting for the stage to exit")
+	}
+	assertAllProcessed(c, payloads)
+	c.Assert(obs.events["dropped/0"], gc.Equals, 3)
+}
+
+func (s *BatchTestSuite) TestNonComparablePayloadsAreRejected(c *gc.C) {
+	processed := new(bool)
+	src := &sourceStub{data: []pipeline.Payload{sliceStringPayload{vals: []string{"0"}, processed: processed}}}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.Batch(2, 0, new(recordingBatchProcessor)))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.ErrorMatches, "(?s).*batched payloads must be comparable.*")
+	c.Assert(*processed, gc.Equals, true)
+	c.Assert(sink.data, gc.HasLen, 0)
+}
+
+// recordingBatchProcessor passes batches through unmodified and records the
+// size of each batch.
+type recordingBatchProcessor struct {
+	mu         sync.Mutex
+	batchSizes []int
+}
+
+func (p *recordingBatchProcessor) ProcessBatch(_ context.Context, batch []pipeline.Payload) ([]pipeline.Payload, error) {
+	p.mu.Lock()
+	p.batchSizes = append(p.batchSizes, len(batch))
+	p.mu.Unlock()
+	return batch, nil
+}
+
+// delayedSourceStub is a sourceStub that waits for the specified delay
+// before emitting the payload at index delayAt.
+type delayedSourceStub struct {
+	sourceStub
+	delayAt int
+	delay   time.Duration
+}
+
+func (s *delayedSourceStub) Next(ctx context.Context) bool {
+	if s.index == s.delayAt {
+		time.Sleep(s.delay)
+	}
+	return s.sourceStub.Next(ctx)
+}
+
+// sliceStringPayload is a payload whose underlying type is not comparable.
+type sliceStringPayload struct {
+	vals      []string
+	processed *bool
+}
+
+func (p sliceStringPayload) Clone() pipeline.Payload { return p }
+func (p sliceStringPayload) MarkAsProcessed()        { *p.processed = true }
+
+// stageParamsStub allows tests to drive a StageRunner directly.
+type stageParamsStub struct {
+	inCh  chan pipeline.Payload
+	outCh chan pipeline.Payload
+	errCh chan error
+	obs   pipeline.Observer
+}
+
+func (p *stageParamsStub) StageIndex() int                 { return 0 }
+func (p *stageParamsStub) Input() <-chan pipeline.Payload  { return p.inCh }
+func (p *stageParamsStub) Output() chan<- pipeline.Payload { return p.outCh }
+func (p *stageParamsStub) Error() chan<- error             { return p.errCh }
+func (p *stageParamsStub) Observer() pipeline.Observer     { return p.obs }
@@ -0,0 +1,249 @@
+package pipeline
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// CheckpointStore is implemented by types that can durably persist the
+// position from which a pipeline source should resume after an interrupted
+// run.
+type CheckpointStore interface {
+	// LoadCheckpoint returns the position saved for key or an empty string
+	// if no position has been saved.
+	LoadCheckpoint(key string) (string, error)
+
+	// SaveCheckpoint saves the position for key replacing any previously
+	// saved position.
+	SaveCheckpoint(key, position string) error
+
+	// ClearCheckpoint removes the position saved for key.
+	ClearCheckpoint(key string) error
+}
+
+const (
+	defaultCheckpointSaveEvery    = 100
+	defaultCheckpointSaveInterval = 5 * time.Second
+)
+
+// CheckpointConfig encapsulates the settings for a CheckpointTracker.
+type CheckpointConfig struct {
+	// The number of acknowledgements after which the tracker persists its
+	// position. If not specified, a default value of 100 will be used
+	// instead.
+	SaveEvery int
+
+	// The time since the last save after which the next acknowledgement
+	// persists the position regardless of SaveEvery. If not specified, a
+	// default value of 5s will be used instead.
+	SaveInterval time.Duration
+}
+
+func (cfg *CheckpointConfig) validate() {
+	if cfg.SaveEvery <= 0 {
+		cfg.SaveEvery = defaultCheckpointSaveEvery
+	}
+	if cfg.SaveInterval <= 0 {
+		cfg.SaveInterval = defaultCheckpointSaveInterval
+	}
+}
+
+// CheckpointTracker keeps track of the payloads emitted by a source that
+// have not been acknowledged yet. The tracker persists the position of the
+// most recently emitted payload for which it and all payloads emitted before
+// it have been acknowledged.
+//
+// To avoid hitting the store for every payload, positions are persisted
+// once every SaveEvery acknowledgements or once SaveInterval has elapsed
+// since the last save, whichever comes first. Flush must be invoked when
+// the pipeline terminates to persist the latest position.
+//
+// A payload should be acknowledged once the pipeline calls MarkAsProcessed
+// on it (and on any clones of it), i.e. when the sink has consumed it or a
+// stage has discarded it.
+type CheckpointTracker struct {
+	store CheckpointStore
+	key   string
+	cfg   CheckpointConfig
+
+	mu      sync.Mutex
+	pending []*checkpointEntry
+	err     error
+
+	// The latest position that can be persisted, whether it has been
+	// persisted yet and the bookkeeping for deciding when to persist it.
+	position      string
+	unsaved       bool
+	acksSinceSave int
+	lastSavedAt   time.Time
+}
+
+type checkpointEntry struct {
+	position string
+	acked    bool
+}
+
+// NewCheckpointTracker returns a CheckpointTracker that persists positions to
+// store under the specified key using the settings in cfg.
+func NewCheckpointTracker(store CheckpointStore, key string, cfg CheckpointConfig) *CheckpointTracker {
+	cfg.validate()
+	return &CheckpointTracker{store: store, key: key, cfg: cfg, lastSavedAt: time.Now()}
+}
+
+// ResumePosition returns the last position persisted by the tracker or an
+// empty string if the source should start from the beginning.
+func (t *CheckpointTracker) ResumePosition() (string, error) {
+	pos, err := t.store.LoadCheckpoint(t.key)
+	if err != nil {
+		return "", xerrors.Errorf("load checkpoint: %w", err)
+	}
+	return pos, nil
+}
+
+// Track registers a payload emitted by the source at the specified position
+// and returns a function for acknowledging it. Payloads must be registered
+// in the order they are emitted. The returned function may be safely called
+// multiple times.
+func (t *CheckpointTracker) Track(position string) func() {
+	entry := &checkpointEntry{position: position}
+	t.mu.Lock()
+	t.pending = append(t.pending, entry)
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { t.ack(entry) })
+	}
+}
+
+func (t *CheckpointTracker) ack(entry *checkpointEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry.acked = true
+	for len(t.pending) != 0 && t.pending[0].acked {
+		t.position, t.unsaved = t.pending[0].position, true
+		t.pending = t.pending[1:]
+	}
+
+	t.acksSinceSave++
+	if t.acksSinceSave < t.cfg.SaveEvery && time.Since(t.lastSavedAt) < t.cfg.SaveInterval {
+		return
+	}
+	t.saveLocked()
+}
+
+// saveLocked persists the latest position if it has not been persisted yet.
+// The caller must hold the tracker lock.
+func (t *CheckpointTracker) saveLocked() {
+	if !t.unsaved || t.err != nil {
+		return
+	}
+
+	if err := t.store.SaveCheckpoint(t.key, t.position); err != nil {
+		t.err = xerrors.Errorf("save checkpoint: %w", err)
+		return
+	}
+	t.unsaved, t.acksSinceSave, t.lastSavedAt = false, 0, time.Now()
+}
+
+// Flush persists the latest position if it has not been persisted yet. It
+// should be invoked once the pipeline that uses the tracker terminates
+// without completing. Flush returns the first error that occurred while
+// persisting a position.
+func (t *CheckpointTracker) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.saveLocked()
+	return t.err
+}
+
+// Err returns the first error that occurred while persisting a position.
+func (t *CheckpointTracker) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Complete clears the persisted position so that the next run starts from
+// the beginning. It should be invoked once the source has been exhausted
+// and all payloads have been processed.
+func (t *CheckpointTracker) Complete() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending, t.unsaved = nil, false
+	if err := t.store.ClearCheckpoint(t.key); err != nil {
+		return xerrors.Errorf("clear checkpoint: %w", err)
+	}
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore that saves each position to a
+// separate file in a directory.
+type FileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore returns a FileCheckpointStore that saves positions
+// to files in dir. The directory is created if it does not exist.
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, xerrors.Errorf("create checkpoint dir: %w", err)
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+// LoadCheckpoint implements CheckpointStore.
+func (s *FileCheckpointStore) LoadCheckpoint(key string) (string, error) {
+	data, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// SaveCheckpoint implements CheckpointStore. To avoid leaving behind a
+// partially written file if the process dies, the position is written to a
+// temporary file which is then renamed.
+func (s *FileCheckpointStore) SaveCheckpoint(key, position string) error {
+	f, err := ioutil.TempFile(s.dir, ".checkpoint-")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.WriteString(position)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// ClearCheckpoint implements CheckpointStore.
+func (s *FileCheckpointStore) ClearCheckpoint(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileCheckpointStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".checkpoint")
+}
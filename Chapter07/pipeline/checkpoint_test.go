@@ -0,0 +1,156 @@
+package pipeline_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(CheckpointTestSuite))
+
+type CheckpointTestSuite struct {
+	dir   string
+	store *pipeline.FileCheckpointStore
+}
+
+func (s *CheckpointTestSuite) SetUpTest(c *gc.C) {
+	var err error
+	s.dir = c.MkDir()
+	s.store, err = pipeline.NewFileCheckpointStore(filepath.Join(s.dir, "checkpoints"))
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *CheckpointTestSuite) TestFileCheckpointStore(c *gc.C) {
+	pos, err := s.store.LoadCheckpoint("foo/bar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(pos, gc.Equals, "")
+
+	c.Assert(s.store.SaveCheckpoint("foo/bar", "42"), gc.IsNil)
+	c.Assert(s.store.SaveCheckpoint("foo/bar", "43"), gc.IsNil)
+
+	// A new store instance for the same dir should see the saved position.
+	store, err := pipeline.NewFileCheckpointStore(filepath.Join(s.dir, "checkpoints"))
+	c.Assert(err, gc.IsNil)
+	pos, err = store.LoadCheckpoint("foo/bar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(pos, gc.Equals, "43")
+
+	// No temporary files should be left behind.
+	files, err := ioutil.ReadDir(filepath.Join(s.dir, "checkpoints"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(files, gc.HasLen, 1)
+
+	c.Assert(s.store.ClearCheckpoint("foo/bar"), gc.IsNil)
+	c.Assert(s.store.ClearCheckpoint("foo/bar"), gc.IsNil)
+	pos, err = s.store.LoadCheckpoint("foo/bar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(pos, gc.Equals, "")
+}
+
+func (s *CheckpointTestSuite) TestTrackerPersistsContiguousPrefix(c *gc.C) {
+	tracker := pipeline.NewCheckpointTracker(s.store, "test", pipeline.CheckpointConfig{SaveEvery: 1})
+	ackA := tracker.Track("a")
+	ackB := tracker.Track("b")
+	ackC := tracker.Track("c")
+
+	// Acknowledging b before a must not advance the checkpoint.
+	ackB()
+	s.assertResumePosition(c, tracker, "")
+
+	ackA()
+	s.assertResumePosition(c, tracker, "b")
+
+	// Duplicate acks are ignored.
+	ackA()
+	ackB()
+	s.assertResumePosition(c, tracker, "b")
+
+	ackC()
+	s.assertResumePosition(c, tracker, "c")
+	c.Assert(tracker.Err(), gc.IsNil)
+
+	c.Assert(tracker.Complete(), gc.IsNil)
+	s.assertResumePosition(c, tracker, "")
+}
+
+func (s *CheckpointTestSuite) TestTrackerBatchesSaves(c *gc.C) {
+	store := &countingCheckpointStore{CheckpointStore: s.store}
+	tracker := pipeline.NewCheckpointTracker(store, "test", pipeline.CheckpointConfig{
+		SaveEvery:    3,
+		SaveInterval: time.Hour,
+	})
+
+	var acks []func()
+	for _, pos := range []string{"a", "b", "c", "d"} {
+		acks = append(acks, tracker.Track(pos))
+	}
+
+	acks[0]()
+	acks[1]()
+	s.assertResumePosition(c, tracker, "")
+
+	acks[2]()
+	s.assertResumePosition(c, tracker, "c")
+
+	acks[3]()
+	s.assertResumePosition(c, tracker, "c")
+	c.Assert(store.saves, gc.Equals, 1)
+
+	// Flushing persists the latest position only if it has not been
+	// persisted yet.
+	c.Assert(tracker.Flush(), gc.IsNil)
+	s.assertResumePosition(c, tracker, "d")
+	c.Assert(tracker.Flush(), gc.IsNil)
+	c.Assert(store.saves, gc.Equals, 2)
+}
+
+func (s *CheckpointTestSuite) TestTrackerSavesAfterInterval(c *gc.C) {
+	tracker := pipeline.NewCheckpointTracker(s.store, "test", pipeline.CheckpointConfig{
+		SaveEvery:    100,
+		SaveInterval: 10 * time.Millisecond,
+	})
+	ackA := tracker.Track("a")
+	ackB := tracker.Track("b")
+
+	ackA()
+	s.assertResumePosition(c, tracker, "")
+
+	time.Sleep(20 * time.Millisecond)
+	ackB()
+	s.assertResumePosition(c, tracker, "b")
+}
+
+func (s *CheckpointTestSuite) TestTrackerSaveError(c *gc.C) {
+	tracker := pipeline.NewCheckpointTracker(failingCheckpointStore{}, "test", pipeline.CheckpointConfig{})
+	tracker.Track("a")()
+	c.Assert(tracker.Err(), gc.IsNil)
+	c.Assert(tracker.Flush(), gc.ErrorMatches, "save checkpoint: disk full")
+	c.Assert(tracker.Err(), gc.ErrorMatches, "save checkpoint: disk full")
+}
+
+func (s *CheckpointTestSuite) assertResumePosition(c *gc.C, tracker *pipeline.CheckpointTracker, exp string) {
+	pos, err := tracker.ResumePosition()
+	c.Assert(err, gc.IsNil)
+	c.Assert(pos, gc.Equals, exp)
+}
+
+type failingCheckpointStore struct{}
+
+func (failingCheckpointStore) LoadCheckpoint(string) (string, error) { return "", nil }
+func (failingCheckpointStore) SaveCheckpoint(string, string) error   { return xerrors.New("disk full") }
+func (failingCheckpointStore) ClearCheckpoint(string) error          { return nil }
+
+// countingCheckpointStore counts the calls to SaveCheckpoint.
+type countingCheckpointStore struct {
+	pipeline.CheckpointStore
+	saves int
+}
+
+func (s *countingCheckpointStore) SaveCheckpoint(key, position string) error {
+	s.saves++
+	return s.CheckpointStore.SaveCheckpoint(key, position)
+}
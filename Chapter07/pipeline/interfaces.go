@@ -1,6 +1,9 @@
 package pipeline
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Payload is implemented by values that can be sent through a pipeline.
 type Payload interface {
@@ -33,6 +36,26 @@ func (f ProcessorFunc) Process(ctx context.Context, p Payload) (Payload, error)
 	return f(ctx, p)
 }
 
+// BatchProcessor is implemented by types that can process batches of Payloads
+// as part of a pipeline stage.
+type BatchProcessor interface {
+	// ProcessBatch operates on a batch of input payloads and returns back
+	// zero or more payloads to be forwarded to the next pipeline stage.
+	// Input payloads that are not included in the returned list are
+	// considered to be discarded.
+	ProcessBatch(context.Context, []Payload) ([]Payload, error)
+}
+
+// BatchProcessorFunc is an adapter to allow the use of plain functions as
+// BatchProcessor instances. If f is a function with the appropriate signature,
+// BatchProcessorFunc(f) is a BatchProcessor that calls f.
+type BatchProcessorFunc func(context.Context, []Payload) ([]Payload, error)
+
+// ProcessBatch calls f(ctx, batch).
+func (f BatchProcessorFunc) ProcessBatch(ctx context.Context, batch []Payload) ([]Payload, error) {
+	return f(ctx, batch)
+}
+
 // StageParams encapsulates the information required for executing a pipeline
 // stage. The pipeline passes a StageParams instance to the Run() method of
 // each stage.
@@ -49,6 +72,10 @@ type StageParams interface {
 	// Error returns a channel for writing errors that were encountered by
 	// a stage while processing payloads.
 	Error() chan<- error
+
+	// Observer returns the Observer that should be notified about the
+	// events that occur while the stage processes payloads.
+	Observer() Observer
 }
 
 // StageRunner is implemented by types that can be strung together to form a
@@ -85,3 +112,34 @@ type Sink interface {
 	// a Pipeline instance.
 	Consume(context.Context, Payload) error
 }
+
+// Observer is implemented by types that monitor the flow of payloads through
+// the stages of a pipeline. Stages are identified by their position in the
+// pipeline while the sink is identified by an index equal to the number of
+// pipeline stages.
+//
+// Observer methods are invoked concurrently by the pipeline stages and must
+// therefore be safe for concurrent use.
+type Observer interface {
+	// PayloadIn is invoked when a stage receives an input payload.
+	PayloadIn(stage int)
+
+	// PayloadOut is invoked when a stage emits an output payload.
+	PayloadOut(stage int)
+
+	// PayloadQueued is invoked when a payload is accepted by a stage
+	// with the amount of time that the payload spent waiting for the
+	// stage to become available.
+	PayloadQueued(stage int, wait time.Duration)
+
+	// PayloadProcessed is invoked when a stage finishes processing a
+	// payload with the amount of time that processing took.
+	PayloadProcessed(stage int, latency time.Duration)
+
+	// PayloadDropped is invoked when a stage discards a payload so that it
+	// does not reach the rest of the pipeline.
+	PayloadDropped(stage int)
+
+	// Error is invoked when a stage encounters an error.
+	Error(stage int, err error)
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+)
+
+var _ pipeline.Observer = (*PrometheusObserver)(nil)
+
+// PrometheusObserver is a pipeline.Observer that exports per-stage payload
+// counters and latency histograms as prometheus metrics. All metrics are
+// labelled with the name of the stage that generated them.
+type PrometheusObserver struct {
+	stageNames []string
+
+	payloadsIn      *prometheus.CounterVec
+	payloadsOut     *prometheus.CounterVec
+	payloadsDropped *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	processingTime  *prometheus.HistogramVec
+	queueWaitTime   *prometheus.HistogramVec
+}
+
+// NewPrometheusObserver creates a new PrometheusObserver and registers its
+// metrics with reg. The names of all metrics are prefixed with namespace.
+//
+// The stageNames argument specifies the label values for each stage in the
+// pipeline followed by the label value for the sink. Stages without a name
+// are labelled with their index.
+func NewPrometheusObserver(reg prometheus.Registerer, namespace string, stageNames ...string) (*PrometheusObserver, error) {
+	stageLabel := []string{"stage"}
+	obs := &PrometheusObserver{
+		stageNames: stageNames,
+		payloadsIn: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pipeline_payloads_in_total",
+			Help:      "The total number of payloads received by each pipeline stage",
+		}, stageLabel),
+		payloadsOut: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pipeline_payloads_out_total",
+			Help:      "The total number of payloads emitted by each pipeline stage",
+		}, stageLabel),
+		payloadsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pipeline_payloads_dropped_total",
+			Help:      "The total number of payloads discarded by each pipeline stage",
+		}, stageLabel),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pipeline_errors_total",
+			Help:      "The total number of errors encountered by each pipeline stage",
+		}, stageLabel),
+		processingTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "pipeline_processing_seconds",
+			Help:      "The time it took each pipeline stage to process a payload",
+			Buckets:   prometheus.DefBuckets,
+		}, stageLabel),
+		queueWaitTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "pipeline_queue_wait_seconds",
+			Help:      "The time payloads spent waiting for each pipeline stage to accept them",
+			Buckets:   prometheus.DefBuckets,
+		}, stageLabel),
+	}
+
+	for _, c := range []prometheus.Collector{
+		obs.payloadsIn, obs.payloadsOut, obs.payloadsDropped,
+		obs.errors, obs.processingTime, obs.queueWaitTime,
+	} {
+		if err := reg.Register(c); err != nil {
+			return nil, xerrors.Errorf("register pipeline metrics: %w", err)
+		}
+	}
+
+	return obs, nil
+}
+
+// PayloadIn implements pipeline.Observer.
+func (o *PrometheusObserver) PayloadIn(stage int) {
+	o.payloadsIn.WithLabelValues(o.stageName(stage)).Inc()
+}
+
+// PayloadOut implements pipeline.Observer.
+func (o *PrometheusObserver) PayloadOut(stage int) {
+	o.payloadsOut.WithLabelValues(o.stageName(stage)).Inc()
+}
+
+// PayloadQueued implements pipeline.Observer.
+func (o *PrometheusObserver) PayloadQueued(stage int, wait time.Duration) {
+	o.queueWaitTime.WithLabelValues(o.stageName(stage)).Observe(wait.Seconds())
+}
+
+// PayloadProcessed implements pipeline.Observer.
+func (o *PrometheusObserver) PayloadProcessed(stage int, latency time.Duration) {
+	o.processingTime.WithLabelValues(o.stageName(stage)).Observe(latency.Seconds())
+}
+
+// PayloadDropped implements pipeline.Observer.
+func (o *PrometheusObserver) PayloadDropped(stage int) {
+	o.payloadsDropped.WithLabelValues(o.stageName(stage)).Inc()
+}
+
+// Error implements pipeline.Observer.
+func (o *PrometheusObserver) Error(stage int, _ error) {
+	o.errors.WithLabelValues(o.stageName(stage)).Inc()
+}
+
+func (o *PrometheusObserver) stageName(stage int) string {
+	if stage >= 0 && stage < len(o.stageNames) && o.stageNames[stage] != "" {
+		return o.stageNames[stage]
+	}
+	return strconv.Itoa(stage)
+}
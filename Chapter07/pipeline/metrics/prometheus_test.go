@@ -0,0 +1,109 @@
+package metrics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(PrometheusObserverTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type PrometheusObserverTestSuite struct{}
+
+func (s *PrometheusObserverTestSuite) TestPipelineMetrics(c *gc.C) {
+	reg := prometheus.NewPedanticRegistry()
+	obs, err := metrics.NewPrometheusObserver(reg, "test", "passthrough", "discard")
+	c.Assert(err, gc.IsNil)
+
+	passthrough := pipeline.ProcessorFunc(func(_ context.Context, p pipeline.Payload) (pipeline.Payload, error) {
+		return p, nil
+	})
+	discard := pipeline.ProcessorFunc(func(context.Context, pipeline.Payload) (pipeline.Payload, error) {
+		return nil, nil
+	})
+
+	p := pipeline.NewWithObserver(obs, pipeline.FIFO(passthrough), pipeline.FIFO(discard))
+	err = p.Process(context.TODO(), &sourceStub{count: 3}, nil)
+	c.Assert(err, gc.IsNil)
+
+	for _, name := range []string{"test_pipeline_processing_seconds", "test_pipeline_queue_wait_seconds"} {
+		count, err := testutil.GatherAndCount(reg, name)
+		c.Assert(err, gc.IsNil)
+		c.Assert(count, gc.Equals, 2, gc.Commentf("unexpected number of %s series", name))
+	}
+
+	specs := []struct {
+		stage      string
+		in         float64
+		out        float64
+		dropped    float64
+		errorCount float64
+	}{
+		{stage: "passthrough", in: 3, out: 3},
+		{stage: "discard", in: 3, dropped: 3},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] stage %q", specIndex, spec.stage)
+		c.Assert(counterValue(c, reg, "test_pipeline_payloads_in_total", spec.stage), gc.Equals, spec.in)
+		c.Assert(counterValue(c, reg, "test_pipeline_payloads_out_total", spec.stage), gc.Equals, spec.out)
+		c.Assert(counterValue(c, reg, "test_pipeline_payloads_dropped_total", spec.stage), gc.Equals, spec.dropped)
+		c.Assert(counterValue(c, reg, "test_pipeline_errors_total", spec.stage), gc.Equals, spec.errorCount)
+	}
+}
+
+func (s *PrometheusObserverTestSuite) TestDuplicateRegistration(c *gc.C) {
+	reg := prometheus.NewRegistry()
+	_, err := metrics.NewPrometheusObserver(reg, "test")
+	c.Assert(err, gc.IsNil)
+
+	_, err = metrics.NewPrometheusObserver(reg, "test")
+	c.Assert(err, gc.ErrorMatches, "register pipeline metrics: .*")
+}
+
+// counterValue returns the value of the counter with the specified name and
+// stage label or zero if the counter has not been incremented.
+func counterValue(c *gc.C, reg *prometheus.Registry, name, stage string) float64 {
+	families, err := reg.Gather()
+	c.Assert(err, gc.IsNil)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "stage" && label.GetValue() == stage {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+type sourceStub struct {
+	count int
+}
+
+func (s *sourceStub) Next(context.Context) bool {
+	if s.count == 0 {
+		return false
+	}
+	s.count--
+	return true
+}
+func (s *sourceStub) Error() error              { return nil }
+func (s *sourceStub) Payload() pipeline.Payload { return new(payloadStub) }
+
+type payloadStub struct{}
+
+func (p *payloadStub) Clone() pipeline.Payload { return new(payloadStub) }
+func (p *payloadStub) MarkAsProcessed()        {}
@@ -3,6 +3,7 @@ package pipeline
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"golang.org/x/xerrors"
@@ -17,25 +18,42 @@ type workerParams struct {
 	inCh  <-chan Payload
 	outCh chan<- Payload
 	errCh chan<- error
+
+	obs Observer
 }
 
 func (p *workerParams) StageIndex() int        { return p.stage }
 func (p *workerParams) Input() <-chan Payload  { return p.inCh }
 func (p *workerParams) Output() chan<- Payload { return p.outCh }
 func (p *workerParams) Error() chan<- error    { return p.errCh }
+func (p *workerParams) Observer() Observer     { return p.obs }
 
 // Pipeline implements a modular, multi-stage pipeline. Each pipeline is
 // constructed out of an input source, an output sink and zero or more
 // processing stages.
 type Pipeline struct {
 	stages []StageRunner
+	obs    Observer
 }
 
 // New returns a new pipeline instance where input payloads will traverse each
 // one of the specified stages.
 func New(stages ...StageRunner) *Pipeline {
+	return NewWithObserver(nil, stages...)
+}
+
+// NewWithObserver returns a new pipeline instance where input payloads will
+// traverse each one of the specified stages. The provided observer (if not
+// nil) is notified about the events that occur while each stage processes
+// payloads.
+func NewWithObserver(obs Observer, stages ...StageRunner) *Pipeline {
+	if obs == nil {
+		obs = noopObserver{}
+	}
+
 	return &Pipeline{
 		stages: stages,
+		obs:    obs,
 	}
 }
 
@@ -72,6 +90,7 @@ func (p *Pipeline) Process(ctx context.Context, source Source, sink Sink) error
 				inCh:  stageCh[stageIndex],
 				outCh: stageCh[stageIndex+1],
 				errCh: errCh,
+				obs:   p.obs,
 			})
 
 			// Signal next stage that no more data is available.
@@ -83,7 +102,7 @@ func (p *Pipeline) Process(ctx context.Context, source Source, sink Sink) error
 	// Start source and sink workers
 	wg.Add(2)
 	go func() {
-		sourceWorker(pCtx, source, stageCh[0], errCh)
+		sourceWorker(pCtx, source, stageCh[0], errCh, p.obs)
 
 		// Signal next stage that no more data is available.
 		close(stageCh[0])
@@ -91,7 +110,12 @@ func (p *Pipeline) Process(ctx context.Context, source Source, sink Sink) error
 	}()
 
 	go func() {
-		sinkWorker(pCtx, sink, stageCh[len(stageCh)-1], errCh)
+		sinkWorker(pCtx, sink, &workerParams{
+			stage: len(p.stages),
+			inCh:  stageCh[len(stageCh)-1],
+			errCh: errCh,
+			obs:   p.obs,
+		})
 		wg.Done()
 	}()
 
@@ -114,11 +138,13 @@ func (p *Pipeline) Process(ctx context.Context, source Source, sink Sink) error
 // sourceWorker implements a worker that reads Payload instances from a Source
 // and pushes them to an output channel that is used as input for the first
 // stage of the pipeline.
-func sourceWorker(ctx context.Context, source Source, outCh chan<- Payload, errCh chan<- error) {
+func sourceWorker(ctx context.Context, source Source, outCh chan<- Payload, errCh chan<- error, obs Observer) {
 	for source.Next(ctx) {
 		payload := source.Payload()
+		sentAt := time.Now()
 		select {
 		case outCh <- payload:
+			obs.PayloadQueued(0, time.Since(sentAt))
 		case <-ctx.Done():
 			// Asked to shutdown
 			return
@@ -134,18 +160,25 @@ func sourceWorker(ctx context.Context, source Source, outCh chan<- Payload, errC
 
 // sinkWorker implements a worker that reads Payload instances from an input
 // channel (the output of the last pipeline stage) and passes them to the
-// provided sink.
-func sinkWorker(ctx context.Context, sink Sink, inCh <-chan Payload, errCh chan<- error) {
+// provided sink. Observer events for the sink are reported using the stage
+// index specified by params.
+func sinkWorker(ctx context.Context, sink Sink, params StageParams) {
+	obs, stage := params.Observer(), params.StageIndex()
 	for {
 		select {
-		case payload, ok := <-inCh:
+		case payload, ok := <-params.Input():
 			if !ok {
 				return
 			}
 
-			if err := sink.Consume(ctx, payload); err != nil {
+			obs.PayloadIn(stage)
+			startedAt := time.Now()
+			err := sink.Consume(ctx, payload)
+			obs.PayloadProcessed(stage, time.Since(startedAt))
+			if err != nil {
+				obs.Error(stage, err)
 				wrappedErr := xerrors.Errorf("pipeline sink: %w", err)
-				maybeEmitError(wrappedErr, errCh)
+				maybeEmitError(wrappedErr, params.Error())
 				return
 			}
 			payload.MarkAsProcessed()
@@ -164,3 +197,13 @@ func maybeEmitError(err error, errCh chan<- error) {
 	default: // error channel is full with other errors.
 	}
 }
+
+// noopObserver is an Observer that ignores all events.
+type noopObserver struct{}
+
+func (noopObserver) PayloadIn(int)                       {}
+func (noopObserver) PayloadOut(int)                      {}
+func (noopObserver) PayloadQueued(int, time.Duration)    {}
+func (noopObserver) PayloadProcessed(int, time.Duration) {}
+func (noopObserver) PayloadDropped(int)                  {}
+func (noopObserver) Error(int, error)                    {}
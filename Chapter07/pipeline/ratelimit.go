@@ -0,0 +1,198 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// KeyFunc extracts a key from a payload. Payloads with the same key share
+// the same rate limit.
+type KeyFunc func(Payload) string
+
+type rateLimited struct {
+	proc    Processor
+	limiter *tokenBucket
+}
+
+// RateLimited returns a StageRunner that processes incoming payloads in a
+// first-in first-out fashion while ensuring that the specified processor is
+// invoked at most rate times per second. Up to burst payloads may be
+// processed back to back if the stage has been idle.
+func RateLimited(proc Processor, rate float64, burst int) StageRunner {
+	if rate <= 0 {
+		panic("RateLimited: rate must be > 0")
+	}
+	if burst <= 0 {
+		panic("RateLimited: burst must be > 0")
+	}
+
+	return &rateLimited{proc: proc, limiter: newTokenBucket(rate, burst)}
+}
+
+// Run implements StageRunner.
+func (r *rateLimited) Run(ctx context.Context, params StageParams) {
+	for {
+		select {
+		case <-ctx.Done():
+			// Asked to cleanly shut down
+			return
+		case payloadIn, ok := <-params.Input():
+			if !ok {
+				return
+			}
+
+			if err := r.limiter.Wait(ctx); err != nil {
+				// Asked to cleanly shut down
+				return
+			}
+
+			if !processPayload(ctx, r.proc, params, payloadIn) {
+				return
+			}
+		}
+	}
+}
+
+// KeyedRateLimited returns a StageRunner that maintains a dynamic worker pool
+// that can scale up to maxWorkers for processing incoming payloads in
+// parallel. The payloads are grouped by the key returned by keyFn and the
+// specified processor is invoked at most rate times per second for each
+// group; up to burst payloads of a group may be processed back to back if
+// the group has been idle. Like DynamicWorkerPool, the stage does not
+// preserve the order of the incoming payloads.
+func KeyedRateLimited(proc Processor, keyFn KeyFunc, rate float64, burst, maxWorkers int) StageRunner {
+	if rate <= 0 {
+		panic("KeyedRateLimited: rate must be > 0")
+	}
+	if burst <= 0 {
+		panic("KeyedRateLimited: burst must be > 0")
+	}
+	if maxWorkers <= 0 {
+		panic("KeyedRateLimited: maxWorkers must be > 0")
+	}
+
+	limiters := newKeyedTokenBuckets(rate, burst)
+	pool := DynamicWorkerPool(proc, maxWorkers).(*dynamicWorkerPool)
+	pool.beforeProcess = func(ctx context.Context, payload Payload) error {
+		return limiters.Wait(ctx, keyFn(payload))
+	}
+	return pool
+}
+
+// tokenBucket implements a token bucket rate limiter. The bucket holds up to
+// burst tokens and is refilled at a constant rate of tokens per second.
+type tokenBucket struct {
+	mu         sync.Mutex
+	rate       float64
+	burst      float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:       rate,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token becomes available or ctx expires.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	b.refill(time.Now())
+
+	// Reserve a token even if the bucket is empty; the deficit determines
+	// how long the caller has to wait for the reserved token.
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// full returns true if the bucket has been idle long enough to be refilled
+// to its maximum capacity.
+func (b *tokenBucket) full(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	return b.tokens >= b.burst
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.lastRefill).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.lastRefill = now
+}
+
+// The minimum number of buckets tracked by keyedTokenBuckets before it
+// attempts to evict idle buckets.
+const minBucketsBeforeEviction = 1024
+
+// keyedTokenBuckets maintains an independent tokenBucket for each key.
+type keyedTokenBuckets struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     int
+	buckets   map[string]*tokenBucket
+	evictSize int
+}
+
+func newKeyedTokenBuckets(rate float64, burst int) *keyedTokenBuckets {
+	return &keyedTokenBuckets{
+		rate:      rate,
+		burst:     burst,
+		buckets:   make(map[string]*tokenBucket),
+		evictSize: minBucketsBeforeEviction,
+	}
+}
+
+// Wait blocks until a token for key becomes available or ctx expires.
+func (k *keyedTokenBuckets) Wait(ctx context.Context, key string) error {
+	return k.bucket(key).Wait(ctx)
+}
+
+func (k *keyedTokenBuckets) bucket(key string) *tokenBucket {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if b, exists := k.buckets[key]; exists {
+		return b
+	}
+
+	// A full bucket behaves exactly like a newly created one so we can
+	// safely drop full buckets to keep the number of tracked keys bounded.
+	if len(k.buckets) >= k.evictSize {
+		now := time.Now()
+		for bucketKey, b := range k.buckets {
+			if b.full(now) {
+				delete(k.buckets, bucketKey)
+			}
+		}
+		if k.evictSize = 2 * len(k.buckets); k.evictSize < minBucketsBeforeEviction {
+			k.evictSize = minBucketsBeforeEviction
+		}
+	}
+
+	b := newTokenBucket(k.rate, k.burst)
+	k.buckets[key] = b
+	return b
+}
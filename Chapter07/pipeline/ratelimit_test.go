@@ -0,0 +1,97 @@
+package pipeline_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(RateLimitTestSuite))
+
+type RateLimitTestSuite struct{}
+
+func (s *RateLimitTestSuite) TestRateLimited(c *gc.C) {
+	src := &sourceStub{data: stringPayloads(6)}
+	sink := new(sinkStub)
+
+	// The first payload is processed immediately; the remaining ones are
+	// processed at 50ms intervals.
+	p := pipeline.New(pipeline.RateLimited(makePassthroughProcessor(), 20, 1))
+	start := time.Now()
+	err := p.Process(context.TODO(), src, sink)
+	elapsed := time.Since(start)
+	c.Assert(err, gc.IsNil)
+	c.Assert(sink.data, gc.DeepEquals, src.data)
+	assertAllProcessed(c, src.data)
+	c.Assert(elapsed >= 240*time.Millisecond, gc.Equals, true, gc.Commentf("pipeline completed after %s", elapsed))
+}
+
+func (s *RateLimitTestSuite) TestRateLimitedBurst(c *gc.C) {
+	src := &sourceStub{data: stringPayloads(5)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.RateLimited(makePassthroughProcessor(), 1, 5))
+	start := time.Now()
+	err := p.Process(context.TODO(), src, sink)
+	elapsed := time.Since(start)
+	c.Assert(err, gc.IsNil)
+	c.Assert(sink.data, gc.DeepEquals, src.data)
+	c.Assert(elapsed < time.Second, gc.Equals, true, gc.Commentf("pipeline completed after %s", elapsed))
+}
+
+func (s *RateLimitTestSuite) TestRateLimitedContextCancellation(c *gc.C) {
+	src := &sourceStub{data: stringPayloads(3)}
+	sink := new(sinkStub)
+
+	ctx, cancelFn := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancelFn()
+
+	p := pipeline.New(pipeline.RateLimited(makePassthroughProcessor(), 0.1, 1))
+	start := time.Now()
+	err := p.Process(ctx, src, sink)
+	elapsed := time.Since(start)
+	c.Assert(err, gc.IsNil)
+	c.Assert(sink.data, gc.HasLen, 1)
+	c.Assert(elapsed < 5*time.Second, gc.Equals, true, gc.Commentf("pipeline completed after %s", elapsed))
+}
+
+func (s *RateLimitTestSuite) TestKeyedRateLimited(c *gc.C) {
+	// Payloads are split into two keys with 3 payloads each. As each key
+	// has its own limit, processing should take ~2 intervals instead of the
+	// ~5 intervals required if a single limit was shared by all payloads.
+	keyFn := func(p pipeline.Payload) string {
+		if p.(*stringPayload).val < "3" {
+			return "a"
+		}
+		return "b"
+	}
+
+	src := &sourceStub{data: stringPayloads(6)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.KeyedRateLimited(makePassthroughProcessor(), keyFn, 10, 1, 6))
+	start := time.Now()
+	err := p.Process(context.TODO(), src, sink)
+	elapsed := time.Since(start)
+	c.Assert(err, gc.IsNil)
+	c.Assert(sink.data, gc.HasLen, len(src.data))
+	assertAllProcessed(c, src.data)
+	c.Assert(elapsed >= 190*time.Millisecond, gc.Equals, true, gc.Commentf("pipeline completed after %s", elapsed))
+	c.Assert(elapsed < 450*time.Millisecond, gc.Equals, true, gc.Commentf("pipeline completed after %s", elapsed))
+}
+
+func (s *RateLimitTestSuite) TestKeyedRateLimitedReuse(c *gc.C) {
+	keyFn := func(pipeline.Payload) string { return "" }
+	p := pipeline.New(pipeline.KeyedRateLimited(makePassthroughProcessor(), keyFn, 1000, 10, 2))
+
+	// The stage should be reusable by subsequent pipeline runs.
+	for run := 0; run < 2; run++ {
+		src := &sourceStub{data: stringPayloads(3)}
+		sink := new(sinkStub)
+		err := p.Process(context.TODO(), src, sink)
+		c.Assert(err, gc.IsNil)
+		c.Assert(sink.data, gc.HasLen, len(src.data))
+	}
+}
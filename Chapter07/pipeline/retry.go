@@ -0,0 +1,166 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultMaxAttempts  = 3
+	defaultRetryBackoff = 100 * time.Millisecond
+	defaultMaxBackoff   = 5 * time.Second
+)
+
+// BackoffFunc returns the amount of time to wait before retrying a payload
+// that has failed to be processed attempt times.
+type BackoffFunc func(attempt int) time.Duration
+
+// ExponentialBackoff returns a BackoffFunc that doubles the delay between
+// consecutive attempts, starting at base and never exceeding max.
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		return delay
+	}
+}
+
+// RetryConfig encapsulates the settings for a processor that retries failed
+// payloads.
+type RetryConfig struct {
+	// The maximum number of times a payload will be passed to the wrapped
+	// processor. If not specified, a default value of 3 attempts will be
+	// used instead.
+	MaxAttempts int
+
+	// A function for calculating the delay before each retry. If not
+	// specified, an exponential backoff starting at 100ms and capped at
+	// 5s will be used instead.
+	Backoff BackoffFunc
+
+	// A sink for payloads that could not be processed after MaxAttempts.
+	// If not specified, the last processing error is returned back to the
+	// stage which will cause the pipeline to terminate.
+	//
+	// Calls to Consume are serialized so the sink does not need to be safe
+	// for concurrent use even when the processor is shared by a worker
+	// pool.
+	//
+	// The sink receives a clone of the failed payload that it may retain
+	// indefinitely; the original payload is marked as processed by the
+	// pipeline once the processor discards it.
+	DeadLetter Sink
+}
+
+func (cfg *RetryConfig) validate() {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Backoff == nil {
+		cfg.Backoff = ExponentialBackoff(defaultRetryBackoff, defaultMaxBackoff)
+	}
+}
+
+// RetryStats contains the number of processing attempts and failures that
+// have been observed by a RetryProcessor.
+type RetryStats struct {
+	// The total number of times that payloads were passed to the wrapped
+	// processor.
+	Attempts uint64
+
+	// The number of attempts that failed with an error.
+	Failures uint64
+
+	// The number of payloads that exhausted their attempts and were sent
+	// to the dead-letter sink.
+	DeadLettered uint64
+}
+
+// RetryProcessor is a Processor that retries payloads which the processor
+// it wraps fails to process. Payloads that still fail after the configured
+// number of attempts are sent to a dead-letter sink and discarded instead of
+// terminating the pipeline.
+//
+// If the context is cancelled while waiting to retry a payload, the payload
+// is dropped.
+//
+// As payloads are retried as-is, the wrapped processor must be able to
+// process a payload that it has previously failed to process.
+type RetryProcessor struct {
+	// The counters are accessed atomically and must be kept at the top
+	// of the struct so they are 64-bit aligned on 32-bit platforms.
+	attempts     uint64
+	failures     uint64
+	deadLettered uint64
+
+	proc Processor
+	cfg  RetryConfig
+
+	deadLetterMu sync.Mutex
+}
+
+// NewRetryProcessor returns a RetryProcessor that wraps proc using the
+// settings in cfg. The returned processor can be used with any of the
+// StageRunner implementations provided by this package.
+func NewRetryProcessor(proc Processor, cfg RetryConfig) *RetryProcessor {
+	cfg.validate()
+	return &RetryProcessor{proc: proc, cfg: cfg}
+}
+
+// Process implements Processor.
+func (r *RetryProcessor) Process(ctx context.Context, payload Payload) (Payload, error) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		var payloadOut Payload
+		atomic.AddUint64(&r.attempts, 1)
+		if payloadOut, err = r.proc.Process(ctx, payload); err == nil {
+			return payloadOut, nil
+		}
+		atomic.AddUint64(&r.failures, 1)
+
+		if attempt == r.cfg.MaxAttempts {
+			break
+		}
+
+		backoff := time.NewTimer(r.cfg.Backoff(attempt))
+		select {
+		case <-backoff.C:
+		case <-ctx.Done():
+			// Asked to shut down; drop the payload instead of retrying it.
+			backoff.Stop()
+			return nil, nil
+		}
+	}
+
+	if r.cfg.DeadLetter == nil {
+		return nil, xerrors.Errorf("giving up after %d attempts: %w", r.cfg.MaxAttempts, err)
+	}
+
+	r.deadLetterMu.Lock()
+	defer r.deadLetterMu.Unlock()
+	if dlErr := r.cfg.DeadLetter.Consume(ctx, payload.Clone()); dlErr != nil {
+		return nil, xerrors.Errorf("dead-letter sink: %w", dlErr)
+	}
+	atomic.AddUint64(&r.deadLettered, 1)
+
+	// Discard the payload so it does not reach the rest of the pipeline.
+	return nil, nil
+}
+
+// Stats returns the number of attempts and failures observed so far.
+func (r *RetryProcessor) Stats() RetryStats {
+	return RetryStats{
+		Attempts:     atomic.LoadUint64(&r.attempts),
+		Failures:     atomic.LoadUint64(&r.failures),
+		DeadLettered: atomic.LoadUint64(&r.deadLettered),
+	}
+}
@@ -0,0 +1,157 @@
+package pipeline_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(RetryTestSuite))
+
+type RetryTestSuite struct{}
+
+func (s *RetryTestSuite) TestRetryUntilSuccess(c *gc.C) {
+	proc := &flakyProcessor{failFor: map[string]int{"0": 2, "2": 1}}
+	retryProc := pipeline.NewRetryProcessor(proc, pipeline.RetryConfig{
+		MaxAttempts: 3,
+		Backoff:     noBackoff,
+	})
+
+	src := &sourceStub{data: stringPayloads(3)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.FIFO(retryProc))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.IsNil)
+	c.Assert(sink.data, gc.DeepEquals, src.data)
+	assertAllProcessed(c, src.data)
+
+	c.Assert(retryProc.Stats(), gc.DeepEquals, pipeline.RetryStats{
+		Attempts: 6,
+		Failures: 3,
+	})
+}
+
+func (s *RetryTestSuite) TestDeadLetterSink(c *gc.C) {
+	proc := &flakyProcessor{failFor: map[string]int{"1": 10}}
+	deadLetter := new(sinkStub)
+	retryProc := pipeline.NewRetryProcessor(proc, pipeline.RetryConfig{
+		MaxAttempts: 2,
+		Backoff:     noBackoff,
+		DeadLetter:  deadLetter,
+	})
+
+	src := &sourceStub{data: stringPayloads(3)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.FixedWorkerPool(retryProc, 2))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.IsNil)
+	c.Assert(deadLetter.data, gc.HasLen, 1)
+	c.Assert(deadLetter.data[0].(*stringPayload).val, gc.Equals, "1")
+	c.Assert(deadLetter.data[0], gc.Not(gc.Equals), src.data[1], gc.Commentf("expected the dead-letter sink to receive a clone of the payload"))
+	c.Assert(deadLetter.data[0].(*stringPayload).processed, gc.Equals, false, gc.Commentf("expected the dead-lettered clone to remain unreleased"))
+	c.Assert(sink.data, gc.HasLen, 2)
+	assertAllProcessed(c, src.data)
+
+	c.Assert(retryProc.Stats(), gc.DeepEquals, pipeline.RetryStats{
+		Attempts:     4,
+		Failures:     2,
+		DeadLettered: 1,
+	})
+}
+
+func (s *RetryTestSuite) TestDeadLetterSinkError(c *gc.C) {
+	proc := &flakyProcessor{failFor: map[string]int{"1": 10}}
+	retryProc := pipeline.NewRetryProcessor(proc, pipeline.RetryConfig{
+		MaxAttempts: 2,
+		Backoff:     noBackoff,
+		DeadLetter:  &sinkStub{err: xerrors.New("sink full")},
+	})
+
+	src := &sourceStub{data: stringPayloads(3)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.FIFO(retryProc))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.ErrorMatches, "(?s).*dead-letter sink: sink full.*")
+}
+
+func (s *RetryTestSuite) TestGiveUpWithoutDeadLetterSink(c *gc.C) {
+	proc := &flakyProcessor{failFor: map[string]int{"1": 10}}
+	retryProc := pipeline.NewRetryProcessor(proc, pipeline.RetryConfig{
+		MaxAttempts: 4,
+		Backoff:     noBackoff,
+	})
+
+	src := &sourceStub{data: stringPayloads(3)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.DynamicWorkerPool(retryProc, 2))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.ErrorMatches, "(?s).*giving up after 4 attempts: flaky processor.*")
+}
+
+func (s *RetryTestSuite) TestDropPayloadWhenCancelledDuringBackoff(c *gc.C) {
+	proc := &flakyProcessor{failFor: map[string]int{"0": 10}}
+	retryProc := pipeline.NewRetryProcessor(proc, pipeline.RetryConfig{
+		MaxAttempts: 3,
+		Backoff:     func(int) time.Duration { return time.Hour },
+	})
+
+	ctx, cancelFn := context.WithCancel(context.TODO())
+	cancelFn()
+
+	out, err := retryProc.Process(ctx, stringPayloads(1)[0])
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.IsNil)
+	c.Assert(retryProc.Stats(), gc.DeepEquals, pipeline.RetryStats{
+		Attempts: 1,
+		Failures: 1,
+	})
+}
+
+func (s *RetryTestSuite) TestExponentialBackoff(c *gc.C) {
+	backoff := pipeline.ExponentialBackoff(100*time.Millisecond, time.Second)
+	specs := []struct {
+		attempt int
+		exp     time.Duration
+	}{
+		{attempt: 1, exp: 100 * time.Millisecond},
+		{attempt: 2, exp: 200 * time.Millisecond},
+		{attempt: 3, exp: 400 * time.Millisecond},
+		{attempt: 4, exp: 800 * time.Millisecond},
+		{attempt: 5, exp: time.Second},
+		{attempt: 100, exp: time.Second},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] attempt %d", specIndex, spec.attempt)
+		c.Assert(backoff(spec.attempt), gc.Equals, spec.exp)
+	}
+}
+
+func noBackoff(int) time.Duration { return 0 }
+
+// flakyProcessor fails to process each payload for the number of attempts
+// specified in failFor and passes it through afterwards.
+type flakyProcessor struct {
+	mu      sync.Mutex
+	failFor map[string]int
+}
+
+func (p *flakyProcessor) Process(_ context.Context, payload pipeline.Payload) (pipeline.Payload, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := payload.(*stringPayload).val
+	if p.failFor[key] > 0 {
+		p.failFor[key]--
+		return nil, xerrors.New("flaky processor")
+	}
+	return payload, nil
+}
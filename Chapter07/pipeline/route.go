@@ -0,0 +1,187 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// Branch is a sequence of stages that payloads traverse in order. Branches
+// can be combined with the Route and Fork stages to assemble pipelines whose
+// topology is a directed acyclic graph. An empty branch passes its input
+// payloads through unmodified.
+type Branch []StageRunner
+
+// RouteFunc returns the index of the branch that should process a payload.
+// A negative index indicates that the payload should be discarded.
+type RouteFunc func(Payload) int
+
+type router struct {
+	route    RouteFunc
+	branches []Branch
+}
+
+// Route returns a StageRunner that sends each incoming payload to the branch
+// selected by route based on the payload contents and merges the outputs of
+// all branches into the output of the stage.
+//
+// The merge step guarantees that payloads processed by the same branch are
+// emitted in the order that the branch outputs them; payloads from different
+// branches may be interleaved. No buffering takes place when payloads enter
+// or leave a branch so back-pressure from the following stages propagates to
+// all branches and the router does not accept a new payload until the branch
+// selected for the previous one is ready to receive it.
+func Route(route RouteFunc, branches ...Branch) StageRunner {
+	if len(branches) == 0 {
+		panic("Route: at least one branch must be specified")
+	}
+
+	return &router{route: route, branches: branches}
+}
+
+// Run implements StageRunner.
+func (r *router) Run(ctx context.Context, params StageParams) {
+	runBranches(ctx, params, r.branches, func(payload Payload, branchIn []chan<- Payload) bool {
+		branch := r.route(payload)
+		switch {
+		case branch < 0:
+			params.Observer().PayloadDropped(params.StageIndex())
+			payload.MarkAsProcessed()
+			return true
+		case branch >= len(branchIn):
+			err := xerrors.Errorf("invalid branch index %d", branch)
+			params.Observer().Error(params.StageIndex(), err)
+			maybeEmitError(xerrors.Errorf("pipeline stage %d: %w", params.StageIndex(), err), params.Error())
+			return false
+		}
+
+		select {
+		case branchIn[branch] <- payload:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
+
+type fork struct {
+	branches []Branch
+}
+
+// Fork returns a StageRunner that passes a copy of each incoming payload to
+// all specified branches and merges their outputs into the output of the
+// stage. Fork provides the same ordering and back-pressure guarantees as
+// Route.
+func Fork(branches ...Branch) StageRunner {
+	if len(branches) == 0 {
+		panic("Fork: at least one branch must be specified")
+	}
+
+	return &fork{branches: branches}
+}
+
+// Run implements StageRunner.
+func (f *fork) Run(ctx context.Context, params StageParams) {
+	runBranches(ctx, params, f.branches, func(payload Payload, branchIn []chan<- Payload) bool {
+		for i := len(branchIn) - 1; i >= 0; i-- {
+			// As each branch might modify the payload, to avoid data
+			// races we need to make a copy of the payload for all
+			// branches except the first.
+			var branchPayload = payload
+			if i != 0 {
+				branchPayload = payload.Clone()
+			}
+			select {
+			case branchIn[i] <- branchPayload:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// runBranches starts the stages for each branch and invokes dispatch for
+// each payload read from the stage input until the input is exhausted, the
+// context expires or dispatch returns false. The outputs of the last stage
+// of each branch are merged into the output of the stage.
+func runBranches(ctx context.Context, params StageParams, branches []Branch, dispatch func(Payload, []chan<- Payload) bool) {
+	var (
+		wg       sync.WaitGroup
+		branchIn = make([]chan<- Payload, len(branches))
+		ownedIn  []chan Payload
+	)
+
+	for i, branch := range branches {
+		// Payloads routed to an empty branch go straight to the output.
+		if len(branch) == 0 {
+			branchIn[i] = params.Output()
+			continue
+		}
+
+		inCh := make(chan Payload)
+		branchIn[i], ownedIn = inCh, append(ownedIn, inCh)
+		wg.Add(1)
+		go func(branch Branch) {
+			runBranch(ctx, params, branch, inCh)
+			wg.Done()
+		}(branch)
+	}
+
+done:
+	for {
+		select {
+		case <-ctx.Done():
+			break done
+		case payload, ok := <-params.Input():
+			if !ok || !dispatch(payload, branchIn) {
+				break done
+			}
+		}
+	}
+
+	// Close input channels and wait for branches to exit
+	for _, ch := range ownedIn {
+		close(ch)
+	}
+	wg.Wait()
+}
+
+// runBranch wires together the stages of a branch and blocks until all of
+// them exit. The last stage of the branch writes its output directly to the
+// output of the stage that owns the branch. Errors and observer events for
+// the branch stages are reported using the index of the owning stage.
+func runBranch(ctx context.Context, params StageParams, branch Branch, inCh <-chan Payload) {
+	var wg sync.WaitGroup
+	for i, stage := range branch {
+		var (
+			outCh  = params.Output()
+			nextCh chan Payload
+		)
+		if i < len(branch)-1 {
+			nextCh = make(chan Payload)
+			outCh = nextCh
+		}
+
+		wg.Add(1)
+		go func(stage StageRunner, stageParams *workerParams, nextCh chan Payload) {
+			stage.Run(ctx, stageParams)
+
+			// Signal next stage that no more data is available.
+			if nextCh != nil {
+				close(nextCh)
+			}
+			wg.Done()
+		}(stage, &workerParams{
+			stage: params.StageIndex(),
+			inCh:  inCh,
+			outCh: outCh,
+			errCh: params.Error(),
+			obs:   params.Observer(),
+		}, nextCh)
+
+		inCh = nextCh
+	}
+	wg.Wait()
+}
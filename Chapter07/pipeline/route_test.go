@@ -0,0 +1,163 @@
+package pipeline_test
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(RouteTestSuite))
+
+type RouteTestSuite struct{}
+
+func (s *RouteTestSuite) TestRouteByContent(c *gc.C) {
+	route := func(p pipeline.Payload) int {
+		val, _ := strconv.Atoi(p.(*stringPayload).val)
+		switch {
+		case val == 9:
+			return -1
+		case val%2 == 0:
+			return 0
+		default:
+			return 1
+		}
+	}
+
+	src := &sourceStub{data: stringPayloads(10)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(
+		pipeline.Route(route,
+			pipeline.Branch{pipeline.FIFO(makeSuffixProcessor("even"))},
+			pipeline.Branch{
+				pipeline.FIFO(makeSuffixProcessor("odd")),
+				pipeline.FIFO(makeSuffixProcessor("odd")),
+			},
+		),
+		pipeline.FIFO(makeSuffixProcessor("merged")),
+	)
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.IsNil)
+	assertAllProcessed(c, src.data)
+
+	// Payloads from the same branch must retain their relative order.
+	var even, odd []string
+	for _, p := range sink.data {
+		val := p.(*stringPayload).val
+		if strings.Contains(val, "even") {
+			even = append(even, val)
+		} else {
+			odd = append(odd, val)
+		}
+	}
+	c.Assert(even, gc.DeepEquals, []string{
+		"0_even_merged", "2_even_merged", "4_even_merged", "6_even_merged", "8_even_merged",
+	})
+	c.Assert(odd, gc.DeepEquals, []string{
+		"1_odd_odd_merged", "3_odd_odd_merged", "5_odd_odd_merged", "7_odd_odd_merged",
+	})
+}
+
+func (s *RouteTestSuite) TestRouteToInvalidBranch(c *gc.C) {
+	route := func(pipeline.Payload) int { return 1 }
+
+	src := &sourceStub{data: stringPayloads(3)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(pipeline.Route(route, pipeline.Branch{pipeline.FIFO(makePassthroughProcessor())}))
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.ErrorMatches, "(?s).*pipeline stage 0: invalid branch index 1.*")
+}
+
+func (s *RouteTestSuite) TestRouteBackpressure(c *gc.C) {
+	syncCh := make(chan struct{}, 10)
+	rendezvousCh := make(chan struct{})
+	blockingProc := pipeline.ProcessorFunc(func(_ context.Context, p pipeline.Payload) (pipeline.Payload, error) {
+		syncCh <- struct{}{}
+		<-rendezvousCh
+		return p, nil
+	})
+
+	src := &countingSourceStub{sourceStub: sourceStub{data: stringPayloads(10)}}
+	sink := new(sinkStub)
+
+	route := func(pipeline.Payload) int { return 0 }
+	p := pipeline.New(pipeline.Route(route, pipeline.Branch{pipeline.FIFO(blockingProc)}))
+	doneCh := make(chan struct{})
+	go func() {
+		err := p.Process(context.TODO(), src, sink)
+		c.Assert(err, gc.IsNil)
+		close(doneCh)
+	}()
+
+	select {
+	case <-syncCh:
+	case <-time.After(10 * time.Second):
+		c.Fatal("timed out waiting for branch to receive the first payload")
+	}
+
+	// While the branch is blocked, at most one payload can be held by the
+	// router and another one by the source worker.
+	time.Sleep(100 * time.Millisecond)
+	c.Assert(atomic.LoadInt32(&src.nextCalls) <= 3, gc.Equals, true, gc.Commentf("source was polled %d times", atomic.LoadInt32(&src.nextCalls)))
+
+	close(rendezvousCh)
+	select {
+	case <-doneCh:
+	case <-time.After(10 * time.Second):
+		c.Fatal("timed out waiting for pipeline to complete")
+	}
+	c.Assert(sink.data, gc.DeepEquals, src.data)
+}
+
+func (s *RouteTestSuite) TestFork(c *gc.C) {
+	src := &sourceStub{data: stringPayloads(2)}
+	sink := new(sinkStub)
+
+	p := pipeline.New(
+		pipeline.Fork(
+			pipeline.Branch{},
+			pipeline.Branch{pipeline.FIFO(makeSuffixProcessor("a"))},
+			pipeline.Branch{
+				pipeline.FIFO(makeSuffixProcessor("b")),
+				pipeline.FIFO(makeSuffixProcessor("c")),
+			},
+		),
+	)
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.IsNil)
+	assertAllProcessed(c, src.data)
+
+	var got []string
+	for _, p := range sink.data {
+		got = append(got, p.(*stringPayload).val)
+	}
+	sort.Strings(got)
+	c.Assert(got, gc.DeepEquals, []string{"0", "0_a", "0_b_c", "1", "1_a", "1_b_c"})
+}
+
+func makeSuffixProcessor(suffix string) pipeline.Processor {
+	return pipeline.ProcessorFunc(func(_ context.Context, p pipeline.Payload) (pipeline.Payload, error) {
+		sp := p.(*stringPayload)
+		sp.val += "_" + suffix
+		return p, nil
+	})
+}
+
+// countingSourceStub is a sourceStub that keeps track of the number of
+// calls to Next.
+type countingSourceStub struct {
+	sourceStub
+	nextCalls int32
+}
+
+func (s *countingSourceStub) Next(ctx context.Context) bool {
+	atomic.AddInt32(&s.nextCalls, 1)
+	return s.sourceStub.Next(ctx)
+}
@@ -3,6 +3,7 @@ package pipeline
 import (
 	"context"
 	"sync"
+	"time"
 
 	"golang.org/x/xerrors"
 )
@@ -30,28 +31,56 @@ func (r fifo) Run(ctx context.Context, params StageParams) {
 				return
 			}
 
-			payloadOut, err := r.proc.Process(ctx, payloadIn)
-			if err != nil {
-				wrappedErr := xerrors.Errorf("pipeline stage %d: %w", params.StageIndex(), err)
-				maybeEmitError(wrappedErr, params.Error())
+			if !processPayload(ctx, r.proc, params, payloadIn) {
 				return
 			}
+		}
+	}
+}
 
-			// If the processor did not output a payload for the
-			// next stage there is nothing we need to do.
-			if payloadOut == nil {
-				payloadIn.MarkAsProcessed()
-				continue
-			}
+// processPayload passes payloadIn to proc and emits the processed payload to
+// the output of the stage while notifying the stage observer about each step.
+// It returns false if the stage should shut down, either because an error
+// occurred or because the context expired.
+func processPayload(ctx context.Context, proc Processor, params StageParams, payloadIn Payload) bool {
+	obs, stage := params.Observer(), params.StageIndex()
+	obs.PayloadIn(stage)
+
+	startedAt := time.Now()
+	payloadOut, err := proc.Process(ctx, payloadIn)
+	obs.PayloadProcessed(stage, time.Since(startedAt))
+	if err != nil {
+		obs.Error(stage, err)
+		wrappedErr := xerrors.Errorf("pipeline stage %d: %w", stage, err)
+		maybeEmitError(wrappedErr, params.Error())
+		return false
+	}
 
-			// Output processed data
-			select {
-			case params.Output() <- payloadOut:
-			case <-ctx.Done():
-				// Asked to cleanly shut down
-				return
-			}
-		}
+	// If the processor did not output a payload for the next stage there
+	// is nothing we need to do.
+	if payloadOut == nil {
+		obs.PayloadDropped(stage)
+		payloadIn.MarkAsProcessed()
+		return true
+	}
+
+	return emitPayload(ctx, params, payloadOut)
+}
+
+// emitPayload sends payload to the output of the stage and notifies the stage
+// observer. It returns false if the context expired before the payload could
+// be sent.
+func emitPayload(ctx context.Context, params StageParams, payload Payload) bool {
+	obs, stage := params.Observer(), params.StageIndex()
+	sentAt := time.Now()
+	select {
+	case params.Output() <- payload:
+		obs.PayloadOut(stage)
+		obs.PayloadQueued(stage+1, time.Since(sentAt))
+		return true
+	case <-ctx.Done():
+		// Asked to cleanly shut down
+		return false
 	}
 }
 
@@ -94,6 +123,10 @@ func (p *fixedWorkerPool) Run(ctx context.Context, params StageParams) {
 type dynamicWorkerPool struct {
 	proc      Processor
 	tokenPool chan struct{}
+
+	// An optional function that workers invoke before processing each
+	// payload. If it returns an error, the payload is not processed.
+	beforeProcess func(context.Context, Payload) error
 }
 
 // DynamicWorkerPool returns a StageRunner that maintains a dynamic worker pool
@@ -134,25 +167,10 @@ stop:
 
 			go func(payloadIn Payload, token struct{}) {
 				defer func() { p.tokenPool <- token }()
-				payloadOut, err := p.proc.Process(ctx, payloadIn)
-				if err != nil {
-					wrappedErr := xerrors.Errorf("pipeline stage %d: %w", params.StageIndex(), err)
-					maybeEmitError(wrappedErr, params.Error())
-					return
-				}
-
-				// If the processor did not output a payload for the
-				// next stage there is nothing we need to do.
-				if payloadOut == nil {
-					payloadIn.MarkAsProcessed()
+				if p.beforeProcess != nil && p.beforeProcess(ctx, payloadIn) != nil {
 					return
 				}
-
-				// Output processed data
-				select {
-				case params.Output() <- payloadOut:
-				case <-ctx.Done():
-				}
+				processPayload(ctx, p.proc, params, payloadIn)
 			}(payloadIn, token)
 		}
 	}
@@ -161,6 +179,11 @@ stop:
 	for i := 0; i < cap(p.tokenPool); i++ {
 		<-p.tokenPool
 	}
+
+	// Refill the token pool so the stage can be reused by subsequent runs.
+	for i := 0; i < cap(p.tokenPool); i++ {
+		p.tokenPool <- struct{}{}
+	}
 }
 
 type broadcast struct {
@@ -200,6 +223,7 @@ func (b *broadcast) Run(ctx context.Context, params StageParams) {
 				inCh:  inCh[fifoIndex],
 				outCh: params.Output(),
 				errCh: params.Error(),
+				obs:   params.Observer(),
 			}
 			b.fifos[fifoIndex].Run(ctx, fifoParams)
 			wg.Done()
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"golang.org/x/xerrors"
 	gc "gopkg.in/check.v1"
 )
 
@@ -149,6 +151,47 @@ func (s StageTestSuite) TestBroadcast(c *gc.C) {
 	c.Assert(sink.data, gc.DeepEquals, expData)
 }
 
+func (s StageTestSuite) TestObserver(c *gc.C) {
+	expErr := xerrors.New("some error")
+	dropOdd := pipeline.ProcessorFunc(func(_ context.Context, p pipeline.Payload) (pipeline.Payload, error) {
+		switch p.(*stringPayload).val {
+		case "1":
+			return nil, nil
+		case "3":
+			return nil, expErr
+		}
+		return p, nil
+	})
+
+	src := &sourceStub{data: stringPayloads(4)}
+	sink := new(sinkStub)
+	obs := newRecordingObserver()
+
+	p := pipeline.NewWithObserver(obs,
+		pipeline.FIFO(makePassthroughProcessor()),
+		pipeline.FIFO(dropOdd),
+	)
+	err := p.Process(context.TODO(), src, sink)
+	c.Assert(err, gc.ErrorMatches, "(?s).*pipeline stage 1: some error.*")
+
+	// The sink is identified by an index equal to the number of stages.
+	c.Assert(obs.events, gc.DeepEquals, map[string]int{
+		"in/0":        4,
+		"out/0":       4,
+		"queued/0":    4,
+		"processed/0": 4,
+		"in/1":        4,
+		"out/1":       2,
+		"queued/1":    4,
+		"processed/1": 4,
+		"dropped/1":   1,
+		"error/1":     1,
+		"in/2":        2,
+		"queued/2":    2,
+		"processed/2": 2,
+	})
+}
+
 func makeMutatingProcessor(index int) pipeline.Processor {
 	return pipeline.ProcessorFunc(func(_ context.Context, p pipeline.Payload) (pipeline.Payload, error) {
 		// Mutate payload to check that each processor got a copy
@@ -163,3 +206,28 @@ func makePassthroughProcessor() pipeline.Processor {
 		return p, nil
 	})
 }
+
+// recordingObserver counts the events reported for each stage.
+type recordingObserver struct {
+	mu     sync.Mutex
+	events map[string]int
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{events: make(map[string]int)}
+}
+
+func (o *recordingObserver) record(event string, stage int) {
+	o.mu.Lock()
+	o.events[fmt.Sprintf("%s/%d", event, stage)]++
+	o.mu.Unlock()
+}
+
+func (o *recordingObserver) PayloadIn(stage int)                      { o.record("in", stage) }
+func (o *recordingObserver) PayloadOut(stage int)                     { o.record("out", stage) }
+func (o *recordingObserver) PayloadQueued(stage int, _ time.Duration) { o.record("queued", stage) }
+func (o *recordingObserver) PayloadProcessed(stage int, _ time.Duration) {
+	o.record("processed", stage)
+}
+func (o *recordingObserver) PayloadDropped(stage int) { o.record("dropped", stage) }
+func (o *recordingObserver) Error(stage int, _ error) { o.record("error", stage) }
@@ -6,12 +6,15 @@ import (
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/snapshot"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 )
 
-//go:generate mockgen -package mocks -destination mocks/mock.go github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto LinkGraphClient,LinkGraph_LinksClient,LinkGraph_EdgesClient
+//go:generate mockgen -package mocks -destination mocks/mock.go github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto LinkGraphClient,LinkGraph_LinksClient,LinkGraph_EdgesClient,LinkGraph_InboundEdgesClient,LinkGraph_ExportClient,LinkGraph_ImportClient,LinkGraph_UpsertLinksClient,LinkGraph_UpsertEdgesClient,LinkGraph_WatchClient
 
 // LinkGraphClient provides an API compatible with the graph.Graph interface
 // for accessing graph instances exposed by a remote gRPC server.
@@ -30,9 +33,13 @@ func NewLinkGraphClient(ctx context.Context, rpcClient proto.LinkGraphClient) *L
 // UpsertLink creates a new link or updates an existing link.
 func (c *LinkGraphClient) UpsertLink(link *graph.Link) error {
 	req := &proto.Link{
-		Uuid:        link.ID[:],
-		Url:         link.URL,
-		RetrievedAt: timeToProto(link.RetrievedAt),
+		Uuid:         link.ID[:],
+		Url:          link.URL,
+		RetrievedAt:  timeToProto(link.RetrievedAt),
+		HttpStatus:   int32(link.HTTPStatus),
+		ContentType:  link.ContentType,
+		ContentHash:  link.ContentHash,
+		FailureCount: uint32(link.FailureCount),
 	}
 	res, err := c.cli.UpsertLink(c.ctx, req)
 	if err != nil {
@@ -68,6 +75,101 @@ func (c *LinkGraphClient) UpsertEdge(edge *graph.Edge) error {
 	return nil
 }
 
+// UpsertLinks creates or updates a batch of links by streaming them to the
+// remote link graph.
+func (c *LinkGraphClient) UpsertLinks(links []*graph.Link) error {
+	ctx, cancelFn := context.WithCancel(c.ctx)
+	defer cancelFn()
+
+	stream, err := c.cli.UpsertLinks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		req := &proto.Link{
+			Uuid:         link.ID[:],
+			Url:          link.URL,
+			RetrievedAt:  timeToProto(link.RetrievedAt),
+			HttpStatus:   int32(link.HTTPStatus),
+			ContentType:  link.ContentType,
+			ContentHash:  link.ContentHash,
+			FailureCount: uint32(link.FailureCount),
+		}
+
+		// Send returns io.EOF if the server aborts the stream; the
+		// actual error is then obtained via the call to CloseAndRecv.
+		if err = stream.Send(req); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if len(res.Links) != len(links) {
+		return xerrors.Errorf("upsert links: expected %d links in response; got %d", len(links), len(res.Links))
+	}
+
+	for i, link := range links {
+		link.ID = uuidFromBytes(res.Links[i].Uuid)
+		link.URL = res.Links[i].Url
+		if link.RetrievedAt, err = ptypes.Timestamp(res.Links[i].RetrievedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpsertEdges creates or updates a batch of edges by streaming them to the
+// remote link graph.
+func (c *LinkGraphClient) UpsertEdges(edges []*graph.Edge) error {
+	ctx, cancelFn := context.WithCancel(c.ctx)
+	defer cancelFn()
+
+	stream, err := c.cli.UpsertEdges(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, edge := range edges {
+		req := &proto.Edge{
+			Uuid:    edge.ID[:],
+			SrcUuid: edge.Src[:],
+			DstUuid: edge.Dst[:],
+		}
+
+		// Send returns io.EOF if the server aborts the stream; the
+		// actual error is then obtained via the call to CloseAndRecv.
+		if err = stream.Send(req); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if len(res.Edges) != len(edges) {
+		return xerrors.Errorf("upsert edges: expected %d edges in response; got %d", len(edges), len(res.Edges))
+	}
+
+	for i, edge := range edges {
+		edge.ID = uuidFromBytes(res.Edges[i].Uuid)
+		if edge.UpdatedAt, err = ptypes.Timestamp(res.Edges[i].UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Links returns an iterator for the set of links whose IDs belong to the
 // [fromID, toID) range and were last accessed before the provided value.
 func (c *LinkGraphClient) Links(fromID, toID uuid.UUID, accessedBefore time.Time) (graph.LinkIterator, error) {
@@ -117,6 +219,42 @@ func (c *LinkGraphClient) Edges(fromID, toID uuid.UUID, updatedBefore time.Time)
 	return &edgeIterator{stream: stream, cancelFn: cancelFn}, nil
 }
 
+// InboundEdges returns an iterator for the set of edges that terminate at the
+// specified link ID.
+func (c *LinkGraphClient) InboundEdges(dstID uuid.UUID) (graph.EdgeIterator, error) {
+	req := &proto.InboundEdgesQuery{DstUuid: dstID[:]}
+
+	ctx, cancelFn := context.WithCancel(c.ctx)
+	stream, err := c.cli.InboundEdges(ctx, req)
+	if err != nil {
+		cancelFn()
+		return nil, err
+	}
+
+	return &edgeIterator{stream: stream, cancelFn: cancelFn}, nil
+}
+
+// InDegree returns the number of edges that terminate at the specified link ID.
+func (c *LinkGraphClient) InDegree(id uuid.UUID) (int, error) {
+	res, err := c.cli.Degree(c.ctx, &proto.LinkDegreeQuery{Uuid: id[:]})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.InDegree), nil
+}
+
+// OutDegree returns the number of edges that originate from the specified link
+// ID.
+func (c *LinkGraphClient) OutDegree(id uuid.UUID) (int, error) {
+	res, err := c.cli.Degree(c.ctx, &proto.LinkDegreeQuery{Uuid: id[:]})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.OutDegree), nil
+}
+
 // RemoveStaleEdges removes any edge that originates from the specified link ID
 // and was updated before the specified timestamp.
 func (c *LinkGraphClient) RemoveStaleEdges(from uuid.UUID, updatedBefore time.Time) error {
@@ -129,6 +267,82 @@ func (c *LinkGraphClient) RemoveStaleEdges(from uuid.UUID, updatedBefore time.Ti
 	return err
 }
 
+// RemoveLink removes the link with the specified ID as well as any edge that
+// originates from or terminates at it.
+func (c *LinkGraphClient) RemoveLink(id uuid.UUID) error {
+	req := &proto.RemoveLinkQuery{Uuid: id[:]}
+
+	_, err := c.cli.RemoveLink(c.ctx, req)
+	return err
+}
+
+// RemoveLinksByHost removes all links whose URL points to the specified host
+// as well as any edges that originate from or terminate at them.
+func (c *LinkGraphClient) RemoveLinksByHost(host string) error {
+	req := &proto.RemoveLinksByHostQuery{Host: host}
+
+	_, err := c.cli.RemoveLinksByHost(c.ctx, req)
+	return err
+}
+
+// Export writes a compressed snapshot of the remote link graph contents to w.
+func (c *LinkGraphClient) Export(w io.Writer) error {
+	ctx, cancelFn := context.WithCancel(c.ctx)
+	defer cancelFn()
+
+	stream, err := c.cli.Export(ctx, new(empty.Empty))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, &chunkReader{recvFn: stream.Recv})
+	return err
+}
+
+// Import reads a compressed link graph snapshot from r and upserts its
+// contents into the remote link graph.
+func (c *LinkGraphClient) Import(r io.Reader) (snapshot.Stats, error) {
+	ctx, cancelFn := context.WithCancel(c.ctx)
+	defer cancelFn()
+
+	stream, err := c.cli.Import(ctx)
+	if err != nil {
+		return snapshot.Stats{}, err
+	}
+
+	// Send returns io.EOF if the server aborts the stream; the actual error
+	// is then obtained via the call to CloseAndRecv.
+	if _, err = io.Copy(chunkWriter(stream.Send), r); err != nil && err != io.EOF {
+		return snapshot.Stats{}, err
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return snapshot.Stats{}, err
+	}
+
+	return snapshot.Stats{Links: int(res.Links), Edges: int(res.Edges)}, nil
+}
+
+// Watch returns an iterator for the link graph mutations that took place
+// after the provided timestamp. The iterator blocks waiting for new events
+// until it is closed.
+func (c *LinkGraphClient) Watch(since time.Time) (graph.ChangeEventIterator, error) {
+	sinceProto, err := ptypes.TimestampProto(since)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFn := context.WithCancel(c.ctx)
+	stream, err := c.cli.Watch(ctx, &proto.WatchRequest{Since: sinceProto})
+	if err != nil {
+		cancelFn()
+		return nil, err
+	}
+
+	return &changeEventIterator{stream: stream, cancelFn: cancelFn}, nil
+}
+
 type linkIterator struct {
 	stream  proto.LinkGraph_LinksClient
 	next    *graph.Link
@@ -159,9 +373,13 @@ func (it *linkIterator) Next() bool {
 	}
 
 	it.next = &graph.Link{
-		ID:          uuidFromBytes(res.Uuid),
-		URL:         res.Url,
-		RetrievedAt: lastAccessed,
+		ID:           uuidFromBytes(res.Uuid),
+		URL:          res.Url,
+		RetrievedAt:  lastAccessed,
+		HTTPStatus:   int(res.HttpStatus),
+		ContentType:  res.ContentType,
+		ContentHash:  res.ContentHash,
+		FailureCount: int(res.FailureCount),
 	}
 	return true
 }
@@ -178,8 +396,14 @@ func (it *linkIterator) Close() error {
 	return nil
 }
 
+// edgeStream is implemented by the client-side of server-streaming RPCs that
+// return edges.
+type edgeStream interface {
+	Recv() (*proto.Edge, error)
+}
+
 type edgeIterator struct {
-	stream  proto.LinkGraph_EdgesClient
+	stream  edgeStream
 	next    *graph.Edge
 	lastErr error
 
@@ -227,3 +451,85 @@ func (it *edgeIterator) Close() error {
 	it.cancelFn()
 	return nil
 }
+
+type changeEventIterator struct {
+	stream  proto.LinkGraph_WatchClient
+	next    *graph.ChangeEvent
+	lastErr error
+
+	// A function to cancel the context used to perform the streaming RPC. It
+	// allows us to abort server-streaming calls from the client side.
+	cancelFn func()
+}
+
+// Next advances the iterator. It blocks until a new event becomes available.
+// If the stream is closed or an error occurs, calls to Next() return false.
+func (it *changeEventIterator) Next() bool {
+	res, err := it.stream.Recv()
+	if err != nil {
+		if err != io.EOF {
+			it.lastErr = err
+		}
+		it.cancelFn()
+		return false
+	}
+
+	evt, err := changeEventFromProto(res)
+	if err != nil {
+		it.lastErr = err
+		it.cancelFn()
+		return false
+	}
+
+	it.next = evt
+	return true
+}
+
+// Error returns the last error encountered by the iterator.
+func (it *changeEventIterator) Error() error { return it.lastErr }
+
+// Event returns the currently fetched change event.
+func (it *changeEventIterator) Event() *graph.ChangeEvent { return it.next }
+
+// Close releases any resources associated with an iterator.
+func (it *changeEventIterator) Close() error {
+	it.cancelFn()
+	return nil
+}
+
+func changeEventFromProto(res *proto.ChangeEvent) (*graph.ChangeEvent, error) {
+	ts, err := ptypes.Timestamp(res.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	evt := &graph.ChangeEvent{Type: graph.ChangeType(res.Type), Timestamp: ts}
+	if res.Link != nil {
+		retrievedAt, err := ptypes.Timestamp(res.Link.RetrievedAt)
+		if err != nil {
+			return nil, err
+		}
+		evt.Link = &graph.Link{
+			ID:           uuidFromBytes(res.Link.Uuid),
+			URL:          res.Link.Url,
+			RetrievedAt:  retrievedAt,
+			HTTPStatus:   int(res.Link.HttpStatus),
+			ContentType:  res.Link.ContentType,
+			ContentHash:  res.Link.ContentHash,
+			FailureCount: int(res.Link.FailureCount),
+		}
+	}
+	if res.Edge != nil {
+		updatedAt, err := ptypes.Timestamp(res.Edge.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		evt.Edge = &graph.Edge{
+			ID:        uuidFromBytes(res.Edge.Uuid),
+			Src:       uuidFromBytes(res.Edge.SrcUuid),
+			Dst:       uuidFromBytes(res.Edge.DstUuid),
+			UpdatedAt: updatedAt,
+		}
+	}
+	return evt, nil
+}
@@ -1,11 +1,14 @@
 package linkgraphapi_test
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/snapshot"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/mocks"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto"
@@ -89,6 +92,89 @@ func (s *ClientTestSuite) TestUpsertEdge(c *gc.C) {
 	c.Assert(edge.ID, gc.DeepEquals, assignedID)
 }
 
+func (s *ClientTestSuite) TestUpsertLinks(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockLinkGraphClient(ctrl)
+	upsertStream := mocks.NewMockLinkGraph_UpsertLinksClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	links := []*graph.Link{
+		{URL: "http://example.com", RetrievedAt: time.Now().Truncate(time.Second).UTC()},
+		{URL: "http://foo.com"},
+	}
+
+	rpcCli.EXPECT().UpsertLinks(gomock.AssignableToTypeOf(ctxWithCancel)).Return(upsertStream, nil)
+	for _, link := range links {
+		upsertStream.EXPECT().Send(&proto.Link{
+			Uuid:        uuid.Nil[:],
+			Url:         link.URL,
+			RetrievedAt: mustEncodeTimestamp(c, link.RetrievedAt),
+		}).Return(nil)
+	}
+
+	assignedIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	upsertStream.EXPECT().CloseAndRecv().Return(
+		&proto.LinkBatch{
+			Links: []*proto.Link{
+				{Uuid: assignedIDs[0][:], Url: links[0].URL, RetrievedAt: mustEncodeTimestamp(c, links[0].RetrievedAt)},
+				{Uuid: assignedIDs[1][:], Url: links[1].URL, RetrievedAt: mustEncodeTimestamp(c, links[1].RetrievedAt)},
+			},
+		},
+		nil,
+	)
+
+	cli := linkgraphapi.NewLinkGraphClient(context.TODO(), rpcCli)
+	c.Assert(cli.UpsertLinks(links), gc.IsNil)
+	for i, link := range links {
+		c.Assert(link.ID, gc.Equals, assignedIDs[i])
+	}
+}
+
+func (s *ClientTestSuite) TestUpsertEdges(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockLinkGraphClient(ctrl)
+	upsertStream := mocks.NewMockLinkGraph_UpsertEdgesClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	edges := []*graph.Edge{
+		{Src: uuid.New(), Dst: uuid.New()},
+		{Src: uuid.New(), Dst: uuid.New()},
+	}
+
+	rpcCli.EXPECT().UpsertEdges(gomock.AssignableToTypeOf(ctxWithCancel)).Return(upsertStream, nil)
+	for _, edge := range edges {
+		upsertStream.EXPECT().Send(&proto.Edge{
+			Uuid:    uuid.Nil[:],
+			SrcUuid: edge.Src[:],
+			DstUuid: edge.Dst[:],
+		}).Return(nil)
+	}
+
+	assignedIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	upsertStream.EXPECT().CloseAndRecv().Return(
+		&proto.EdgeBatch{
+			Edges: []*proto.Edge{
+				{Uuid: assignedIDs[0][:], SrcUuid: edges[0].Src[:], DstUuid: edges[0].Dst[:], UpdatedAt: ptypes.TimestampNow()},
+				{Uuid: assignedIDs[1][:], SrcUuid: edges[1].Src[:], DstUuid: edges[1].Dst[:], UpdatedAt: ptypes.TimestampNow()},
+			},
+		},
+		nil,
+	)
+
+	cli := linkgraphapi.NewLinkGraphClient(context.TODO(), rpcCli)
+	c.Assert(cli.UpsertEdges(edges), gc.IsNil)
+	for i, edge := range edges {
+		c.Assert(edge.ID, gc.Equals, assignedIDs[i])
+		c.Assert(edge.UpdatedAt.IsZero(), gc.Equals, false)
+	}
+}
+
 func (s *ClientTestSuite) TestLinks(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -108,8 +194,8 @@ func (s *ClientTestSuite) TestLinks(c *gc.C) {
 	uuid2 := uuid.New()
 	lastAccessed := mustEncodeTimestamp(c, now)
 	returns := [][]interface{}{
-		{&proto.Link{Uuid: uuid1[:], Url: "http://example.com", RetrievedAt: lastAccessed}, nil},
-		{&proto.Link{Uuid: uuid2[:], Url: "http://example.com", RetrievedAt: lastAccessed}, nil},
+		{&proto.Link{Uuid: uuid1[:], Url: "http://example.com", RetrievedAt: lastAccessed, HttpStatus: 404, FailureCount: 2}, nil},
+		{&proto.Link{Uuid: uuid2[:], Url: "http://example.com", RetrievedAt: lastAccessed, HttpStatus: 404, FailureCount: 2}, nil},
 		{nil, io.EOF},
 	}
 	linkStream.EXPECT().Recv().DoAndReturn(
@@ -133,6 +219,8 @@ func (s *ClientTestSuite) TestLinks(c *gc.C) {
 		}
 		c.Assert(next.URL, gc.Equals, "http://example.com")
 		c.Assert(next.RetrievedAt, gc.Equals, now)
+		c.Assert(next.HTTPStatus, gc.Equals, 404)
+		c.Assert(next.FailureCount, gc.Equals, 2)
 	}
 	c.Assert(it.Error(), gc.IsNil)
 	c.Assert(it.Close(), gc.IsNil)
@@ -193,6 +281,76 @@ func (s *ClientTestSuite) TestEdges(c *gc.C) {
 	c.Assert(edgeCount, gc.Equals, 2)
 }
 
+func (s *ClientTestSuite) TestInboundEdges(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockLinkGraphClient(ctrl)
+	edgeStream := mocks.NewMockLinkGraph_InboundEdgesClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	dstID := uuid.New()
+	rpcCli.EXPECT().InboundEdges(
+		gomock.AssignableToTypeOf(ctxWithCancel),
+		&proto.InboundEdgesQuery{DstUuid: dstID[:]},
+	).Return(edgeStream, nil)
+
+	edgeID := uuid.New()
+	srcID := uuid.New()
+	updatedAt := time.Now().UTC()
+
+	returns := [][]interface{}{
+		{&proto.Edge{Uuid: edgeID[:], SrcUuid: srcID[:], DstUuid: dstID[:], UpdatedAt: mustEncodeTimestamp(c, updatedAt)}, nil},
+		{nil, io.EOF},
+	}
+	edgeStream.EXPECT().Recv().DoAndReturn(
+		func() (interface{}, interface{}) {
+			next := returns[0]
+			returns = returns[1:]
+			return next[0], next[1]
+		},
+	).Times(len(returns))
+
+	cli := linkgraphapi.NewLinkGraphClient(context.TODO(), rpcCli)
+	it, err := cli.InboundEdges(dstID)
+	c.Assert(err, gc.IsNil)
+
+	var edgeCount int
+	for it.Next() {
+		edgeCount++
+		next := it.Edge()
+		c.Assert(next.ID, gc.DeepEquals, edgeID)
+		c.Assert(next.Src, gc.DeepEquals, srcID)
+		c.Assert(next.Dst, gc.DeepEquals, dstID)
+		c.Assert(next.UpdatedAt, gc.DeepEquals, updatedAt)
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+	c.Assert(edgeCount, gc.Equals, 1)
+}
+
+func (s *ClientTestSuite) TestDegree(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockLinkGraphClient(ctrl)
+
+	linkID := uuid.New()
+	rpcCli.EXPECT().Degree(
+		gomock.AssignableToTypeOf(context.TODO()),
+		&proto.LinkDegreeQuery{Uuid: linkID[:]},
+	).Return(&proto.LinkDegree{InDegree: 3, OutDegree: 5}, nil).Times(2)
+
+	cli := linkgraphapi.NewLinkGraphClient(context.TODO(), rpcCli)
+	inDegree, err := cli.InDegree(linkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(inDegree, gc.Equals, 3)
+
+	outDegree, err := cli.OutDegree(linkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(outDegree, gc.Equals, 5)
+}
+
 func (s *ClientTestSuite) TestRetainVersionedEdges(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -210,3 +368,156 @@ func (s *ClientTestSuite) TestRetainVersionedEdges(c *gc.C) {
 	err := cli.RemoveStaleEdges(from, now)
 	c.Assert(err, gc.IsNil)
 }
+
+func (s *ClientTestSuite) TestRemoveLink(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockLinkGraphClient(ctrl)
+
+	linkID := uuid.New()
+	rpcCli.EXPECT().RemoveLink(
+		gomock.AssignableToTypeOf(context.TODO()),
+		&proto.RemoveLinkQuery{Uuid: linkID[:]},
+	).Return(new(empty.Empty), nil)
+
+	cli := linkgraphapi.NewLinkGraphClient(context.TODO(), rpcCli)
+	err := cli.RemoveLink(linkID)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestRemoveLinksByHost(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockLinkGraphClient(ctrl)
+
+	rpcCli.EXPECT().RemoveLinksByHost(
+		gomock.AssignableToTypeOf(context.TODO()),
+		&proto.RemoveLinksByHostQuery{Host: "spam.com"},
+	).Return(new(empty.Empty), nil)
+
+	cli := linkgraphapi.NewLinkGraphClient(context.TODO(), rpcCli)
+	err := cli.RemoveLinksByHost("spam.com")
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestExport(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockLinkGraphClient(ctrl)
+	exportStream := mocks.NewMockLinkGraph_ExportClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	rpcCli.EXPECT().Export(
+		gomock.AssignableToTypeOf(ctxWithCancel),
+		new(empty.Empty),
+	).Return(exportStream, nil)
+
+	returns := [][]interface{}{
+		{&proto.SnapshotChunk{Data: []byte("hello ")}, nil},
+		{&proto.SnapshotChunk{Data: []byte("world")}, nil},
+		{nil, io.EOF},
+	}
+	exportStream.EXPECT().Recv().DoAndReturn(
+		func() (interface{}, interface{}) {
+			next := returns[0]
+			returns = returns[1:]
+			return next[0], next[1]
+		},
+	).Times(len(returns))
+
+	var buf bytes.Buffer
+	cli := linkgraphapi.NewLinkGraphClient(context.TODO(), rpcCli)
+	c.Assert(cli.Export(&buf), gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, "hello world")
+}
+
+func (s *ClientTestSuite) TestImport(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockLinkGraphClient(ctrl)
+	importStream := mocks.NewMockLinkGraph_ImportClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	rpcCli.EXPECT().Import(
+		gomock.AssignableToTypeOf(ctxWithCancel),
+	).Return(importStream, nil)
+
+	var sent bytes.Buffer
+	importStream.EXPECT().Send(gomock.Any()).DoAndReturn(
+		func(chunk *proto.SnapshotChunk) error {
+			_, _ = sent.Write(chunk.Data)
+			return nil
+		},
+	).AnyTimes()
+	importStream.EXPECT().CloseAndRecv().Return(&proto.ImportSummary{Links: 2, Edges: 1}, nil)
+
+	cli := linkgraphapi.NewLinkGraphClient(context.TODO(), rpcCli)
+	stats, err := cli.Import(strings.NewReader("hello world"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(stats, gc.DeepEquals, snapshot.Stats{Links: 2, Edges: 1})
+	c.Assert(sent.String(), gc.Equals, "hello world")
+}
+
+func (s *ClientTestSuite) TestWatch(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockLinkGraphClient(ctrl)
+	watchStream := mocks.NewMockLinkGraph_WatchClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	since := time.Now().Add(-time.Minute).UTC()
+	rpcCli.EXPECT().Watch(
+		gomock.AssignableToTypeOf(ctxWithCancel),
+		&proto.WatchRequest{Since: mustEncodeTimestamp(c, since)},
+	).Return(watchStream, nil)
+
+	linkID := uuid.New()
+	edgeID := uuid.New()
+	now := time.Now().UTC()
+	returns := [][]interface{}{
+		{&proto.ChangeEvent{
+			Type:      proto.ChangeEvent_LINK_UPSERTED,
+			Timestamp: mustEncodeTimestamp(c, now),
+			Link:      &proto.Link{Uuid: linkID[:], Url: "http://example.com", RetrievedAt: mustEncodeTimestamp(c, now)},
+		}, nil},
+		{&proto.ChangeEvent{
+			Type:      proto.ChangeEvent_EDGE_UPSERTED,
+			Timestamp: mustEncodeTimestamp(c, now),
+			Edge:      &proto.Edge{Uuid: edgeID[:], SrcUuid: linkID[:], DstUuid: linkID[:], UpdatedAt: mustEncodeTimestamp(c, now)},
+		}, nil},
+		{nil, io.EOF},
+	}
+	watchStream.EXPECT().Recv().DoAndReturn(
+		func() (interface{}, interface{}) {
+			next := returns[0]
+			returns = returns[1:]
+			return next[0], next[1]
+		},
+	).Times(len(returns))
+
+	cli := linkgraphapi.NewLinkGraphClient(context.TODO(), rpcCli)
+	it, err := cli.Watch(since)
+	c.Assert(err, gc.IsNil)
+
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Event(), gc.DeepEquals, &graph.ChangeEvent{
+		Type:      graph.LinkUpserted,
+		Timestamp: now,
+		Link:      &graph.Link{ID: linkID, URL: "http://example.com", RetrievedAt: now},
+	})
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Event(), gc.DeepEquals, &graph.ChangeEvent{
+		Type:      graph.EdgeUpserted,
+		Timestamp: now,
+		Edge:      &graph.Edge{ID: edgeID, Src: linkID, Dst: linkID, UpdatedAt: now},
+	})
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
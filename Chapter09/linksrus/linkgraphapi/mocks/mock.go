@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto (interfaces: LinkGraphClient,LinkGraph_LinksClient,LinkGraph_EdgesClient)
+// Source: github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto (interfaces: LinkGraphClient,LinkGraph_LinksClient,LinkGraph_EdgesClient,LinkGraph_InboundEdgesClient,LinkGraph_ExportClient,LinkGraph_ImportClient,LinkGraph_UpsertLinksClient,LinkGraph_UpsertEdgesClient,LinkGraph_WatchClient)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -37,6 +37,26 @@ func (m *MockLinkGraphClient) EXPECT() *MockLinkGraphClientMockRecorder {
 	return m.recorder
 }
 
+// Degree mocks base method
+func (m *MockLinkGraphClient) Degree(arg0 context.Context, arg1 *proto.LinkDegreeQuery, arg2 ...grpc.CallOption) (*proto.LinkDegree, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Degree", varargs...)
+	ret0, _ := ret[0].(*proto.LinkDegree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Degree indicates an expected call of Degree
+func (mr *MockLinkGraphClientMockRecorder) Degree(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Degree", reflect.TypeOf((*MockLinkGraphClient)(nil).Degree), varargs...)
+}
+
 // Edges mocks base method
 func (m *MockLinkGraphClient) Edges(arg0 context.Context, arg1 *proto.Range, arg2 ...grpc.CallOption) (proto.LinkGraph_EdgesClient, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +77,66 @@ func (mr *MockLinkGraphClientMockRecorder) Edges(arg0, arg1 interface{}, arg2 ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edges", reflect.TypeOf((*MockLinkGraphClient)(nil).Edges), varargs...)
 }
 
+// Export mocks base method
+func (m *MockLinkGraphClient) Export(arg0 context.Context, arg1 *empty.Empty, arg2 ...grpc.CallOption) (proto.LinkGraph_ExportClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Export", varargs...)
+	ret0, _ := ret[0].(proto.LinkGraph_ExportClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export
+func (mr *MockLinkGraphClientMockRecorder) Export(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockLinkGraphClient)(nil).Export), varargs...)
+}
+
+// Import mocks base method
+func (m *MockLinkGraphClient) Import(arg0 context.Context, arg1 ...grpc.CallOption) (proto.LinkGraph_ImportClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Import", varargs...)
+	ret0, _ := ret[0].(proto.LinkGraph_ImportClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import
+func (mr *MockLinkGraphClientMockRecorder) Import(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockLinkGraphClient)(nil).Import), varargs...)
+}
+
+// InboundEdges mocks base method
+func (m *MockLinkGraphClient) InboundEdges(arg0 context.Context, arg1 *proto.InboundEdgesQuery, arg2 ...grpc.CallOption) (proto.LinkGraph_InboundEdgesClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InboundEdges", varargs...)
+	ret0, _ := ret[0].(proto.LinkGraph_InboundEdgesClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InboundEdges indicates an expected call of InboundEdges
+func (mr *MockLinkGraphClientMockRecorder) InboundEdges(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InboundEdges", reflect.TypeOf((*MockLinkGraphClient)(nil).InboundEdges), varargs...)
+}
+
 // Links mocks base method
 func (m *MockLinkGraphClient) Links(arg0 context.Context, arg1 *proto.Range, arg2 ...grpc.CallOption) (proto.LinkGraph_LinksClient, error) {
 	m.ctrl.T.Helper()
@@ -77,6 +157,46 @@ func (mr *MockLinkGraphClientMockRecorder) Links(arg0, arg1 interface{}, arg2 ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Links", reflect.TypeOf((*MockLinkGraphClient)(nil).Links), varargs...)
 }
 
+// RemoveLink mocks base method
+func (m *MockLinkGraphClient) RemoveLink(arg0 context.Context, arg1 *proto.RemoveLinkQuery, arg2 ...grpc.CallOption) (*empty.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveLink", varargs...)
+	ret0, _ := ret[0].(*empty.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveLink indicates an expected call of RemoveLink
+func (mr *MockLinkGraphClientMockRecorder) RemoveLink(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLink", reflect.TypeOf((*MockLinkGraphClient)(nil).RemoveLink), varargs...)
+}
+
+// RemoveLinksByHost mocks base method
+func (m *MockLinkGraphClient) RemoveLinksByHost(arg0 context.Context, arg1 *proto.RemoveLinksByHostQuery, arg2 ...grpc.CallOption) (*empty.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveLinksByHost", varargs...)
+	ret0, _ := ret[0].(*empty.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveLinksByHost indicates an expected call of RemoveLinksByHost
+func (mr *MockLinkGraphClientMockRecorder) RemoveLinksByHost(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLinksByHost", reflect.TypeOf((*MockLinkGraphClient)(nil).RemoveLinksByHost), varargs...)
+}
+
 // RemoveStaleEdges mocks base method
 func (m *MockLinkGraphClient) RemoveStaleEdges(arg0 context.Context, arg1 *proto.RemoveStaleEdgesQuery, arg2 ...grpc.CallOption) (*empty.Empty, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +237,26 @@ func (mr *MockLinkGraphClientMockRecorder) UpsertEdge(arg0, arg1 interface{}, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertEdge", reflect.TypeOf((*MockLinkGraphClient)(nil).UpsertEdge), varargs...)
 }
 
+// UpsertEdges mocks base method
+func (m *MockLinkGraphClient) UpsertEdges(arg0 context.Context, arg1 ...grpc.CallOption) (proto.LinkGraph_UpsertEdgesClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpsertEdges", varargs...)
+	ret0, _ := ret[0].(proto.LinkGraph_UpsertEdgesClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertEdges indicates an expected call of UpsertEdges
+func (mr *MockLinkGraphClientMockRecorder) UpsertEdges(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertEdges", reflect.TypeOf((*MockLinkGraphClient)(nil).UpsertEdges), varargs...)
+}
+
 // UpsertLink mocks base method
 func (m *MockLinkGraphClient) UpsertLink(arg0 context.Context, arg1 *proto.Link, arg2 ...grpc.CallOption) (*proto.Link, error) {
 	m.ctrl.T.Helper()
@@ -137,6 +277,46 @@ func (mr *MockLinkGraphClientMockRecorder) UpsertLink(arg0, arg1 interface{}, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLink", reflect.TypeOf((*MockLinkGraphClient)(nil).UpsertLink), varargs...)
 }
 
+// UpsertLinks mocks base method
+func (m *MockLinkGraphClient) UpsertLinks(arg0 context.Context, arg1 ...grpc.CallOption) (proto.LinkGraph_UpsertLinksClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpsertLinks", varargs...)
+	ret0, _ := ret[0].(proto.LinkGraph_UpsertLinksClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertLinks indicates an expected call of UpsertLinks
+func (mr *MockLinkGraphClientMockRecorder) UpsertLinks(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLinks", reflect.TypeOf((*MockLinkGraphClient)(nil).UpsertLinks), varargs...)
+}
+
+// Watch mocks base method
+func (m *MockLinkGraphClient) Watch(arg0 context.Context, arg1 *proto.WatchRequest, arg2 ...grpc.CallOption) (proto.LinkGraph_WatchClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Watch", varargs...)
+	ret0, _ := ret[0].(proto.LinkGraph_WatchClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch
+func (mr *MockLinkGraphClientMockRecorder) Watch(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockLinkGraphClient)(nil).Watch), varargs...)
+}
+
 // MockLinkGraph_LinksClient is a mock of LinkGraph_LinksClient interface
 type MockLinkGraph_LinksClient struct {
 	ctrl     *gomock.Controller
@@ -382,3 +562,783 @@ func (mr *MockLinkGraph_EdgesClientMockRecorder) Trailer() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockLinkGraph_EdgesClient)(nil).Trailer))
 }
+
+// MockLinkGraph_InboundEdgesClient is a mock of LinkGraph_InboundEdgesClient interface
+type MockLinkGraph_InboundEdgesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkGraph_InboundEdgesClientMockRecorder
+}
+
+// MockLinkGraph_InboundEdgesClientMockRecorder is the mock recorder for MockLinkGraph_InboundEdgesClient
+type MockLinkGraph_InboundEdgesClientMockRecorder struct {
+	mock *MockLinkGraph_InboundEdgesClient
+}
+
+// NewMockLinkGraph_InboundEdgesClient creates a new mock instance
+func NewMockLinkGraph_InboundEdgesClient(ctrl *gomock.Controller) *MockLinkGraph_InboundEdgesClient {
+	mock := &MockLinkGraph_InboundEdgesClient{ctrl: ctrl}
+	mock.recorder = &MockLinkGraph_InboundEdgesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLinkGraph_InboundEdgesClient) EXPECT() *MockLinkGraph_InboundEdgesClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method
+func (m *MockLinkGraph_InboundEdgesClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockLinkGraph_InboundEdgesClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockLinkGraph_InboundEdgesClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockLinkGraph_InboundEdgesClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockLinkGraph_InboundEdgesClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockLinkGraph_InboundEdgesClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockLinkGraph_InboundEdgesClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockLinkGraph_InboundEdgesClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockLinkGraph_InboundEdgesClient)(nil).Header))
+}
+
+// Recv mocks base method
+func (m *MockLinkGraph_InboundEdgesClient) Recv() (*proto.Edge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.Edge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockLinkGraph_InboundEdgesClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockLinkGraph_InboundEdgesClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method
+func (m *MockLinkGraph_InboundEdgesClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockLinkGraph_InboundEdgesClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockLinkGraph_InboundEdgesClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method
+func (m *MockLinkGraph_InboundEdgesClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockLinkGraph_InboundEdgesClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockLinkGraph_InboundEdgesClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method
+func (m *MockLinkGraph_InboundEdgesClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockLinkGraph_InboundEdgesClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockLinkGraph_InboundEdgesClient)(nil).Trailer))
+}
+
+// MockLinkGraph_ExportClient is a mock of LinkGraph_ExportClient interface
+type MockLinkGraph_ExportClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkGraph_ExportClientMockRecorder
+}
+
+// MockLinkGraph_ExportClientMockRecorder is the mock recorder for MockLinkGraph_ExportClient
+type MockLinkGraph_ExportClientMockRecorder struct {
+	mock *MockLinkGraph_ExportClient
+}
+
+// NewMockLinkGraph_ExportClient creates a new mock instance
+func NewMockLinkGraph_ExportClient(ctrl *gomock.Controller) *MockLinkGraph_ExportClient {
+	mock := &MockLinkGraph_ExportClient{ctrl: ctrl}
+	mock.recorder = &MockLinkGraph_ExportClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLinkGraph_ExportClient) EXPECT() *MockLinkGraph_ExportClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method
+func (m *MockLinkGraph_ExportClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockLinkGraph_ExportClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockLinkGraph_ExportClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockLinkGraph_ExportClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockLinkGraph_ExportClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockLinkGraph_ExportClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockLinkGraph_ExportClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockLinkGraph_ExportClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockLinkGraph_ExportClient)(nil).Header))
+}
+
+// Recv mocks base method
+func (m *MockLinkGraph_ExportClient) Recv() (*proto.SnapshotChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.SnapshotChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockLinkGraph_ExportClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockLinkGraph_ExportClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method
+func (m *MockLinkGraph_ExportClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockLinkGraph_ExportClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockLinkGraph_ExportClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method
+func (m *MockLinkGraph_ExportClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockLinkGraph_ExportClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockLinkGraph_ExportClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method
+func (m *MockLinkGraph_ExportClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockLinkGraph_ExportClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockLinkGraph_ExportClient)(nil).Trailer))
+}
+
+// MockLinkGraph_ImportClient is a mock of LinkGraph_ImportClient interface
+type MockLinkGraph_ImportClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkGraph_ImportClientMockRecorder
+}
+
+// MockLinkGraph_ImportClientMockRecorder is the mock recorder for MockLinkGraph_ImportClient
+type MockLinkGraph_ImportClientMockRecorder struct {
+	mock *MockLinkGraph_ImportClient
+}
+
+// NewMockLinkGraph_ImportClient creates a new mock instance
+func NewMockLinkGraph_ImportClient(ctrl *gomock.Controller) *MockLinkGraph_ImportClient {
+	mock := &MockLinkGraph_ImportClient{ctrl: ctrl}
+	mock.recorder = &MockLinkGraph_ImportClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLinkGraph_ImportClient) EXPECT() *MockLinkGraph_ImportClientMockRecorder {
+	return m.recorder
+}
+
+// CloseAndRecv mocks base method
+func (m *MockLinkGraph_ImportClient) CloseAndRecv() (*proto.ImportSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*proto.ImportSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv
+func (mr *MockLinkGraph_ImportClientMockRecorder) CloseAndRecv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockLinkGraph_ImportClient)(nil).CloseAndRecv))
+}
+
+// CloseSend mocks base method
+func (m *MockLinkGraph_ImportClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockLinkGraph_ImportClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockLinkGraph_ImportClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockLinkGraph_ImportClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockLinkGraph_ImportClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockLinkGraph_ImportClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockLinkGraph_ImportClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockLinkGraph_ImportClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockLinkGraph_ImportClient)(nil).Header))
+}
+
+// RecvMsg mocks base method
+func (m *MockLinkGraph_ImportClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockLinkGraph_ImportClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockLinkGraph_ImportClient)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method
+func (m *MockLinkGraph_ImportClient) Send(arg0 *proto.SnapshotChunk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockLinkGraph_ImportClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockLinkGraph_ImportClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method
+func (m *MockLinkGraph_ImportClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockLinkGraph_ImportClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockLinkGraph_ImportClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method
+func (m *MockLinkGraph_ImportClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockLinkGraph_ImportClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockLinkGraph_ImportClient)(nil).Trailer))
+}
+
+// MockLinkGraph_UpsertLinksClient is a mock of LinkGraph_UpsertLinksClient interface
+type MockLinkGraph_UpsertLinksClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkGraph_UpsertLinksClientMockRecorder
+}
+
+// MockLinkGraph_UpsertLinksClientMockRecorder is the mock recorder for MockLinkGraph_UpsertLinksClient
+type MockLinkGraph_UpsertLinksClientMockRecorder struct {
+	mock *MockLinkGraph_UpsertLinksClient
+}
+
+// NewMockLinkGraph_UpsertLinksClient creates a new mock instance
+func NewMockLinkGraph_UpsertLinksClient(ctrl *gomock.Controller) *MockLinkGraph_UpsertLinksClient {
+	mock := &MockLinkGraph_UpsertLinksClient{ctrl: ctrl}
+	mock.recorder = &MockLinkGraph_UpsertLinksClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLinkGraph_UpsertLinksClient) EXPECT() *MockLinkGraph_UpsertLinksClientMockRecorder {
+	return m.recorder
+}
+
+// CloseAndRecv mocks base method
+func (m *MockLinkGraph_UpsertLinksClient) CloseAndRecv() (*proto.LinkBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*proto.LinkBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv
+func (mr *MockLinkGraph_UpsertLinksClientMockRecorder) CloseAndRecv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockLinkGraph_UpsertLinksClient)(nil).CloseAndRecv))
+}
+
+// CloseSend mocks base method
+func (m *MockLinkGraph_UpsertLinksClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockLinkGraph_UpsertLinksClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockLinkGraph_UpsertLinksClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockLinkGraph_UpsertLinksClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockLinkGraph_UpsertLinksClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockLinkGraph_UpsertLinksClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockLinkGraph_UpsertLinksClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockLinkGraph_UpsertLinksClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockLinkGraph_UpsertLinksClient)(nil).Header))
+}
+
+// RecvMsg mocks base method
+func (m *MockLinkGraph_UpsertLinksClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockLinkGraph_UpsertLinksClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockLinkGraph_UpsertLinksClient)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method
+func (m *MockLinkGraph_UpsertLinksClient) Send(arg0 *proto.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockLinkGraph_UpsertLinksClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockLinkGraph_UpsertLinksClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method
+func (m *MockLinkGraph_UpsertLinksClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockLinkGraph_UpsertLinksClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockLinkGraph_UpsertLinksClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method
+func (m *MockLinkGraph_UpsertLinksClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockLinkGraph_UpsertLinksClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockLinkGraph_UpsertLinksClient)(nil).Trailer))
+}
+
+// MockLinkGraph_UpsertEdgesClient is a mock of LinkGraph_UpsertEdgesClient interface
+type MockLinkGraph_UpsertEdgesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkGraph_UpsertEdgesClientMockRecorder
+}
+
+// MockLinkGraph_UpsertEdgesClientMockRecorder is the mock recorder for MockLinkGraph_UpsertEdgesClient
+type MockLinkGraph_UpsertEdgesClientMockRecorder struct {
+	mock *MockLinkGraph_UpsertEdgesClient
+}
+
+// NewMockLinkGraph_UpsertEdgesClient creates a new mock instance
+func NewMockLinkGraph_UpsertEdgesClient(ctrl *gomock.Controller) *MockLinkGraph_UpsertEdgesClient {
+	mock := &MockLinkGraph_UpsertEdgesClient{ctrl: ctrl}
+	mock.recorder = &MockLinkGraph_UpsertEdgesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLinkGraph_UpsertEdgesClient) EXPECT() *MockLinkGraph_UpsertEdgesClientMockRecorder {
+	return m.recorder
+}
+
+// CloseAndRecv mocks base method
+func (m *MockLinkGraph_UpsertEdgesClient) CloseAndRecv() (*proto.EdgeBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*proto.EdgeBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv
+func (mr *MockLinkGraph_UpsertEdgesClientMockRecorder) CloseAndRecv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockLinkGraph_UpsertEdgesClient)(nil).CloseAndRecv))
+}
+
+// CloseSend mocks base method
+func (m *MockLinkGraph_UpsertEdgesClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockLinkGraph_UpsertEdgesClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockLinkGraph_UpsertEdgesClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockLinkGraph_UpsertEdgesClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockLinkGraph_UpsertEdgesClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockLinkGraph_UpsertEdgesClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockLinkGraph_UpsertEdgesClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockLinkGraph_UpsertEdgesClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockLinkGraph_UpsertEdgesClient)(nil).Header))
+}
+
+// RecvMsg mocks base method
+func (m *MockLinkGraph_UpsertEdgesClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockLinkGraph_UpsertEdgesClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockLinkGraph_UpsertEdgesClient)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method
+func (m *MockLinkGraph_UpsertEdgesClient) Send(arg0 *proto.Edge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockLinkGraph_UpsertEdgesClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockLinkGraph_UpsertEdgesClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method
+func (m *MockLinkGraph_UpsertEdgesClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockLinkGraph_UpsertEdgesClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockLinkGraph_UpsertEdgesClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method
+func (m *MockLinkGraph_UpsertEdgesClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockLinkGraph_UpsertEdgesClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockLinkGraph_UpsertEdgesClient)(nil).Trailer))
+}
+
+// MockLinkGraph_WatchClient is a mock of LinkGraph_WatchClient interface
+type MockLinkGraph_WatchClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkGraph_WatchClientMockRecorder
+}
+
+// MockLinkGraph_WatchClientMockRecorder is the mock recorder for MockLinkGraph_WatchClient
+type MockLinkGraph_WatchClientMockRecorder struct {
+	mock *MockLinkGraph_WatchClient
+}
+
+// NewMockLinkGraph_WatchClient creates a new mock instance
+func NewMockLinkGraph_WatchClient(ctrl *gomock.Controller) *MockLinkGraph_WatchClient {
+	mock := &MockLinkGraph_WatchClient{ctrl: ctrl}
+	mock.recorder = &MockLinkGraph_WatchClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLinkGraph_WatchClient) EXPECT() *MockLinkGraph_WatchClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method
+func (m *MockLinkGraph_WatchClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockLinkGraph_WatchClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockLinkGraph_WatchClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockLinkGraph_WatchClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockLinkGraph_WatchClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockLinkGraph_WatchClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockLinkGraph_WatchClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockLinkGraph_WatchClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockLinkGraph_WatchClient)(nil).Header))
+}
+
+// Recv mocks base method
+func (m *MockLinkGraph_WatchClient) Recv() (*proto.ChangeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.ChangeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockLinkGraph_WatchClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockLinkGraph_WatchClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method
+func (m *MockLinkGraph_WatchClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockLinkGraph_WatchClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockLinkGraph_WatchClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method
+func (m *MockLinkGraph_WatchClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockLinkGraph_WatchClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockLinkGraph_WatchClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method
+func (m *MockLinkGraph_WatchClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockLinkGraph_WatchClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockLinkGraph_WatchClient)(nil).Trailer))
+}
@@ -26,11 +26,49 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type ChangeEvent_Type int32
+
+const (
+	ChangeEvent_UNKNOWN       ChangeEvent_Type = 0
+	ChangeEvent_LINK_UPSERTED ChangeEvent_Type = 1
+	ChangeEvent_LINK_REMOVED  ChangeEvent_Type = 2
+	ChangeEvent_EDGE_UPSERTED ChangeEvent_Type = 3
+	ChangeEvent_EDGE_REMOVED  ChangeEvent_Type = 4
+)
+
+var ChangeEvent_Type_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "LINK_UPSERTED",
+	2: "LINK_REMOVED",
+	3: "EDGE_UPSERTED",
+	4: "EDGE_REMOVED",
+}
+
+var ChangeEvent_Type_value = map[string]int32{
+	"UNKNOWN":       0,
+	"LINK_UPSERTED": 1,
+	"LINK_REMOVED":  2,
+	"EDGE_UPSERTED": 3,
+	"EDGE_REMOVED":  4,
+}
+
+func (x ChangeEvent_Type) String() string {
+	return proto.EnumName(ChangeEvent_Type_name, int32(x))
+}
+
+func (ChangeEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13, 0}
+}
+
 // Link describes a link in the linkgraph.
 type Link struct {
 	Uuid                 []byte               `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	Url                  string               `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
 	RetrievedAt          *timestamp.Timestamp `protobuf:"bytes,3,opt,name=retrieved_at,json=retrievedAt,proto3" json:"retrieved_at,omitempty"`
+	HttpStatus           int32                `protobuf:"varint,4,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
+	ContentType          string               `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	ContentHash          string               `protobuf:"bytes,6,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"`
+	FailureCount         uint32               `protobuf:"varint,7,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -82,6 +120,34 @@ func (m *Link) GetRetrievedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *Link) GetHttpStatus() int32 {
+	if m != nil {
+		return m.HttpStatus
+	}
+	return 0
+}
+
+func (m *Link) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *Link) GetContentHash() string {
+	if m != nil {
+		return m.ContentHash
+	}
+	return ""
+}
+
+func (m *Link) GetFailureCount() uint32 {
+	if m != nil {
+		return m.FailureCount
+	}
+	return 0
+}
+
 // Edge describes an edge in the linkgraph.
 type Edge struct {
 	Uuid                 []byte               `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
@@ -195,6 +261,494 @@ func (m *RemoveStaleEdgesQuery) GetUpdatedBefore() *timestamp.Timestamp {
 	return nil
 }
 
+// RemoveLinkQuery describes a query for removing a link from the graph.
+type RemoveLinkQuery struct {
+	Uuid                 []byte   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveLinkQuery) Reset()         { *m = RemoveLinkQuery{} }
+func (m *RemoveLinkQuery) String() string { return proto.CompactTextString(m) }
+func (*RemoveLinkQuery) ProtoMessage()    {}
+func (*RemoveLinkQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+}
+
+func (m *RemoveLinkQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveLinkQuery.Unmarshal(m, b)
+}
+func (m *RemoveLinkQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveLinkQuery.Marshal(b, m, deterministic)
+}
+func (m *RemoveLinkQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveLinkQuery.Merge(m, src)
+}
+func (m *RemoveLinkQuery) XXX_Size() int {
+	return xxx_messageInfo_RemoveLinkQuery.Size(m)
+}
+func (m *RemoveLinkQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveLinkQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveLinkQuery proto.InternalMessageInfo
+
+func (m *RemoveLinkQuery) GetUuid() []byte {
+	if m != nil {
+		return m.Uuid
+	}
+	return nil
+}
+
+// RemoveLinksByHostQuery describes a query for removing all links that point
+// to a particular host from the graph.
+type RemoveLinksByHostQuery struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveLinksByHostQuery) Reset()         { *m = RemoveLinksByHostQuery{} }
+func (m *RemoveLinksByHostQuery) String() string { return proto.CompactTextString(m) }
+func (*RemoveLinksByHostQuery) ProtoMessage()    {}
+func (*RemoveLinksByHostQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+}
+
+func (m *RemoveLinksByHostQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveLinksByHostQuery.Unmarshal(m, b)
+}
+func (m *RemoveLinksByHostQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveLinksByHostQuery.Marshal(b, m, deterministic)
+}
+func (m *RemoveLinksByHostQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveLinksByHostQuery.Merge(m, src)
+}
+func (m *RemoveLinksByHostQuery) XXX_Size() int {
+	return xxx_messageInfo_RemoveLinksByHostQuery.Size(m)
+}
+func (m *RemoveLinksByHostQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveLinksByHostQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveLinksByHostQuery proto.InternalMessageInfo
+
+func (m *RemoveLinksByHostQuery) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+// LinkBatch describes a batch of upserted links.
+type LinkBatch struct {
+	Links                []*Link  `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinkBatch) Reset()         { *m = LinkBatch{} }
+func (m *LinkBatch) String() string { return proto.CompactTextString(m) }
+func (*LinkBatch) ProtoMessage()    {}
+func (*LinkBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+}
+
+func (m *LinkBatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LinkBatch.Unmarshal(m, b)
+}
+func (m *LinkBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LinkBatch.Marshal(b, m, deterministic)
+}
+func (m *LinkBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinkBatch.Merge(m, src)
+}
+func (m *LinkBatch) XXX_Size() int {
+	return xxx_messageInfo_LinkBatch.Size(m)
+}
+func (m *LinkBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinkBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinkBatch proto.InternalMessageInfo
+
+func (m *LinkBatch) GetLinks() []*Link {
+	if m != nil {
+		return m.Links
+	}
+	return nil
+}
+
+// EdgeBatch describes a batch of upserted edges.
+type EdgeBatch struct {
+	Edges                []*Edge  `protobuf:"bytes,1,rep,name=edges,proto3" json:"edges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EdgeBatch) Reset()         { *m = EdgeBatch{} }
+func (m *EdgeBatch) String() string { return proto.CompactTextString(m) }
+func (*EdgeBatch) ProtoMessage()    {}
+func (*EdgeBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+}
+
+func (m *EdgeBatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EdgeBatch.Unmarshal(m, b)
+}
+func (m *EdgeBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EdgeBatch.Marshal(b, m, deterministic)
+}
+func (m *EdgeBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EdgeBatch.Merge(m, src)
+}
+func (m *EdgeBatch) XXX_Size() int {
+	return xxx_messageInfo_EdgeBatch.Size(m)
+}
+func (m *EdgeBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_EdgeBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EdgeBatch proto.InternalMessageInfo
+
+func (m *EdgeBatch) GetEdges() []*Edge {
+	if m != nil {
+		return m.Edges
+	}
+	return nil
+}
+
+// InboundEdgesQuery describes a query for streaming the edges that terminate
+// at a particular link.
+type InboundEdgesQuery struct {
+	DstUuid              []byte   `protobuf:"bytes,1,opt,name=dst_uuid,json=dstUuid,proto3" json:"dst_uuid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InboundEdgesQuery) Reset()         { *m = InboundEdgesQuery{} }
+func (m *InboundEdgesQuery) String() string { return proto.CompactTextString(m) }
+func (*InboundEdgesQuery) ProtoMessage()    {}
+func (*InboundEdgesQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+}
+
+func (m *InboundEdgesQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InboundEdgesQuery.Unmarshal(m, b)
+}
+func (m *InboundEdgesQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InboundEdgesQuery.Marshal(b, m, deterministic)
+}
+func (m *InboundEdgesQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InboundEdgesQuery.Merge(m, src)
+}
+func (m *InboundEdgesQuery) XXX_Size() int {
+	return xxx_messageInfo_InboundEdgesQuery.Size(m)
+}
+func (m *InboundEdgesQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_InboundEdgesQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InboundEdgesQuery proto.InternalMessageInfo
+
+func (m *InboundEdgesQuery) GetDstUuid() []byte {
+	if m != nil {
+		return m.DstUuid
+	}
+	return nil
+}
+
+// LinkDegreeQuery describes a query for retrieving the in/out degree of a
+// link.
+type LinkDegreeQuery struct {
+	Uuid                 []byte   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinkDegreeQuery) Reset()         { *m = LinkDegreeQuery{} }
+func (m *LinkDegreeQuery) String() string { return proto.CompactTextString(m) }
+func (*LinkDegreeQuery) ProtoMessage()    {}
+func (*LinkDegreeQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+}
+
+func (m *LinkDegreeQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LinkDegreeQuery.Unmarshal(m, b)
+}
+func (m *LinkDegreeQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LinkDegreeQuery.Marshal(b, m, deterministic)
+}
+func (m *LinkDegreeQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinkDegreeQuery.Merge(m, src)
+}
+func (m *LinkDegreeQuery) XXX_Size() int {
+	return xxx_messageInfo_LinkDegreeQuery.Size(m)
+}
+func (m *LinkDegreeQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinkDegreeQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinkDegreeQuery proto.InternalMessageInfo
+
+func (m *LinkDegreeQuery) GetUuid() []byte {
+	if m != nil {
+		return m.Uuid
+	}
+	return nil
+}
+
+// LinkDegree describes the number of edges that terminate at (in_degree) and
+// originate from (out_degree) a link.
+type LinkDegree struct {
+	InDegree             uint64   `protobuf:"varint,1,opt,name=in_degree,json=inDegree,proto3" json:"in_degree,omitempty"`
+	OutDegree            uint64   `protobuf:"varint,2,opt,name=out_degree,json=outDegree,proto3" json:"out_degree,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinkDegree) Reset()         { *m = LinkDegree{} }
+func (m *LinkDegree) String() string { return proto.CompactTextString(m) }
+func (*LinkDegree) ProtoMessage()    {}
+func (*LinkDegree) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+}
+
+func (m *LinkDegree) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LinkDegree.Unmarshal(m, b)
+}
+func (m *LinkDegree) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LinkDegree.Marshal(b, m, deterministic)
+}
+func (m *LinkDegree) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinkDegree.Merge(m, src)
+}
+func (m *LinkDegree) XXX_Size() int {
+	return xxx_messageInfo_LinkDegree.Size(m)
+}
+func (m *LinkDegree) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinkDegree.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinkDegree proto.InternalMessageInfo
+
+func (m *LinkDegree) GetInDegree() uint64 {
+	if m != nil {
+		return m.InDegree
+	}
+	return 0
+}
+
+func (m *LinkDegree) GetOutDegree() uint64 {
+	if m != nil {
+		return m.OutDegree
+	}
+	return 0
+}
+
+// SnapshotChunk carries a chunk of a compressed link graph snapshot.
+type SnapshotChunk struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotChunk) Reset()         { *m = SnapshotChunk{} }
+func (m *SnapshotChunk) String() string { return proto.CompactTextString(m) }
+func (*SnapshotChunk) ProtoMessage()    {}
+func (*SnapshotChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+}
+
+func (m *SnapshotChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotChunk.Unmarshal(m, b)
+}
+func (m *SnapshotChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotChunk.Marshal(b, m, deterministic)
+}
+func (m *SnapshotChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotChunk.Merge(m, src)
+}
+func (m *SnapshotChunk) XXX_Size() int {
+	return xxx_messageInfo_SnapshotChunk.Size(m)
+}
+func (m *SnapshotChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotChunk proto.InternalMessageInfo
+
+func (m *SnapshotChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// ImportSummary describes the number of links and edges that were imported
+// from a link graph snapshot.
+type ImportSummary struct {
+	Links                uint64   `protobuf:"varint,1,opt,name=links,proto3" json:"links,omitempty"`
+	Edges                uint64   `protobuf:"varint,2,opt,name=edges,proto3" json:"edges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportSummary) Reset()         { *m = ImportSummary{} }
+func (m *ImportSummary) String() string { return proto.CompactTextString(m) }
+func (*ImportSummary) ProtoMessage()    {}
+func (*ImportSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+
+func (m *ImportSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportSummary.Unmarshal(m, b)
+}
+func (m *ImportSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportSummary.Marshal(b, m, deterministic)
+}
+func (m *ImportSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportSummary.Merge(m, src)
+}
+func (m *ImportSummary) XXX_Size() int {
+	return xxx_messageInfo_ImportSummary.Size(m)
+}
+func (m *ImportSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportSummary proto.InternalMessageInfo
+
+func (m *ImportSummary) GetLinks() uint64 {
+	if m != nil {
+		return m.Links
+	}
+	return 0
+}
+
+func (m *ImportSummary) GetEdges() uint64 {
+	if m != nil {
+		return m.Edges
+	}
+	return 0
+}
+
+// WatchRequest describes a request for streaming the link graph mutations
+// that took place after a particular point in time.
+type WatchRequest struct {
+	Since                *timestamp.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+// ChangeEvent describes a link graph mutation.
+type ChangeEvent struct {
+	Type      ChangeEvent_Type     `protobuf:"varint,1,opt,name=type,proto3,enum=proto.ChangeEvent_Type" json:"type,omitempty"`
+	Timestamp *timestamp.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Populated for link events.
+	Link *Link `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
+	// Populated for edge events.
+	Edge                 *Edge    `protobuf:"bytes,4,opt,name=edge,proto3" json:"edge,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChangeEvent) Reset()         { *m = ChangeEvent{} }
+func (m *ChangeEvent) String() string { return proto.CompactTextString(m) }
+func (*ChangeEvent) ProtoMessage()    {}
+func (*ChangeEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
+}
+
+func (m *ChangeEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeEvent.Unmarshal(m, b)
+}
+func (m *ChangeEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeEvent.Marshal(b, m, deterministic)
+}
+func (m *ChangeEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeEvent.Merge(m, src)
+}
+func (m *ChangeEvent) XXX_Size() int {
+	return xxx_messageInfo_ChangeEvent.Size(m)
+}
+func (m *ChangeEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeEvent proto.InternalMessageInfo
+
+func (m *ChangeEvent) GetType() ChangeEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return ChangeEvent_UNKNOWN
+}
+
+func (m *ChangeEvent) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *ChangeEvent) GetLink() *Link {
+	if m != nil {
+		return m.Link
+	}
+	return nil
+}
+
+func (m *ChangeEvent) GetEdge() *Edge {
+	if m != nil {
+		return m.Edge
+	}
+	return nil
+}
+
 // Range specifies the [fromID, toID) range to use when streaming Links or Edges.
 type Range struct {
 	FromUuid []byte `protobuf:"bytes,1,opt,name=from_uuid,json=fromUuid,proto3" json:"from_uuid,omitempty"`
@@ -210,7 +764,7 @@ func (m *Range) Reset()         { *m = Range{} }
 func (m *Range) String() string { return proto.CompactTextString(m) }
 func (*Range) ProtoMessage()    {}
 func (*Range) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
 }
 
 func (m *Range) XXX_Unmarshal(b []byte) error {
@@ -253,41 +807,86 @@ func (m *Range) GetFilter() *timestamp.Timestamp {
 }
 
 func init() {
+	proto.RegisterEnum("proto.ChangeEvent_Type", ChangeEvent_Type_name, ChangeEvent_Type_value)
 	proto.RegisterType((*Link)(nil), "proto.Link")
 	proto.RegisterType((*Edge)(nil), "proto.Edge")
 	proto.RegisterType((*RemoveStaleEdgesQuery)(nil), "proto.RemoveStaleEdgesQuery")
+	proto.RegisterType((*RemoveLinkQuery)(nil), "proto.RemoveLinkQuery")
+	proto.RegisterType((*RemoveLinksByHostQuery)(nil), "proto.RemoveLinksByHostQuery")
+	proto.RegisterType((*LinkBatch)(nil), "proto.LinkBatch")
+	proto.RegisterType((*EdgeBatch)(nil), "proto.EdgeBatch")
+	proto.RegisterType((*InboundEdgesQuery)(nil), "proto.InboundEdgesQuery")
+	proto.RegisterType((*LinkDegreeQuery)(nil), "proto.LinkDegreeQuery")
+	proto.RegisterType((*LinkDegree)(nil), "proto.LinkDegree")
+	proto.RegisterType((*SnapshotChunk)(nil), "proto.SnapshotChunk")
+	proto.RegisterType((*ImportSummary)(nil), "proto.ImportSummary")
+	proto.RegisterType((*WatchRequest)(nil), "proto.WatchRequest")
+	proto.RegisterType((*ChangeEvent)(nil), "proto.ChangeEvent")
 	proto.RegisterType((*Range)(nil), "proto.Range")
 }
 
 func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
 
 var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 391 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x4f, 0xef, 0xd2, 0x40,
-	0x10, 0xcd, 0x42, 0xf9, 0xd3, 0x01, 0x0d, 0xd9, 0x44, 0xc5, 0x62, 0x22, 0x69, 0x8c, 0xe1, 0x54,
-	0x08, 0x9e, 0x3c, 0x78, 0xc0, 0x84, 0xe8, 0xc1, 0x8b, 0x55, 0xce, 0xa4, 0xd0, 0x69, 0x6d, 0x68,
-	0xd9, 0xba, 0x3b, 0xc5, 0xf0, 0x19, 0xfc, 0xb4, 0x7e, 0x03, 0xb3, 0xdb, 0x56, 0x2b, 0xf2, 0xe3,
-	0xf7, 0x3b, 0x75, 0xdf, 0xbc, 0x37, 0x7d, 0x6f, 0x67, 0x16, 0xec, 0x20, 0x4f, 0xbc, 0x5c, 0x0a,
-	0x12, 0xbc, 0x63, 0x3e, 0xce, 0xcb, 0x58, 0x88, 0x38, 0xc5, 0xb9, 0x41, 0xbb, 0x22, 0x9a, 0x53,
-	0x92, 0xa1, 0xa2, 0x20, 0xcb, 0x4b, 0x9d, 0x33, 0xb9, 0x14, 0x60, 0x96, 0xd3, 0xb9, 0x24, 0xdd,
-	0x03, 0x58, 0x9f, 0x92, 0xe3, 0x81, 0x73, 0xb0, 0x8a, 0x22, 0x09, 0xc7, 0x6c, 0xca, 0x66, 0x43,
-	0xdf, 0x9c, 0xf9, 0x08, 0xda, 0x85, 0x4c, 0xc7, 0xad, 0x29, 0x9b, 0xd9, 0xbe, 0x3e, 0xf2, 0x77,
-	0x30, 0x94, 0x48, 0x32, 0xc1, 0x13, 0x86, 0xdb, 0x80, 0xc6, 0xed, 0x29, 0x9b, 0x0d, 0x96, 0x8e,
-	0x57, 0x3a, 0x78, 0xb5, 0x83, 0xf7, 0xb5, 0x8e, 0xe0, 0x0f, 0xfe, 0xe8, 0x57, 0xe4, 0xfe, 0x64,
-	0x60, 0xad, 0xc3, 0x18, 0xaf, 0xba, 0x3d, 0x87, 0xbe, 0x92, 0xfb, 0xad, 0xa9, 0xb7, 0x4c, 0xbd,
-	0xa7, 0xe4, 0x7e, 0x53, 0x51, 0xa1, 0xa2, 0x92, 0x6a, 0x97, 0x54, 0xa8, 0xc8, 0x50, 0x6f, 0x01,
-	0x8a, 0x3c, 0x0c, 0xa8, 0xcc, 0x63, 0xdd, 0x9b, 0xc7, 0xae, 0xd4, 0x2b, 0x72, 0x7f, 0xc0, 0x13,
-	0x1f, 0x33, 0x71, 0xc2, 0x2f, 0x14, 0xa4, 0xa8, 0x73, 0xa9, 0xcf, 0x05, 0xca, 0x33, 0x9f, 0x80,
-	0x1d, 0x49, 0x91, 0x6d, 0x1b, 0x11, 0xfb, 0xba, 0x60, 0x0c, 0x57, 0xf0, 0xb8, 0x36, 0xdc, 0x61,
-	0x24, 0x24, 0x9a, 0xb0, 0xb7, 0x4d, 0x1f, 0x55, 0x1d, 0xef, 0x4d, 0x83, 0xfb, 0x1d, 0x3a, 0x7e,
-	0x70, 0x8c, 0xf1, 0xb6, 0xd1, 0x33, 0xe8, 0x91, 0x68, 0x8e, 0xa3, 0x4b, 0xc2, 0x10, 0x4b, 0xe8,
-	0x46, 0x49, 0x4a, 0x28, 0x1f, 0x30, 0xfe, 0x4a, 0xb9, 0xfc, 0xc5, 0xc0, 0xd6, 0x7b, 0xfe, 0x20,
-	0x83, 0xfc, 0x1b, 0x7f, 0x0d, 0xb0, 0xc9, 0x15, 0x4a, 0x32, 0xab, 0x1f, 0x94, 0x8d, 0x9e, 0x06,
-	0x4e, 0x13, 0xfc, 0xd5, 0x99, 0xa5, 0xd5, 0x94, 0x06, 0x4e, 0x13, 0xf0, 0x57, 0xd0, 0xd1, 0x7a,
-	0xc5, 0x87, 0x55, 0xd5, 0x5c, 0xef, 0x9f, 0x7f, 0x2d, 0x98, 0x56, 0x99, 0x21, 0xdf, 0xa1, 0xd2,
-	0xdc, 0x82, 0xf1, 0x8f, 0x30, 0xba, 0xdc, 0x0a, 0x7f, 0x51, 0x37, 0x5c, 0x5b, 0x97, 0xf3, 0xf4,
-	0xbf, 0xfb, 0xaf, 0xf5, 0x03, 0xdf, 0x75, 0x0d, 0x7e, 0xf3, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x40,
-	0x5e, 0xe7, 0x01, 0x33, 0x03, 0x00, 0x00,
+	// 928 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x95, 0x5d, 0x8f, 0xdb, 0x44,
+	0x14, 0x86, 0x71, 0x62, 0x67, 0x37, 0xc7, 0x49, 0xc9, 0x0e, 0x65, 0x6b, 0x5c, 0xaa, 0x4d, 0x5d,
+	0x40, 0x91, 0x40, 0x6e, 0x94, 0x4a, 0x50, 0x04, 0x48, 0xec, 0x87, 0xd5, 0x5d, 0x5a, 0xb6, 0xe0,
+	0xec, 0xd2, 0x2b, 0x14, 0x79, 0xe3, 0x49, 0x6c, 0x91, 0x78, 0x5c, 0xcf, 0x78, 0x21, 0xbf, 0x81,
+	0x6b, 0x7e, 0x27, 0x97, 0xdc, 0xa2, 0x99, 0x71, 0xe2, 0x71, 0x3e, 0xda, 0xbd, 0x8a, 0xe7, 0xbc,
+	0xcf, 0x9c, 0x33, 0x73, 0xec, 0xf3, 0x06, 0x9a, 0x41, 0x1a, 0xbb, 0x69, 0x46, 0x18, 0x41, 0x86,
+	0xf8, 0xb1, 0x8f, 0xa6, 0x84, 0x4c, 0x67, 0xf8, 0xa9, 0x58, 0xdd, 0xe4, 0x93, 0xa7, 0x2c, 0x9e,
+	0x63, 0xca, 0x82, 0x79, 0x2a, 0x39, 0xfb, 0xe1, 0x3a, 0x80, 0xe7, 0x29, 0x5b, 0x48, 0xd1, 0xf9,
+	0x4f, 0x03, 0xfd, 0x55, 0x9c, 0xfc, 0x81, 0x10, 0xe8, 0x79, 0x1e, 0x87, 0x96, 0xd6, 0xd5, 0x7a,
+	0x2d, 0x5f, 0x3c, 0xa3, 0x0e, 0xd4, 0xf3, 0x6c, 0x66, 0xd5, 0xba, 0x5a, 0xaf, 0xe9, 0xf3, 0x47,
+	0xf4, 0x03, 0xb4, 0x32, 0xcc, 0xb2, 0x18, 0xdf, 0xe2, 0x70, 0x14, 0x30, 0xab, 0xde, 0xd5, 0x7a,
+	0xe6, 0xc0, 0x76, 0x65, 0x09, 0x77, 0x59, 0xc2, 0xbd, 0x5a, 0x9e, 0xc1, 0x37, 0x57, 0xfc, 0x31,
+	0x43, 0x47, 0x60, 0x46, 0x8c, 0xa5, 0x23, 0xca, 0x02, 0x96, 0x53, 0x4b, 0xef, 0x6a, 0x3d, 0xc3,
+	0x07, 0x1e, 0x1a, 0x8a, 0x08, 0x7a, 0x0c, 0xad, 0x31, 0x49, 0x18, 0x4e, 0xd8, 0x88, 0x2d, 0x52,
+	0x6c, 0x19, 0xa2, 0xb4, 0x59, 0xc4, 0xae, 0x16, 0x29, 0x56, 0x91, 0x28, 0xa0, 0x91, 0xd5, 0xa8,
+	0x20, 0xe7, 0x01, 0x8d, 0xd0, 0x13, 0x68, 0x4f, 0x82, 0x78, 0x96, 0x67, 0x78, 0x34, 0x26, 0x79,
+	0xc2, 0xac, 0xbd, 0xae, 0xd6, 0x6b, 0xfb, 0xad, 0x22, 0x78, 0xca, 0x63, 0xce, 0xdf, 0x1a, 0xe8,
+	0x5e, 0x38, 0xc5, 0x5b, 0x6f, 0xfe, 0x09, 0xec, 0xd3, 0x6c, 0x3c, 0x12, 0xf1, 0x9a, 0x88, 0xef,
+	0xd1, 0x6c, 0x7c, 0x5d, 0x48, 0x21, 0x65, 0x52, 0xaa, 0x4b, 0x29, 0xa4, 0x4c, 0x48, 0xdf, 0x02,
+	0xe4, 0x69, 0x18, 0x30, 0xd9, 0x1b, 0xfd, 0xbd, 0xbd, 0x69, 0x16, 0xf4, 0x31, 0x73, 0xfe, 0x84,
+	0x8f, 0x7d, 0x3c, 0x27, 0xb7, 0x78, 0xc8, 0x82, 0x19, 0xe6, 0xe7, 0xa2, 0xbf, 0xe6, 0x38, 0x5b,
+	0xa0, 0x87, 0xd0, 0x9c, 0x64, 0x64, 0x3e, 0x52, 0x8e, 0xb8, 0xcf, 0x03, 0xa2, 0xe0, 0x31, 0xdc,
+	0x5b, 0x16, 0xbc, 0xc1, 0x13, 0x92, 0x61, 0xab, 0xf6, 0xde, 0xa2, 0xed, 0x62, 0xc7, 0x89, 0xd8,
+	0xe0, 0x7c, 0x0e, 0x1f, 0xca, 0xc2, 0xfc, 0x2b, 0x90, 0x25, 0xb7, 0x34, 0xc4, 0xf9, 0x0a, 0x0e,
+	0x4b, 0x8c, 0x9e, 0x2c, 0xce, 0x09, 0x65, 0x2b, 0x3a, 0x22, 0x94, 0x09, 0xba, 0xe9, 0x8b, 0x67,
+	0xc7, 0x85, 0x26, 0xe7, 0x4e, 0x02, 0x36, 0x8e, 0xd0, 0x63, 0x30, 0x66, 0x7c, 0x93, 0xa5, 0x75,
+	0xeb, 0x3d, 0x73, 0x60, 0xca, 0x43, 0xb9, 0x1c, 0xf0, 0xa5, 0xc2, 0x79, 0x7e, 0xe5, 0x15, 0x8f,
+	0xf9, 0xfd, 0xd7, 0x78, 0x0e, 0xf8, 0x52, 0x71, 0x5c, 0x38, 0xb8, 0x48, 0x6e, 0x48, 0x9e, 0x84,
+	0x4a, 0xa7, 0xd4, 0x17, 0xa3, 0x55, 0x5e, 0x0c, 0xbf, 0x24, 0x2f, 0x77, 0x86, 0xa7, 0x19, 0xc6,
+	0xbb, 0x2f, 0x79, 0x0e, 0x50, 0x62, 0xbc, 0xf3, 0x71, 0x32, 0x0a, 0xc5, 0x42, 0x60, 0xba, 0xbf,
+	0x1f, 0x27, 0x85, 0xf8, 0x08, 0x80, 0xe4, 0x6c, 0xa9, 0xd6, 0x84, 0xda, 0x24, 0x39, 0x93, 0xb2,
+	0xf3, 0x04, 0xda, 0xc3, 0x24, 0x48, 0x69, 0x44, 0xd8, 0x69, 0x94, 0xcb, 0xf1, 0x0a, 0x03, 0x16,
+	0x2c, 0xcb, 0xf1, 0x67, 0xe7, 0x3b, 0x68, 0x5f, 0xcc, 0x53, 0x92, 0xb1, 0x61, 0x3e, 0x9f, 0x07,
+	0xd9, 0x02, 0xdd, 0x2f, 0x3b, 0xc5, 0xf3, 0xc9, 0x05, 0x8f, 0xca, 0x7e, 0xc8, 0x2a, 0x45, 0x0b,
+	0x7e, 0x84, 0xd6, 0x1b, 0xde, 0x2e, 0x1f, 0xbf, 0xcd, 0x31, 0x65, 0xa8, 0x0f, 0x06, 0x8d, 0x93,
+	0xb1, 0x3c, 0xe9, 0xbb, 0xbf, 0x00, 0x09, 0x3a, 0xff, 0xd4, 0xc0, 0x3c, 0x8d, 0x82, 0x64, 0x8a,
+	0xbd, 0x5b, 0x9c, 0x30, 0xf4, 0x25, 0xe8, 0x62, 0xe6, 0x78, 0x82, 0x7b, 0x83, 0x07, 0x45, 0xdb,
+	0x15, 0xc2, 0xe5, 0xf3, 0xe7, 0x0b, 0x08, 0x3d, 0x87, 0xe6, 0xca, 0x67, 0xee, 0xf0, 0xd1, 0x95,
+	0x30, 0x3a, 0x02, 0x9d, 0xdf, 0xab, 0xb0, 0x8e, 0xca, 0xd7, 0x20, 0x04, 0x0e, 0xf0, 0x2b, 0x5a,
+	0x7a, 0x05, 0x10, 0xaf, 0x5f, 0x08, 0xce, 0xef, 0xa0, 0x0b, 0x27, 0x30, 0x61, 0xef, 0xfa, 0xf2,
+	0xe5, 0xe5, 0xeb, 0x37, 0x97, 0x9d, 0x0f, 0xd0, 0x01, 0xb4, 0x5f, 0x5d, 0x5c, 0xbe, 0x1c, 0x5d,
+	0xff, 0x32, 0xf4, 0xfc, 0x2b, 0xef, 0xac, 0xa3, 0xa1, 0x0e, 0xb4, 0x44, 0xc8, 0xf7, 0x7e, 0x7e,
+	0xfd, 0x9b, 0x77, 0xd6, 0xa9, 0x71, 0xc8, 0x3b, 0x7b, 0xe1, 0x95, 0x50, 0x9d, 0x43, 0x22, 0xb4,
+	0x84, 0x74, 0xe7, 0x2d, 0x18, 0x3e, 0xbf, 0xf3, 0xbb, 0x47, 0xef, 0x01, 0xec, 0x31, 0xa2, 0x1a,
+	0x44, 0x83, 0x11, 0x21, 0x0c, 0xa0, 0x31, 0x89, 0x67, 0x0c, 0x67, 0x77, 0x30, 0xc7, 0x82, 0x1c,
+	0xfc, 0x6b, 0xc8, 0x81, 0x79, 0x91, 0x05, 0x69, 0x84, 0xbe, 0x00, 0xb8, 0x4e, 0x29, 0xce, 0x18,
+	0x0f, 0x21, 0xb5, 0x43, 0xb6, 0xba, 0x28, 0x39, 0x61, 0x63, 0x6a, 0xa3, 0x6c, 0x75, 0x81, 0x5c,
+	0x30, 0xcb, 0x7c, 0xb4, 0x9a, 0xb0, 0xa3, 0x2c, 0xc4, 0xf8, 0xf5, 0xb4, 0x92, 0x17, 0xc3, 0x55,
+	0x4d, 0xdc, 0x51, 0x16, 0x4b, 0xfe, 0x33, 0x30, 0x64, 0xe6, 0x56, 0x21, 0x8a, 0xf6, 0x55, 0xce,
+	0xda, 0x17, 0x94, 0xcc, 0xb7, 0x9d, 0xe2, 0x5a, 0x5f, 0x43, 0xdf, 0x40, 0x4b, 0x9d, 0x6c, 0x64,
+	0x15, 0xf2, 0xc6, 0xb8, 0xaf, 0x6f, 0x7c, 0x06, 0x8d, 0x62, 0x34, 0x0f, 0x95, 0xba, 0xca, 0xc4,
+	0xdb, 0x07, 0x1b, 0x71, 0x74, 0x0e, 0x9d, 0x75, 0xd7, 0x45, 0x9f, 0x2e, 0x8f, 0xb7, 0xcd, 0x8e,
+	0xed, 0xc3, 0x8d, 0xb7, 0xe9, 0xf1, 0x7f, 0x53, 0xf4, 0x3d, 0x40, 0xe9, 0x8f, 0xab, 0x23, 0xac,
+	0x39, 0xeb, 0xce, 0xdd, 0x3f, 0xc1, 0xc1, 0x86, 0xbb, 0xa2, 0x47, 0x1b, 0x49, 0x54, 0xdf, 0xdd,
+	0x99, 0xeb, 0x39, 0x34, 0xbc, 0xbf, 0xb8, 0xab, 0xa0, 0x1d, 0x84, 0x7d, 0xbf, 0x48, 0x5c, 0x71,
+	0xa8, 0xbe, 0x86, 0xbe, 0x86, 0x86, 0xf4, 0x23, 0xb4, 0x95, 0x58, 0xed, 0xab, 0x98, 0x56, 0x4f,
+	0x43, 0x03, 0x30, 0x84, 0x15, 0xa1, 0x8f, 0x0a, 0x40, 0x35, 0x26, 0x1b, 0x6d, 0x1a, 0x49, 0x5f,
+	0xbb, 0x69, 0x88, 0xe0, 0xb3, 0xff, 0x07, 0x00, 0xae, 0x2f, 0x6b, 0x83, 0xd0, 0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -306,13 +905,39 @@ type LinkGraphClient interface {
 	UpsertLink(ctx context.Context, in *Link, opts ...grpc.CallOption) (*Link, error)
 	// UpsertEdge inserts or updates an edge.
 	UpsertEdge(ctx context.Context, in *Edge, opts ...grpc.CallOption) (*Edge, error)
+	// UpsertLinks inserts or updates a batch of links streamed by the
+	// client and returns the upserted links in the same order.
+	UpsertLinks(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertLinksClient, error)
+	// UpsertEdges inserts or updates a batch of edges streamed by the
+	// client and returns the upserted edges in the same order.
+	UpsertEdges(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertEdgesClient, error)
 	// Links streams the set of links in the specified ID range.
 	Links(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_LinksClient, error)
 	// Edges streams the set of edges in the specified ID range.
 	Edges(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_EdgesClient, error)
+	// InboundEdges streams the set of edges that terminate at the specified
+	// link ID.
+	InboundEdges(ctx context.Context, in *InboundEdgesQuery, opts ...grpc.CallOption) (LinkGraph_InboundEdgesClient, error)
+	// Degree returns the in/out degree for the specified link ID.
+	Degree(ctx context.Context, in *LinkDegreeQuery, opts ...grpc.CallOption) (*LinkDegree, error)
 	// RemoveStaleEdges removes any edge that originates from the specified
 	// link ID and was updated before the specified timestamp.
 	RemoveStaleEdges(ctx context.Context, in *RemoveStaleEdgesQuery, opts ...grpc.CallOption) (*empty.Empty, error)
+	// RemoveLink removes a link as well as any edge that originates from or
+	// terminates at it.
+	RemoveLink(ctx context.Context, in *RemoveLinkQuery, opts ...grpc.CallOption) (*empty.Empty, error)
+	// RemoveLinksByHost removes all links that point to the specified host
+	// as well as any edges that originate from or terminate at them.
+	RemoveLinksByHost(ctx context.Context, in *RemoveLinksByHostQuery, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Export streams a compressed snapshot of the link graph contents.
+	Export(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (LinkGraph_ExportClient, error)
+	// Import reads a compressed snapshot of a link graph and upserts its
+	// contents into the link graph.
+	Import(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_ImportClient, error)
+	// Watch streams the link graph mutations that took place after the
+	// specified timestamp. The stream remains open until the client cancels
+	// it.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (LinkGraph_WatchClient, error)
 }
 
 type linkGraphClient struct {
@@ -341,47 +966,115 @@ func (c *linkGraphClient) UpsertEdge(ctx context.Context, in *Edge, opts ...grpc
 	return out, nil
 }
 
-func (c *linkGraphClient) Links(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_LinksClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[0], "/proto.LinkGraph/Links", opts...)
+func (c *linkGraphClient) UpsertLinks(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertLinksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[0], "/proto.LinkGraph/UpsertLinks", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &linkGraphLinksClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
+	x := &linkGraphUpsertLinksClient{stream}
 	return x, nil
 }
 
-type LinkGraph_LinksClient interface {
-	Recv() (*Link, error)
+type LinkGraph_UpsertLinksClient interface {
+	Send(*Link) error
+	CloseAndRecv() (*LinkBatch, error)
 	grpc.ClientStream
 }
 
-type linkGraphLinksClient struct {
+type linkGraphUpsertLinksClient struct {
 	grpc.ClientStream
 }
 
-func (x *linkGraphLinksClient) Recv() (*Link, error) {
-	m := new(Link)
+func (x *linkGraphUpsertLinksClient) Send(m *Link) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertLinksClient) CloseAndRecv() (*LinkBatch, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(LinkBatch)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *linkGraphClient) Edges(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_EdgesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[1], "/proto.LinkGraph/Edges", opts...)
+func (c *linkGraphClient) UpsertEdges(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertEdgesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[1], "/proto.LinkGraph/UpsertEdges", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &linkGraphEdgesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
+	x := &linkGraphUpsertEdgesClient{stream}
+	return x, nil
+}
+
+type LinkGraph_UpsertEdgesClient interface {
+	Send(*Edge) error
+	CloseAndRecv() (*EdgeBatch, error)
+	grpc.ClientStream
+}
+
+type linkGraphUpsertEdgesClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphUpsertEdgesClient) Send(m *Edge) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertEdgesClient) CloseAndRecv() (*EdgeBatch, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(EdgeBatch)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) Links(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_LinksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[2], "/proto.LinkGraph/Links", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphLinksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LinkGraph_LinksClient interface {
+	Recv() (*Link, error)
+	grpc.ClientStream
+}
+
+type linkGraphLinksClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphLinksClient) Recv() (*Link, error) {
+	m := new(Link)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) Edges(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_EdgesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[3], "/proto.LinkGraph/Edges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphEdgesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
 	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
 	}
@@ -405,6 +1098,47 @@ func (x *linkGraphEdgesClient) Recv() (*Edge, error) {
 	return m, nil
 }
 
+func (c *linkGraphClient) InboundEdges(ctx context.Context, in *InboundEdgesQuery, opts ...grpc.CallOption) (LinkGraph_InboundEdgesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[4], "/proto.LinkGraph/InboundEdges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphInboundEdgesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LinkGraph_InboundEdgesClient interface {
+	Recv() (*Edge, error)
+	grpc.ClientStream
+}
+
+type linkGraphInboundEdgesClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphInboundEdgesClient) Recv() (*Edge, error) {
+	m := new(Edge)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) Degree(ctx context.Context, in *LinkDegreeQuery, opts ...grpc.CallOption) (*LinkDegree, error) {
+	out := new(LinkDegree)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/Degree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *linkGraphClient) RemoveStaleEdges(ctx context.Context, in *RemoveStaleEdgesQuery, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
 	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RemoveStaleEdges", in, out, opts...)
@@ -414,19 +1148,161 @@ func (c *linkGraphClient) RemoveStaleEdges(ctx context.Context, in *RemoveStaleE
 	return out, nil
 }
 
+func (c *linkGraphClient) RemoveLink(ctx context.Context, in *RemoveLinkQuery, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RemoveLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) RemoveLinksByHost(ctx context.Context, in *RemoveLinksByHostQuery, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RemoveLinksByHost", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) Export(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (LinkGraph_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[5], "/proto.LinkGraph/Export", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LinkGraph_ExportClient interface {
+	Recv() (*SnapshotChunk, error)
+	grpc.ClientStream
+}
+
+type linkGraphExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphExportClient) Recv() (*SnapshotChunk, error) {
+	m := new(SnapshotChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) Import(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[6], "/proto.LinkGraph/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphImportClient{stream}
+	return x, nil
+}
+
+type LinkGraph_ImportClient interface {
+	Send(*SnapshotChunk) error
+	CloseAndRecv() (*ImportSummary, error)
+	grpc.ClientStream
+}
+
+type linkGraphImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphImportClient) Send(m *SnapshotChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *linkGraphImportClient) CloseAndRecv() (*ImportSummary, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (LinkGraph_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LinkGraph_serviceDesc.Streams[7], "/proto.LinkGraph/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LinkGraph_WatchClient interface {
+	Recv() (*ChangeEvent, error)
+	grpc.ClientStream
+}
+
+type linkGraphWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphWatchClient) Recv() (*ChangeEvent, error) {
+	m := new(ChangeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // LinkGraphServer is the server API for LinkGraph service.
 type LinkGraphServer interface {
 	// UpsertLink inserts or updates a link.
 	UpsertLink(context.Context, *Link) (*Link, error)
 	// UpsertEdge inserts or updates an edge.
 	UpsertEdge(context.Context, *Edge) (*Edge, error)
+	// UpsertLinks inserts or updates a batch of links streamed by the
+	// client and returns the upserted links in the same order.
+	UpsertLinks(LinkGraph_UpsertLinksServer) error
+	// UpsertEdges inserts or updates a batch of edges streamed by the
+	// client and returns the upserted edges in the same order.
+	UpsertEdges(LinkGraph_UpsertEdgesServer) error
 	// Links streams the set of links in the specified ID range.
 	Links(*Range, LinkGraph_LinksServer) error
 	// Edges streams the set of edges in the specified ID range.
 	Edges(*Range, LinkGraph_EdgesServer) error
+	// InboundEdges streams the set of edges that terminate at the specified
+	// link ID.
+	InboundEdges(*InboundEdgesQuery, LinkGraph_InboundEdgesServer) error
+	// Degree returns the in/out degree for the specified link ID.
+	Degree(context.Context, *LinkDegreeQuery) (*LinkDegree, error)
 	// RemoveStaleEdges removes any edge that originates from the specified
 	// link ID and was updated before the specified timestamp.
 	RemoveStaleEdges(context.Context, *RemoveStaleEdgesQuery) (*empty.Empty, error)
+	// RemoveLink removes a link as well as any edge that originates from or
+	// terminates at it.
+	RemoveLink(context.Context, *RemoveLinkQuery) (*empty.Empty, error)
+	// RemoveLinksByHost removes all links that point to the specified host
+	// as well as any edges that originate from or terminate at them.
+	RemoveLinksByHost(context.Context, *RemoveLinksByHostQuery) (*empty.Empty, error)
+	// Export streams a compressed snapshot of the link graph contents.
+	Export(*empty.Empty, LinkGraph_ExportServer) error
+	// Import reads a compressed snapshot of a link graph and upserts its
+	// contents into the link graph.
+	Import(LinkGraph_ImportServer) error
+	// Watch streams the link graph mutations that took place after the
+	// specified timestamp. The stream remains open until the client cancels
+	// it.
+	Watch(*WatchRequest, LinkGraph_WatchServer) error
 }
 
 // UnimplementedLinkGraphServer can be embedded to have forward compatible implementations.
@@ -439,15 +1315,42 @@ func (*UnimplementedLinkGraphServer) UpsertLink(ctx context.Context, req *Link)
 func (*UnimplementedLinkGraphServer) UpsertEdge(ctx context.Context, req *Edge) (*Edge, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpsertEdge not implemented")
 }
+func (*UnimplementedLinkGraphServer) UpsertLinks(srv LinkGraph_UpsertLinksServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpsertLinks not implemented")
+}
+func (*UnimplementedLinkGraphServer) UpsertEdges(srv LinkGraph_UpsertEdgesServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpsertEdges not implemented")
+}
 func (*UnimplementedLinkGraphServer) Links(req *Range, srv LinkGraph_LinksServer) error {
 	return status.Errorf(codes.Unimplemented, "method Links not implemented")
 }
 func (*UnimplementedLinkGraphServer) Edges(req *Range, srv LinkGraph_EdgesServer) error {
 	return status.Errorf(codes.Unimplemented, "method Edges not implemented")
 }
+func (*UnimplementedLinkGraphServer) InboundEdges(req *InboundEdgesQuery, srv LinkGraph_InboundEdgesServer) error {
+	return status.Errorf(codes.Unimplemented, "method InboundEdges not implemented")
+}
+func (*UnimplementedLinkGraphServer) Degree(ctx context.Context, req *LinkDegreeQuery) (*LinkDegree, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Degree not implemented")
+}
 func (*UnimplementedLinkGraphServer) RemoveStaleEdges(ctx context.Context, req *RemoveStaleEdgesQuery) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveStaleEdges not implemented")
 }
+func (*UnimplementedLinkGraphServer) RemoveLink(ctx context.Context, req *RemoveLinkQuery) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveLink not implemented")
+}
+func (*UnimplementedLinkGraphServer) RemoveLinksByHost(ctx context.Context, req *RemoveLinksByHostQuery) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveLinksByHost not implemented")
+}
+func (*UnimplementedLinkGraphServer) Export(req *empty.Empty, srv LinkGraph_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (*UnimplementedLinkGraphServer) Import(srv LinkGraph_ImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (*UnimplementedLinkGraphServer) Watch(req *WatchRequest, srv LinkGraph_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 
 func RegisterLinkGraphServer(s *grpc.Server, srv LinkGraphServer) {
 	s.RegisterService(&_LinkGraph_serviceDesc, srv)
@@ -489,6 +1392,58 @@ func _LinkGraph_UpsertEdge_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LinkGraph_UpsertLinks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LinkGraphServer).UpsertLinks(&linkGraphUpsertLinksServer{stream})
+}
+
+type LinkGraph_UpsertLinksServer interface {
+	SendAndClose(*LinkBatch) error
+	Recv() (*Link, error)
+	grpc.ServerStream
+}
+
+type linkGraphUpsertLinksServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphUpsertLinksServer) SendAndClose(m *LinkBatch) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertLinksServer) Recv() (*Link, error) {
+	m := new(Link)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LinkGraph_UpsertEdges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LinkGraphServer).UpsertEdges(&linkGraphUpsertEdgesServer{stream})
+}
+
+type LinkGraph_UpsertEdgesServer interface {
+	SendAndClose(*EdgeBatch) error
+	Recv() (*Edge, error)
+	grpc.ServerStream
+}
+
+type linkGraphUpsertEdgesServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphUpsertEdgesServer) SendAndClose(m *EdgeBatch) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertEdgesServer) Recv() (*Edge, error) {
+	m := new(Edge)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _LinkGraph_Links_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(Range)
 	if err := stream.RecvMsg(m); err != nil {
@@ -531,6 +1486,45 @@ func (x *linkGraphEdgesServer) Send(m *Edge) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _LinkGraph_InboundEdges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InboundEdgesQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LinkGraphServer).InboundEdges(m, &linkGraphInboundEdgesServer{stream})
+}
+
+type LinkGraph_InboundEdgesServer interface {
+	Send(*Edge) error
+	grpc.ServerStream
+}
+
+type linkGraphInboundEdgesServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphInboundEdgesServer) Send(m *Edge) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LinkGraph_Degree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkDegreeQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).Degree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/Degree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).Degree(ctx, req.(*LinkDegreeQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LinkGraph_RemoveStaleEdges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RemoveStaleEdgesQuery)
 	if err := dec(in); err != nil {
@@ -549,6 +1543,110 @@ func _LinkGraph_RemoveStaleEdges_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LinkGraph_RemoveLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveLinkQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).RemoveLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/RemoveLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).RemoveLink(ctx, req.(*RemoveLinkQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_RemoveLinksByHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveLinksByHostQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).RemoveLinksByHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/RemoveLinksByHost",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).RemoveLinksByHost(ctx, req.(*RemoveLinksByHostQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(empty.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LinkGraphServer).Export(m, &linkGraphExportServer{stream})
+}
+
+type LinkGraph_ExportServer interface {
+	Send(*SnapshotChunk) error
+	grpc.ServerStream
+}
+
+type linkGraphExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphExportServer) Send(m *SnapshotChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LinkGraph_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LinkGraphServer).Import(&linkGraphImportServer{stream})
+}
+
+type LinkGraph_ImportServer interface {
+	SendAndClose(*ImportSummary) error
+	Recv() (*SnapshotChunk, error)
+	grpc.ServerStream
+}
+
+type linkGraphImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphImportServer) SendAndClose(m *ImportSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *linkGraphImportServer) Recv() (*SnapshotChunk, error) {
+	m := new(SnapshotChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LinkGraph_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LinkGraphServer).Watch(m, &linkGraphWatchServer{stream})
+}
+
+type LinkGraph_WatchServer interface {
+	Send(*ChangeEvent) error
+	grpc.ServerStream
+}
+
+type linkGraphWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphWatchServer) Send(m *ChangeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _LinkGraph_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.LinkGraph",
 	HandlerType: (*LinkGraphServer)(nil),
@@ -561,12 +1659,34 @@ var _LinkGraph_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpsertEdge",
 			Handler:    _LinkGraph_UpsertEdge_Handler,
 		},
+		{
+			MethodName: "Degree",
+			Handler:    _LinkGraph_Degree_Handler,
+		},
 		{
 			MethodName: "RemoveStaleEdges",
 			Handler:    _LinkGraph_RemoveStaleEdges_Handler,
 		},
+		{
+			MethodName: "RemoveLink",
+			Handler:    _LinkGraph_RemoveLink_Handler,
+		},
+		{
+			MethodName: "RemoveLinksByHost",
+			Handler:    _LinkGraph_RemoveLinksByHost_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UpsertLinks",
+			Handler:       _LinkGraph_UpsertLinks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UpsertEdges",
+			Handler:       _LinkGraph_UpsertEdges_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "Links",
 			Handler:       _LinkGraph_Links_Handler,
@@ -577,6 +1697,26 @@ var _LinkGraph_serviceDesc = grpc.ServiceDesc{
 			Handler:       _LinkGraph_Edges_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "InboundEdges",
+			Handler:       _LinkGraph_InboundEdges_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Export",
+			Handler:       _LinkGraph_Export_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Import",
+			Handler:       _LinkGraph_Import_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _LinkGraph_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api.proto",
 }
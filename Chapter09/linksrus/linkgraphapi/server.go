@@ -1,10 +1,13 @@
 package linkgraphapi
 
 import (
+	"bufio"
 	"context"
+	"io"
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/snapshot"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
@@ -14,6 +17,10 @@ import (
 
 var _ proto.LinkGraphServer = (*LinkGraphServer)(nil)
 
+// watchPollInterval controls how often the server polls the graph for new
+// change events while serving a Watch RPC.
+const watchPollInterval = time.Second
+
 // LinkGraphServer provides a gRPC layer for accessing a link graph.
 type LinkGraphServer struct {
 	g graph.Graph
@@ -30,8 +37,12 @@ func (s *LinkGraphServer) UpsertLink(_ context.Context, req *proto.Link) (*proto
 	var (
 		err  error
 		link = graph.Link{
-			ID:  uuidFromBytes(req.Uuid),
-			URL: req.Url,
+			ID:           uuidFromBytes(req.Uuid),
+			URL:          req.Url,
+			HTTPStatus:   int(req.HttpStatus),
+			ContentType:  req.ContentType,
+			ContentHash:  req.ContentHash,
+			FailureCount: int(req.FailureCount),
 		}
 	)
 
@@ -68,6 +79,83 @@ func (s *LinkGraphServer) UpsertEdge(_ context.Context, req *proto.Edge) (*proto
 	return req, nil
 }
 
+// UpsertLinks inserts or updates a batch of links streamed by the client.
+func (s *LinkGraphServer) UpsertLinks(r proto.LinkGraph_UpsertLinksServer) error {
+	var (
+		reqs  []*proto.Link
+		links []*graph.Link
+	)
+	for {
+		req, err := r.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		link := &graph.Link{
+			ID:           uuidFromBytes(req.Uuid),
+			URL:          req.Url,
+			HTTPStatus:   int(req.HttpStatus),
+			ContentType:  req.ContentType,
+			ContentHash:  req.ContentHash,
+			FailureCount: int(req.FailureCount),
+		}
+		if link.RetrievedAt, err = ptypes.Timestamp(req.RetrievedAt); err != nil {
+			return err
+		}
+
+		reqs = append(reqs, req)
+		links = append(links, link)
+	}
+
+	if err := s.g.UpsertLinks(links); err != nil {
+		return err
+	}
+
+	for i, link := range links {
+		reqs[i].RetrievedAt = timeToProto(link.RetrievedAt)
+		reqs[i].Url = link.URL
+		reqs[i].Uuid = link.ID[:]
+	}
+	return r.SendAndClose(&proto.LinkBatch{Links: reqs})
+}
+
+// UpsertEdges inserts or updates a batch of edges streamed by the client.
+func (s *LinkGraphServer) UpsertEdges(r proto.LinkGraph_UpsertEdgesServer) error {
+	var (
+		reqs  []*proto.Edge
+		edges []*graph.Edge
+	)
+	for {
+		req, err := r.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		reqs = append(reqs, req)
+		edges = append(edges, &graph.Edge{
+			ID:  uuidFromBytes(req.Uuid),
+			Src: uuidFromBytes(req.SrcUuid),
+			Dst: uuidFromBytes(req.DstUuid),
+		})
+	}
+
+	if err := s.g.UpsertEdges(edges); err != nil {
+		return err
+	}
+
+	for i, edge := range edges {
+		reqs[i].Uuid = edge.ID[:]
+		reqs[i].SrcUuid = edge.Src[:]
+		reqs[i].DstUuid = edge.Dst[:]
+		reqs[i].UpdatedAt = timeToProto(edge.UpdatedAt)
+	}
+	return r.SendAndClose(&proto.EdgeBatch{Edges: reqs})
+}
+
 // Links streams the set of links whose IDs belong to the specified partition
 // range and were accessed before the specified timestamp.
 func (s *LinkGraphServer) Links(idRange *proto.Range, w proto.LinkGraph_LinksServer) error {
@@ -94,9 +182,13 @@ func (s *LinkGraphServer) Links(idRange *proto.Range, w proto.LinkGraph_LinksSer
 	for it.Next() {
 		link := it.Link()
 		msg := &proto.Link{
-			Uuid:        link.ID[:],
-			Url:         link.URL,
-			RetrievedAt: timeToProto(link.RetrievedAt),
+			Uuid:         link.ID[:],
+			Url:          link.URL,
+			RetrievedAt:  timeToProto(link.RetrievedAt),
+			HttpStatus:   int32(link.HTTPStatus),
+			ContentType:  link.ContentType,
+			ContentHash:  link.ContentHash,
+			FailureCount: uint32(link.FailureCount),
 		}
 		if err := w.Send(msg); err != nil {
 			_ = it.Close()
@@ -155,6 +247,59 @@ func (s *LinkGraphServer) Edges(idRange *proto.Range, w proto.LinkGraph_EdgesSer
 	return it.Close()
 }
 
+// InboundEdges streams the set of edges that terminate at the specified link
+// ID.
+func (s *LinkGraphServer) InboundEdges(req *proto.InboundEdgesQuery, w proto.LinkGraph_InboundEdgesServer) error {
+	dstID, err := uuid.FromBytes(req.DstUuid)
+	if err != nil {
+		return err
+	}
+
+	it, err := s.g.InboundEdges(dstID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = it.Close() }()
+
+	for it.Next() {
+		edge := it.Edge()
+		msg := &proto.Edge{
+			Uuid:      edge.ID[:],
+			SrcUuid:   edge.Src[:],
+			DstUuid:   edge.Dst[:],
+			UpdatedAt: timeToProto(edge.UpdatedAt),
+		}
+		if err := w.Send(msg); err != nil {
+			_ = it.Close()
+			return err
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	return it.Close()
+}
+
+// Degree returns the in/out degree for the specified link ID.
+func (s *LinkGraphServer) Degree(_ context.Context, req *proto.LinkDegreeQuery) (*proto.LinkDegree, error) {
+	linkID := uuidFromBytes(req.Uuid)
+	inDegree, err := s.g.InDegree(linkID)
+	if err != nil {
+		return nil, err
+	}
+	outDegree, err := s.g.OutDegree(linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.LinkDegree{
+		InDegree:  uint64(inDegree),
+		OutDegree: uint64(outDegree),
+	}, nil
+}
+
 // RemoveStaleEdges removes any edge that originates from the specified
 // link ID and was updated before the specified timestamp.
 func (s *LinkGraphServer) RemoveStaleEdges(_ context.Context, req *proto.RemoveStaleEdgesQuery) (*empty.Empty, error) {
@@ -171,6 +316,126 @@ func (s *LinkGraphServer) RemoveStaleEdges(_ context.Context, req *proto.RemoveS
 	return new(empty.Empty), err
 }
 
+// RemoveLink removes a link as well as any edge that originates from or
+// terminates at it.
+func (s *LinkGraphServer) RemoveLink(_ context.Context, req *proto.RemoveLinkQuery) (*empty.Empty, error) {
+	err := s.g.RemoveLink(uuidFromBytes(req.Uuid))
+	return new(empty.Empty), err
+}
+
+// RemoveLinksByHost removes all links that point to the specified host as well
+// as any edges that originate from or terminate at them.
+func (s *LinkGraphServer) RemoveLinksByHost(_ context.Context, req *proto.RemoveLinksByHostQuery) (*empty.Empty, error) {
+	err := s.g.RemoveLinksByHost(req.Host)
+	return new(empty.Empty), err
+}
+
+// Export streams a compressed snapshot of the link graph contents.
+func (s *LinkGraphServer) Export(_ *empty.Empty, w proto.LinkGraph_ExportServer) error {
+	cw := bufio.NewWriterSize(chunkWriter(w.Send), snapshotChunkSize)
+	if _, err := snapshot.Export(cw, s.g); err != nil {
+		return err
+	}
+	return cw.Flush()
+}
+
+// Import reads a compressed snapshot of a link graph and upserts its contents
+// into the link graph.
+func (s *LinkGraphServer) Import(r proto.LinkGraph_ImportServer) error {
+	stats, err := snapshot.Import(&chunkReader{recvFn: r.Recv}, s.g)
+	if err != nil {
+		return err
+	}
+
+	return r.SendAndClose(&proto.ImportSummary{
+		Links: uint64(stats.Links),
+		Edges: uint64(stats.Edges),
+	})
+}
+
+// Watch streams the link graph mutations that took place after the specified
+// timestamp. The server polls the graph for new events until the client
+// cancels the RPC.
+func (s *LinkGraphServer) Watch(req *proto.WatchRequest, w proto.LinkGraph_WatchServer) error {
+	since, err := ptypes.Timestamp(req.Since)
+	if err != nil && req.Since != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		last, err := s.sendChangeEvents(since, w)
+		if err != nil {
+			return err
+		}
+
+		// The graph may cap the number of events returned by each
+		// Watch call so keep polling without delay until we catch up.
+		if last.After(since) {
+			since = last
+			if w.Context().Err() != nil {
+				return nil
+			}
+			continue
+		}
+
+		select {
+		case <-w.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendChangeEvents streams the change events that took place after since and
+// returns the timestamp of the last streamed event.
+func (s *LinkGraphServer) sendChangeEvents(since time.Time, w proto.LinkGraph_WatchServer) (time.Time, error) {
+	it, err := s.g.Watch(since)
+	if err != nil {
+		return since, err
+	}
+	defer func() { _ = it.Close() }()
+
+	for it.Next() {
+		evt := it.Event()
+		msg := &proto.ChangeEvent{
+			Type:      proto.ChangeEvent_Type(evt.Type),
+			Timestamp: timeToProto(evt.Timestamp),
+		}
+		if evt.Link != nil {
+			msg.Link = &proto.Link{
+				Uuid:         evt.Link.ID[:],
+				Url:          evt.Link.URL,
+				RetrievedAt:  timeToProto(evt.Link.RetrievedAt),
+				HttpStatus:   int32(evt.Link.HTTPStatus),
+				ContentType:  evt.Link.ContentType,
+				ContentHash:  evt.Link.ContentHash,
+				FailureCount: uint32(evt.Link.FailureCount),
+			}
+		}
+		if evt.Edge != nil {
+			msg.Edge = &proto.Edge{
+				Uuid:      evt.Edge.ID[:],
+				SrcUuid:   evt.Edge.Src[:],
+				DstUuid:   evt.Edge.Dst[:],
+				UpdatedAt: timeToProto(evt.Edge.UpdatedAt),
+			}
+		}
+		if err := w.Send(msg); err != nil {
+			return since, err
+		}
+		since = evt.Timestamp
+	}
+
+	if err := it.Error(); err != nil {
+		return since, err
+	}
+
+	return since, it.Close()
+}
+
 func uuidFromBytes(b []byte) uuid.UUID {
 	if len(b) != 16 {
 		return uuid.Nil
@@ -1,6 +1,7 @@
 package linkgraphapi_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -8,10 +9,13 @@ import (
 	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/snapshot"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/store/memory"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/test/bufconn"
 	gc "gopkg.in/check.v1"
@@ -135,6 +139,59 @@ func (s *ServerTestSuite) TestUpdateEdge(c *gc.C) {
 	c.Assert(mustDecodeTimestamp(c, res.UpdatedAt).After(edge.UpdatedAt), gc.Equals, true, gc.Commentf("expected UpdatedAt field to be set to a newer timestamp after updating edge"))
 }
 
+func (s *ServerTestSuite) TestUpsertLinks(c *gc.C) {
+	existing := &graph.Link{URL: "http://example.com"}
+	c.Assert(s.g.UpsertLink(existing), gc.IsNil)
+
+	stream, err := s.cli.UpsertLinks(context.TODO())
+	c.Assert(err, gc.IsNil)
+	accessedAt := mustEncodeTimestamp(c, time.Now().Truncate(time.Second).UTC())
+	for _, linkURL := range []string{"http://example.com", "http://foo.com"} {
+		c.Assert(stream.Send(&proto.Link{Url: linkURL, RetrievedAt: accessedAt}), gc.IsNil)
+	}
+	res, err := stream.CloseAndRecv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.Links, gc.HasLen, 2)
+
+	c.Assert(res.Links[0].Uuid, gc.DeepEquals, existing.ID[:], gc.Commentf("UUID changed while upserting"))
+	c.Assert(res.Links[1].Url, gc.Equals, "http://foo.com")
+	linkID, err := uuid.FromBytes(res.Links[1].Uuid)
+	c.Assert(err, gc.IsNil)
+	c.Assert(linkID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("UUID not assigned to new link"))
+
+	stored, err := s.g.FindLink(linkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored.URL, gc.Equals, "http://foo.com")
+}
+
+func (s *ServerTestSuite) TestUpsertEdges(c *gc.C) {
+	src := &graph.Link{URL: "http://example.com"}
+	dst := &graph.Link{URL: "http://foo.com"}
+	c.Assert(s.g.UpsertLink(src), gc.IsNil)
+	c.Assert(s.g.UpsertLink(dst), gc.IsNil)
+
+	stream, err := s.cli.UpsertEdges(context.TODO())
+	c.Assert(err, gc.IsNil)
+	c.Assert(stream.Send(&proto.Edge{SrcUuid: src.ID[:], DstUuid: dst.ID[:]}), gc.IsNil)
+	c.Assert(stream.Send(&proto.Edge{SrcUuid: dst.ID[:], DstUuid: src.ID[:]}), gc.IsNil)
+	res, err := stream.CloseAndRecv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.Edges, gc.HasLen, 2)
+	for _, edge := range res.Edges {
+		c.Assert(edge.Uuid, gc.Not(gc.DeepEquals), uuid.Nil[:], gc.Commentf("UUID not assigned to new edge"))
+		c.Assert(edge.UpdatedAt.Seconds, gc.Not(gc.Equals), 0)
+	}
+
+	// Upserting a batch with an edge that refers to an unknown link should
+	// fail.
+	stream, err = s.cli.UpsertEdges(context.TODO())
+	c.Assert(err, gc.IsNil)
+	unknownID := uuid.New()
+	c.Assert(stream.Send(&proto.Edge{SrcUuid: src.ID[:], DstUuid: unknownID[:]}), gc.IsNil)
+	_, err = stream.CloseAndRecv()
+	c.Assert(err, gc.NotNil)
+}
+
 func (s *ServerTestSuite) TestLinks(c *gc.C) {
 	// Add links to the graph
 	sawLinks := make(map[uuid.UUID]bool)
@@ -228,6 +285,74 @@ func (s *ServerTestSuite) TestEdges(c *gc.C) {
 	}
 }
 
+func (s *ServerTestSuite) TestInboundEdges(c *gc.C) {
+	// Add links pointing to the same destination
+	dst := &graph.Link{URL: "http://example.com"}
+	c.Assert(s.g.UpsertLink(dst), gc.IsNil)
+	sawEdges := make(map[uuid.UUID]bool)
+	for i := 0; i < 10; i++ {
+		src := &graph.Link{URL: fmt.Sprintf("http://example.com/%d", i)}
+		c.Assert(s.g.UpsertLink(src), gc.IsNil)
+
+		edge := &graph.Edge{Src: src.ID, Dst: dst.ID}
+		c.Assert(s.g.UpsertEdge(edge), gc.IsNil)
+		sawEdges[edge.ID] = false
+	}
+
+	stream, err := s.cli.InboundEdges(context.TODO(), &proto.InboundEdgesQuery{DstUuid: dst.ID[:]})
+	c.Assert(err, gc.IsNil)
+	for {
+		next, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			c.Fatal(err)
+		}
+
+		edgeID, err := uuid.FromBytes(next.Uuid)
+		c.Assert(err, gc.IsNil)
+		c.Assert(next.DstUuid, gc.DeepEquals, dst.ID[:])
+
+		alreadySeen, exists := sawEdges[edgeID]
+		if !exists {
+			c.Fatalf("saw unexpected edges with ID %q", edgeID)
+		} else if alreadySeen {
+			c.Fatalf("saw duplicate edges with ID %q", edgeID)
+		}
+		sawEdges[edgeID] = true
+	}
+
+	for edgeID, seen := range sawEdges {
+		if !seen {
+			c.Fatalf("expected to see edge with ID %q", edgeID)
+		}
+	}
+}
+
+func (s *ServerTestSuite) TestDegree(c *gc.C) {
+	src := &graph.Link{URL: "http://example.com"}
+	dst := &graph.Link{URL: "http://foo.com"}
+	c.Assert(s.g.UpsertLink(src), gc.IsNil)
+	c.Assert(s.g.UpsertLink(dst), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: src.ID, Dst: dst.ID}), gc.IsNil)
+
+	res, err := s.cli.Degree(context.TODO(), &proto.LinkDegreeQuery{Uuid: src.ID[:]})
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.InDegree, gc.Equals, uint64(0))
+	c.Assert(res.OutDegree, gc.Equals, uint64(1))
+
+	res, err = s.cli.Degree(context.TODO(), &proto.LinkDegreeQuery{Uuid: dst.ID[:]})
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.InDegree, gc.Equals, uint64(1))
+	c.Assert(res.OutDegree, gc.Equals, uint64(0))
+
+	// Querying the degree of an unknown link should fail
+	unknownID := uuid.New()
+	_, err = s.cli.Degree(context.TODO(), &proto.LinkDegreeQuery{Uuid: unknownID[:]})
+	c.Assert(err, gc.NotNil)
+}
+
 func (s *ServerTestSuite) TestRetainVersionedEdges(c *gc.C) {
 	// Add three links and and two edges to the graph with different versions
 	src := &graph.Link{URL: "http://example.com"}
@@ -265,3 +390,151 @@ func (s *ServerTestSuite) TestRetainVersionedEdges(c *gc.C) {
 	c.Assert(it.Error(), gc.IsNil)
 	c.Assert(edgeCount, gc.Equals, 1)
 }
+
+func (s *ServerTestSuite) TestRemoveLink(c *gc.C) {
+	src := &graph.Link{URL: "http://example.com"}
+	dst := &graph.Link{URL: "http://foo.com"}
+	c.Assert(s.g.UpsertLink(src), gc.IsNil)
+	c.Assert(s.g.UpsertLink(dst), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: src.ID, Dst: dst.ID}), gc.IsNil)
+
+	_, err := s.cli.RemoveLink(context.TODO(), &proto.RemoveLinkQuery{Uuid: dst.ID[:]})
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.g.FindLink(dst.ID)
+	c.Assert(xerrors.Is(err, graph.ErrNotFound), gc.Equals, true)
+
+	it, err := s.g.Edges(minUUID, maxUUID, time.Now())
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, false, gc.Commentf("expected edge to removed link to be dropped"))
+	c.Assert(it.Error(), gc.IsNil)
+
+	// Removing an unknown link should fail
+	_, err = s.cli.RemoveLink(context.TODO(), &proto.RemoveLinkQuery{Uuid: dst.ID[:]})
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *ServerTestSuite) TestRemoveLinksByHost(c *gc.C) {
+	kept := &graph.Link{URL: "http://example.com"}
+	c.Assert(s.g.UpsertLink(kept), gc.IsNil)
+	for i := 0; i < 3; i++ {
+		c.Assert(s.g.UpsertLink(&graph.Link{URL: fmt.Sprintf("http://spam.com/%d", i)}), gc.IsNil)
+	}
+
+	_, err := s.cli.RemoveLinksByHost(context.TODO(), &proto.RemoveLinksByHostQuery{Host: "spam.com"})
+	c.Assert(err, gc.IsNil)
+
+	it, err := s.g.Links(minUUID, maxUUID, time.Now())
+	c.Assert(err, gc.IsNil)
+
+	var linkCount int
+	for it.Next() {
+		linkCount++
+		c.Assert(it.Link().ID, gc.Equals, kept.ID)
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(linkCount, gc.Equals, 1)
+}
+
+func (s *ServerTestSuite) TestExport(c *gc.C) {
+	src := &graph.Link{URL: "http://example.com"}
+	dst := &graph.Link{URL: "http://foo.com"}
+	c.Assert(s.g.UpsertLink(src), gc.IsNil)
+	c.Assert(s.g.UpsertLink(dst), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(&graph.Edge{Src: src.ID, Dst: dst.ID}), gc.IsNil)
+
+	stream, err := s.cli.Export(context.TODO(), new(empty.Empty))
+	c.Assert(err, gc.IsNil)
+
+	var buf bytes.Buffer
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			c.Fatal(err)
+		}
+		_, _ = buf.Write(chunk.Data)
+	}
+
+	stats, err := snapshot.Import(&buf, memory.NewInMemoryGraph())
+	c.Assert(err, gc.IsNil)
+	c.Assert(stats, gc.DeepEquals, snapshot.Stats{Links: 2, Edges: 1})
+}
+
+func (s *ServerTestSuite) TestImport(c *gc.C) {
+	other := memory.NewInMemoryGraph()
+	src := &graph.Link{URL: "http://example.com"}
+	dst := &graph.Link{URL: "http://foo.com"}
+	c.Assert(other.UpsertLink(src), gc.IsNil)
+	c.Assert(other.UpsertLink(dst), gc.IsNil)
+	c.Assert(other.UpsertEdge(&graph.Edge{Src: src.ID, Dst: dst.ID}), gc.IsNil)
+
+	var buf bytes.Buffer
+	_, err := snapshot.Export(&buf, other)
+	c.Assert(err, gc.IsNil)
+
+	stream, err := s.cli.Import(context.TODO())
+	c.Assert(err, gc.IsNil)
+	// Send the snapshot in multiple chunks
+	for data := buf.Bytes(); len(data) > 0; {
+		n := 16
+		if n > len(data) {
+			n = len(data)
+		}
+		c.Assert(stream.Send(&proto.SnapshotChunk{Data: data[:n]}), gc.IsNil)
+		data = data[n:]
+	}
+	res, err := stream.CloseAndRecv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.Links, gc.Equals, uint64(2))
+	c.Assert(res.Edges, gc.Equals, uint64(1))
+
+	it, err := s.g.Edges(minUUID, maxUUID, time.Now())
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, true)
+	edge := it.Edge()
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+
+	imported, err := s.g.FindLink(edge.Dst)
+	c.Assert(err, gc.IsNil)
+	c.Assert(imported.URL, gc.Equals, dst.URL)
+}
+
+func (s *ServerTestSuite) TestImportInvalidSnapshot(c *gc.C) {
+	stream, err := s.cli.Import(context.TODO())
+	c.Assert(err, gc.IsNil)
+	c.Assert(stream.Send(&proto.SnapshotChunk{Data: []byte("not a snapshot")}), gc.IsNil)
+
+	_, err = stream.CloseAndRecv()
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *ServerTestSuite) TestWatch(c *gc.C) {
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	src := &graph.Link{URL: "http://example.com", RetrievedAt: time.Now()}
+	c.Assert(s.g.UpsertLink(src), gc.IsNil)
+
+	ctx, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+	stream, err := s.cli.Watch(ctx, &proto.WatchRequest{Since: mustEncodeTimestamp(c, since)})
+	c.Assert(err, gc.IsNil)
+
+	evt, err := stream.Recv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(evt.Type, gc.Equals, proto.ChangeEvent_LINK_UPSERTED)
+	c.Assert(evt.Link.Uuid, gc.DeepEquals, src.ID[:])
+
+	// Changes made while the stream is open should be picked up by the
+	// next poll.
+	dst := &graph.Link{URL: "http://foo.com", RetrievedAt: time.Now()}
+	c.Assert(s.g.UpsertLink(dst), gc.IsNil)
+
+	evt, err = stream.Recv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(evt.Type, gc.Equals, proto.ChangeEvent_LINK_UPSERTED)
+	c.Assert(evt.Link.Uuid, gc.DeepEquals, dst.ID[:])
+}
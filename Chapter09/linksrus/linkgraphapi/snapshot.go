@@ -0,0 +1,51 @@
+package linkgraphapi
+
+import "github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto"
+
+// snapshotChunkSize specifies the max size of each snapshot chunk that is
+// sent over the wire.
+const snapshotChunkSize = 64 * 1024
+
+// chunkWriter adapts a function that sends snapshot chunks to an io.Writer.
+type chunkWriter func(*proto.SnapshotChunk) error
+
+// Write implements io.Writer by splitting p into snapshot chunks.
+func (w chunkWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		n := len(p)
+		if n > snapshotChunkSize {
+			n = snapshotChunkSize
+		}
+
+		if err := w(&proto.SnapshotChunk{Data: append([]byte(nil), p[:n]...)}); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+
+	return written, nil
+}
+
+// chunkReader adapts a function that receives snapshot chunks to an
+// io.Reader.
+type chunkReader struct {
+	recvFn func() (*proto.SnapshotChunk, error)
+	buf    []byte
+}
+
+// Read implements io.Reader.
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.recvFn()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.Data
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
@@ -3,6 +3,7 @@ package textindexerapi
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi/proto"
@@ -31,10 +32,13 @@ func NewTextIndexerClient(ctx context.Context, rpcClient proto.TextIndexerClient
 // existing document.
 func (c *TextIndexerClient) Index(doc *index.Document) error {
 	req := &proto.Document{
-		LinkId:  doc.LinkID[:],
-		Url:     doc.URL,
-		Title:   doc.Title,
-		Content: doc.Content,
+		LinkId:      doc.LinkID[:],
+		Url:         doc.URL,
+		Title:       doc.Title,
+		Content:     doc.Content,
+		Description: doc.Description,
+		Headings:    doc.Headings,
+		Language:    doc.Language,
 	}
 	res, err := c.cli.Index(c.ctx, req)
 	if err != nil {
@@ -61,6 +65,68 @@ func (c *TextIndexerClient) UpdateScore(linkID uuid.UUID, score float64) error {
 	return err
 }
 
+// AddAnchorText records the text of a link pointing to the document with the
+// specified link ID.
+func (c *TextIndexerClient) AddAnchorText(linkID uuid.UUID, text string) error {
+	req := &proto.AddAnchorTextRequest{
+		LinkId: linkID[:],
+		Text:   text,
+	}
+	_, err := c.cli.AddAnchorText(c.ctx, req)
+	return err
+}
+
+// AddAnchorTexts records the texts of links pointing to the documents with the
+// specified link IDs in a single batch.
+func (c *TextIndexerClient) AddAnchorTexts(texts map[uuid.UUID][]string) error {
+	req := &proto.AddAnchorTextsRequest{
+		AnchorTexts: make([]*proto.AnchorTexts, 0, len(texts)),
+	}
+	for linkID, list := range texts {
+		linkID := linkID
+		req.AnchorTexts = append(req.AnchorTexts, &proto.AnchorTexts{
+			LinkId: linkID[:],
+			Texts:  list,
+		})
+	}
+	_, err := c.cli.AddAnchorTexts(c.ctx, req)
+	return err
+}
+
+// Delete removes the document with the specified link ID from the index.
+func (c *TextIndexerClient) Delete(linkID uuid.UUID) error {
+	req := &proto.DeleteRequest{LinkId: linkID[:]}
+	_, err := c.cli.Delete(c.ctx, req)
+	return err
+}
+
+// DeleteOlderThan removes all documents that were last indexed before the
+// specified timestamp.
+func (c *TextIndexerClient) DeleteOlderThan(t time.Time) error {
+	req := &proto.DeleteOlderThanRequest{IndexedBefore: timeToProto(t)}
+	_, err := c.cli.DeleteOlderThan(c.ctx, req)
+	return err
+}
+
+// Suggest returns alternative spellings for the terms of a search expression
+// that are not present in the index.
+func (c *TextIndexerClient) Suggest(expression string) ([]string, error) {
+	res, err := c.cli.Suggest(c.ctx, &proto.SuggestRequest{Expression: expression})
+	if err != nil {
+		return nil, err
+	}
+	return res.Suggestions, nil
+}
+
+// Complete returns completions for a partially typed search expression.
+func (c *TextIndexerClient) Complete(expression string) ([]string, error) {
+	res, err := c.cli.Complete(c.ctx, &proto.CompleteRequest{Expression: expression})
+	if err != nil {
+		return nil, err
+	}
+	return res.Completions, nil
+}
+
 // Search the index for a particular query and return back a result iterator.
 func (c *TextIndexerClient) Search(query index.Query) (index.Iterator, error) {
 	ctx, cancelFn := context.WithCancel(c.ctx)
@@ -68,6 +134,14 @@ func (c *TextIndexerClient) Search(query index.Query) (index.Iterator, error) {
 		Type:       proto.Query_Type(query.Type),
 		Expression: query.Expression,
 		Offset:     query.Offset,
+		Cursor:     query.Cursor,
+		Backward:   query.Backward,
+		Facets:     facetRequestsToProto(query.Facets),
+		Ranking:    rankingToProto(query.Ranking),
+		Language:   languagePreferenceToProto(query.Language),
+	}
+	if query.Tree != nil {
+		req.Tree = queryNodeToProto(query.Tree)
 	}
 	stream, err := c.cli.Search(ctx, req)
 	if err != nil {
@@ -84,18 +158,33 @@ func (c *TextIndexerClient) Search(query index.Query) (index.Iterator, error) {
 		cancelFn()
 		return nil, xerrors.Errorf("expected server to report the result count before sending any documents")
 	}
-
-	return &resultIterator{
+	it := &resultIterator{
 		total:    res.GetDocCount(),
 		stream:   stream,
 		cancelFn: cancelFn,
-	}, nil
+	}
+
+	// Read facet counts
+	if len(query.Facets) != 0 {
+		if res, err = stream.Recv(); err != nil {
+			cancelFn()
+			return nil, err
+		} else if res.GetFacets() == nil {
+			cancelFn()
+			return nil, xerrors.Errorf("expected server to report the facet counts before sending any documents")
+		}
+		it.facets = facetResultsFromProto(res.GetFacets())
+	}
+
+	return it, nil
 }
 
 type resultIterator struct {
 	total   uint64
+	facets  []index.FacetResult
 	stream  proto.TextIndexer_SearchClient
 	next    *index.Document
+	cursor  string
 	lastErr error
 
 	// A function to cancel the context used to perform the streaming RPC. It
@@ -132,12 +221,17 @@ func (it *resultIterator) Next() bool {
 	}
 
 	it.next = &index.Document{
-		LinkID:    linkID,
-		URL:       resDoc.Url,
-		Title:     resDoc.Title,
-		Content:   resDoc.Content,
-		IndexedAt: t,
+		LinkID:      linkID,
+		URL:         resDoc.Url,
+		Title:       resDoc.Title,
+		Content:     resDoc.Content,
+		Description: resDoc.Description,
+		Headings:    resDoc.Headings,
+		AnchorText:  resDoc.AnchorText,
+		Language:    resDoc.Language,
+		IndexedAt:   t,
 	}
+	it.cursor = res.Cursor
 	return true
 }
 
@@ -150,6 +244,13 @@ func (it *resultIterator) Document() *index.Document { return it.next }
 // TotalCount returns the approximate number of search results.
 func (it *resultIterator) TotalCount() uint64 { return it.total }
 
+// Facets returns the bucket counts for the facets requested by the search
+// query.
+func (it *resultIterator) Facets() []index.FacetResult { return it.facets }
+
+// Cursor returns the opaque cursor for the currently fetched document.
+func (it *resultIterator) Cursor() string { return it.cursor }
+
 // Close releases any resources associated with an iterator.
 func (it *resultIterator) Close() error {
 	it.cancelFn()
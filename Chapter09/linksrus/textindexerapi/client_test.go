@@ -11,6 +11,7 @@ import (
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi/mocks"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi/proto"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
 	gc "gopkg.in/check.v1"
@@ -27,19 +28,23 @@ func (s *ClientTestSuite) TestIndex(c *gc.C) {
 
 	now := time.Now().Truncate(time.Second).UTC()
 	doc := &index.Document{
-		LinkID:  uuid.New(),
-		URL:     "http://example.com",
-		Title:   "Title",
-		Content: "Lorem Ipsum",
+		LinkID:      uuid.New(),
+		URL:         "http://example.com",
+		Title:       "Title",
+		Content:     "Lorem Ipsum",
+		Description: "Dolor sit amet",
+		Headings:    []string{"Consectetur"},
 	}
 
 	rpcCli.EXPECT().Index(
 		gomock.AssignableToTypeOf(context.TODO()),
 		&proto.Document{
-			LinkId:  doc.LinkID[:],
-			Url:     doc.URL,
-			Title:   doc.Title,
-			Content: doc.Content,
+			LinkId:      doc.LinkID[:],
+			Url:         doc.URL,
+			Title:       doc.Title,
+			Content:     doc.Content,
+			Description: doc.Description,
+			Headings:    doc.Headings,
 		},
 	).Return(
 		&proto.Document{
@@ -78,6 +83,115 @@ func (s *ClientTestSuite) TestUpdateScore(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *ClientTestSuite) TestAddAnchorText(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+
+	linkID := uuid.New()
+
+	rpcCli.EXPECT().AddAnchorText(
+		gomock.AssignableToTypeOf(context.TODO()),
+		&proto.AddAnchorTextRequest{
+			LinkId: linkID[:],
+			Text:   "Lorem ipsum",
+		},
+	).Return(new(empty.Empty), nil)
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	err := cli.AddAnchorText(linkID, "Lorem ipsum")
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestAddAnchorTexts(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+
+	linkID := uuid.New()
+
+	rpcCli.EXPECT().AddAnchorTexts(
+		gomock.AssignableToTypeOf(context.TODO()),
+		&proto.AddAnchorTextsRequest{
+			AnchorTexts: []*proto.AnchorTexts{
+				{LinkId: linkID[:], Texts: []string{"Lorem ipsum", "dolor"}},
+			},
+		},
+	).Return(new(empty.Empty), nil)
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	err := cli.AddAnchorTexts(map[uuid.UUID][]string{linkID: {"Lorem ipsum", "dolor"}})
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestDelete(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+
+	linkID := uuid.New()
+
+	rpcCli.EXPECT().Delete(
+		gomock.AssignableToTypeOf(context.TODO()),
+		&proto.DeleteRequest{LinkId: linkID[:]},
+	).Return(new(empty.Empty), nil)
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	err := cli.Delete(linkID)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestDeleteOlderThan(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+
+	now := time.Now()
+	indexedBefore, err := ptypes.TimestampProto(now)
+	c.Assert(err, gc.IsNil)
+
+	rpcCli.EXPECT().DeleteOlderThan(
+		gomock.AssignableToTypeOf(context.TODO()),
+		&proto.DeleteOlderThanRequest{IndexedBefore: indexedBefore},
+	).Return(new(empty.Empty), nil)
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	err = cli.DeleteOlderThan(now)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestSuggest(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+
+	rpcCli.EXPECT().Suggest(
+		gomock.AssignableToTypeOf(context.TODO()),
+		&proto.SuggestRequest{Expression: "lorme ipsum"},
+	).Return(&proto.SuggestResponse{Suggestions: []string{"lorem ipsum"}}, nil)
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	got, err := cli.Suggest("lorme ipsum")
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.DeepEquals, []string{"lorem ipsum"})
+}
+
+func (s *ClientTestSuite) TestComplete(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+
+	rpcCli.EXPECT().Complete(
+		gomock.AssignableToTypeOf(context.TODO()),
+		&proto.CompleteRequest{Expression: "lorem ips"},
+	).Return(&proto.CompleteResponse{Completions: []string{"lorem ipsum"}}, nil)
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	got, err := cli.Complete("lorem ips")
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.DeepEquals, []string{"lorem ipsum"})
+}
+
 func (s *ClientTestSuite) TestSearch(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -145,3 +259,254 @@ func (s *ClientTestSuite) TestSearch(c *gc.C) {
 	c.Assert(it.Close(), gc.IsNil)
 	c.Assert(docCount, gc.Equals, 2)
 }
+
+func (s *ClientTestSuite) TestSearchWithQueryTree(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+	resultStream := mocks.NewMockTextIndexer_SearchClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	from := time.Now().Truncate(time.Second).UTC()
+	rpcCli.EXPECT().Search(
+		gomock.AssignableToTypeOf(ctxWithCancel),
+		&proto.Query{
+			Offset: 10,
+			Tree: &proto.QueryNode{Node: &proto.QueryNode_Bool{Bool: &proto.BoolNode{
+				Must: []*proto.QueryNode{
+					{Node: &proto.QueryNode_Term{Term: &proto.TermNode{Field: proto.TermNode_TITLE, Text: "foo bar", Phrase: true}}},
+					{Node: &proto.QueryNode_DateRange{DateRange: &proto.DateRangeNode{From: mustEncodeTimestamp(c, from)}}},
+				},
+				MustNot: []*proto.QueryNode{
+					{Node: &proto.QueryNode_Site{Site: &proto.SiteNode{Host: "example.com"}}},
+				},
+			}}},
+		},
+	).Return(resultStream, nil)
+
+	returns := [][]interface{}{
+		{&proto.QueryResult{Result: &proto.QueryResult_DocCount{DocCount: 0}}, nil},
+		{nil, io.EOF},
+	}
+	resultStream.EXPECT().Recv().DoAndReturn(
+		func() (interface{}, interface{}) {
+			next := returns[0]
+			returns = returns[1:]
+			return next[0], next[1]
+		},
+	).Times(len(returns))
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	it, err := cli.Search(index.Query{
+		Offset: 10,
+		Tree: &index.BoolNode{
+			Must: []index.QueryNode{
+				&index.TermNode{Field: index.FieldTitle, Text: "foo bar", Phrase: true},
+				&index.DateRangeNode{From: from},
+			},
+			MustNot: []index.QueryNode{
+				&index.SiteNode{Host: "example.com"},
+			},
+		},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.TotalCount(), gc.Equals, uint64(0))
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestSearchWithRanking(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+	resultStream := mocks.NewMockTextIndexer_SearchClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	rpcCli.EXPECT().Search(
+		gomock.AssignableToTypeOf(ctxWithCancel),
+		&proto.Query{
+			Type:       proto.Query_MATCH,
+			Expression: "foo",
+			Ranking: &proto.Ranking{
+				RelevanceWeight:   0.5,
+				PageRankWeight:    2,
+				TitleBoost:        3,
+				FreshnessHalfLife: ptypes.DurationProto(time.Hour),
+			},
+		},
+	).Return(resultStream, nil)
+
+	resultStream.EXPECT().Recv().Return(&proto.QueryResult{Result: &proto.QueryResult_DocCount{DocCount: 0}}, nil)
+	resultStream.EXPECT().Recv().Return(nil, io.EOF)
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	it, err := cli.Search(index.Query{
+		Type:       index.QueryTypeMatch,
+		Expression: "foo",
+		Ranking: &index.Ranking{
+			RelevanceWeight:   0.5,
+			PageRankWeight:    2,
+			TitleBoost:        3,
+			FreshnessHalfLife: time.Hour,
+		},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestSearchWithCursor(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+	resultStream := mocks.NewMockTextIndexer_SearchClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	linkID := uuid.New()
+	rpcCli.EXPECT().Search(
+		gomock.AssignableToTypeOf(ctxWithCancel),
+		&proto.Query{
+			Type:       proto.Query_MATCH,
+			Expression: "foo",
+			Cursor:     "cursor-0",
+			Backward:   true,
+		},
+	).Return(resultStream, nil)
+
+	resultStream.EXPECT().Recv().Return(&proto.QueryResult{Result: &proto.QueryResult_DocCount{DocCount: 2}}, nil)
+	resultStream.EXPECT().Recv().Return(&proto.QueryResult{
+		Result: &proto.QueryResult_Doc{
+			Doc: &proto.Document{
+				LinkId:    linkID[:],
+				IndexedAt: mustEncodeTimestamp(c, time.Now()),
+			},
+		},
+		Cursor: "cursor-1",
+	}, nil)
+	resultStream.EXPECT().Recv().Return(nil, io.EOF)
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	it, err := cli.Search(index.Query{
+		Type:       index.QueryTypeMatch,
+		Expression: "foo",
+		Cursor:     "cursor-0",
+		Backward:   true,
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Document().LinkID, gc.Equals, linkID)
+	c.Assert(it.Cursor(), gc.Equals, "cursor-1")
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestSearchWithLanguage(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+	resultStream := mocks.NewMockTextIndexer_SearchClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	linkID := uuid.New()
+	rpcCli.EXPECT().Search(
+		gomock.AssignableToTypeOf(ctxWithCancel),
+		&proto.Query{
+			Type:       proto.Query_MATCH,
+			Expression: "foo",
+			Language:   &proto.LanguagePreference{Language: "fr", Boost: 2},
+		},
+	).Return(resultStream, nil)
+
+	resultStream.EXPECT().Recv().Return(&proto.QueryResult{Result: &proto.QueryResult_DocCount{DocCount: 1}}, nil)
+	resultStream.EXPECT().Recv().Return(&proto.QueryResult{Result: &proto.QueryResult_Doc{
+		Doc: &proto.Document{
+			LinkId:    linkID[:],
+			Language:  "fr",
+			IndexedAt: mustEncodeTimestamp(c, time.Now()),
+		},
+	}}, nil)
+	resultStream.EXPECT().Recv().Return(nil, io.EOF)
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	it, err := cli.Search(index.Query{
+		Type:       index.QueryTypeMatch,
+		Expression: "foo",
+		Language:   &index.LanguagePreference{Language: "fr", Boost: 2},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Document().LinkID, gc.Equals, linkID)
+	c.Assert(it.Document().Language, gc.Equals, "fr")
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
+
+func (s *ClientTestSuite) TestSearchWithFacets(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	rpcCli := mocks.NewMockTextIndexerClient(ctrl)
+	resultStream := mocks.NewMockTextIndexer_SearchClient(ctrl)
+
+	ctxWithCancel, cancelFn := context.WithCancel(context.TODO())
+	defer cancelFn()
+
+	rpcCli.EXPECT().Search(
+		gomock.AssignableToTypeOf(ctxWithCancel),
+		&proto.Query{
+			Type:       proto.Query_MATCH,
+			Expression: "foo",
+			Facets: []*proto.FacetRequest{
+				{Name: "months", Type: proto.FacetRequest_INDEXED_AT, Limit: 2, Interval: proto.FacetRequest_MONTH},
+				{Name: "rank", Type: proto.FacetRequest_PAGE_RANK, Ranges: []float64{0.5}},
+			},
+		},
+	).Return(resultStream, nil)
+
+	returns := [][]interface{}{
+		{&proto.QueryResult{Result: &proto.QueryResult_DocCount{DocCount: 0}}, nil},
+		{&proto.QueryResult{Result: &proto.QueryResult_Facets{Facets: &proto.FacetResults{
+			Facets: []*proto.FacetResult{
+				{Name: "months", Buckets: []*proto.FacetBucket{{Key: "2019-01", Count: 0}, {Key: "2019-02", Count: 0}}},
+				{Name: "rank", Buckets: []*proto.FacetBucket{{Key: "..0.5", Count: 0}, {Key: "0.5..", Count: 0}}},
+			},
+		}}}, nil},
+		{nil, io.EOF},
+	}
+	resultStream.EXPECT().Recv().DoAndReturn(
+		func() (interface{}, interface{}) {
+			next := returns[0]
+			returns = returns[1:]
+			return next[0], next[1]
+		},
+	).Times(len(returns))
+
+	cli := textindexerapi.NewTextIndexerClient(context.TODO(), rpcCli)
+	it, err := cli.Search(index.Query{
+		Type:       index.QueryTypeMatch,
+		Expression: "foo",
+		Facets: []index.FacetRequest{
+			{Name: "months", Type: index.FacetTypeIndexedAt, Size: 2, Interval: index.DateIntervalMonth},
+			{Name: "rank", Type: index.FacetTypePageRank, Ranges: []float64{0.5}},
+		},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Facets(), gc.DeepEquals, []index.FacetResult{
+		{Name: "months", Buckets: []index.FacetBucket{{Key: "2019-01", Count: 0}, {Key: "2019-02", Count: 0}}},
+		{Name: "rank", Buckets: []index.FacetBucket{{Key: "..0.5", Count: 0}, {Key: "0.5..", Count: 0}}},
+	})
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
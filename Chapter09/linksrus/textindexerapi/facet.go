@@ -0,0 +1,68 @@
+package textindexerapi
+
+import (
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi/proto"
+)
+
+func facetRequestsToProto(reqs []index.FacetRequest) []*proto.FacetRequest {
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	res := make([]*proto.FacetRequest, 0, len(reqs))
+	for _, req := range reqs {
+		res = append(res, &proto.FacetRequest{
+			Name:     req.Name,
+			Type:     proto.FacetRequest_Type(req.Type),
+			Limit:    uint32(req.Size),
+			Interval: proto.FacetRequest_Interval(req.Interval),
+			Ranges:   req.Ranges,
+		})
+	}
+	return res
+}
+
+func facetRequestsFromProto(reqs []*proto.FacetRequest) []index.FacetRequest {
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	res := make([]index.FacetRequest, 0, len(reqs))
+	for _, req := range reqs {
+		res = append(res, index.FacetRequest{
+			Name:     req.Name,
+			Type:     index.FacetType(req.Type),
+			Size:     int(req.Limit),
+			Interval: index.DateInterval(req.Interval),
+			Ranges:   req.Ranges,
+		})
+	}
+	return res
+}
+
+func facetResultsToProto(results []index.FacetResult) *proto.FacetResults {
+	res := &proto.FacetResults{
+		Facets: make([]*proto.FacetResult, 0, len(results)),
+	}
+	for _, result := range results {
+		facet := &proto.FacetResult{Name: result.Name}
+		for _, bucket := range result.Buckets {
+			facet.Buckets = append(facet.Buckets, &proto.FacetBucket{Key: bucket.Key, Count: bucket.Count})
+		}
+		res.Facets = append(res.Facets, facet)
+	}
+	return res
+}
+
+func facetResultsFromProto(results *proto.FacetResults) []index.FacetResult {
+	res := make([]index.FacetResult, 0, len(results.GetFacets()))
+	for _, result := range results.GetFacets() {
+		facet := index.FacetResult{Name: result.Name}
+		for _, bucket := range result.Buckets {
+			facet.Buckets = append(facet.Buckets, index.FacetBucket{Key: bucket.Key, Count: bucket.Count})
+		}
+		res = append(res, facet)
+	}
+	return res
+}
@@ -0,0 +1,20 @@
+package textindexerapi
+
+import (
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi/proto"
+)
+
+func languagePreferenceToProto(p *index.LanguagePreference) *proto.LanguagePreference {
+	if p == nil {
+		return nil
+	}
+	return &proto.LanguagePreference{Language: p.Language, Boost: p.Boost}
+}
+
+func languagePreferenceFromProto(p *proto.LanguagePreference) *index.LanguagePreference {
+	if p == nil {
+		return nil
+	}
+	return &index.LanguagePreference{Language: p.Language, Boost: p.Boost}
+}
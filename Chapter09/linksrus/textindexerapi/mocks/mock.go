@@ -37,6 +37,106 @@ func (m *MockTextIndexerClient) EXPECT() *MockTextIndexerClientMockRecorder {
 	return m.recorder
 }
 
+// AddAnchorText mocks base method
+func (m *MockTextIndexerClient) AddAnchorText(arg0 context.Context, arg1 *proto.AddAnchorTextRequest, arg2 ...grpc.CallOption) (*empty.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddAnchorText", varargs...)
+	ret0, _ := ret[0].(*empty.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAnchorText indicates an expected call of AddAnchorText
+func (mr *MockTextIndexerClientMockRecorder) AddAnchorText(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAnchorText", reflect.TypeOf((*MockTextIndexerClient)(nil).AddAnchorText), varargs...)
+}
+
+// AddAnchorTexts mocks base method
+func (m *MockTextIndexerClient) AddAnchorTexts(arg0 context.Context, arg1 *proto.AddAnchorTextsRequest, arg2 ...grpc.CallOption) (*empty.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddAnchorTexts", varargs...)
+	ret0, _ := ret[0].(*empty.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAnchorTexts indicates an expected call of AddAnchorTexts
+func (mr *MockTextIndexerClientMockRecorder) AddAnchorTexts(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAnchorTexts", reflect.TypeOf((*MockTextIndexerClient)(nil).AddAnchorTexts), varargs...)
+}
+
+// Complete mocks base method
+func (m *MockTextIndexerClient) Complete(arg0 context.Context, arg1 *proto.CompleteRequest, arg2 ...grpc.CallOption) (*proto.CompleteResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Complete", varargs...)
+	ret0, _ := ret[0].(*proto.CompleteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Complete indicates an expected call of Complete
+func (mr *MockTextIndexerClientMockRecorder) Complete(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockTextIndexerClient)(nil).Complete), varargs...)
+}
+
+// Delete mocks base method
+func (m *MockTextIndexerClient) Delete(arg0 context.Context, arg1 *proto.DeleteRequest, arg2 ...grpc.CallOption) (*empty.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(*empty.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockTextIndexerClientMockRecorder) Delete(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTextIndexerClient)(nil).Delete), varargs...)
+}
+
+// DeleteOlderThan mocks base method
+func (m *MockTextIndexerClient) DeleteOlderThan(arg0 context.Context, arg1 *proto.DeleteOlderThanRequest, arg2 ...grpc.CallOption) (*empty.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteOlderThan", varargs...)
+	ret0, _ := ret[0].(*empty.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOlderThan indicates an expected call of DeleteOlderThan
+func (mr *MockTextIndexerClientMockRecorder) DeleteOlderThan(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOlderThan", reflect.TypeOf((*MockTextIndexerClient)(nil).DeleteOlderThan), varargs...)
+}
+
 // Index mocks base method
 func (m *MockTextIndexerClient) Index(arg0 context.Context, arg1 *proto.Document, arg2 ...grpc.CallOption) (*proto.Document, error) {
 	m.ctrl.T.Helper()
@@ -77,6 +177,26 @@ func (mr *MockTextIndexerClientMockRecorder) Search(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockTextIndexerClient)(nil).Search), varargs...)
 }
 
+// Suggest mocks base method
+func (m *MockTextIndexerClient) Suggest(arg0 context.Context, arg1 *proto.SuggestRequest, arg2 ...grpc.CallOption) (*proto.SuggestResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Suggest", varargs...)
+	ret0, _ := ret[0].(*proto.SuggestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest
+func (mr *MockTextIndexerClientMockRecorder) Suggest(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockTextIndexerClient)(nil).Suggest), varargs...)
+}
+
 // UpdateScore mocks base method
 func (m *MockTextIndexerClient) UpdateScore(arg0 context.Context, arg1 *proto.UpdateScoreRequest, arg2 ...grpc.CallOption) (*empty.Empty, error) {
 	m.ctrl.T.Helper()
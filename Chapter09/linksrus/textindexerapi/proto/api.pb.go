@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	empty "github.com/golang/protobuf/ptypes/empty"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	grpc "google.golang.org/grpc"
@@ -51,16 +52,107 @@ func (Query_Type) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{1, 0}
 }
 
+type TermNode_Field int32
+
+const (
+	TermNode_ANY   TermNode_Field = 0
+	TermNode_TITLE TermNode_Field = 1
+)
+
+var TermNode_Field_name = map[int32]string{
+	0: "ANY",
+	1: "TITLE",
+}
+
+var TermNode_Field_value = map[string]int32{
+	"ANY":   0,
+	"TITLE": 1,
+}
+
+func (x TermNode_Field) String() string {
+	return proto.EnumName(TermNode_Field_name, int32(x))
+}
+
+func (TermNode_Field) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5, 0}
+}
+
+type FacetRequest_Type int32
+
+const (
+	FacetRequest_HOST       FacetRequest_Type = 0
+	FacetRequest_INDEXED_AT FacetRequest_Type = 1
+	FacetRequest_PAGE_RANK  FacetRequest_Type = 2
+)
+
+var FacetRequest_Type_name = map[int32]string{
+	0: "HOST",
+	1: "INDEXED_AT",
+	2: "PAGE_RANK",
+}
+
+var FacetRequest_Type_value = map[string]int32{
+	"HOST":       0,
+	"INDEXED_AT": 1,
+	"PAGE_RANK":  2,
+}
+
+func (x FacetRequest_Type) String() string {
+	return proto.EnumName(FacetRequest_Type_name, int32(x))
+}
+
+func (FacetRequest_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9, 0}
+}
+
+type FacetRequest_Interval int32
+
+const (
+	FacetRequest_DAY   FacetRequest_Interval = 0
+	FacetRequest_MONTH FacetRequest_Interval = 1
+	FacetRequest_YEAR  FacetRequest_Interval = 2
+)
+
+var FacetRequest_Interval_name = map[int32]string{
+	0: "DAY",
+	1: "MONTH",
+	2: "YEAR",
+}
+
+var FacetRequest_Interval_value = map[string]int32{
+	"DAY":   0,
+	"MONTH": 1,
+	"YEAR":  2,
+}
+
+func (x FacetRequest_Interval) String() string {
+	return proto.EnumName(FacetRequest_Interval_name, int32(x))
+}
+
+func (FacetRequest_Interval) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9, 1}
+}
+
 // Document represents an indexed document.
 type Document struct {
-	LinkId               []byte               `protobuf:"bytes,1,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
-	Url                  string               `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	Title                string               `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Content              string               `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
-	IndexedAt            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=indexed_at,json=indexedAt,proto3" json:"indexed_at,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	LinkId    []byte               `protobuf:"bytes,1,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
+	Url       string               `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Title     string               `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Content   string               `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	IndexedAt *timestamp.Timestamp `protobuf:"bytes,5,opt,name=indexed_at,json=indexedAt,proto3" json:"indexed_at,omitempty"`
+	// The ISO 639-1 code of the document language (if known).
+	Language string `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`
+	// The contents of the description meta tag (if available).
+	Description string `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	// The text of the top-level headings in the document.
+	Headings []string `protobuf:"bytes,8,rep,name=headings,proto3" json:"headings,omitempty"`
+	// The texts of links pointing to the document. This field is ignored by
+	// the Index RPC; anchor texts are recorded via the AddAnchorText and
+	// AddAnchorTexts RPCs.
+	AnchorText           []string `protobuf:"bytes,9,rep,name=anchor_text,json=anchorText,proto3" json:"anchor_text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Document) Reset()         { *m = Document{} }
@@ -123,14 +215,60 @@ func (m *Document) GetIndexedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *Document) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+func (m *Document) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Document) GetHeadings() []string {
+	if m != nil {
+		return m.Headings
+	}
+	return nil
+}
+
+func (m *Document) GetAnchorText() []string {
+	if m != nil {
+		return m.AnchorText
+	}
+	return nil
+}
+
 // Query represents a search query.
 type Query struct {
-	Type                 Query_Type `protobuf:"varint,1,opt,name=type,proto3,enum=proto.Query_Type" json:"type,omitempty"`
-	Expression           string     `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
-	Offset               uint64     `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	Type       Query_Type `protobuf:"varint,1,opt,name=type,proto3,enum=proto.Query_Type" json:"type,omitempty"`
+	Expression string     `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+	Offset     uint64     `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// An optional structured query tree. If specified, the type and
+	// expression fields are ignored.
+	Tree *QueryNode `protobuf:"bytes,4,opt,name=tree,proto3" json:"tree,omitempty"`
+	// An optional list of facets to calculate over the matching documents.
+	Facets []*FacetRequest `protobuf:"bytes,5,rep,name=facets,proto3" json:"facets,omitempty"`
+	// An optional ranking model for ordering the search results. If not
+	// specified, the default ranking model of the indexer is used.
+	Ranking *Ranking `protobuf:"bytes,6,opt,name=ranking,proto3" json:"ranking,omitempty"`
+	// An optional language preference for filtering or boosting documents
+	// based on their language.
+	Language *LanguagePreference `protobuf:"bytes,7,opt,name=language,proto3" json:"language,omitempty"`
+	// An optional opaque cursor returned along with a previous search result.
+	// If specified, the offset field is ignored and the results start with
+	// the document following the one that the cursor points to.
+	Cursor string `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// If set together with cursor, the documents preceding the one that the
+	// cursor points to are returned in reverse ranked order.
+	Backward             bool     `protobuf:"varint,9,opt,name=backward,proto3" json:"backward,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Query) Reset()         { *m = Query{} }
@@ -172,184 +310,1350 @@ func (m *Query) GetExpression() string {
 	return ""
 }
 
-func (m *Query) GetOffset() uint64 {
+func (m *Query) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *Query) GetTree() *QueryNode {
+	if m != nil {
+		return m.Tree
+	}
+	return nil
+}
+
+func (m *Query) GetFacets() []*FacetRequest {
+	if m != nil {
+		return m.Facets
+	}
+	return nil
+}
+
+func (m *Query) GetRanking() *Ranking {
+	if m != nil {
+		return m.Ranking
+	}
+	return nil
+}
+
+func (m *Query) GetLanguage() *LanguagePreference {
+	if m != nil {
+		return m.Language
+	}
+	return nil
+}
+
+func (m *Query) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *Query) GetBackward() bool {
+	if m != nil {
+		return m.Backward
+	}
+	return false
+}
+
+// Ranking describes how the relevance, PageRank and freshness of matching
+// documents are blended to order search results.
+type Ranking struct {
+	RelevanceWeight      float64            `protobuf:"fixed64,1,opt,name=relevance_weight,json=relevanceWeight,proto3" json:"relevance_weight,omitempty"`
+	PageRankWeight       float64            `protobuf:"fixed64,2,opt,name=page_rank_weight,json=pageRankWeight,proto3" json:"page_rank_weight,omitempty"`
+	TitleBoost           float64            `protobuf:"fixed64,3,opt,name=title_boost,json=titleBoost,proto3" json:"title_boost,omitempty"`
+	FreshnessHalfLife    *duration.Duration `protobuf:"bytes,4,opt,name=freshness_half_life,json=freshnessHalfLife,proto3" json:"freshness_half_life,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *Ranking) Reset()         { *m = Ranking{} }
+func (m *Ranking) String() string { return proto.CompactTextString(m) }
+func (*Ranking) ProtoMessage()    {}
+func (*Ranking) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{2}
+}
+
+func (m *Ranking) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Ranking.Unmarshal(m, b)
+}
+func (m *Ranking) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Ranking.Marshal(b, m, deterministic)
+}
+func (m *Ranking) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ranking.Merge(m, src)
+}
+func (m *Ranking) XXX_Size() int {
+	return xxx_messageInfo_Ranking.Size(m)
+}
+func (m *Ranking) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ranking.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Ranking proto.InternalMessageInfo
+
+func (m *Ranking) GetRelevanceWeight() float64 {
+	if m != nil {
+		return m.RelevanceWeight
+	}
+	return 0
+}
+
+func (m *Ranking) GetPageRankWeight() float64 {
+	if m != nil {
+		return m.PageRankWeight
+	}
+	return 0
+}
+
+func (m *Ranking) GetTitleBoost() float64 {
+	if m != nil {
+		return m.TitleBoost
+	}
+	return 0
+}
+
+func (m *Ranking) GetFreshnessHalfLife() *duration.Duration {
+	if m != nil {
+		return m.FreshnessHalfLife
+	}
+	return nil
+}
+
+// LanguagePreference describes how the language of documents affects the
+// results of a query. A boost less than or equal to 1 excludes documents in
+// other languages.
+type LanguagePreference struct {
+	Language             string   `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`
+	Boost                float64  `protobuf:"fixed64,2,opt,name=boost,proto3" json:"boost,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LanguagePreference) Reset()         { *m = LanguagePreference{} }
+func (m *LanguagePreference) String() string { return proto.CompactTextString(m) }
+func (*LanguagePreference) ProtoMessage()    {}
+func (*LanguagePreference) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+}
+
+func (m *LanguagePreference) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LanguagePreference.Unmarshal(m, b)
+}
+func (m *LanguagePreference) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LanguagePreference.Marshal(b, m, deterministic)
+}
+func (m *LanguagePreference) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LanguagePreference.Merge(m, src)
+}
+func (m *LanguagePreference) XXX_Size() int {
+	return xxx_messageInfo_LanguagePreference.Size(m)
+}
+func (m *LanguagePreference) XXX_DiscardUnknown() {
+	xxx_messageInfo_LanguagePreference.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LanguagePreference proto.InternalMessageInfo
+
+func (m *LanguagePreference) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+func (m *LanguagePreference) GetBoost() float64 {
+	if m != nil {
+		return m.Boost
+	}
+	return 0
+}
+
+// QueryNode represents a node of a structured query tree.
+type QueryNode struct {
+	// Types that are valid to be assigned to Node:
+	//	*QueryNode_Term
+	//	*QueryNode_Site
+	//	*QueryNode_DateRange
+	//	*QueryNode_Bool
+	Node                 isQueryNode_Node `protobuf_oneof:"node"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *QueryNode) Reset()         { *m = QueryNode{} }
+func (m *QueryNode) String() string { return proto.CompactTextString(m) }
+func (*QueryNode) ProtoMessage()    {}
+func (*QueryNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+}
+
+func (m *QueryNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryNode.Unmarshal(m, b)
+}
+func (m *QueryNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryNode.Marshal(b, m, deterministic)
+}
+func (m *QueryNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryNode.Merge(m, src)
+}
+func (m *QueryNode) XXX_Size() int {
+	return xxx_messageInfo_QueryNode.Size(m)
+}
+func (m *QueryNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryNode proto.InternalMessageInfo
+
+type isQueryNode_Node interface {
+	isQueryNode_Node()
+}
+
+type QueryNode_Term struct {
+	Term *TermNode `protobuf:"bytes,1,opt,name=term,proto3,oneof"`
+}
+
+type QueryNode_Site struct {
+	Site *SiteNode `protobuf:"bytes,2,opt,name=site,proto3,oneof"`
+}
+
+type QueryNode_DateRange struct {
+	DateRange *DateRangeNode `protobuf:"bytes,3,opt,name=date_range,json=dateRange,proto3,oneof"`
+}
+
+type QueryNode_Bool struct {
+	Bool *BoolNode `protobuf:"bytes,4,opt,name=bool,proto3,oneof"`
+}
+
+func (*QueryNode_Term) isQueryNode_Node()      {}
+func (*QueryNode_Site) isQueryNode_Node()      {}
+func (*QueryNode_DateRange) isQueryNode_Node() {}
+
+func (*QueryNode_Bool) isQueryNode_Node() {}
+
+func (m *QueryNode) GetNode() isQueryNode_Node {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *QueryNode) GetTerm() *TermNode {
+	if x, ok := m.GetNode().(*QueryNode_Term); ok {
+		return x.Term
+	}
+	return nil
+}
+
+func (m *QueryNode) GetSite() *SiteNode {
+	if x, ok := m.GetNode().(*QueryNode_Site); ok {
+		return x.Site
+	}
+	return nil
+}
+
+func (m *QueryNode) GetDateRange() *DateRangeNode {
+	if x, ok := m.GetNode().(*QueryNode_DateRange); ok {
+		return x.DateRange
+	}
+	return nil
+}
+
+func (m *QueryNode) GetBool() *BoolNode {
+	if x, ok := m.GetNode().(*QueryNode_Bool); ok {
+		return x.Bool
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*QueryNode) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*QueryNode_Term)(nil),
+		(*QueryNode_Site)(nil),
+		(*QueryNode_DateRange)(nil),
+		(*QueryNode_Bool)(nil),
+	}
+}
+
+// TermNode matches documents that contain one or more keywords.
+type TermNode struct {
+	Field                TermNode_Field `protobuf:"varint,1,opt,name=field,proto3,enum=proto.TermNode_Field" json:"field,omitempty"`
+	Text                 string         `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Phrase               bool           `protobuf:"varint,3,opt,name=phrase,proto3" json:"phrase,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *TermNode) Reset()         { *m = TermNode{} }
+func (m *TermNode) String() string { return proto.CompactTextString(m) }
+func (*TermNode) ProtoMessage()    {}
+func (*TermNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+}
+
+func (m *TermNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TermNode.Unmarshal(m, b)
+}
+func (m *TermNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TermNode.Marshal(b, m, deterministic)
+}
+func (m *TermNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TermNode.Merge(m, src)
+}
+func (m *TermNode) XXX_Size() int {
+	return xxx_messageInfo_TermNode.Size(m)
+}
+func (m *TermNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_TermNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TermNode proto.InternalMessageInfo
+
+func (m *TermNode) GetField() TermNode_Field {
+	if m != nil {
+		return m.Field
+	}
+	return TermNode_ANY
+}
+
+func (m *TermNode) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+func (m *TermNode) GetPhrase() bool {
+	if m != nil {
+		return m.Phrase
+	}
+	return false
+}
+
+// SiteNode matches documents whose URL points to a particular host or any of
+// its sub-domains.
+type SiteNode struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SiteNode) Reset()         { *m = SiteNode{} }
+func (m *SiteNode) String() string { return proto.CompactTextString(m) }
+func (*SiteNode) ProtoMessage()    {}
+func (*SiteNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+}
+
+func (m *SiteNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SiteNode.Unmarshal(m, b)
+}
+func (m *SiteNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SiteNode.Marshal(b, m, deterministic)
+}
+func (m *SiteNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SiteNode.Merge(m, src)
+}
+func (m *SiteNode) XXX_Size() int {
+	return xxx_messageInfo_SiteNode.Size(m)
+}
+func (m *SiteNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_SiteNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SiteNode proto.InternalMessageInfo
+
+func (m *SiteNode) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+// DateRangeNode matches documents indexed within the [from, to) range. A
+// missing bound leaves that end of the range open.
+type DateRangeNode struct {
+	From                 *timestamp.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *DateRangeNode) Reset()         { *m = DateRangeNode{} }
+func (m *DateRangeNode) String() string { return proto.CompactTextString(m) }
+func (*DateRangeNode) ProtoMessage()    {}
+func (*DateRangeNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+}
+
+func (m *DateRangeNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DateRangeNode.Unmarshal(m, b)
+}
+func (m *DateRangeNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DateRangeNode.Marshal(b, m, deterministic)
+}
+func (m *DateRangeNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DateRangeNode.Merge(m, src)
+}
+func (m *DateRangeNode) XXX_Size() int {
+	return xxx_messageInfo_DateRangeNode.Size(m)
+}
+func (m *DateRangeNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_DateRangeNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DateRangeNode proto.InternalMessageInfo
+
+func (m *DateRangeNode) GetFrom() *timestamp.Timestamp {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *DateRangeNode) GetTo() *timestamp.Timestamp {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+// BoolNode combines a set of sub-queries.
+type BoolNode struct {
+	Must                 []*QueryNode `protobuf:"bytes,1,rep,name=must,proto3" json:"must,omitempty"`
+	Should               []*QueryNode `protobuf:"bytes,2,rep,name=should,proto3" json:"should,omitempty"`
+	MustNot              []*QueryNode `protobuf:"bytes,3,rep,name=must_not,json=mustNot,proto3" json:"must_not,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BoolNode) Reset()         { *m = BoolNode{} }
+func (m *BoolNode) String() string { return proto.CompactTextString(m) }
+func (*BoolNode) ProtoMessage()    {}
+func (*BoolNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+}
+
+func (m *BoolNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BoolNode.Unmarshal(m, b)
+}
+func (m *BoolNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BoolNode.Marshal(b, m, deterministic)
+}
+func (m *BoolNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BoolNode.Merge(m, src)
+}
+func (m *BoolNode) XXX_Size() int {
+	return xxx_messageInfo_BoolNode.Size(m)
+}
+func (m *BoolNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_BoolNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BoolNode proto.InternalMessageInfo
+
+func (m *BoolNode) GetMust() []*QueryNode {
+	if m != nil {
+		return m.Must
+	}
+	return nil
+}
+
+func (m *BoolNode) GetShould() []*QueryNode {
+	if m != nil {
+		return m.Should
+	}
+	return nil
+}
+
+func (m *BoolNode) GetMustNot() []*QueryNode {
+	if m != nil {
+		return m.MustNot
+	}
+	return nil
+}
+
+// FacetRequest describes a set of counts to be calculated over the
+// documents that match a query.
+type FacetRequest struct {
+	Name                 string                `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 FacetRequest_Type     `protobuf:"varint,2,opt,name=type,proto3,enum=proto.FacetRequest_Type" json:"type,omitempty"`
+	Limit                uint32                `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Interval             FacetRequest_Interval `protobuf:"varint,4,opt,name=interval,proto3,enum=proto.FacetRequest_Interval" json:"interval,omitempty"`
+	Ranges               []float64             `protobuf:"fixed64,5,rep,packed,name=ranges,proto3" json:"ranges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *FacetRequest) Reset()         { *m = FacetRequest{} }
+func (m *FacetRequest) String() string { return proto.CompactTextString(m) }
+func (*FacetRequest) ProtoMessage()    {}
+func (*FacetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+}
+
+func (m *FacetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FacetRequest.Unmarshal(m, b)
+}
+func (m *FacetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FacetRequest.Marshal(b, m, deterministic)
+}
+func (m *FacetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FacetRequest.Merge(m, src)
+}
+func (m *FacetRequest) XXX_Size() int {
+	return xxx_messageInfo_FacetRequest.Size(m)
+}
+func (m *FacetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FacetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FacetRequest proto.InternalMessageInfo
+
+func (m *FacetRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FacetRequest) GetType() FacetRequest_Type {
+	if m != nil {
+		return m.Type
+	}
+	return FacetRequest_HOST
+}
+
+func (m *FacetRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *FacetRequest) GetInterval() FacetRequest_Interval {
+	if m != nil {
+		return m.Interval
+	}
+	return FacetRequest_DAY
+}
+
+func (m *FacetRequest) GetRanges() []float64 {
+	if m != nil {
+		return m.Ranges
+	}
+	return nil
+}
+
+// FacetBucket contains the number of matching documents for a facet value.
+type FacetBucket struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count                uint64   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FacetBucket) Reset()         { *m = FacetBucket{} }
+func (m *FacetBucket) String() string { return proto.CompactTextString(m) }
+func (*FacetBucket) ProtoMessage()    {}
+func (*FacetBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+}
+
+func (m *FacetBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FacetBucket.Unmarshal(m, b)
+}
+func (m *FacetBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FacetBucket.Marshal(b, m, deterministic)
+}
+func (m *FacetBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FacetBucket.Merge(m, src)
+}
+func (m *FacetBucket) XXX_Size() int {
+	return xxx_messageInfo_FacetBucket.Size(m)
+}
+func (m *FacetBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_FacetBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FacetBucket proto.InternalMessageInfo
+
+func (m *FacetBucket) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *FacetBucket) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// FacetResult contains the bucket counts for a requested facet.
+type FacetResult struct {
+	Name                 string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Buckets              []*FacetBucket `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *FacetResult) Reset()         { *m = FacetResult{} }
+func (m *FacetResult) String() string { return proto.CompactTextString(m) }
+func (*FacetResult) ProtoMessage()    {}
+func (*FacetResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+
+func (m *FacetResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FacetResult.Unmarshal(m, b)
+}
+func (m *FacetResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FacetResult.Marshal(b, m, deterministic)
+}
+func (m *FacetResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FacetResult.Merge(m, src)
+}
+func (m *FacetResult) XXX_Size() int {
+	return xxx_messageInfo_FacetResult.Size(m)
+}
+func (m *FacetResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_FacetResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FacetResult proto.InternalMessageInfo
+
+func (m *FacetResult) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FacetResult) GetBuckets() []*FacetBucket {
+	if m != nil {
+		return m.Buckets
+	}
+	return nil
+}
+
+// FacetResults contains the bucket counts for all requested facets.
+type FacetResults struct {
+	Facets               []*FacetResult `protobuf:"bytes,1,rep,name=facets,proto3" json:"facets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *FacetResults) Reset()         { *m = FacetResults{} }
+func (m *FacetResults) String() string { return proto.CompactTextString(m) }
+func (*FacetResults) ProtoMessage()    {}
+func (*FacetResults) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
+
+func (m *FacetResults) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FacetResults.Unmarshal(m, b)
+}
+func (m *FacetResults) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FacetResults.Marshal(b, m, deterministic)
+}
+func (m *FacetResults) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FacetResults.Merge(m, src)
+}
+func (m *FacetResults) XXX_Size() int {
+	return xxx_messageInfo_FacetResults.Size(m)
+}
+func (m *FacetResults) XXX_DiscardUnknown() {
+	xxx_messageInfo_FacetResults.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FacetResults proto.InternalMessageInfo
+
+func (m *FacetResults) GetFacets() []*FacetResult {
+	if m != nil {
+		return m.Facets
+	}
+	return nil
+}
+
+// QueryResult contains either the total count of results for a query, the
+// facet counts for the query or a single document from the resultset.
+type QueryResult struct {
+	// Types that are valid to be assigned to Result:
+	//	*QueryResult_DocCount
+	//	*QueryResult_Doc
+	//	*QueryResult_Facets
+	Result isQueryResult_Result `protobuf_oneof:"result"`
+	// The opaque cursor for the document in the doc field.
+	Cursor               string   `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryResult) Reset()         { *m = QueryResult{} }
+func (m *QueryResult) String() string { return proto.CompactTextString(m) }
+func (*QueryResult) ProtoMessage()    {}
+func (*QueryResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
+}
+
+func (m *QueryResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryResult.Unmarshal(m, b)
+}
+func (m *QueryResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryResult.Marshal(b, m, deterministic)
+}
+func (m *QueryResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryResult.Merge(m, src)
+}
+func (m *QueryResult) XXX_Size() int {
+	return xxx_messageInfo_QueryResult.Size(m)
+}
+func (m *QueryResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryResult proto.InternalMessageInfo
+
+type isQueryResult_Result interface {
+	isQueryResult_Result()
+}
+
+type QueryResult_DocCount struct {
+	DocCount uint64 `protobuf:"varint,1,opt,name=doc_count,json=docCount,proto3,oneof"`
+}
+
+type QueryResult_Doc struct {
+	Doc *Document `protobuf:"bytes,2,opt,name=doc,proto3,oneof"`
+}
+
+type QueryResult_Facets struct {
+	Facets *FacetResults `protobuf:"bytes,3,opt,name=facets,proto3,oneof"`
+}
+
+func (*QueryResult_DocCount) isQueryResult_Result() {}
+
+func (*QueryResult_Doc) isQueryResult_Result()    {}
+func (*QueryResult_Facets) isQueryResult_Result() {}
+
+func (m *QueryResult) GetResult() isQueryResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *QueryResult) GetDocCount() uint64 {
+	if x, ok := m.GetResult().(*QueryResult_DocCount); ok {
+		return x.DocCount
+	}
+	return 0
+}
+
+func (m *QueryResult) GetDoc() *Document {
+	if x, ok := m.GetResult().(*QueryResult_Doc); ok {
+		return x.Doc
+	}
+	return nil
+}
+
+func (m *QueryResult) GetFacets() *FacetResults {
+	if x, ok := m.GetResult().(*QueryResult_Facets); ok {
+		return x.Facets
+	}
+	return nil
+}
+
+func (m *QueryResult) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*QueryResult) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*QueryResult_DocCount)(nil),
+		(*QueryResult_Doc)(nil),
+		(*QueryResult_Facets)(nil),
+	}
+}
+
+// UpdateScoreRequest encapsulates the parameters for the UpdateScore RPC.
+type UpdateScoreRequest struct {
+	LinkId               []byte   `protobuf:"bytes,1,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
+	PageRankScore        float64  `protobuf:"fixed64,2,opt,name=page_rank_score,json=pageRankScore,proto3" json:"page_rank_score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateScoreRequest) Reset()         { *m = UpdateScoreRequest{} }
+func (m *UpdateScoreRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateScoreRequest) ProtoMessage()    {}
+func (*UpdateScoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
+}
+
+func (m *UpdateScoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateScoreRequest.Unmarshal(m, b)
+}
+func (m *UpdateScoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateScoreRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateScoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateScoreRequest.Merge(m, src)
+}
+func (m *UpdateScoreRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateScoreRequest.Size(m)
+}
+func (m *UpdateScoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateScoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateScoreRequest proto.InternalMessageInfo
+
+func (m *UpdateScoreRequest) GetLinkId() []byte {
+	if m != nil {
+		return m.LinkId
+	}
+	return nil
+}
+
+func (m *UpdateScoreRequest) GetPageRankScore() float64 {
+	if m != nil {
+		return m.PageRankScore
+	}
+	return 0
+}
+
+// AddAnchorTextRequest encapsulates the parameters for the AddAnchorText RPC.
+type AddAnchorTextRequest struct {
+	LinkId               []byte   `protobuf:"bytes,1,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
+	Text                 string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddAnchorTextRequest) Reset()         { *m = AddAnchorTextRequest{} }
+func (m *AddAnchorTextRequest) String() string { return proto.CompactTextString(m) }
+func (*AddAnchorTextRequest) ProtoMessage()    {}
+func (*AddAnchorTextRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
+}
+
+func (m *AddAnchorTextRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddAnchorTextRequest.Unmarshal(m, b)
+}
+func (m *AddAnchorTextRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddAnchorTextRequest.Marshal(b, m, deterministic)
+}
+func (m *AddAnchorTextRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddAnchorTextRequest.Merge(m, src)
+}
+func (m *AddAnchorTextRequest) XXX_Size() int {
+	return xxx_messageInfo_AddAnchorTextRequest.Size(m)
+}
+func (m *AddAnchorTextRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddAnchorTextRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddAnchorTextRequest proto.InternalMessageInfo
+
+func (m *AddAnchorTextRequest) GetLinkId() []byte {
+	if m != nil {
+		return m.LinkId
+	}
+	return nil
+}
+
+func (m *AddAnchorTextRequest) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+// AnchorTexts holds the texts of links pointing to the document with the
+// specified link ID.
+type AnchorTexts struct {
+	LinkId               []byte   `protobuf:"bytes,1,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
+	Texts                []string `protobuf:"bytes,2,rep,name=texts,proto3" json:"texts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AnchorTexts) Reset()         { *m = AnchorTexts{} }
+func (m *AnchorTexts) String() string { return proto.CompactTextString(m) }
+func (*AnchorTexts) ProtoMessage()    {}
+func (*AnchorTexts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{16}
+}
+
+func (m *AnchorTexts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AnchorTexts.Unmarshal(m, b)
+}
+func (m *AnchorTexts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AnchorTexts.Marshal(b, m, deterministic)
+}
+func (m *AnchorTexts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnchorTexts.Merge(m, src)
+}
+func (m *AnchorTexts) XXX_Size() int {
+	return xxx_messageInfo_AnchorTexts.Size(m)
+}
+func (m *AnchorTexts) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnchorTexts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AnchorTexts proto.InternalMessageInfo
+
+func (m *AnchorTexts) GetLinkId() []byte {
+	if m != nil {
+		return m.LinkId
+	}
+	return nil
+}
+
+func (m *AnchorTexts) GetTexts() []string {
+	if m != nil {
+		return m.Texts
+	}
+	return nil
+}
+
+// AddAnchorTextsRequest encapsulates the parameters for the AddAnchorTexts
+// RPC.
+type AddAnchorTextsRequest struct {
+	AnchorTexts          []*AnchorTexts `protobuf:"bytes,1,rep,name=anchor_texts,json=anchorTexts,proto3" json:"anchor_texts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AddAnchorTextsRequest) Reset()         { *m = AddAnchorTextsRequest{} }
+func (m *AddAnchorTextsRequest) String() string { return proto.CompactTextString(m) }
+func (*AddAnchorTextsRequest) ProtoMessage()    {}
+func (*AddAnchorTextsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{17}
+}
+
+func (m *AddAnchorTextsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddAnchorTextsRequest.Unmarshal(m, b)
+}
+func (m *AddAnchorTextsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddAnchorTextsRequest.Marshal(b, m, deterministic)
+}
+func (m *AddAnchorTextsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddAnchorTextsRequest.Merge(m, src)
+}
+func (m *AddAnchorTextsRequest) XXX_Size() int {
+	return xxx_messageInfo_AddAnchorTextsRequest.Size(m)
+}
+func (m *AddAnchorTextsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddAnchorTextsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddAnchorTextsRequest proto.InternalMessageInfo
+
+func (m *AddAnchorTextsRequest) GetAnchorTexts() []*AnchorTexts {
+	if m != nil {
+		return m.AnchorTexts
+	}
+	return nil
+}
+
+// SuggestRequest encapsulates the parameters for the Suggest RPC.
+type SuggestRequest struct {
+	Expression           string   `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SuggestRequest) Reset()         { *m = SuggestRequest{} }
+func (m *SuggestRequest) String() string { return proto.CompactTextString(m) }
+func (*SuggestRequest) ProtoMessage()    {}
+func (*SuggestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{18}
+}
+
+func (m *SuggestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SuggestRequest.Unmarshal(m, b)
+}
+func (m *SuggestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SuggestRequest.Marshal(b, m, deterministic)
+}
+func (m *SuggestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SuggestRequest.Merge(m, src)
+}
+func (m *SuggestRequest) XXX_Size() int {
+	return xxx_messageInfo_SuggestRequest.Size(m)
+}
+func (m *SuggestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SuggestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SuggestRequest proto.InternalMessageInfo
+
+func (m *SuggestRequest) GetExpression() string {
 	if m != nil {
-		return m.Offset
+		return m.Expression
 	}
-	return 0
+	return ""
 }
 
-// QueryResult contains either the total count of results for a query or a
-// single document from the resultset.
-type QueryResult struct {
-	// Types that are valid to be assigned to Result:
-	//	*QueryResult_DocCount
-	//	*QueryResult_Doc
-	Result               isQueryResult_Result `protobuf_oneof:"result"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+// SuggestResponse contains the spelling suggestions for a search expression.
+type SuggestResponse struct {
+	Suggestions          []string `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *QueryResult) Reset()         { *m = QueryResult{} }
-func (m *QueryResult) String() string { return proto.CompactTextString(m) }
-func (*QueryResult) ProtoMessage()    {}
-func (*QueryResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{2}
+func (m *SuggestResponse) Reset()         { *m = SuggestResponse{} }
+func (m *SuggestResponse) String() string { return proto.CompactTextString(m) }
+func (*SuggestResponse) ProtoMessage()    {}
+func (*SuggestResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{19}
 }
 
-func (m *QueryResult) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_QueryResult.Unmarshal(m, b)
+func (m *SuggestResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SuggestResponse.Unmarshal(m, b)
 }
-func (m *QueryResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_QueryResult.Marshal(b, m, deterministic)
+func (m *SuggestResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SuggestResponse.Marshal(b, m, deterministic)
 }
-func (m *QueryResult) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_QueryResult.Merge(m, src)
+func (m *SuggestResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SuggestResponse.Merge(m, src)
 }
-func (m *QueryResult) XXX_Size() int {
-	return xxx_messageInfo_QueryResult.Size(m)
+func (m *SuggestResponse) XXX_Size() int {
+	return xxx_messageInfo_SuggestResponse.Size(m)
 }
-func (m *QueryResult) XXX_DiscardUnknown() {
-	xxx_messageInfo_QueryResult.DiscardUnknown(m)
+func (m *SuggestResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SuggestResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_QueryResult proto.InternalMessageInfo
+var xxx_messageInfo_SuggestResponse proto.InternalMessageInfo
 
-type isQueryResult_Result interface {
-	isQueryResult_Result()
+func (m *SuggestResponse) GetSuggestions() []string {
+	if m != nil {
+		return m.Suggestions
+	}
+	return nil
 }
 
-type QueryResult_DocCount struct {
-	DocCount uint64 `protobuf:"varint,1,opt,name=doc_count,json=docCount,proto3,oneof"`
+// CompleteRequest encapsulates the parameters for the Complete RPC.
+type CompleteRequest struct {
+	Expression           string   `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type QueryResult_Doc struct {
-	Doc *Document `protobuf:"bytes,2,opt,name=doc,proto3,oneof"`
+func (m *CompleteRequest) Reset()         { *m = CompleteRequest{} }
+func (m *CompleteRequest) String() string { return proto.CompactTextString(m) }
+func (*CompleteRequest) ProtoMessage()    {}
+func (*CompleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{20}
 }
 
-func (*QueryResult_DocCount) isQueryResult_Result() {}
+func (m *CompleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompleteRequest.Unmarshal(m, b)
+}
+func (m *CompleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompleteRequest.Marshal(b, m, deterministic)
+}
+func (m *CompleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteRequest.Merge(m, src)
+}
+func (m *CompleteRequest) XXX_Size() int {
+	return xxx_messageInfo_CompleteRequest.Size(m)
+}
+func (m *CompleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteRequest.DiscardUnknown(m)
+}
 
-func (*QueryResult_Doc) isQueryResult_Result() {}
+var xxx_messageInfo_CompleteRequest proto.InternalMessageInfo
 
-func (m *QueryResult) GetResult() isQueryResult_Result {
+func (m *CompleteRequest) GetExpression() string {
 	if m != nil {
-		return m.Result
+		return m.Expression
 	}
-	return nil
+	return ""
 }
 
-func (m *QueryResult) GetDocCount() uint64 {
-	if x, ok := m.GetResult().(*QueryResult_DocCount); ok {
-		return x.DocCount
-	}
-	return 0
+// CompleteResponse contains the completions for a partially typed search
+// expression.
+type CompleteResponse struct {
+	Completions          []string `protobuf:"bytes,1,rep,name=completions,proto3" json:"completions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *QueryResult) GetDoc() *Document {
-	if x, ok := m.GetResult().(*QueryResult_Doc); ok {
-		return x.Doc
-	}
-	return nil
+func (m *CompleteResponse) Reset()         { *m = CompleteResponse{} }
+func (m *CompleteResponse) String() string { return proto.CompactTextString(m) }
+func (*CompleteResponse) ProtoMessage()    {}
+func (*CompleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
 }
 
-// XXX_OneofWrappers is for the internal use of the proto package.
-func (*QueryResult) XXX_OneofWrappers() []interface{} {
-	return []interface{}{
-		(*QueryResult_DocCount)(nil),
-		(*QueryResult_Doc)(nil),
+func (m *CompleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompleteResponse.Unmarshal(m, b)
+}
+func (m *CompleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompleteResponse.Marshal(b, m, deterministic)
+}
+func (m *CompleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteResponse.Merge(m, src)
+}
+func (m *CompleteResponse) XXX_Size() int {
+	return xxx_messageInfo_CompleteResponse.Size(m)
+}
+func (m *CompleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompleteResponse proto.InternalMessageInfo
+
+func (m *CompleteResponse) GetCompletions() []string {
+	if m != nil {
+		return m.Completions
 	}
+	return nil
 }
 
-// UpdateScoreRequest encapsulates the parameters for the UpdateScore RPC.
-type UpdateScoreRequest struct {
+// DeleteRequest encapsulates the parameters for the Delete RPC.
+type DeleteRequest struct {
 	LinkId               []byte   `protobuf:"bytes,1,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
-	PageRankScore        float64  `protobuf:"fixed64,2,opt,name=page_rank_score,json=pageRankScore,proto3" json:"page_rank_score,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateScoreRequest) Reset()         { *m = UpdateScoreRequest{} }
-func (m *UpdateScoreRequest) String() string { return proto.CompactTextString(m) }
-func (*UpdateScoreRequest) ProtoMessage()    {}
-func (*UpdateScoreRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()    {}
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
 }
 
-func (m *UpdateScoreRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateScoreRequest.Unmarshal(m, b)
+func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
 }
-func (m *UpdateScoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateScoreRequest.Marshal(b, m, deterministic)
+func (m *DeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRequest.Marshal(b, m, deterministic)
 }
-func (m *UpdateScoreRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateScoreRequest.Merge(m, src)
+func (m *DeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRequest.Merge(m, src)
 }
-func (m *UpdateScoreRequest) XXX_Size() int {
-	return xxx_messageInfo_UpdateScoreRequest.Size(m)
+func (m *DeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteRequest.Size(m)
 }
-func (m *UpdateScoreRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateScoreRequest.DiscardUnknown(m)
+func (m *DeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UpdateScoreRequest proto.InternalMessageInfo
+var xxx_messageInfo_DeleteRequest proto.InternalMessageInfo
 
-func (m *UpdateScoreRequest) GetLinkId() []byte {
+func (m *DeleteRequest) GetLinkId() []byte {
 	if m != nil {
 		return m.LinkId
 	}
 	return nil
 }
 
-func (m *UpdateScoreRequest) GetPageRankScore() float64 {
+// DeleteOlderThanRequest encapsulates the parameters for the DeleteOlderThan
+// RPC.
+type DeleteOlderThanRequest struct {
+	IndexedBefore        *timestamp.Timestamp `protobuf:"bytes,1,opt,name=indexed_before,json=indexedBefore,proto3" json:"indexed_before,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *DeleteOlderThanRequest) Reset()         { *m = DeleteOlderThanRequest{} }
+func (m *DeleteOlderThanRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteOlderThanRequest) ProtoMessage()    {}
+func (*DeleteOlderThanRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
+}
+
+func (m *DeleteOlderThanRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteOlderThanRequest.Unmarshal(m, b)
+}
+func (m *DeleteOlderThanRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteOlderThanRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteOlderThanRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteOlderThanRequest.Merge(m, src)
+}
+func (m *DeleteOlderThanRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteOlderThanRequest.Size(m)
+}
+func (m *DeleteOlderThanRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteOlderThanRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteOlderThanRequest proto.InternalMessageInfo
+
+func (m *DeleteOlderThanRequest) GetIndexedBefore() *timestamp.Timestamp {
 	if m != nil {
-		return m.PageRankScore
+		return m.IndexedBefore
 	}
-	return 0
+	return nil
 }
 
 func init() {
 	proto.RegisterEnum("proto.Query_Type", Query_Type_name, Query_Type_value)
+	proto.RegisterEnum("proto.TermNode_Field", TermNode_Field_name, TermNode_Field_value)
+	proto.RegisterEnum("proto.FacetRequest_Type", FacetRequest_Type_name, FacetRequest_Type_value)
+	proto.RegisterEnum("proto.FacetRequest_Interval", FacetRequest_Interval_name, FacetRequest_Interval_value)
 	proto.RegisterType((*Document)(nil), "proto.Document")
 	proto.RegisterType((*Query)(nil), "proto.Query")
+	proto.RegisterType((*Ranking)(nil), "proto.Ranking")
+	proto.RegisterType((*LanguagePreference)(nil), "proto.LanguagePreference")
+	proto.RegisterType((*QueryNode)(nil), "proto.QueryNode")
+	proto.RegisterType((*TermNode)(nil), "proto.TermNode")
+	proto.RegisterType((*SiteNode)(nil), "proto.SiteNode")
+	proto.RegisterType((*DateRangeNode)(nil), "proto.DateRangeNode")
+	proto.RegisterType((*BoolNode)(nil), "proto.BoolNode")
+	proto.RegisterType((*FacetRequest)(nil), "proto.FacetRequest")
+	proto.RegisterType((*FacetBucket)(nil), "proto.FacetBucket")
+	proto.RegisterType((*FacetResult)(nil), "proto.FacetResult")
+	proto.RegisterType((*FacetResults)(nil), "proto.FacetResults")
 	proto.RegisterType((*QueryResult)(nil), "proto.QueryResult")
 	proto.RegisterType((*UpdateScoreRequest)(nil), "proto.UpdateScoreRequest")
+	proto.RegisterType((*AddAnchorTextRequest)(nil), "proto.AddAnchorTextRequest")
+	proto.RegisterType((*AnchorTexts)(nil), "proto.AnchorTexts")
+	proto.RegisterType((*AddAnchorTextsRequest)(nil), "proto.AddAnchorTextsRequest")
+	proto.RegisterType((*SuggestRequest)(nil), "proto.SuggestRequest")
+	proto.RegisterType((*SuggestResponse)(nil), "proto.SuggestResponse")
+	proto.RegisterType((*CompleteRequest)(nil), "proto.CompleteRequest")
+	proto.RegisterType((*CompleteResponse)(nil), "proto.CompleteResponse")
+	proto.RegisterType((*DeleteRequest)(nil), "proto.DeleteRequest")
+	proto.RegisterType((*DeleteOlderThanRequest)(nil), "proto.DeleteOlderThanRequest")
 }
 
 func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
 
 var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 449 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0x86, 0xb3, 0xc4, 0x76, 0xe3, 0x71, 0xa1, 0x61, 0x84, 0x8a, 0x31, 0x2a, 0x44, 0x46, 0xa0,
-	0x20, 0x21, 0x17, 0x85, 0x13, 0x37, 0x42, 0xa9, 0x94, 0x1e, 0x90, 0x60, 0x93, 0x9e, 0x38, 0x58,
-	0xae, 0x3d, 0x09, 0x56, 0x1c, 0xef, 0xb2, 0x5e, 0x4b, 0xc9, 0x03, 0xf0, 0x1e, 0x1c, 0x79, 0x4c,
-	0xe4, 0xb5, 0x23, 0x45, 0xa9, 0xc4, 0xc9, 0xfb, 0xff, 0x33, 0x3b, 0xf3, 0x79, 0x66, 0xc1, 0x4d,
-	0x64, 0x1e, 0x49, 0x25, 0xb4, 0x40, 0xdb, 0x7c, 0x82, 0x97, 0x2b, 0x21, 0x56, 0x05, 0x5d, 0x1a,
-	0x75, 0x57, 0x2f, 0x2f, 0x75, 0xbe, 0xa1, 0x4a, 0x27, 0x1b, 0xd9, 0xe6, 0x05, 0xcf, 0x8f, 0x13,
-	0x68, 0x23, 0xf5, 0xae, 0x0d, 0x86, 0x7f, 0x18, 0x0c, 0xbe, 0x88, 0xb4, 0xde, 0x50, 0xa9, 0xf1,
-	0x29, 0x9c, 0x14, 0x79, 0xb9, 0x8e, 0xf3, 0xcc, 0x67, 0x23, 0x36, 0x3e, 0xe5, 0x4e, 0x23, 0x6f,
-	0x32, 0x1c, 0x42, 0xbf, 0x56, 0x85, 0xff, 0x60, 0xc4, 0xc6, 0x2e, 0x6f, 0x8e, 0xf8, 0x04, 0x6c,
-	0x9d, 0xeb, 0x82, 0xfc, 0xbe, 0xf1, 0x5a, 0x81, 0x3e, 0x9c, 0xa4, 0xa2, 0xd4, 0x54, 0x6a, 0xdf,
-	0x32, 0xfe, 0x5e, 0xe2, 0x47, 0x80, 0xbc, 0xcc, 0x68, 0x4b, 0x59, 0x9c, 0x68, 0xdf, 0x1e, 0xb1,
-	0xb1, 0x37, 0x09, 0xa2, 0x96, 0x2c, 0xda, 0x93, 0x45, 0x8b, 0x3d, 0x3a, 0x77, 0xbb, 0xec, 0xa9,
-	0x0e, 0x7f, 0x33, 0xb0, 0xbf, 0xd7, 0xa4, 0x76, 0xf8, 0x1a, 0x2c, 0xbd, 0x93, 0x64, 0xe0, 0x1e,
-	0x4d, 0x1e, 0xb7, 0xf7, 0x22, 0x13, 0x8b, 0x16, 0x3b, 0x49, 0xdc, 0x84, 0xf1, 0x05, 0x00, 0x6d,
-	0xa5, 0xa2, 0xaa, 0xca, 0x45, 0xd9, 0x41, 0x1f, 0x38, 0x78, 0x0e, 0x8e, 0x58, 0x2e, 0x2b, 0xd2,
-	0x06, 0xde, 0xe2, 0x9d, 0x0a, 0x2f, 0xc0, 0x6a, 0xaa, 0xa0, 0x0b, 0xf6, 0xd7, 0xe9, 0xe2, 0x6a,
-	0x36, 0xec, 0x21, 0x80, 0xf3, 0x6d, 0xc6, 0xa7, 0xf3, 0xeb, 0x21, 0x0b, 0x7f, 0x80, 0x67, 0x5a,
-	0x71, 0xaa, 0xea, 0x42, 0xe3, 0x05, 0xb8, 0x99, 0x48, 0xe3, 0x54, 0xd4, 0xa5, 0x36, 0x44, 0xd6,
-	0xac, 0xc7, 0x07, 0x99, 0x48, 0xaf, 0x1a, 0x07, 0x5f, 0x41, 0x3f, 0x13, 0xa9, 0xe9, 0xee, 0x4d,
-	0xce, 0x3a, 0xd4, 0xfd, 0xa4, 0x67, 0x3d, 0xde, 0x44, 0x3f, 0x0f, 0xc0, 0x51, 0xa6, 0x5a, 0x78,
-	0x0b, 0x78, 0x2b, 0xb3, 0x44, 0xd3, 0x3c, 0x15, 0x8a, 0x38, 0xfd, 0xaa, 0xa9, 0xfa, 0xcf, 0x42,
-	0xde, 0xc0, 0x99, 0x4c, 0x56, 0x14, 0xab, 0xa4, 0x5c, 0xc7, 0x55, 0x73, 0xc5, 0x74, 0x62, 0xfc,
-	0x61, 0x63, 0xf3, 0xa4, 0x5c, 0x9b, 0x3a, 0x93, 0xbf, 0x0c, 0xbc, 0x05, 0x6d, 0xf5, 0x8d, 0x99,
-	0xa6, 0xc2, 0xb7, 0x60, 0x9b, 0x23, 0x1e, 0x13, 0x05, 0xc7, 0x06, 0xbe, 0x03, 0x67, 0x4e, 0x89,
-	0x4a, 0x7f, 0xe2, 0xe9, 0xe1, 0xa0, 0x03, 0x3c, 0x54, 0xed, 0x2c, 0xde, 0x33, 0xfc, 0x04, 0xde,
-	0x01, 0x3f, 0x3e, 0xeb, 0x92, 0xee, 0xff, 0x53, 0x70, 0x7e, 0x6f, 0xeb, 0xd7, 0xcd, 0x7b, 0xbc,
-	0x73, 0x8c, 0xfe, 0xf0, 0x2f, 0x00, 0x00, 0xff, 0xff, 0xe2, 0xe2, 0xc2, 0xc9, 0xe2, 0x02, 0x00,
-	0x00,
+	// 1459 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0xc9, 0x72, 0xdb, 0x46,
+	0x13, 0x16, 0x28, 0x2e, 0x60, 0x43, 0xa2, 0xe8, 0xb1, 0x2c, 0xc3, 0xf4, 0xa6, 0xc2, 0xbf, 0x14,
+	0xbd, 0x84, 0x76, 0xe8, 0x28, 0xe5, 0x2c, 0x87, 0x50, 0xa2, 0x14, 0xaa, 0x62, 0x4b, 0xce, 0x88,
+	0xae, 0xc4, 0x95, 0x03, 0x0b, 0x02, 0x86, 0x24, 0x4a, 0x20, 0x86, 0xc1, 0x0c, 0x6d, 0xe9, 0x96,
+	0x5b, 0x1e, 0x20, 0xc7, 0xdc, 0xf2, 0x02, 0x79, 0x84, 0xdc, 0xf3, 0x2e, 0x79, 0x87, 0xd4, 0x6c,
+	0x14, 0x48, 0x91, 0x51, 0x4e, 0x64, 0x77, 0x7f, 0xdd, 0xd3, 0xd3, 0xfd, 0x4d, 0xa3, 0xa1, 0xec,
+	0x8f, 0xa3, 0xc6, 0x38, 0xa5, 0x9c, 0xa2, 0x82, 0xfc, 0xa9, 0x3d, 0x1c, 0x50, 0x3a, 0x88, 0xc9,
+	0x33, 0x29, 0x9d, 0x4e, 0xfa, 0xcf, 0x78, 0x34, 0x22, 0x8c, 0xfb, 0xa3, 0xb1, 0xc2, 0xd5, 0xee,
+	0xce, 0x03, 0xc8, 0x68, 0xcc, 0x2f, 0xb4, 0xf1, 0xc1, 0xbc, 0x31, 0x9c, 0xa4, 0x3e, 0x8f, 0x68,
+	0xa2, 0xec, 0xde, 0xaf, 0x39, 0xb0, 0xdb, 0x34, 0x98, 0x8c, 0x48, 0xc2, 0xd1, 0x6d, 0x28, 0xc5,
+	0x51, 0x72, 0xd6, 0x8b, 0x42, 0xd7, 0xda, 0xb6, 0xea, 0x6b, 0xb8, 0x28, 0xc4, 0xc3, 0x10, 0x55,
+	0x61, 0x75, 0x92, 0xc6, 0x6e, 0x6e, 0xdb, 0xaa, 0x97, 0xb1, 0xf8, 0x8b, 0x36, 0xa1, 0xc0, 0x23,
+	0x1e, 0x13, 0x77, 0x55, 0xea, 0x94, 0x80, 0x5c, 0x28, 0x05, 0x34, 0xe1, 0x24, 0xe1, 0x6e, 0x5e,
+	0xea, 0x8d, 0x88, 0x3e, 0x03, 0x88, 0x92, 0x90, 0x9c, 0x93, 0xb0, 0xe7, 0x73, 0xb7, 0xb0, 0x6d,
+	0xd5, 0x9d, 0x66, 0xad, 0xa1, 0x92, 0x6b, 0x98, 0xe4, 0x1a, 0x5d, 0x73, 0x35, 0x5c, 0xd6, 0xe8,
+	0x16, 0x47, 0x35, 0xb0, 0x63, 0x3f, 0x19, 0x4c, 0xfc, 0x01, 0x71, 0x8b, 0x32, 0xea, 0x54, 0x46,
+	0xdb, 0xe0, 0x84, 0x84, 0x05, 0x69, 0x34, 0x16, 0x77, 0x72, 0x4b, 0xd2, 0x9c, 0x55, 0x09, 0xef,
+	0x21, 0xf1, 0xc3, 0x28, 0x19, 0x30, 0xd7, 0xde, 0x5e, 0x15, 0xde, 0x46, 0x46, 0x0f, 0xc1, 0xf1,
+	0x93, 0x60, 0x48, 0xd3, 0x1e, 0x27, 0xe7, 0xdc, 0x2d, 0x4b, 0x33, 0x28, 0x55, 0x97, 0x9c, 0x73,
+	0xef, 0xaf, 0x1c, 0x14, 0xbe, 0x9d, 0x90, 0xf4, 0x02, 0xfd, 0x0f, 0xf2, 0xfc, 0x62, 0x4c, 0x64,
+	0x5d, 0x2a, 0xcd, 0x1b, 0x2a, 0xe5, 0x86, 0xb4, 0x35, 0xba, 0x17, 0x63, 0x82, 0xa5, 0x19, 0x3d,
+	0x00, 0x20, 0xe7, 0xe3, 0x94, 0x30, 0x26, 0xd2, 0x51, 0xf5, 0xca, 0x68, 0xd0, 0x16, 0x14, 0x69,
+	0xbf, 0xcf, 0x08, 0x97, 0x75, 0xcb, 0x63, 0x2d, 0xa1, 0xff, 0x42, 0x9e, 0xa7, 0x84, 0xc8, 0xaa,
+	0x39, 0xcd, 0x6a, 0x36, 0xfc, 0x11, 0x0d, 0x45, 0xf4, 0x94, 0x10, 0xf4, 0x04, 0x8a, 0x7d, 0x3f,
+	0x20, 0x9c, 0xb9, 0x85, 0xed, 0xd5, 0xba, 0xd3, 0xbc, 0xa9, 0x71, 0x07, 0x42, 0x89, 0xc9, 0x8f,
+	0x13, 0xc2, 0x38, 0xd6, 0x10, 0x54, 0x87, 0x52, 0xea, 0x27, 0x67, 0x51, 0x32, 0x90, 0x55, 0x73,
+	0x9a, 0x15, 0x8d, 0xc6, 0x4a, 0x8b, 0x8d, 0x19, 0xed, 0x64, 0x0a, 0x5c, 0x92, 0xd0, 0x3b, 0x1a,
+	0xfa, 0x4a, 0xab, 0xdf, 0xa4, 0xa4, 0x4f, 0x52, 0x92, 0x04, 0x24, 0x53, 0xfb, 0x2d, 0x28, 0x06,
+	0x93, 0x94, 0xd1, 0xd4, 0xb5, 0xe5, 0x3d, 0xb5, 0x24, 0x2a, 0x7e, 0xea, 0x07, 0x67, 0x1f, 0xfc,
+	0x34, 0x74, 0xcb, 0xdb, 0x56, 0xdd, 0xc6, 0x53, 0xd9, 0xbb, 0x0f, 0x79, 0x51, 0x2d, 0x54, 0x86,
+	0xc2, 0xeb, 0x56, 0x77, 0xaf, 0x53, 0x5d, 0x41, 0x00, 0xc5, 0x37, 0x1d, 0xdc, 0x3a, 0xd9, 0xaf,
+	0x5a, 0xde, 0x9f, 0x16, 0x94, 0x74, 0x7a, 0xe8, 0x11, 0x54, 0x53, 0x12, 0x93, 0xf7, 0x7e, 0x12,
+	0x90, 0xde, 0x07, 0x12, 0x0d, 0x86, 0x5c, 0x56, 0xdf, 0xc2, 0x1b, 0x53, 0xfd, 0x77, 0x52, 0x8d,
+	0xea, 0x50, 0x1d, 0xfb, 0x03, 0xd2, 0x13, 0x17, 0x32, 0xd0, 0x9c, 0x84, 0x56, 0x84, 0x5e, 0x44,
+	0xd4, 0xc8, 0x87, 0xe0, 0x48, 0xa6, 0xf6, 0x4e, 0x29, 0x65, 0xaa, 0x09, 0x16, 0x06, 0xa9, 0xda,
+	0x15, 0x1a, 0x74, 0x08, 0x37, 0xfb, 0x29, 0x61, 0xc3, 0x84, 0x30, 0xd6, 0x1b, 0xfa, 0x71, 0xbf,
+	0x17, 0x47, 0x7d, 0xd3, 0x97, 0x3b, 0x57, 0x08, 0xdb, 0xd6, 0xaf, 0x09, 0xdf, 0x98, 0x7a, 0x75,
+	0xfc, 0xb8, 0xff, 0x2a, 0xea, 0x13, 0xef, 0x00, 0xd0, 0xd5, 0xfa, 0xcd, 0xb0, 0xd9, 0x9a, 0x63,
+	0xf3, 0x26, 0x14, 0x54, 0x5e, 0x2a, 0x79, 0x25, 0x78, 0x7f, 0x58, 0x50, 0x9e, 0x32, 0x41, 0x12,
+	0x91, 0xa4, 0x23, 0xe9, 0xeb, 0x34, 0x37, 0x74, 0xa3, 0xba, 0x24, 0x1d, 0x09, 0x73, 0x67, 0x05,
+	0x4b, 0xb3, 0x80, 0xb1, 0x88, 0x13, 0x37, 0x37, 0x03, 0x3b, 0x89, 0x38, 0x31, 0x30, 0x61, 0x46,
+	0x3b, 0x00, 0xa1, 0xcf, 0x65, 0xe5, 0x06, 0xea, 0x2d, 0x3b, 0xcd, 0x4d, 0x0d, 0x6e, 0xfb, 0x5c,
+	0x94, 0x6e, 0x60, 0x3c, 0xca, 0xa1, 0x51, 0x88, 0xe8, 0xa7, 0x94, 0xc6, 0x6e, 0x7e, 0x26, 0xfa,
+	0x2e, 0xa5, 0xb1, 0x89, 0x2e, 0xcc, 0xbb, 0x45, 0xc8, 0x27, 0x34, 0x24, 0xde, 0x4f, 0x16, 0xd8,
+	0x26, 0x43, 0xf4, 0x04, 0x0a, 0xfd, 0x88, 0xc4, 0xa1, 0x7e, 0x4a, 0xb7, 0xe6, 0x6e, 0xd0, 0x38,
+	0x10, 0x46, 0xac, 0x30, 0x08, 0x89, 0xdb, 0x9e, 0x73, 0xfd, 0x92, 0xe4, 0x7f, 0xc1, 0xbb, 0xf1,
+	0x30, 0xf5, 0x99, 0xca, 0xd7, 0xc6, 0x5a, 0xf2, 0xee, 0x42, 0x41, 0xfa, 0xa2, 0x12, 0xac, 0xb6,
+	0x8e, 0xde, 0x55, 0x57, 0x04, 0xcb, 0xba, 0x87, 0xdd, 0x57, 0x82, 0x59, 0x0f, 0xc0, 0x36, 0x97,
+	0x17, 0x41, 0x87, 0xa2, 0xca, 0xaa, 0xfc, 0xf2, 0xbf, 0x77, 0x06, 0xeb, 0x33, 0xf7, 0x45, 0x0d,
+	0xc8, 0xf7, 0x53, 0x6a, 0xea, 0xfc, 0x4f, 0xa3, 0x4a, 0xe2, 0xd0, 0x63, 0xc8, 0x71, 0xea, 0xe6,
+	0xae, 0x45, 0xe7, 0x38, 0xf5, 0x7e, 0xb6, 0xc0, 0x36, 0xc5, 0x12, 0x4f, 0x7f, 0x34, 0x91, 0xd9,
+	0xac, 0x2e, 0x7e, 0xfa, 0xc2, 0x8a, 0xea, 0x50, 0x64, 0x43, 0x3a, 0x89, 0x43, 0x37, 0xb7, 0x04,
+	0xa7, 0xed, 0xe8, 0x09, 0xd8, 0xc2, 0xa3, 0x97, 0x50, 0xc1, 0xef, 0xc5, 0xd8, 0x92, 0x40, 0x1c,
+	0x51, 0xee, 0xfd, 0x92, 0x83, 0xb5, 0xec, 0xf4, 0x10, 0xb5, 0x49, 0xfc, 0x91, 0xa1, 0xa6, 0xfc,
+	0x8f, 0x9e, 0xea, 0xd9, 0x97, 0x93, 0x0d, 0x73, 0x17, 0x0c, 0x9d, 0xec, 0x08, 0xdc, 0x84, 0x42,
+	0x1c, 0x8d, 0x22, 0xf5, 0xb8, 0xd6, 0xb1, 0x12, 0xd0, 0x4b, 0xb0, 0xa3, 0x84, 0x93, 0xf4, 0xbd,
+	0xaf, 0x58, 0x53, 0x69, 0xde, 0x5b, 0x14, 0xe7, 0x50, 0x63, 0xf0, 0x14, 0x2d, 0xda, 0x2d, 0xd9,
+	0xa9, 0x86, 0x9e, 0x85, 0xb5, 0xe4, 0x3d, 0xd3, 0xa3, 0xc4, 0x86, 0x7c, 0xe7, 0xf8, 0xa4, 0x5b,
+	0x5d, 0x41, 0x15, 0x80, 0xc3, 0xa3, 0xf6, 0xfe, 0xf7, 0xfb, 0xed, 0x5e, 0xab, 0x5b, 0xb5, 0xd0,
+	0x3a, 0x94, 0xdf, 0xb4, 0xbe, 0xde, 0xef, 0xe1, 0xd6, 0xd1, 0x37, 0xd5, 0x9c, 0x57, 0x07, 0xdb,
+	0x84, 0x17, 0x14, 0x69, 0xb7, 0x34, 0x45, 0x5e, 0x1f, 0x1f, 0x75, 0x3b, 0x55, 0x4b, 0x04, 0x7a,
+	0xb7, 0xdf, 0xc2, 0xd5, 0x9c, 0xb7, 0x03, 0x8e, 0xcc, 0x6a, 0x77, 0x12, 0x9c, 0x11, 0x2e, 0xbe,
+	0x7e, 0x67, 0xe4, 0x42, 0x97, 0x44, 0xfc, 0x15, 0x77, 0x0c, 0xe8, 0x24, 0x51, 0xbc, 0xcc, 0x63,
+	0x25, 0x78, 0xc7, 0xda, 0x0d, 0x13, 0x36, 0x89, 0x97, 0x95, 0xb2, 0x74, 0x2a, 0x83, 0x32, 0xdd,
+	0x47, 0x94, 0xad, 0x82, 0x3a, 0x0f, 0x1b, 0x88, 0xf7, 0x39, 0xac, 0x65, 0x02, 0x32, 0xf4, 0x78,
+	0x3a, 0xff, 0xad, 0xab, 0xce, 0x0a, 0x64, 0xc6, 0xbf, 0xf7, 0x9b, 0x05, 0x8e, 0x6c, 0xb8, 0xce,
+	0xe6, 0x3e, 0x94, 0x43, 0x1a, 0xf4, 0x54, 0xda, 0x22, 0xa5, 0x7c, 0x67, 0x05, 0xdb, 0x21, 0x0d,
+	0xf6, 0x84, 0x06, 0xfd, 0x07, 0x56, 0x43, 0x1a, 0xcc, 0x8d, 0x0b, 0xb3, 0x18, 0x74, 0x56, 0xb0,
+	0xb0, 0xa2, 0x8f, 0xa6, 0xe7, 0xab, 0x49, 0x71, 0xf3, 0xea, 0xf9, 0xac, 0xb3, 0x32, 0xfd, 0x02,
+	0x5d, 0x7e, 0x20, 0xf2, 0xd9, 0x0f, 0xc4, 0xae, 0x0d, 0xc5, 0x54, 0x82, 0xbd, 0xb7, 0x80, 0xde,
+	0x8e, 0xc5, 0x58, 0x39, 0x09, 0x68, 0x4a, 0x0c, 0x07, 0x97, 0xae, 0x21, 0xff, 0x87, 0x8d, 0xcb,
+	0x39, 0xcf, 0x84, 0x8b, 0x9e, 0x94, 0xeb, 0x66, 0xcc, 0xcb, 0x38, 0xde, 0x1e, 0x6c, 0xb6, 0xc2,
+	0xb0, 0x35, 0xfd, 0x8e, 0x5f, 0x1b, 0x78, 0xc1, 0x98, 0xf1, 0xbe, 0x04, 0xe7, 0x32, 0x02, 0x5b,
+	0xee, 0x2b, 0x36, 0x21, 0x72, 0xae, 0x1b, 0x5a, 0xc6, 0x4a, 0xf0, 0x8e, 0xe0, 0xd6, 0x4c, 0x0a,
+	0xcc, 0xe4, 0xb0, 0x03, 0x6b, 0x99, 0x9d, 0x63, 0xbe, 0x93, 0x59, 0x07, 0xe7, 0x72, 0x11, 0x61,
+	0xde, 0x73, 0xa8, 0x9c, 0x4c, 0x06, 0x03, 0xf1, 0x81, 0xd7, 0x81, 0x66, 0x57, 0x0d, 0x6b, 0x7e,
+	0xd5, 0xf0, 0x5e, 0xc0, 0xc6, 0xd4, 0x83, 0x8d, 0x69, 0xc2, 0xe4, 0xb6, 0xc4, 0x94, 0x2a, 0xa2,
+	0x89, 0x3a, 0xba, 0x8c, 0xb3, 0x2a, 0xef, 0x63, 0xd8, 0xd8, 0xa3, 0xa3, 0x71, 0x4c, 0x38, 0xf9,
+	0xb7, 0xe7, 0x7c, 0x02, 0xd5, 0x4b, 0x97, 0xcb, 0x83, 0x02, 0xa5, 0xcb, 0x1e, 0x94, 0x51, 0x79,
+	0x75, 0x58, 0x6f, 0x93, 0xec, 0x31, 0xcb, 0xea, 0xeb, 0xfd, 0x00, 0x5b, 0x0a, 0x79, 0x1c, 0x87,
+	0x24, 0xed, 0x0e, 0xfd, 0xc4, 0xb8, 0xb4, 0xa0, 0x62, 0x76, 0xca, 0x53, 0xd2, 0x17, 0x6c, 0xb8,
+	0x7e, 0x58, 0xaf, 0x6b, 0x8f, 0x5d, 0xe9, 0xd0, 0xfc, 0x3d, 0x0f, 0x8e, 0x28, 0xf0, 0xa1, 0xd4,
+	0xa6, 0xe8, 0x11, 0x14, 0xe4, 0x5f, 0x34, 0xff, 0x04, 0x6a, 0xf3, 0x0a, 0xf4, 0x14, 0x8a, 0x27,
+	0xc4, 0x4f, 0x83, 0x21, 0x5a, 0xcb, 0xce, 0xd7, 0x1a, 0xca, 0x4a, 0xea, 0x51, 0x3c, 0xb7, 0xd0,
+	0x57, 0xe0, 0x64, 0x98, 0x8e, 0xcc, 0x82, 0x75, 0x95, 0xfd, 0xb5, 0xad, 0x2b, 0xd9, 0xef, 0x8b,
+	0x7d, 0x1e, 0xb5, 0x61, 0x7d, 0x86, 0x51, 0xe8, 0xae, 0xe1, 0xcc, 0x02, 0xaa, 0x2f, 0x8d, 0x72,
+	0x00, 0x95, 0x59, 0x5e, 0xa2, 0x7b, 0x8b, 0xc2, 0xb0, 0xeb, 0xe2, 0x7c, 0x0a, 0x45, 0xd5, 0x15,
+	0x34, 0x5d, 0x17, 0xb2, 0xed, 0x5c, 0xea, 0xd7, 0x81, 0x8d, 0xb9, 0x6e, 0xa2, 0xfb, 0x33, 0x01,
+	0xe6, 0xbb, 0xbc, 0x34, 0xd2, 0x4b, 0x28, 0x69, 0x7e, 0x23, 0xb3, 0x43, 0xcc, 0xbe, 0x90, 0xda,
+	0xd6, 0xbc, 0x5a, 0xb3, 0xf3, 0x0b, 0xb0, 0x0d, 0x63, 0x91, 0xc1, 0xcc, 0xb1, 0xbe, 0x76, 0xfb,
+	0x8a, 0x5e, 0x39, 0x9f, 0x16, 0xa5, 0xfe, 0xc5, 0xdf, 0x03, 0x00, 0x6b, 0xb1, 0xd2, 0xea, 0xa9,
+	0x0d, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -368,12 +1672,30 @@ type TextIndexerClient interface {
 	// and existing document.
 	Index(ctx context.Context, in *Document, opts ...grpc.CallOption) (*Document, error)
 	// Search the index for a particular query and stream the results back to
-	// the client. The first response will include the total result count while
-	// all subsequent responses will include documents from the resultset.
+	// the client. The first response will include the total result count. If
+	// the query requests any facets, the second response will include the
+	// facet counts. All subsequent responses will include documents from the
+	// resultset.
 	Search(ctx context.Context, in *Query, opts ...grpc.CallOption) (TextIndexer_SearchClient, error)
 	// UpdateScore updates the PageRank score for a document with the specified
 	// link ID.
 	UpdateScore(ctx context.Context, in *UpdateScoreRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// AddAnchorText records the text of a link pointing to the document with
+	// the specified link ID.
+	AddAnchorText(ctx context.Context, in *AddAnchorTextRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// AddAnchorTexts records the texts of links pointing to the documents with
+	// the specified link IDs in a single batch.
+	AddAnchorTexts(ctx context.Context, in *AddAnchorTextsRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Delete removes the document with the specified link ID from the index.
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// DeleteOlderThan removes all documents that were last indexed before the
+	// specified timestamp.
+	DeleteOlderThan(ctx context.Context, in *DeleteOlderThanRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Suggest returns alternative spellings for the terms of a search
+	// expression that are not present in the index.
+	Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error)
+	// Complete returns completions for a partially typed search expression.
+	Complete(ctx context.Context, in *CompleteRequest, opts ...grpc.CallOption) (*CompleteResponse, error)
 }
 
 type textIndexerClient struct {
@@ -434,18 +1756,90 @@ func (c *textIndexerClient) UpdateScore(ctx context.Context, in *UpdateScoreRequ
 	return out, nil
 }
 
+func (c *textIndexerClient) AddAnchorText(ctx context.Context, in *AddAnchorTextRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/AddAnchorText", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textIndexerClient) AddAnchorTexts(ctx context.Context, in *AddAnchorTextsRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/AddAnchorTexts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textIndexerClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textIndexerClient) DeleteOlderThan(ctx context.Context, in *DeleteOlderThanRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/DeleteOlderThan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textIndexerClient) Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error) {
+	out := new(SuggestResponse)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/Suggest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textIndexerClient) Complete(ctx context.Context, in *CompleteRequest, opts ...grpc.CallOption) (*CompleteResponse, error) {
+	out := new(CompleteResponse)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/Complete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TextIndexerServer is the server API for TextIndexer service.
 type TextIndexerServer interface {
 	// Index inserts a new document to the index or updates the index entry for
 	// and existing document.
 	Index(context.Context, *Document) (*Document, error)
 	// Search the index for a particular query and stream the results back to
-	// the client. The first response will include the total result count while
-	// all subsequent responses will include documents from the resultset.
+	// the client. The first response will include the total result count. If
+	// the query requests any facets, the second response will include the
+	// facet counts. All subsequent responses will include documents from the
+	// resultset.
 	Search(*Query, TextIndexer_SearchServer) error
 	// UpdateScore updates the PageRank score for a document with the specified
 	// link ID.
 	UpdateScore(context.Context, *UpdateScoreRequest) (*empty.Empty, error)
+	// AddAnchorText records the text of a link pointing to the document with
+	// the specified link ID.
+	AddAnchorText(context.Context, *AddAnchorTextRequest) (*empty.Empty, error)
+	// AddAnchorTexts records the texts of links pointing to the documents with
+	// the specified link IDs in a single batch.
+	AddAnchorTexts(context.Context, *AddAnchorTextsRequest) (*empty.Empty, error)
+	// Delete removes the document with the specified link ID from the index.
+	Delete(context.Context, *DeleteRequest) (*empty.Empty, error)
+	// DeleteOlderThan removes all documents that were last indexed before the
+	// specified timestamp.
+	DeleteOlderThan(context.Context, *DeleteOlderThanRequest) (*empty.Empty, error)
+	// Suggest returns alternative spellings for the terms of a search
+	// expression that are not present in the index.
+	Suggest(context.Context, *SuggestRequest) (*SuggestResponse, error)
+	// Complete returns completions for a partially typed search expression.
+	Complete(context.Context, *CompleteRequest) (*CompleteResponse, error)
 }
 
 // UnimplementedTextIndexerServer can be embedded to have forward compatible implementations.
@@ -461,6 +1855,24 @@ func (*UnimplementedTextIndexerServer) Search(req *Query, srv TextIndexer_Search
 func (*UnimplementedTextIndexerServer) UpdateScore(ctx context.Context, req *UpdateScoreRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateScore not implemented")
 }
+func (*UnimplementedTextIndexerServer) AddAnchorText(ctx context.Context, req *AddAnchorTextRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAnchorText not implemented")
+}
+func (*UnimplementedTextIndexerServer) AddAnchorTexts(ctx context.Context, req *AddAnchorTextsRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAnchorTexts not implemented")
+}
+func (*UnimplementedTextIndexerServer) Delete(ctx context.Context, req *DeleteRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedTextIndexerServer) DeleteOlderThan(ctx context.Context, req *DeleteOlderThanRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOlderThan not implemented")
+}
+func (*UnimplementedTextIndexerServer) Suggest(ctx context.Context, req *SuggestRequest) (*SuggestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suggest not implemented")
+}
+func (*UnimplementedTextIndexerServer) Complete(ctx context.Context, req *CompleteRequest) (*CompleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Complete not implemented")
+}
 
 func RegisterTextIndexerServer(s *grpc.Server, srv TextIndexerServer) {
 	s.RegisterService(&_TextIndexer_serviceDesc, srv)
@@ -523,6 +1935,114 @@ func _TextIndexer_UpdateScore_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TextIndexer_AddAnchorText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAnchorTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).AddAnchorText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/AddAnchorText",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).AddAnchorText(ctx, req.(*AddAnchorTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextIndexer_AddAnchorTexts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAnchorTextsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).AddAnchorTexts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/AddAnchorTexts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).AddAnchorTexts(ctx, req.(*AddAnchorTextsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextIndexer_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextIndexer_DeleteOlderThan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteOlderThanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).DeleteOlderThan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/DeleteOlderThan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).DeleteOlderThan(ctx, req.(*DeleteOlderThanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextIndexer_Suggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).Suggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/Suggest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).Suggest(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextIndexer_Complete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).Complete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/Complete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).Complete(ctx, req.(*CompleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TextIndexer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.TextIndexer",
 	HandlerType: (*TextIndexerServer)(nil),
@@ -535,6 +2055,30 @@ var _TextIndexer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateScore",
 			Handler:    _TextIndexer_UpdateScore_Handler,
 		},
+		{
+			MethodName: "AddAnchorText",
+			Handler:    _TextIndexer_AddAnchorText_Handler,
+		},
+		{
+			MethodName: "AddAnchorTexts",
+			Handler:    _TextIndexer_AddAnchorTexts_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _TextIndexer_Delete_Handler,
+		},
+		{
+			MethodName: "DeleteOlderThan",
+			Handler:    _TextIndexer_DeleteOlderThan_Handler,
+		},
+		{
+			MethodName: "Suggest",
+			Handler:    _TextIndexer_Suggest_Handler,
+		},
+		{
+			MethodName: "Complete",
+			Handler:    _TextIndexer_Complete_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,142 @@
+package textindexerapi
+
+import (
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/xerrors"
+)
+
+// queryNodeToProto converts a structured query tree into its protobuf
+// representation.
+func queryNodeToProto(node index.QueryNode) *proto.QueryNode {
+	switch n := node.(type) {
+	case *index.TermNode:
+		return &proto.QueryNode{
+			Node: &proto.QueryNode_Term{
+				Term: &proto.TermNode{
+					Field:  proto.TermNode_Field(n.Field),
+					Text:   n.Text,
+					Phrase: n.Phrase,
+				},
+			},
+		}
+	case *index.SiteNode:
+		return &proto.QueryNode{
+			Node: &proto.QueryNode_Site{Site: &proto.SiteNode{Host: n.Host}},
+		}
+	case *index.DateRangeNode:
+		return &proto.QueryNode{
+			Node: &proto.QueryNode_DateRange{
+				DateRange: &proto.DateRangeNode{
+					From: optionalTimeToProto(n.From),
+					To:   optionalTimeToProto(n.To),
+				},
+			},
+		}
+	case *index.BoolNode:
+		return &proto.QueryNode{
+			Node: &proto.QueryNode_Bool{
+				Bool: &proto.BoolNode{
+					Must:    queryNodeListToProto(n.Must),
+					Should:  queryNodeListToProto(n.Should),
+					MustNot: queryNodeListToProto(n.MustNot),
+				},
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+func queryNodeListToProto(list []index.QueryNode) []*proto.QueryNode {
+	if len(list) == 0 {
+		return nil
+	}
+
+	res := make([]*proto.QueryNode, 0, len(list))
+	for _, node := range list {
+		res = append(res, queryNodeToProto(node))
+	}
+	return res
+}
+
+// queryNodeFromProto converts the protobuf representation of a structured
+// query tree into an index.QueryNode.
+func queryNodeFromProto(node *proto.QueryNode) (index.QueryNode, error) {
+	switch n := node.GetNode().(type) {
+	case *proto.QueryNode_Term:
+		return &index.TermNode{
+			Field:  index.Field(n.Term.GetField()),
+			Text:   n.Term.GetText(),
+			Phrase: n.Term.GetPhrase(),
+		}, nil
+	case *proto.QueryNode_Site:
+		return &index.SiteNode{Host: n.Site.GetHost()}, nil
+	case *proto.QueryNode_DateRange:
+		from, err := optionalTimeFromProto(n.DateRange.GetFrom())
+		if err != nil {
+			return nil, err
+		}
+		to, err := optionalTimeFromProto(n.DateRange.GetTo())
+		if err != nil {
+			return nil, err
+		}
+		return &index.DateRangeNode{From: from, To: to}, nil
+	case *proto.QueryNode_Bool:
+		var (
+			res index.BoolNode
+			err error
+		)
+		if res.Must, err = queryNodeListFromProto(n.Bool.GetMust()); err != nil {
+			return nil, err
+		}
+		if res.Should, err = queryNodeListFromProto(n.Bool.GetShould()); err != nil {
+			return nil, err
+		}
+		if res.MustNot, err = queryNodeListFromProto(n.Bool.GetMustNot()); err != nil {
+			return nil, err
+		}
+		return &res, nil
+	default:
+		return nil, xerrors.Errorf("unsupported query node type %T", n)
+	}
+}
+
+func queryNodeListFromProto(list []*proto.QueryNode) ([]index.QueryNode, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	res := make([]index.QueryNode, 0, len(list))
+	for _, node := range list {
+		converted, err := queryNodeFromProto(node)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, converted)
+	}
+	return res, nil
+}
+
+func optionalTimeToProto(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timeToProto(t)
+}
+
+func optionalTimeFromProto(ts *timestamp.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("unable to decode date range bound: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,44 @@
+package textindexerapi
+
+import (
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi/proto"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/xerrors"
+)
+
+func rankingToProto(r *index.Ranking) *proto.Ranking {
+	if r == nil {
+		return nil
+	}
+
+	return &proto.Ranking{
+		RelevanceWeight:   r.RelevanceWeight,
+		PageRankWeight:    r.PageRankWeight,
+		TitleBoost:        r.TitleBoost,
+		FreshnessHalfLife: ptypes.DurationProto(r.FreshnessHalfLife),
+	}
+}
+
+func rankingFromProto(r *proto.Ranking) (*index.Ranking, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	var halfLife time.Duration
+	if r.FreshnessHalfLife != nil {
+		var err error
+		if halfLife, err = ptypes.Duration(r.FreshnessHalfLife); err != nil {
+			return nil, xerrors.Errorf("unable to decode freshness half-life of ranking model: %w", err)
+		}
+	}
+
+	return &index.Ranking{
+		RelevanceWeight:   r.RelevanceWeight,
+		PageRankWeight:    r.PageRankWeight,
+		TitleBoost:        r.TitleBoost,
+		FreshnessHalfLife: halfLife,
+	}, nil
+}
@@ -29,10 +29,13 @@ func NewTextIndexerServer(i index.Indexer) *TextIndexerServer {
 // and existing document.
 func (s *TextIndexerServer) Index(_ context.Context, req *proto.Document) (*proto.Document, error) {
 	doc := &index.Document{
-		LinkID:  uuidFromBytes(req.LinkId),
-		URL:     req.Url,
-		Title:   req.Title,
-		Content: req.Content,
+		LinkID:      uuidFromBytes(req.LinkId),
+		URL:         req.Url,
+		Title:       req.Title,
+		Content:     req.Content,
+		Description: req.Description,
+		Headings:    req.Headings,
+		Language:    req.Language,
 	}
 
 	err := s.i.Index(doc)
@@ -51,15 +54,85 @@ func (s *TextIndexerServer) UpdateScore(_ context.Context, req *proto.UpdateScor
 	return new(empty.Empty), s.i.UpdateScore(linkID, req.PageRankScore)
 }
 
+// AddAnchorText records the text of a link pointing to the document with the
+// specified link ID.
+func (s *TextIndexerServer) AddAnchorText(_ context.Context, req *proto.AddAnchorTextRequest) (*empty.Empty, error) {
+	linkID := uuidFromBytes(req.LinkId)
+	return new(empty.Empty), s.i.AddAnchorText(linkID, req.Text)
+}
+
+// AddAnchorTexts records the texts of links pointing to the documents with the
+// specified link IDs in a single batch.
+func (s *TextIndexerServer) AddAnchorTexts(_ context.Context, req *proto.AddAnchorTextsRequest) (*empty.Empty, error) {
+	texts := make(map[uuid.UUID][]string, len(req.AnchorTexts))
+	for _, entry := range req.AnchorTexts {
+		linkID := uuidFromBytes(entry.LinkId)
+		texts[linkID] = append(texts[linkID], entry.Texts...)
+	}
+	return new(empty.Empty), s.i.AddAnchorTexts(texts)
+}
+
+// Delete removes the document with the specified link ID from the index.
+func (s *TextIndexerServer) Delete(_ context.Context, req *proto.DeleteRequest) (*empty.Empty, error) {
+	linkID := uuidFromBytes(req.LinkId)
+	return new(empty.Empty), s.i.Delete(linkID)
+}
+
+// DeleteOlderThan removes all documents that were last indexed before the
+// specified timestamp.
+func (s *TextIndexerServer) DeleteOlderThan(_ context.Context, req *proto.DeleteOlderThanRequest) (*empty.Empty, error) {
+	indexedBefore, err := ptypes.Timestamp(req.IndexedBefore)
+	if err != nil {
+		return nil, err
+	}
+	return new(empty.Empty), s.i.DeleteOlderThan(indexedBefore)
+}
+
+// Suggest returns alternative spellings for the terms of a search expression
+// that are not present in the index.
+func (s *TextIndexerServer) Suggest(_ context.Context, req *proto.SuggestRequest) (*proto.SuggestResponse, error) {
+	suggestions, err := s.i.Suggest(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.SuggestResponse{Suggestions: suggestions}, nil
+}
+
+// Complete returns completions for a partially typed search expression.
+func (s *TextIndexerServer) Complete(_ context.Context, req *proto.CompleteRequest) (*proto.CompleteResponse, error) {
+	completions, err := s.i.Complete(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.CompleteResponse{Completions: completions}, nil
+}
+
 // Search the index for a particular query and stream the results back to the
-// client. The first response will include the total result count while all
-// subsequent responses will include documents from the resultset.
+// client. The first response will include the total result count. If the
+// query requests any facets, the second response will include the facet
+// counts. All subsequent responses will include documents from the resultset.
 func (s *TextIndexerServer) Search(req *proto.Query, w proto.TextIndexer_SearchServer) error {
 	query := index.Query{
 		Type:       index.QueryType(req.Type),
 		Expression: req.Expression,
 		Offset:     req.Offset,
+		Cursor:     req.Cursor,
+		Backward:   req.Backward,
+		Facets:     facetRequestsFromProto(req.Facets),
+		Language:   languagePreferenceFromProto(req.Language),
+	}
+	if req.Tree != nil {
+		tree, err := queryNodeFromProto(req.Tree)
+		if err != nil {
+			return err
+		}
+		query.Tree = tree
 	}
+	ranking, err := rankingFromProto(req.Ranking)
+	if err != nil {
+		return err
+	}
+	query.Ranking = ranking
 
 	it, err := s.i.Search(query)
 	if err != nil {
@@ -75,19 +148,35 @@ func (s *TextIndexerServer) Search(req *proto.Query, w proto.TextIndexer_SearchS
 		return err
 	}
 
+	// Send back the facet counts
+	if len(query.Facets) != 0 {
+		facetsRes := &proto.QueryResult{
+			Result: &proto.QueryResult_Facets{Facets: facetResultsToProto(it.Facets())},
+		}
+		if err = w.Send(facetsRes); err != nil {
+			_ = it.Close()
+			return err
+		}
+	}
+
 	// Start streaming
 	for it.Next() {
 		doc := it.Document()
 		res := proto.QueryResult{
 			Result: &proto.QueryResult_Doc{
 				Doc: &proto.Document{
-					LinkId:    doc.LinkID[:],
-					Url:       doc.URL,
-					Title:     doc.Title,
-					Content:   doc.Content,
-					IndexedAt: timeToProto(doc.IndexedAt),
+					LinkId:      doc.LinkID[:],
+					Url:         doc.URL,
+					Title:       doc.Title,
+					Content:     doc.Content,
+					Description: doc.Description,
+					Headings:    doc.Headings,
+					AnchorText:  doc.AnchorText,
+					Language:    doc.Language,
+					IndexedAt:   timeToProto(doc.IndexedAt),
 				},
 			},
+			Cursor: it.Cursor(),
 		}
 		if err = w.SendMsg(&res); err != nil {
 			_ = it.Close()
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"time"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/memory"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/test/bufconn"
@@ -65,10 +67,12 @@ func (s *ServerTestSuite) TearDownTest(c *gc.C) {
 func (s *ServerTestSuite) TestIndex(c *gc.C) {
 	linkID := uuid.New()
 	doc := &proto.Document{
-		LinkId:  linkID[:],
-		Url:     "http://example.com",
-		Title:   "Test",
-		Content: "Lorem Ipsum",
+		LinkId:      linkID[:],
+		Url:         "http://example.com",
+		Title:       "Test",
+		Content:     "Lorem Ipsum",
+		Description: "Dolor sit amet",
+		Headings:    []string{"Consectetur"},
 	}
 	res, err := s.cli.Index(context.TODO(), doc)
 	c.Assert(err, gc.IsNil)
@@ -82,6 +86,8 @@ func (s *ServerTestSuite) TestIndex(c *gc.C) {
 	c.Assert(indexedDoc.URL, gc.Equals, doc.Url)
 	c.Assert(indexedDoc.Title, gc.Equals, doc.Title)
 	c.Assert(indexedDoc.Content, gc.Equals, doc.Content)
+	c.Assert(indexedDoc.Description, gc.Equals, doc.Description)
+	c.Assert(indexedDoc.Headings, gc.DeepEquals, doc.Headings)
 	c.Assert(indexedDoc.IndexedAt.Unix(), gc.Not(gc.Equals), 0)
 }
 
@@ -143,6 +149,83 @@ func (s *ServerTestSuite) TestUpdateScore(c *gc.C) {
 	c.Assert(indexedDoc.PageRank, gc.Equals, 0.5)
 }
 
+func (s *ServerTestSuite) TestAddAnchorText(c *gc.C) {
+	linkID := uuid.New()
+	req := &proto.AddAnchorTextRequest{
+		LinkId: linkID[:],
+		Text:   "Lorem ipsum",
+	}
+	_, err := s.cli.AddAnchorText(context.TODO(), req)
+	c.Assert(err, gc.IsNil)
+
+	indexedDoc, err := s.i.FindByID(linkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(indexedDoc.AnchorText, gc.DeepEquals, []string{"Lorem ipsum"})
+}
+
+func (s *ServerTestSuite) TestAddAnchorTexts(c *gc.C) {
+	linkID1, linkID2 := uuid.New(), uuid.New()
+	req := &proto.AddAnchorTextsRequest{
+		AnchorTexts: []*proto.AnchorTexts{
+			{LinkId: linkID1[:], Texts: []string{"Lorem ipsum", "dolor"}},
+			{LinkId: linkID2[:], Texts: []string{"sit amet"}},
+		},
+	}
+	_, err := s.cli.AddAnchorTexts(context.TODO(), req)
+	c.Assert(err, gc.IsNil)
+
+	indexedDoc, err := s.i.FindByID(linkID1)
+	c.Assert(err, gc.IsNil)
+	c.Assert(indexedDoc.AnchorText, gc.DeepEquals, []string{"Lorem ipsum", "dolor"})
+	indexedDoc, err = s.i.FindByID(linkID2)
+	c.Assert(err, gc.IsNil)
+	c.Assert(indexedDoc.AnchorText, gc.DeepEquals, []string{"sit amet"})
+}
+
+func (s *ServerTestSuite) TestDelete(c *gc.C) {
+	idList := s.indexDocs(c, 2)
+
+	_, err := s.cli.Delete(context.TODO(), &proto.DeleteRequest{LinkId: idList[0][:]})
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.i.FindByID(idList[0])
+	c.Assert(err, gc.ErrorMatches, ".*not found")
+	_, err = s.i.FindByID(idList[1])
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *ServerTestSuite) TestDeleteOlderThan(c *gc.C) {
+	idList := s.indexDocs(c, 2)
+
+	indexedBefore, err := ptypes.TimestampProto(time.Now().Add(time.Minute))
+	c.Assert(err, gc.IsNil)
+	_, err = s.cli.DeleteOlderThan(context.TODO(), &proto.DeleteOlderThanRequest{IndexedBefore: indexedBefore})
+	c.Assert(err, gc.IsNil)
+
+	for _, linkID := range idList {
+		_, err = s.i.FindByID(linkID)
+		c.Assert(err, gc.ErrorMatches, ".*not found")
+	}
+}
+
+func (s *ServerTestSuite) TestSuggest(c *gc.C) {
+	_ = s.indexDocs(c, 1)
+
+	res, err := s.cli.Suggest(context.TODO(), &proto.SuggestRequest{Expression: "Tset"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.Suggestions, gc.Not(gc.HasLen), 0)
+	c.Assert(res.Suggestions[0], gc.Equals, "test")
+}
+
+func (s *ServerTestSuite) TestComplete(c *gc.C) {
+	_ = s.indexDocs(c, 1)
+
+	res, err := s.cli.Complete(context.TODO(), &proto.CompleteRequest{Expression: "Te"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.Completions, gc.Not(gc.HasLen), 0)
+	c.Assert(res.Completions[0], gc.Equals, "test")
+}
+
 func (s *ServerTestSuite) TestSearch(c *gc.C) {
 	idList := s.indexDocs(c, 100)
 
@@ -168,6 +251,31 @@ func (s *ServerTestSuite) TestSearchWithOffset(c *gc.C) {
 	s.assertSearchResultsMatchList(c, stream, 100, idList[50:])
 }
 
+func (s *ServerTestSuite) TestSearchWithCursor(c *gc.C) {
+	idList := s.indexDocs(c, 100)
+
+	stream, err := s.cli.Search(context.TODO(), &proto.Query{
+		Type:       proto.Query_MATCH,
+		Expression: "Test",
+		Offset:     49,
+	})
+	c.Assert(err, gc.IsNil)
+	_, err = stream.Recv()
+	c.Assert(err, gc.IsNil)
+	res, err := stream.Recv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.Cursor, gc.Not(gc.Equals), "")
+
+	stream, err = s.cli.Search(context.TODO(), &proto.Query{
+		Type:       proto.Query_MATCH,
+		Expression: "Test",
+		Cursor:     res.Cursor,
+	})
+	c.Assert(err, gc.IsNil)
+
+	s.assertSearchResultsMatchList(c, stream, 100, idList[50:])
+}
+
 func (s *ServerTestSuite) TestSearchWithOffsetAfterEndOfResultset(c *gc.C) {
 	_ = s.indexDocs(c, 100)
 
@@ -181,6 +289,115 @@ func (s *ServerTestSuite) TestSearchWithOffsetAfterEndOfResultset(c *gc.C) {
 	s.assertSearchResultsMatchList(c, stream, 100, nil)
 }
 
+func (s *ServerTestSuite) TestSearchWithQueryTree(c *gc.C) {
+	idList := s.indexDocs(c, 10)
+
+	stream, err := s.cli.Search(context.TODO(), &proto.Query{
+		Tree: &proto.QueryNode{Node: &proto.QueryNode_Bool{Bool: &proto.BoolNode{
+			Must: []*proto.QueryNode{
+				{Node: &proto.QueryNode_Site{Site: &proto.SiteNode{Host: "example.com"}}},
+				{Node: &proto.QueryNode_DateRange{DateRange: &proto.DateRangeNode{
+					From: mustEncodeTimestamp(c, time.Now().Add(-time.Hour)),
+				}}},
+			},
+			MustNot: []*proto.QueryNode{
+				{Node: &proto.QueryNode_Term{Term: &proto.TermNode{Field: proto.TermNode_TITLE, Text: "5"}}},
+			},
+		}}},
+	})
+	c.Assert(err, gc.IsNil)
+
+	expIDList := append(append([]uuid.UUID(nil), idList[:5]...), idList[6:]...)
+	s.assertSearchResultsMatchList(c, stream, 9, expIDList)
+}
+
+func (s *ServerTestSuite) TestSearchWithFacets(c *gc.C) {
+	_ = s.indexDocs(c, 10)
+
+	stream, err := s.cli.Search(context.TODO(), &proto.Query{
+		Type:       proto.Query_MATCH,
+		Expression: "Test",
+		Facets: []*proto.FacetRequest{
+			{Name: "sites", Type: proto.FacetRequest_HOST},
+		},
+	})
+	c.Assert(err, gc.IsNil)
+
+	next, err := stream.Recv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(next.GetDocCount(), gc.Equals, uint64(10))
+
+	// Second message should contain the facet counts
+	next, err = stream.Recv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(next.GetFacets(), gc.DeepEquals, &proto.FacetResults{
+		Facets: []*proto.FacetResult{
+			{
+				Name:    "sites",
+				Buckets: []*proto.FacetBucket{{Key: "example.com", Count: 10}},
+			},
+		},
+	})
+
+	var docCount int
+	for {
+		next, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, gc.IsNil)
+		c.Assert(next.GetDoc(), gc.NotNil)
+		docCount++
+	}
+	c.Assert(docCount, gc.Equals, 10)
+}
+
+func (s *ServerTestSuite) TestSearchWithRanking(c *gc.C) {
+	idList := s.indexDocs(c, 10)
+
+	// Invert the PageRank weight so that results are sorted in reverse
+	// order.
+	stream, err := s.cli.Search(context.TODO(), &proto.Query{
+		Type:       proto.Query_MATCH,
+		Expression: "Test",
+		Ranking:    &proto.Ranking{PageRankWeight: -1},
+	})
+	c.Assert(err, gc.IsNil)
+
+	for left, right := 0, len(idList)-1; left < right; left, right = left+1, right-1 {
+		idList[left], idList[right] = idList[right], idList[left]
+	}
+	s.assertSearchResultsMatchList(c, stream, 10, idList)
+}
+
+func (s *ServerTestSuite) TestSearchWithLanguage(c *gc.C) {
+	linkIDs := [2]uuid.UUID{uuid.New(), uuid.New()}
+	for i, lang := range []string{"de", "en"} {
+		_, err := s.cli.Index(context.TODO(), &proto.Document{
+			LinkId:   linkIDs[i][:],
+			Url:      fmt.Sprintf("http://example.com/%s", lang),
+			Title:    "Test",
+			Language: lang,
+		})
+		c.Assert(err, gc.IsNil)
+	}
+
+	stream, err := s.cli.Search(context.TODO(), &proto.Query{
+		Type:       proto.Query_MATCH,
+		Expression: "Test",
+		Language:   &proto.LanguagePreference{Language: "de"},
+	})
+	c.Assert(err, gc.IsNil)
+
+	next, err := stream.Recv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(next.GetDocCount(), gc.Equals, uint64(1))
+	next, err = stream.Recv()
+	c.Assert(err, gc.IsNil)
+	c.Assert(next.GetDoc().LinkId, gc.DeepEquals, linkIDs[0][:])
+	c.Assert(next.GetDoc().Language, gc.Equals, "de")
+}
+
 func (s *ServerTestSuite) assertSearchResultsMatchList(c *gc.C, stream proto.TextIndexer_SearchClient, expTotalCount int, expIDList []uuid.UUID) {
 	// First message should be the result count
 	next, err := stream.Recv()
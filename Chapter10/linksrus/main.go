@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"io"
 	"net/url"
 	"os"
 	"os/signal"
@@ -11,18 +12,24 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/canonical"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/store/bolt"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/store/cdb"
 	memgraph "github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/store/memory"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	diskindex "github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/disk"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/es"
 	memindex "github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/memory"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/partition"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/service"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/service/crawler"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/service/frontend"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/service/metrics"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/service/pagerank"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
 )
@@ -49,10 +56,15 @@ func main() {
 }
 
 func runMain(logger *logrus.Entry) error {
-	svcGroup, err := setupServices(logger)
+	svcGroup, closers, err := setupServices(logger)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		for _, closer := range closers {
+			_ = closer.Close()
+		}
+	}()
 
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
@@ -71,11 +83,14 @@ func runMain(logger *logrus.Entry) error {
 	return svcGroup.Run(ctx)
 }
 
-func setupServices(logger *logrus.Entry) (service.Group, error) {
+// setupServices creates the services for the monolith and returns them
+// together with a list of resources that must be closed on shutdown.
+func setupServices(logger *logrus.Entry) (service.Group, []io.Closer, error) {
 	var (
 		frontendCfg frontend.Config
 		crawlerCfg  crawler.Config
 		pageRankCfg pagerank.Config
+		metricsCfg  metrics.Config
 	)
 
 	flag.StringVar(&frontendCfg.ListenAddr, "frontend-listen-addr", ":8080", "The address to listen for incoming front-end requests")
@@ -85,12 +100,22 @@ func setupServices(logger *logrus.Entry) (service.Group, error) {
 	flag.IntVar(&crawlerCfg.FetchWorkers, "crawler-num-workers", runtime.NumCPU(), "The number of workers to use for crawling web-pages (defaults to number of CPUs)")
 	flag.DurationVar(&crawlerCfg.UpdateInterval, "crawler-update-interval", 5*time.Minute, "The time between subsequent crawler runs")
 	flag.DurationVar(&crawlerCfg.ReIndexThreshold, "crawler-reindex-threshold", 7*24*time.Hour, "The minimum amount of time before re-indexing an already-crawled link")
+	flag.DurationVar(&crawlerCfg.FailureBackoff, "crawler-failure-backoff", 24*time.Hour, "The delay before retrying a link that could not be retrieved; doubles with each consecutive failure")
+	flag.IntVar(&crawlerCfg.MaxHostConns, "crawler-max-host-conns", 2, "The maximum number of concurrent requests to the same host; set to 0 to disable per-host politeness limits")
+	flag.DurationVar(&crawlerCfg.MinHostDelay, "crawler-min-host-delay", time.Second, "The minimum time between the start of two requests to the same host")
+	flag.DurationVar(&crawlerCfg.MaxCrawlDelay, "crawler-max-crawl-delay", 30*time.Second, "The maximum robots.txt Crawl-delay value to honor; set to 0 to ignore robots.txt files")
+	flag.StringVar(&crawlerCfg.RobotsUserAgent, "crawler-robots-user-agent", "", "The user agent to match against the User-agent lines of robots.txt files; leave empty to only honor the rules for all user agents")
+	crawlerCheckpointDir := flag.String("crawler-checkpoint-dir", "", "A directory for persisting the progress of crawler passes so they can be resumed after a restart; leave empty to disable")
 
 	flag.IntVar(&pageRankCfg.ComputeWorkers, "pagerank-num-workers", runtime.NumCPU(), "The number of workers to use for calculating PageRank scores (defaults to number of CPUs)")
 	flag.DurationVar(&pageRankCfg.UpdateInterval, "pagerank-update-interval", time.Hour, "The time between subsequent PageRank score updates")
 
-	linkGraphURI := flag.String("link-graph-uri", "in-memory://", "The URI for connecting to the link-graph (supported URIs: in-memory://, postgresql://user@host:26257/linkgraph?sslmode=disable)")
-	textIndexerURI := flag.String("text-indexer-uri", "in-memory://", "The URI for connecting to the text indexer (supported URIs: in-memory://, es://node1:9200,...,nodeN:9200)")
+	flag.StringVar(&metricsCfg.ListenAddr, "metrics-listen-addr", ":9090", "The address to listen for incoming prometheus metric scrape requests")
+
+	linkGraphURI := flag.String("link-graph-uri", "in-memory://", "The URI for connecting to the link-graph (supported URIs: in-memory://, bolt:///path/to/data/dir, postgresql://user@host:26257/linkgraph?sslmode=disable)")
+	textIndexerURI := flag.String("text-indexer-uri", "in-memory://", "The URI for connecting to the text indexer (supported URIs: in-memory://, bleve:///path/to/index/dir, es://node1:9200,...,nodeN:9200)")
+
+	urlStripParams := flag.String("url-strip-params", strings.Join(canonical.DefaultTrackingParams, ","), "A comma-separated list of query parameters to strip from submitted and crawled URLs; entries ending in '*' are treated as prefixes")
 
 	partitionDetMode := flag.String("partition-detection-mode", "single", "The partition detection mode to use. Supported values are 'dns=HEADLESS_SERVICE_NAME' (k8s) and 'single' (local dev mode)")
 	flag.Parse()
@@ -99,19 +124,34 @@ func setupServices(logger *logrus.Entry) (service.Group, error) {
 	// plug it into the service configurations.
 	linkGraph, err := getLinkGraph(*linkGraphURI, logger)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	textIndexer, err := getTextIndexer(*textIndexerURI, logger)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Ensure that any store that persists its data locally gets a chance
+	// to flush it when the monolith shuts down.
+	var closers []io.Closer
+	for _, store := range []interface{}{linkGraph, textIndexer} {
+		if closer, ok := store.(io.Closer); ok {
+			closers = append(closers, closer)
+		}
 	}
 
 	// Create a helper for detecting the partition assigned to this instance.
 	partDet, err := getPartitionDetector(*partitionDetMode)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	// Use the same URL canonicalization rules for both submitted and
+	// crawled links.
+	urlCanonicalizer := canonical.NewURLCanonicalizer(strings.Split(*urlStripParams, ",")...)
+	frontendCfg.URLCanonicalizer = urlCanonicalizer
+	crawlerCfg.URLCanonicalizer = urlCanonicalizer
+
 	var svc service.Service
 	var svcGroup service.Group
 
@@ -121,17 +161,23 @@ func setupServices(logger *logrus.Entry) (service.Group, error) {
 	if svc, err = frontend.NewService(frontendCfg); err == nil {
 		svcGroup = append(svcGroup, svc)
 	} else {
-		return nil, err
+		return nil, nil, err
 	}
 
 	crawlerCfg.GraphAPI = linkGraph
 	crawlerCfg.IndexAPI = textIndexer
 	crawlerCfg.PartitionDetector = partDet
+	crawlerCfg.MetricsRegisterer = prometheus.DefaultRegisterer
+	if *crawlerCheckpointDir != "" {
+		if crawlerCfg.Checkpoints, err = pipeline.NewFileCheckpointStore(*crawlerCheckpointDir); err != nil {
+			return nil, nil, err
+		}
+	}
 	crawlerCfg.Logger = logger.WithField("service", "crawler")
 	if svc, err = crawler.NewService(crawlerCfg); err == nil {
 		svcGroup = append(svcGroup, svc)
 	} else {
-		return nil, err
+		return nil, nil, err
 	}
 
 	pageRankCfg.GraphAPI = linkGraph
@@ -141,15 +187,25 @@ func setupServices(logger *logrus.Entry) (service.Group, error) {
 	if svc, err = pagerank.NewService(pageRankCfg); err == nil {
 		svcGroup = append(svcGroup, svc)
 	} else {
-		return nil, err
+		return nil, nil, err
+	}
+
+	metricsCfg.Gatherer = prometheus.DefaultGatherer
+	metricsCfg.Logger = logger.WithField("service", "metrics")
+	if svc, err = metrics.NewService(metricsCfg); err == nil {
+		svcGroup = append(svcGroup, svc)
+	} else {
+		return nil, nil, err
 	}
 
-	return svcGroup, nil
+	return svcGroup, closers, nil
 }
 
 type linkGraph interface {
 	UpsertLink(link *graph.Link) error
+	UpsertLinks(links []*graph.Link) error
 	UpsertEdge(edge *graph.Edge) error
+	UpsertEdges(edges []*graph.Edge) error
 	RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error
 	Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
 	Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error)
@@ -169,6 +225,12 @@ func getLinkGraph(linkGraphURI string, logger *logrus.Entry) (linkGraph, error)
 	case "in-memory":
 		logger.Info("using in-memory graph")
 		return memgraph.NewInMemoryGraph(), nil
+	case "bolt":
+		if uri.Path == "" {
+			return nil, xerrors.Errorf("bolt link graph URI must specify a data directory path")
+		}
+		logger.WithField("path", uri.Path).Info("using bolt graph")
+		return bolt.NewBoltGraph(uri.Path)
 	case "postgresql":
 		logger.Info("using CDB graph")
 		return cdb.NewCockroachDbGraph(linkGraphURI)
@@ -180,7 +242,11 @@ func getLinkGraph(linkGraphURI string, logger *logrus.Entry) (linkGraph, error)
 type textIndexer interface {
 	Index(text *index.Document) error
 	UpdateScore(linkID uuid.UUID, score float64) error
+	AddAnchorTexts(texts map[uuid.UUID][]string) error
+	Delete(linkID uuid.UUID) error
 	Search(query index.Query) (index.Iterator, error)
+	Suggest(expression string) ([]string, error)
+	Complete(expression string) ([]string, error)
 }
 
 func getTextIndexer(textIndexerURI string, logger *logrus.Entry) (textIndexer, error) {
@@ -197,6 +263,12 @@ func getTextIndexer(textIndexerURI string, logger *logrus.Entry) (textIndexer, e
 	case "in-memory":
 		logger.Info("using in-memory indexer")
 		return memindex.NewInMemoryBleveIndexer()
+	case "bleve":
+		if uri.Path == "" {
+			return nil, xerrors.Errorf("bleve text indexer URI must specify an index directory path")
+		}
+		logger.WithField("path", uri.Path).Info("using on-disk bleve indexer")
+		return diskindex.NewOnDiskBleveIndexer(uri.Path)
 	case "es":
 		nodes := strings.Split(uri.Host, ",")
 		for i := 0; i < len(nodes); i++ {
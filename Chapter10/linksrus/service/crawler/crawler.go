@@ -2,6 +2,7 @@ package crawler
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"time"
@@ -10,10 +11,13 @@ import (
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
 	crawler_pipeline "github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/crawler"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/crawler/privnet"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline/metrics"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/partition"
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
 	"github.com/juju/clock"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
 )
@@ -24,7 +28,9 @@ import (
 // GraphAPI defines as set of API methods for accessing the link graph.
 type GraphAPI interface {
 	UpsertLink(link *graph.Link) error
+	UpsertLinks(links []*graph.Link) error
 	UpsertEdge(edge *graph.Edge) error
+	UpsertEdges(edges []*graph.Edge) error
 	RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error
 	Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
 }
@@ -32,6 +38,8 @@ type GraphAPI interface {
 // IndexAPI defines a set of API methods for indexing crawled documents.
 type IndexAPI interface {
 	Index(doc *index.Document) error
+	Delete(linkID uuid.UUID) error
+	AddAnchorTexts(texts map[uuid.UUID][]string) error
 }
 
 // Config encapsulates the settings for configuring the web-crawler service.
@@ -51,6 +59,11 @@ type Config struct {
 	// http.DefaultClient will be used instead.
 	URLGetter crawler_pipeline.URLGetter
 
+	// An API for converting extracted links into a canonical form. If not
+	// specified, a canonicalizer that strips the default set of tracking
+	// query parameters will be used instead.
+	URLCanonicalizer crawler_pipeline.URLCanonicalizer
+
 	// An API for detecting the partition assignments for this service.
 	PartitionDetector partition.Detector
 
@@ -61,12 +74,42 @@ type Config struct {
 	// The number of concurrent workers used for retrieving links.
 	FetchWorkers int
 
+	// The delay before retrying a link that could not be retrieved. The
+	// delay doubles with each consecutive failure. If zero, failed links
+	// are retried on every pass.
+	FailureBackoff time.Duration
+
+	// The maximum number of concurrent requests to the same host. If zero,
+	// no per-host limits are enforced.
+	MaxHostConns int
+
+	// The minimum time between the start of two requests to the same host.
+	MinHostDelay time.Duration
+
+	// The maximum robots.txt Crawl-delay value that will be honored. If
+	// zero, robots.txt files are not consulted.
+	MaxCrawlDelay time.Duration
+
+	// The user agent to match against the User-agent lines of robots.txt
+	// files. If not specified, only the rules for all user agents are
+	// considered.
+	RobotsUserAgent string
+
 	// The time between subsequent crawler passes.
 	UpdateInterval time.Duration
 
 	// The minimum amount of time before re-indexing an already-crawled link.
 	ReIndexThreshold time.Duration
 
+	// A registry for exporting per-stage metrics for the crawler pipeline.
+	// If not specified, no metrics will be collected.
+	MetricsRegisterer prometheus.Registerer
+
+	// A store for persisting the progress of each crawler pass so that a
+	// pass interrupted by a service restart resumes where it left off. If
+	// not specified, interrupted passes start from the beginning.
+	Checkpoints pipeline.CheckpointStore
+
 	// The logger to use. If not defined an output-discarding logger will
 	// be used instead.
 	Logger *logrus.Entry
@@ -95,6 +138,9 @@ func (cfg *Config) validate() error {
 	if cfg.FetchWorkers <= 0 {
 		err = multierror.Append(err, xerrors.Errorf("invalid value for fetch workers"))
 	}
+	if cfg.MaxHostConns < 0 {
+		err = multierror.Append(err, xerrors.Errorf("invalid value for max host connections"))
+	}
 	if cfg.UpdateInterval == 0 {
 		err = multierror.Append(err, xerrors.Errorf("invalid value for update interval"))
 	}
@@ -119,6 +165,15 @@ func NewService(cfg Config) (*Service, error) {
 		return nil, xerrors.Errorf("crawler service: config validation failed: %w", err)
 	}
 
+	var obs pipeline.Observer
+	if cfg.MetricsRegisterer != nil {
+		promObs, err := metrics.NewPrometheusObserver(cfg.MetricsRegisterer, "crawler", crawler_pipeline.PipelineStageNames()...)
+		if err != nil {
+			return nil, xerrors.Errorf("crawler service: %w", err)
+		}
+		obs = promObs
+	}
+
 	return &Service{
 		cfg: cfg,
 		crawler: crawler_pipeline.NewCrawler(crawler_pipeline.Config{
@@ -127,6 +182,15 @@ func NewService(cfg Config) (*Service, error) {
 			Graph:                  cfg.GraphAPI,
 			Indexer:                cfg.IndexAPI,
 			FetchWorkers:           cfg.FetchWorkers,
+			FailureBackoff:         cfg.FailureBackoff,
+			MaxHostConns:           cfg.MaxHostConns,
+			MinHostDelay:           cfg.MinHostDelay,
+			MaxCrawlDelay:          cfg.MaxCrawlDelay,
+			RobotsUserAgent:        cfg.RobotsUserAgent,
+			URLCanonicalizer:       cfg.URLCanonicalizer,
+			Observer:               obs,
+			Checkpoints:            cfg.Checkpoints,
+			Logger:                 cfg.Logger,
 		}),
 	}, nil
 }
@@ -181,7 +245,10 @@ func (svc *Service) crawlGraph(ctx context.Context, curPartition, numPartitions
 		return xerrors.Errorf("crawler: unable to retrieve links iterator: %w", err)
 	}
 
-	processed, err := svc.crawler.Crawl(ctx, linkIt)
+	// Partition assignments may change between restarts so the checkpoint
+	// key must identify both the partition and the number of partitions.
+	checkpointKey := fmt.Sprintf("partition-%d-of-%d", curPartition, numPartitions)
+	processed, err := svc.crawler.CrawlWithCheckpoint(ctx, checkpointKey, linkIt)
 	if err != nil {
 		return xerrors.Errorf("crawler: unable to complete crawling the link graph: %w", err)
 	} else if err = linkIt.Close(); err != nil {
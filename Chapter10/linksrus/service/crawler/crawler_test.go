@@ -5,11 +5,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter07/pipeline"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/partition"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/service/crawler/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/juju/clock/testclock"
+	"github.com/prometheus/client_golang/prometheus"
 	gc "gopkg.in/check.v1"
 )
 
@@ -54,6 +56,10 @@ func (s *ConfigTestSuite) TestConfigValidation(c *gc.C) {
 	cfg.FetchWorkers = 0
 	c.Assert(cfg.validate(), gc.ErrorMatches, "(?ms).*invalid value for fetch workers.*")
 
+	cfg = origCfg
+	cfg.MaxHostConns = -1
+	c.Assert(cfg.validate(), gc.ErrorMatches, "(?ms).*invalid value for max host connections.*")
+
 	cfg = origCfg
 	cfg.UpdateInterval = 0
 	c.Assert(cfg.validate(), gc.ErrorMatches, "(?ms).*invalid value for update interval.*")
@@ -117,6 +123,63 @@ func (s *CrawlerTestSuite) TestFullRun(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *CrawlerTestSuite) TestPipelineMetricsRegistration(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	cfg := Config{
+		GraphAPI:          mocks.NewMockGraphAPI(ctrl),
+		IndexAPI:          mocks.NewMockIndexAPI(ctrl),
+		PartitionDetector: partition.Fixed{Partition: 0, NumPartitions: 1},
+		FetchWorkers:      1,
+		UpdateInterval:    time.Minute,
+		ReIndexThreshold:  12 * time.Hour,
+		MetricsRegisterer: prometheus.NewRegistry(),
+	}
+	_, err := NewService(cfg)
+	c.Assert(err, gc.IsNil)
+
+	// Registering the pipeline metrics twice should fail.
+	_, err = NewService(cfg)
+	c.Assert(err, gc.ErrorMatches, "crawler service: register pipeline metrics: .*")
+}
+
+func (s *CrawlerTestSuite) TestCheckpointClearedAfterPass(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	store, err := pipeline.NewFileCheckpointStore(c.MkDir())
+	c.Assert(err, gc.IsNil)
+
+	// Simulate a pass for partition 1 of 2 that was interrupted.
+	c.Assert(store.SaveCheckpoint("partition-1-of-2", uuid.New().String()), gc.IsNil)
+
+	mockGraph := mocks.NewMockGraphAPI(ctrl)
+	cfg := Config{
+		GraphAPI:          mockGraph,
+		IndexAPI:          mocks.NewMockIndexAPI(ctrl),
+		PartitionDetector: partition.Fixed{Partition: 1, NumPartitions: 2},
+		FetchWorkers:      1,
+		UpdateInterval:    time.Minute,
+		ReIndexThreshold:  12 * time.Hour,
+		Checkpoints:       store,
+	}
+	svc, err := NewService(cfg)
+	c.Assert(err, gc.IsNil)
+
+	mockIt := mocks.NewMockLinkIterator(ctrl)
+	mockIt.EXPECT().Next().Return(false)
+	mockIt.EXPECT().Error().Return(nil)
+	mockIt.EXPECT().Close().Return(nil)
+	mockGraph.EXPECT().Links(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockIt, nil)
+
+	c.Assert(svc.crawlGraph(context.TODO(), 1, 2), gc.IsNil)
+
+	pos, err := store.LoadCheckpoint("partition-1-of-2")
+	c.Assert(err, gc.IsNil)
+	c.Assert(pos, gc.Equals, "", gc.Commentf("checkpoint was not cleared after a complete pass"))
+}
+
 func Test(t *testing.T) {
 	// Run all gocheck test-suites
 	gc.TestingT(t)
@@ -79,6 +79,20 @@ func (mr *MockGraphAPIMockRecorder) UpsertEdge(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertEdge", reflect.TypeOf((*MockGraphAPI)(nil).UpsertEdge), arg0)
 }
 
+// UpsertEdges mocks base method
+func (m *MockGraphAPI) UpsertEdges(arg0 []*graph.Edge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertEdges", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertEdges indicates an expected call of UpsertEdges
+func (mr *MockGraphAPIMockRecorder) UpsertEdges(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertEdges", reflect.TypeOf((*MockGraphAPI)(nil).UpsertEdges), arg0)
+}
+
 // UpsertLink mocks base method
 func (m *MockGraphAPI) UpsertLink(arg0 *graph.Link) error {
 	m.ctrl.T.Helper()
@@ -93,6 +107,20 @@ func (mr *MockGraphAPIMockRecorder) UpsertLink(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLink", reflect.TypeOf((*MockGraphAPI)(nil).UpsertLink), arg0)
 }
 
+// UpsertLinks mocks base method
+func (m *MockGraphAPI) UpsertLinks(arg0 []*graph.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLinks", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertLinks indicates an expected call of UpsertLinks
+func (mr *MockGraphAPIMockRecorder) UpsertLinks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLinks", reflect.TypeOf((*MockGraphAPI)(nil).UpsertLinks), arg0)
+}
+
 // MockIndexAPI is a mock of IndexAPI interface
 type MockIndexAPI struct {
 	ctrl     *gomock.Controller
@@ -116,6 +144,34 @@ func (m *MockIndexAPI) EXPECT() *MockIndexAPIMockRecorder {
 	return m.recorder
 }
 
+// AddAnchorTexts mocks base method
+func (m *MockIndexAPI) AddAnchorTexts(arg0 map[uuid.UUID][]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAnchorTexts", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAnchorTexts indicates an expected call of AddAnchorTexts
+func (mr *MockIndexAPIMockRecorder) AddAnchorTexts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAnchorTexts", reflect.TypeOf((*MockIndexAPI)(nil).AddAnchorTexts), arg0)
+}
+
+// Delete mocks base method
+func (m *MockIndexAPI) Delete(arg0 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockIndexAPIMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIndexAPI)(nil).Delete), arg0)
+}
+
 // Index mocks base method
 func (m *MockIndexAPI) Index(arg0 *index.Document) error {
 	m.ctrl.T.Helper()
@@ -2,6 +2,7 @@ package frontend
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
@@ -12,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/canonical"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
 	"github.com/gorilla/mux"
@@ -24,9 +26,10 @@ import (
 //go:generate mockgen -package mocks -destination mocks/mock_indexer.go github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index Iterator
 
 const (
-	indexEndpoint      = "/"
-	searchEndpoint     = "/search"
-	submitLinkEndpoint = "/submit/site"
+	indexEndpoint        = "/"
+	searchEndpoint       = "/search"
+	autocompleteEndpoint = "/autocomplete"
+	submitLinkEndpoint   = "/submit/site"
 
 	defaultResultsPerPage   = 10
 	defaultMaxSummaryLength = 256
@@ -40,6 +43,14 @@ type GraphAPI interface {
 // IndexAPI defines a set of API methods for searching crawled documents.
 type IndexAPI interface {
 	Search(query index.Query) (index.Iterator, error)
+	Suggest(expression string) ([]string, error)
+	Complete(expression string) ([]string, error)
+}
+
+// URLCanonicalizer is implemented by objects that can convert URLs into a
+// canonical form.
+type URLCanonicalizer interface {
+	Canonicalize(rawURL string) (string, error)
 }
 
 // Config encapsulates the settings for configuring the front-end service.
@@ -62,6 +73,11 @@ type Config struct {
 	// instead.
 	MaxSummaryLength int
 
+	// A URLCanonicalizer for converting submitted links into a canonical
+	// form. If not specified, a canonical.URLCanonicalizer that strips the
+	// default set of tracking parameters will be used instead.
+	URLCanonicalizer URLCanonicalizer
+
 	// The logger to use. If not defined an output-discarding logger will
 	// be used instead.
 	Logger *logrus.Entry
@@ -84,6 +100,9 @@ func (cfg *Config) validate() error {
 	if cfg.GraphAPI == nil {
 		err = multierror.Append(err, xerrors.Errorf("graph API has not been provided"))
 	}
+	if cfg.URLCanonicalizer == nil {
+		cfg.URLCanonicalizer = canonical.NewURLCanonicalizer()
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = logrus.NewEntry(&logrus.Logger{Out: ioutil.Discard})
 	}
@@ -115,6 +134,7 @@ func NewService(cfg Config) (*Service, error) {
 
 	svc.router.HandleFunc(indexEndpoint, svc.renderIndexPage).Methods("GET")
 	svc.router.HandleFunc(searchEndpoint, svc.renderSearchResults).Methods("GET")
+	svc.router.HandleFunc(autocompleteEndpoint, svc.autocomplete).Methods("GET")
 	svc.router.HandleFunc(submitLinkEndpoint, svc.submitLink).Methods("GET", "POST")
 	svc.router.NotFoundHandler = http.HandlerFunc(svc.render404Page)
 	return svc, nil
@@ -152,28 +172,31 @@ func (svc *Service) Run(ctx context.Context) error {
 
 func (svc *Service) renderIndexPage(w http.ResponseWriter, _ *http.Request) {
 	_ = svc.tplExecutor(indexPageTemplate, w, map[string]interface{}{
-		"searchEndpoint":     searchEndpoint,
-		"submitLinkEndpoint": submitLinkEndpoint,
+		"searchEndpoint":       searchEndpoint,
+		"autocompleteEndpoint": autocompleteEndpoint,
+		"submitLinkEndpoint":   submitLinkEndpoint,
 	})
 }
 
 func (svc *Service) render404Page(w http.ResponseWriter, _ *http.Request) {
 	_ = svc.tplExecutor(msgPageTemplate, w, map[string]interface{}{
-		"indexEndpoint":  indexEndpoint,
-		"searchEndpoint": searchEndpoint,
-		"messageTitle":   "Page not found",
-		"messageContent": "Page not found.",
+		"indexEndpoint":        indexEndpoint,
+		"searchEndpoint":       searchEndpoint,
+		"autocompleteEndpoint": autocompleteEndpoint,
+		"messageTitle":         "Page not found",
+		"messageContent":       "Page not found.",
 	})
 }
 
 func (svc *Service) renderSearchErrorPage(w http.ResponseWriter, searchTerms string) {
 	w.WriteHeader(http.StatusInternalServerError)
 	_ = svc.tplExecutor(msgPageTemplate, w, map[string]interface{}{
-		"indexEndpoint":  indexEndpoint,
-		"searchEndpoint": searchEndpoint,
-		"searchTerms":    searchTerms,
-		"messageTitle":   "Error",
-		"messageContent": "An error occurred; please try again later.",
+		"indexEndpoint":        indexEndpoint,
+		"searchEndpoint":       searchEndpoint,
+		"autocompleteEndpoint": autocompleteEndpoint,
+		"searchTerms":          searchTerms,
+		"messageTitle":         "Error",
+		"messageContent":       "An error occurred; please try again later.",
 	})
 }
 
@@ -200,8 +223,14 @@ func (svc *Service) submitLink(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		link.Fragment = ""
-		if err = svc.cfg.GraphAPI.UpsertLink(&graph.Link{URL: link.String()}); err != nil {
+		canonicalLink, err := svc.cfg.URLCanonicalizer.Canonicalize(link.String())
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			msg = "Invalid web site URL."
+			return
+		}
+
+		if err = svc.cfg.GraphAPI.UpsertLink(&graph.Link{URL: canonicalLink}); err != nil {
 			svc.cfg.Logger.WithField("err", err).Errorf("could not upsert link into link graph")
 			w.WriteHeader(http.StatusInternalServerError)
 			msg = "An error occurred while adding web site to our index; please try again later."
@@ -217,8 +246,13 @@ func (svc *Service) submitLink(w http.ResponseWriter, r *http.Request) {
 func (svc *Service) renderSearchResults(w http.ResponseWriter, r *http.Request) {
 	searchTerms := r.URL.Query().Get("q")
 	offset, _ := strconv.ParseUint(r.URL.Query().Get("offset"), 10, 64)
+	page := pageRequest{
+		offset:   offset,
+		cursor:   r.URL.Query().Get("cursor"),
+		backward: r.URL.Query().Get("dir") == "prev",
+	}
 
-	matchedDocs, pagination, err := svc.runQuery(searchTerms, offset)
+	matchedDocs, pagination, err := svc.runQuery(searchTerms, page)
 	if err != nil {
 		svc.cfg.Logger.WithField("err", err).Errorf("search query execution failed")
 		svc.renderSearchErrorPage(w, searchTerms)
@@ -227,21 +261,80 @@ func (svc *Service) renderSearchResults(w http.ResponseWriter, r *http.Request)
 
 	// Render results page
 	if err := svc.tplExecutor(resultsPageTemplate, w, map[string]interface{}{
-		"indexEndpoint":  indexEndpoint,
-		"searchEndpoint": searchEndpoint,
-		"searchTerms":    searchTerms,
-		"pagination":     pagination,
-		"results":        matchedDocs,
+		"indexEndpoint":        indexEndpoint,
+		"searchEndpoint":       searchEndpoint,
+		"autocompleteEndpoint": autocompleteEndpoint,
+		"searchTerms":          searchTerms,
+		"pagination":           pagination,
+		"results":              matchedDocs,
+		"suggestion":           svc.spellingSuggestion(searchTerms, matchedDocs),
 	}); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 }
 
-func (svc *Service) runQuery(searchTerms string, offset uint64) ([]matchedDoc, *paginationDetails, error) {
-	var query = index.Query{Type: index.QueryTypeMatch, Expression: searchTerms, Offset: offset}
-	if strings.HasPrefix(searchTerms, `"`) && strings.HasSuffix(searchTerms, `"`) {
-		query.Type = index.QueryTypePhrase
-		searchTerms = strings.Trim(searchTerms, `"`)
+// autocomplete responds with a JSON document containing the completions for
+// a partially typed search expression.
+func (svc *Service) autocomplete(w http.ResponseWriter, r *http.Request) {
+	res := autocompleteResponse{
+		Query:       r.URL.Query().Get("q"),
+		Completions: []string{},
+	}
+
+	if strings.TrimSpace(res.Query) != "" {
+		completions, err := svc.cfg.IndexAPI.Complete(res.Query)
+		if err != nil {
+			svc.cfg.Logger.WithField("err", err).Errorf("could not retrieve search completions")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		res.Completions = append(res.Completions, completions...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// spellingSuggestion returns an alternative spelling for a search query that
+// did not match any documents. It returns nil if the query matched some
+// documents or if no suggestions are available. Errors are logged but
+// otherwise ignored so that they do not prevent the results page from being
+// rendered.
+func (svc *Service) spellingSuggestion(searchTerms string, matchedDocs []matchedDoc) *spellingSuggestion {
+	if len(matchedDocs) != 0 || strings.TrimSpace(searchTerms) == "" {
+		return nil
+	}
+
+	suggestions, err := svc.cfg.IndexAPI.Suggest(searchTerms)
+	if err != nil {
+		svc.cfg.Logger.WithField("err", err).Warnf("could not retrieve spelling suggestions")
+		return nil
+	} else if len(suggestions) == 0 {
+		return nil
+	}
+
+	return &spellingSuggestion{
+		Expression: suggestions[0],
+		Link:       fmt.Sprintf("%s?q=%s", searchEndpoint, url.QueryEscape(suggestions[0])),
+	}
+}
+
+func (svc *Service) runQuery(searchTerms string, page pageRequest) ([]matchedDoc, *paginationDetails, error) {
+	var query = index.Query{
+		Type:       index.QueryTypeMatch,
+		Expression: searchTerms,
+		Offset:     page.offset,
+		Cursor:     page.cursor,
+		Backward:   page.backward,
+	}
+	var highlightTerms = searchTerms
+
+	// Convert the search terms into a structured query. If the terms cannot
+	// be parsed (e.g. due to an unterminated quote) fall back to a plain
+	// keyword search.
+	if tree, err := index.ParseQuery(searchTerms); err == nil {
+		query.Tree = tree
+		highlightTerms = strings.Join(matchedTerms(tree, nil), " ")
 	}
 
 	resultIt, err := svc.cfg.IndexAPI.Search(query)
@@ -251,17 +344,19 @@ func (svc *Service) runQuery(searchTerms string, offset uint64) ([]matchedDoc, *
 	defer func() { _ = resultIt.Close() }()
 
 	// Wrap each result in a matchedDoc shim and generate a short summary which
-	// highlights the matching search terms.
-	summarizer := newMatchSummarizer(searchTerms, svc.cfg.MaxSummaryLength)
-	highlighter := newMatchHighlighter(searchTerms)
+	// highlights the matching search terms. If the meta description of a
+	// result matches the search terms, it is used as the summary instead.
+	summarizer := newMatchSummarizer(highlightTerms, svc.cfg.MaxSummaryLength)
+	highlighter := newMatchHighlighter(highlightTerms)
 	matchedDocs := make([]matchedDoc, 0, svc.cfg.ResultsPerPage)
 	for resCount := 0; resultIt.Next() && resCount < svc.cfg.ResultsPerPage; resCount++ {
 		doc := resultIt.Document()
 		matchedDocs = append(matchedDocs, matchedDoc{
-			doc: doc,
+			doc:    doc,
+			cursor: resultIt.Cursor(),
 			summary: highlighter.Highlight(
 				template.HTMLEscapeString(
-					summarizer.MatchSummary(doc.Content),
+					summarizer.DocumentSummary(doc.Description, doc.Content),
 				),
 			),
 		})
@@ -271,23 +366,90 @@ func (svc *Service) runQuery(searchTerms string, offset uint64) ([]matchedDoc, *
 		return nil, nil, err
 	}
 
-	// Setup paginator and generate prev/next links
+	// When paging backwards the results are returned in reverse order.
+	if page.backward {
+		for i, j := 0, len(matchedDocs)-1; i < j; i, j = i+1, j-1 {
+			matchedDocs[i], matchedDocs[j] = matchedDocs[j], matchedDocs[i]
+		}
+	}
+
+	return matchedDocs, svc.paginate(searchTerms, page, matchedDocs, int(resultIt.TotalCount())), nil
+}
+
+// paginate sets up the paginator for a page of search results and generates
+// the cursor-based prev/next links.
+func (svc *Service) paginate(searchTerms string, page pageRequest, matchedDocs []matchedDoc, total int) *paginationDetails {
+	// The position of the first result on the page is encoded in its cursor.
+	// Fall back to the requested offset if the page is empty or the cursor
+	// cannot be decoded.
+	from := int(page.offset) + 1
+	if len(matchedDocs) != 0 {
+		if cursor, err := index.ParseCursor(matchedDocs[0].cursor); err == nil {
+			from = int(cursor.Position) + 1
+		}
+	}
+
 	pagination := &paginationDetails{
-		From:  int(offset + 1),
-		To:    int(offset) + len(matchedDocs),
-		Total: int(resultIt.TotalCount()),
+		From:  from,
+		To:    from + len(matchedDocs) - 1,
+		Total: total,
+	}
+	if len(matchedDocs) == 0 {
+		return pagination
 	}
-	if offset > 0 {
+
+	if pagination.From > 1 {
 		pagination.PrevLink = fmt.Sprintf("%s?q=%s", searchEndpoint, searchTerms)
-		if prevOffset := int(offset) - svc.cfg.ResultsPerPage; prevOffset > 0 {
-			pagination.PrevLink += fmt.Sprintf("&offset=%d", prevOffset)
+		if pagination.From-1 > svc.cfg.ResultsPerPage {
+			pagination.PrevLink += fmt.Sprintf("&cursor=%s&dir=prev", matchedDocs[0].cursor)
 		}
 	}
-	if nextPageOffset := int(offset) + len(matchedDocs); nextPageOffset < pagination.Total {
-		pagination.NextLink = fmt.Sprintf("%s?q=%s&offset=%d", searchEndpoint, searchTerms, nextPageOffset)
+	if pagination.To < pagination.Total {
+		pagination.NextLink = fmt.Sprintf("%s?q=%s&cursor=%s", searchEndpoint, searchTerms, matchedDocs[len(matchedDocs)-1].cursor)
+	}
+
+	return pagination
+}
+
+// matchedTerms appends to dst the text of all term nodes in a query tree
+// that are not excluded from the search results.
+func matchedTerms(node index.QueryNode, dst []string) []string {
+	switch n := node.(type) {
+	case *index.TermNode:
+		dst = append(dst, n.Text)
+	case *index.BoolNode:
+		for _, sub := range n.Must {
+			dst = matchedTerms(sub, dst)
+		}
+		for _, sub := range n.Should {
+			dst = matchedTerms(sub, dst)
+		}
 	}
+	return dst
+}
+
+// autocompleteResponse is the JSON payload returned by the autocomplete
+// endpoint.
+type autocompleteResponse struct {
+	Query       string   `json:"query"`
+	Completions []string `json:"completions"`
+}
+
+// spellingSuggestion encapsulates the details for rendering a "did you mean"
+// link for a search query that did not match any documents.
+type spellingSuggestion struct {
+	Expression string
+	Link       string
+}
 
-	return matchedDocs, pagination, nil
+// pageRequest describes the page of search results requested by the user.
+// If cursor is set, the page starts right after (or, if backward is true,
+// ends right before) the result it refers to; otherwise, offset specifies
+// the number of results to skip.
+type pageRequest struct {
+	offset   uint64
+	cursor   string
+	backward bool
 }
 
 // paginationDetails encapsulates the details for rendering a paginator component.
@@ -303,6 +465,7 @@ type paginationDetails struct {
 // rendering its contents in a search results view.
 type matchedDoc struct {
 	doc     *index.Document
+	cursor  string
 	summary string
 }
 
@@ -7,11 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter10/linksrus/service/frontend/mocks"
 	"github.com/golang/mock/gomock"
+	"golang.org/x/xerrors"
 	gc "gopkg.in/check.v1"
 )
 
@@ -38,11 +40,29 @@ func (s *FrontendTestSuite) TestSubmitLink(c *gc.C) {
 	c.Assert(res.Code, gc.Equals, http.StatusOK)
 }
 
+func (s *FrontendTestSuite) TestSubmitLinkCanonicalization(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	fe, mockGraph, _ := s.setupService(c, ctrl)
+	mockGraph.EXPECT().UpsertLink(&graph.Link{
+		URL: "https://www.example.com/a?id=1",
+	}).Return(nil)
+
+	req := httptest.NewRequest("POST", submitLinkEndpoint, nil)
+	req.Form = url.Values{}
+	req.Form.Add("link", "HTTPS://WWW.Example.com:443/a/b/../?utm_source=x&id=1#top")
+	res := httptest.NewRecorder()
+	fe.router.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusOK)
+}
+
 func (s *FrontendTestSuite) TestSearch(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
 
-	mockIt := s.mockIterator(ctrl, 10)
+	mockIt := s.mockIterator(ctrl, 10, 0, 1)
 
 	fe, _, mockIndex := s.setupService(c, ctrl)
 	mockIndex.EXPECT().Search(gomock.Any()).Return(mockIt, nil)
@@ -53,7 +73,7 @@ func (s *FrontendTestSuite) TestSearch(c *gc.C) {
 		c.Assert(pgDetails.To, gc.Equals, 2)
 		c.Assert(pgDetails.Total, gc.Equals, 10)
 		c.Assert(pgDetails.PrevLink, gc.Equals, "")
-		c.Assert(pgDetails.NextLink, gc.Equals, "/search?q=KEYWORD&offset=2")
+		c.Assert(pgDetails.NextLink, gc.Equals, "/search?q=KEYWORD&cursor="+cursorAt(1))
 		return nil
 	}
 
@@ -64,14 +84,162 @@ func (s *FrontendTestSuite) TestSearch(c *gc.C) {
 	c.Assert(res.Code, gc.Equals, http.StatusOK)
 }
 
-func (s *FrontendTestSuite) TestPaginatedSearchOnSecondPage(c *gc.C) {
+func (s *FrontendTestSuite) TestSearchQueryParsing(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockIt := s.mockIterator(ctrl, 10, 0, 1)
+
+	fe, _, mockIndex := s.setupService(c, ctrl)
+	mockIndex.EXPECT().Search(index.Query{
+		Type:       index.QueryTypeMatch,
+		Expression: `"lorem ipsum" -dolor site:example.com`,
+		Tree: &index.BoolNode{
+			Should: []index.QueryNode{
+				&index.TermNode{Text: "lorem ipsum", Phrase: true},
+				&index.SiteNode{Host: "example.com"},
+			},
+			MustNot: []index.QueryNode{
+				&index.TermNode{Text: "dolor"},
+			},
+		},
+	}).Return(mockIt, nil)
+
+	fe.tplExecutor = func(_ *template.Template, _ io.Writer, data map[string]interface{}) error {
+		c.Assert(data["searchTerms"], gc.Equals, `"lorem ipsum" -dolor site:example.com`)
+		return nil
+	}
+
+	req := httptest.NewRequest("GET", searchEndpoint+"?q="+url.QueryEscape(`"lorem ipsum" -dolor site:example.com`), nil)
+	res := httptest.NewRecorder()
+	fe.router.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusOK)
+}
+
+func (s *FrontendTestSuite) TestSearchWithSpellingSuggestion(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockIt := s.emptyIterator(ctrl)
+
+	fe, _, mockIndex := s.setupService(c, ctrl)
+	mockIndex.EXPECT().Search(gomock.Any()).Return(mockIt, nil)
+	mockIndex.EXPECT().Suggest("lorm ipsun").Return([]string{"lorem ipsum", "lore ipsum"}, nil)
+
+	fe.tplExecutor = func(_ *template.Template, _ io.Writer, data map[string]interface{}) error {
+		c.Assert(data["suggestion"], gc.DeepEquals, &spellingSuggestion{
+			Expression: "lorem ipsum",
+			Link:       "/search?q=lorem+ipsum",
+		})
+		return nil
+	}
+
+	req := httptest.NewRequest("GET", searchEndpoint+"?q="+url.QueryEscape("lorm ipsun"), nil)
+	res := httptest.NewRecorder()
+	fe.router.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusOK)
+}
+
+func (s *FrontendTestSuite) TestSearchWithoutSpellingSuggestion(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
 
-	mockIt := s.mockIterator(ctrl, 10)
+	mockIt := s.emptyIterator(ctrl)
 
 	fe, _, mockIndex := s.setupService(c, ctrl)
 	mockIndex.EXPECT().Search(gomock.Any()).Return(mockIt, nil)
+	mockIndex.EXPECT().Suggest("lorem").Return(nil, nil)
+
+	fe.tplExecutor = func(_ *template.Template, _ io.Writer, data map[string]interface{}) error {
+		c.Assert(data["suggestion"], gc.IsNil)
+		return nil
+	}
+
+	req := httptest.NewRequest("GET", searchEndpoint+"?q=lorem", nil)
+	res := httptest.NewRecorder()
+	fe.router.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusOK)
+}
+
+func (s *FrontendTestSuite) TestAutocomplete(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	fe, _, mockIndex := s.setupService(c, ctrl)
+	mockIndex.EXPECT().Complete("lorem ips").Return([]string{"lorem ipsum", "Lorem Ipsum Dolor"}, nil)
+
+	req := httptest.NewRequest("GET", autocompleteEndpoint+"?q="+url.QueryEscape("lorem ips"), nil)
+	res := httptest.NewRecorder()
+	fe.router.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusOK)
+	c.Assert(res.Header().Get("Content-Type"), gc.Equals, "application/json")
+	c.Assert(res.Body.String(), gc.Equals, `{"query":"lorem ips","completions":["lorem ipsum","Lorem Ipsum Dolor"]}`+"\n")
+}
+
+func (s *FrontendTestSuite) TestAutocompleteWithEmptyQuery(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	fe, _, _ := s.setupService(c, ctrl)
+
+	req := httptest.NewRequest("GET", autocompleteEndpoint+"?q=+", nil)
+	res := httptest.NewRecorder()
+	fe.router.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusOK)
+	c.Assert(res.Body.String(), gc.Equals, `{"query":" ","completions":[]}`+"\n")
+}
+
+func (s *FrontendTestSuite) TestAutocompleteError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	fe, _, mockIndex := s.setupService(c, ctrl)
+	mockIndex.EXPECT().Complete("lorem").Return(nil, xerrors.New("index unavailable"))
+
+	req := httptest.NewRequest("GET", autocompleteEndpoint+"?q=lorem", nil)
+	res := httptest.NewRecorder()
+	fe.router.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusInternalServerError)
+}
+
+func (s *FrontendTestSuite) TestIndexPageIncludesAutocompleteScript(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	fe, _, _ := s.setupService(c, ctrl)
+
+	req := httptest.NewRequest("GET", indexEndpoint, nil)
+	res := httptest.NewRecorder()
+	fe.router.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusOK)
+	c.Assert(strings.Contains(res.Body.String(), `fetch("/autocomplete" + '?q='`), gc.Equals, true)
+}
+
+func (s *FrontendTestSuite) TestMatchedTerms(c *gc.C) {
+	tree, err := index.ParseQuery(`+lorem "ipsum dolor" -sit (amet OR NOT elit) site:example.com`)
+	c.Assert(err, gc.IsNil)
+	c.Assert(matchedTerms(tree, nil), gc.DeepEquals, []string{"lorem", "ipsum dolor", "amet"})
+}
+
+func (s *FrontendTestSuite) TestPaginatedSearchOnSecondPage(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockIt := s.mockIterator(ctrl, 10, 2, 1)
+
+	fe, _, mockIndex := s.setupService(c, ctrl)
+	mockIndex.EXPECT().Search(gomock.Any()).DoAndReturn(func(q index.Query) (index.Iterator, error) {
+		c.Assert(q.Cursor, gc.Equals, cursorAt(1))
+		c.Assert(q.Backward, gc.Equals, false)
+		return mockIt, nil
+	})
 
 	fe.tplExecutor = func(_ *template.Template, _ io.Writer, data map[string]interface{}) error {
 		pgDetails := data["pagination"].(*paginationDetails)
@@ -79,11 +247,11 @@ func (s *FrontendTestSuite) TestPaginatedSearchOnSecondPage(c *gc.C) {
 		c.Assert(pgDetails.To, gc.Equals, 4)
 		c.Assert(pgDetails.Total, gc.Equals, 10)
 		c.Assert(pgDetails.PrevLink, gc.Equals, "/search?q=KEYWORD")
-		c.Assert(pgDetails.NextLink, gc.Equals, "/search?q=KEYWORD&offset=4")
+		c.Assert(pgDetails.NextLink, gc.Equals, "/search?q=KEYWORD&cursor="+cursorAt(3))
 		return nil
 	}
 
-	req := httptest.NewRequest("GET", searchEndpoint+"?q=KEYWORD&offset=2", nil)
+	req := httptest.NewRequest("GET", searchEndpoint+"?q=KEYWORD&cursor="+cursorAt(1), nil)
 	res := httptest.NewRecorder()
 	fe.router.ServeHTTP(res, req)
 
@@ -94,7 +262,7 @@ func (s *FrontendTestSuite) TestPaginatedSearchOnThirdPage(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
 
-	mockIt := s.mockIterator(ctrl, 10)
+	mockIt := s.mockIterator(ctrl, 10, 4, 1)
 
 	fe, _, mockIndex := s.setupService(c, ctrl)
 	mockIndex.EXPECT().Search(gomock.Any()).Return(mockIt, nil)
@@ -104,33 +272,73 @@ func (s *FrontendTestSuite) TestPaginatedSearchOnThirdPage(c *gc.C) {
 		c.Assert(pgDetails.From, gc.Equals, 5)
 		c.Assert(pgDetails.To, gc.Equals, 6)
 		c.Assert(pgDetails.Total, gc.Equals, 10)
-		c.Assert(pgDetails.PrevLink, gc.Equals, "/search?q=KEYWORD&offset=2")
-		c.Assert(pgDetails.NextLink, gc.Equals, "/search?q=KEYWORD&offset=6")
+		c.Assert(pgDetails.PrevLink, gc.Equals, "/search?q=KEYWORD&cursor="+cursorAt(4)+"&dir=prev")
+		c.Assert(pgDetails.NextLink, gc.Equals, "/search?q=KEYWORD&cursor="+cursorAt(5))
 		return nil
 	}
 
-	req := httptest.NewRequest("GET", searchEndpoint+"?q=KEYWORD&offset=4", nil)
+	req := httptest.NewRequest("GET", searchEndpoint+"?q=KEYWORD&cursor="+cursorAt(3), nil)
 	res := httptest.NewRecorder()
 	fe.router.ServeHTTP(res, req)
 
 	c.Assert(res.Code, gc.Equals, http.StatusOK)
 }
 
-func (s *FrontendTestSuite) TestPaginatedSearchOnLastPage(c *gc.C) {
+func (s *FrontendTestSuite) TestPaginatedSearchBackwards(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
 
-	mockIt := s.mockIterator(ctrl, 10)
+	// When paging backwards, results are returned in reverse order.
+	mockIt := s.mockIterator(ctrl, 10, 5, -1)
 
 	fe, _, mockIndex := s.setupService(c, ctrl)
-	mockIndex.EXPECT().Search(gomock.Any()).Return(mockIt, nil)
+	mockIndex.EXPECT().Search(gomock.Any()).DoAndReturn(func(q index.Query) (index.Iterator, error) {
+		c.Assert(q.Cursor, gc.Equals, cursorAt(6))
+		c.Assert(q.Backward, gc.Equals, true)
+		return mockIt, nil
+	})
+
+	fe.tplExecutor = func(_ *template.Template, _ io.Writer, data map[string]interface{}) error {
+		results := data["results"].([]matchedDoc)
+		c.Assert(results, gc.HasLen, 2)
+		c.Assert(results[0].cursor, gc.Equals, cursorAt(4))
+		c.Assert(results[1].cursor, gc.Equals, cursorAt(5))
+
+		pgDetails := data["pagination"].(*paginationDetails)
+		c.Assert(pgDetails.From, gc.Equals, 5)
+		c.Assert(pgDetails.To, gc.Equals, 6)
+		c.Assert(pgDetails.Total, gc.Equals, 10)
+		c.Assert(pgDetails.PrevLink, gc.Equals, "/search?q=KEYWORD&cursor="+cursorAt(4)+"&dir=prev")
+		c.Assert(pgDetails.NextLink, gc.Equals, "/search?q=KEYWORD&cursor="+cursorAt(5))
+		return nil
+	}
+
+	req := httptest.NewRequest("GET", searchEndpoint+"?q=KEYWORD&cursor="+cursorAt(6)+"&dir=prev", nil)
+	res := httptest.NewRecorder()
+	fe.router.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusOK)
+}
+
+func (s *FrontendTestSuite) TestPaginatedSearchOnLastPageWithOffset(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockIt := s.mockIterator(ctrl, 10, 8, 1)
+
+	fe, _, mockIndex := s.setupService(c, ctrl)
+	mockIndex.EXPECT().Search(gomock.Any()).DoAndReturn(func(q index.Query) (index.Iterator, error) {
+		c.Assert(q.Offset, gc.Equals, uint64(8))
+		c.Assert(q.Cursor, gc.Equals, "")
+		return mockIt, nil
+	})
 
 	fe.tplExecutor = func(_ *template.Template, _ io.Writer, data map[string]interface{}) error {
 		pgDetails := data["pagination"].(*paginationDetails)
 		c.Assert(pgDetails.From, gc.Equals, 9)
 		c.Assert(pgDetails.To, gc.Equals, 10)
 		c.Assert(pgDetails.Total, gc.Equals, 10)
-		c.Assert(pgDetails.PrevLink, gc.Equals, "/search?q=KEYWORD&offset=6")
+		c.Assert(pgDetails.PrevLink, gc.Equals, "/search?q=KEYWORD&cursor="+cursorAt(8)+"&dir=prev")
 		c.Assert(pgDetails.NextLink, gc.Equals, "")
 		return nil
 	}
@@ -157,7 +365,16 @@ func (s *FrontendTestSuite) setupService(c *gc.C, ctrl *gomock.Controller) (*Ser
 	return fe, mockGraph, mockIndexer
 }
 
-func (s *FrontendTestSuite) mockIterator(ctrl *gomock.Controller, numResults int) *mocks.MockIterator {
+func (s *FrontendTestSuite) emptyIterator(ctrl *gomock.Controller) *mocks.MockIterator {
+	it := mocks.NewMockIterator(ctrl)
+	it.EXPECT().TotalCount().Return(uint64(0))
+	it.EXPECT().Next().Return(false)
+	it.EXPECT().Error().Return(nil)
+	it.EXPECT().Close().Return(nil)
+	return it
+}
+
+func (s *FrontendTestSuite) mockIterator(ctrl *gomock.Controller, numResults, firstPos, step int) *mocks.MockIterator {
 	it := mocks.NewMockIterator(ctrl)
 	it.EXPECT().TotalCount().Return(uint64(numResults))
 
@@ -175,7 +392,15 @@ func (s *FrontendTestSuite) mockIterator(ctrl *gomock.Controller, numResults int
 		}
 	}).MaxTimes(numResults)
 
+	it.EXPECT().Cursor().DoAndReturn(func() string {
+		return cursorAt(firstPos + (nextDoc-1)*step)
+	}).AnyTimes()
+
 	it.EXPECT().Error().Return(nil)
 	it.EXPECT().Close().Return(nil)
 	return it
 }
+
+func cursorAt(pos int) string {
+	return index.Cursor{Position: uint64(pos)}.String()
+}
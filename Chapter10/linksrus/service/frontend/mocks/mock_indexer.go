@@ -47,6 +47,20 @@ func (mr *MockIteratorMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIterator)(nil).Close))
 }
 
+// Cursor mocks base method
+func (m *MockIterator) Cursor() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cursor")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Cursor indicates an expected call of Cursor
+func (mr *MockIteratorMockRecorder) Cursor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cursor", reflect.TypeOf((*MockIterator)(nil).Cursor))
+}
+
 // Document mocks base method
 func (m *MockIterator) Document() *index.Document {
 	m.ctrl.T.Helper()
@@ -75,6 +89,20 @@ func (mr *MockIteratorMockRecorder) Error() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockIterator)(nil).Error))
 }
 
+// Facets mocks base method
+func (m *MockIterator) Facets() []index.FacetResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Facets")
+	ret0, _ := ret[0].([]index.FacetResult)
+	return ret0
+}
+
+// Facets indicates an expected call of Facets
+func (mr *MockIteratorMockRecorder) Facets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Facets", reflect.TypeOf((*MockIterator)(nil).Facets))
+}
+
 // Next mocks base method
 func (m *MockIterator) Next() bool {
 	m.ctrl.T.Helper()
@@ -71,6 +71,21 @@ func (m *MockIndexAPI) EXPECT() *MockIndexAPIMockRecorder {
 	return m.recorder
 }
 
+// Complete mocks base method
+func (m *MockIndexAPI) Complete(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Complete indicates an expected call of Complete
+func (mr *MockIndexAPIMockRecorder) Complete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIndexAPI)(nil).Complete), arg0)
+}
+
 // Search mocks base method
 func (m *MockIndexAPI) Search(arg0 index.Query) (index.Iterator, error) {
 	m.ctrl.T.Helper()
@@ -85,3 +100,18 @@ func (mr *MockIndexAPIMockRecorder) Search(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockIndexAPI)(nil).Search), arg0)
 }
+
+// Suggest mocks base method
+func (m *MockIndexAPI) Suggest(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest
+func (mr *MockIndexAPIMockRecorder) Suggest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockIndexAPI)(nil).Suggest), arg0)
+}
@@ -55,6 +55,22 @@ func (h *matchSummarizer) MatchSummary(content string) string {
 	return strings.TrimSpace(h.sumBuf.String())
 }
 
+// DocumentSummary returns a summary for a document with the specified meta
+// description and content. The description is preferred if it contains at
+// least one of the search terms; otherwise, a summary is generated from the
+// matching sentences of the content.
+func (h *matchSummarizer) DocumentSummary(description, content string) string {
+	description = strings.TrimSpace(description)
+	if description == "" || h.matchRatio(description) == 0 {
+		return h.MatchSummary(content)
+	}
+
+	if runes := []rune(description); len(runes) > h.maxSummaryLen {
+		description = string(runes[:h.maxSummaryLen]) + "..."
+	}
+	return description
+}
+
 func (h *matchSummarizer) snippetsForSummary(content string) []*matchSnippet {
 	// Split content in sentences and keep the ones with at least one matching term.
 	var matches []*matchSnippet
@@ -57,3 +57,15 @@ ea commodi consequatur?.`[1:]
 	summary := summarizer.MatchSummary(input)
 	c.Assert(summary, gc.Equals, expSummary)
 }
+
+func (s *SummarizerTestSuite) TestDocumentSummary(c *gc.C) {
+	content := "Lorem ipsum dolor sit amet. Consectetur KEYWORD adipiscing elit."
+	summarizer := newMatchSummarizer("KEYWORD", 16)
+
+	// Descriptions that match the search terms are preferred.
+	c.Assert(summarizer.DocumentSummary("A keyword description", content), gc.Equals, "A keyword descri...")
+
+	// Otherwise, the summary is generated from the content.
+	c.Assert(summarizer.DocumentSummary("An unrelated description", content), gc.Equals, summarizer.MatchSummary(content))
+	c.Assert(summarizer.DocumentSummary("", content), gc.Equals, summarizer.MatchSummary(content))
+}
@@ -41,7 +41,7 @@ var (
 			<br/><br/>
       <a rel="nofollow" href="{{.submitLinkEndpoint}}">Submit Web Site</a>
     </section>
-  </body>
+` + autocompleteScript + `  </body>
 </html>
 `))
 
@@ -90,7 +90,7 @@ var (
     <section class="rc">
       <span class="rt">{{.messageContent}}</span>
     </section>
-  </body>
+` + autocompleteScript + `  </body>
 </html>
 `))
 
@@ -119,6 +119,7 @@ var (
 			.rc cite{color:green;font-size:0.8em;display:block;margin-bottom:2px;}
 			.rc .ms {text-align:justify;font-size:0.9em;}
 			.rc .ms em{background-color:yellow;font-weight:bold;}
+			.rc .dym {margin:10px 0 0 0;font-size:0.9em;}
 			.nb{padding:15px 20px;border-top:1px solid gray;}
 			.nb a{padding-right:15px;text-decoration:none;color:blue;}
 			.nb a:visited{color:blue;}
@@ -161,9 +162,10 @@ var (
 		{{else}}
     <section class="rc">
       <span class="rt">Your search query did not match any pages.</span>
+		  {{if .suggestion}}<p class="dym">Did you mean <a rel="nofollow" href="{{.suggestion.Link}}">{{.suggestion.Expression}}</a>?</p>{{end}}
     </section>
 		{{end}}
-  </body>
+` + autocompleteScript + `  </body>
 </html>
 `))
 
@@ -213,3 +215,42 @@ var (
 </html>
 `))
 )
+
+// autocompleteScript progressively enhances the search box of a page with
+// completions retrieved from the autocomplete endpoint. Browsers without
+// JavaScript or fetch support fall back to a plain search box.
+const autocompleteScript = `    <script>
+      (function() {
+        var input = document.querySelector('input[name="q"]');
+        if (!input || !window.fetch) { return; }
+
+        var list = document.createElement('datalist');
+        list.id = 'ac';
+        document.body.appendChild(list);
+        input.setAttribute('list', list.id);
+        input.setAttribute('autocomplete', 'off');
+
+        var timer, lastQuery = '';
+        input.addEventListener('input', function() {
+          clearTimeout(timer);
+          timer = setTimeout(function() {
+            var query = input.value;
+            if (query === lastQuery || query.trim() === '') { return; }
+            lastQuery = query;
+            fetch({{.autocompleteEndpoint}} + '?q=' + encodeURIComponent(query)).then(function(res) {
+              return res.ok ? res.json() : {completions: []};
+            }).then(function(data) {
+              // Ignore responses for stale queries.
+              if (input.value !== query) { return; }
+              list.innerHTML = '';
+              (data.completions || []).forEach(function(completion) {
+                var opt = document.createElement('option');
+                opt.value = completion;
+                list.appendChild(opt);
+              });
+            }).catch(function() {});
+          }, 150);
+        });
+      })();
+    </script>
+`
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(new(MetricsTestSuite))
+
+type MetricsTestSuite struct{}
+
+func (s *MetricsTestSuite) TestConfigValidation(c *gc.C) {
+	cfg := Config{ListenAddr: ":9090"}
+	c.Assert(cfg.validate(), gc.IsNil)
+	c.Assert(cfg.Gatherer, gc.Equals, prometheus.DefaultGatherer, gc.Commentf("default gatherer was not assigned"))
+	c.Assert(cfg.Logger, gc.Not(gc.IsNil), gc.Commentf("default logger was not assigned"))
+
+	cfg = Config{}
+	c.Assert(cfg.validate(), gc.ErrorMatches, "listen address has not been specified")
+}
+
+func (s *MetricsTestSuite) TestExportMetrics(c *gc.C) {
+	reg := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "test_counter_total",
+		Help: "A counter for testing purposes",
+	})
+	c.Assert(reg.Register(counter), gc.IsNil)
+	counter.Add(42)
+
+	svc, err := NewService(Config{ListenAddr: ":0", Gatherer: reg})
+	c.Assert(err, gc.IsNil)
+
+	req := httptest.NewRequest("GET", metricsEndpoint, nil)
+	res := httptest.NewRecorder()
+	svc.mux.ServeHTTP(res, req)
+
+	c.Assert(res.Code, gc.Equals, http.StatusOK)
+	body, err := ioutil.ReadAll(res.Body)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(body), gc.Matches, "(?s).*test_counter_total 42.*")
+}
+
+func Test(t *testing.T) {
+	// Run all gocheck test-suites
+	gc.TestingT(t)
+}
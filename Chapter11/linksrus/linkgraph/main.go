@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	_ "net/http/pprof"
@@ -13,6 +14,7 @@ import (
 	"syscall"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/graph"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/store/bolt"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/store/cdb"
 	memgraph "github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/linkgraph/store/memory"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi"
@@ -55,7 +57,7 @@ func makeApp() *cli.App {
 			Name:   "link-graph-uri",
 			Value:  "in-memory://",
 			EnvVar: "LINK_GRAPH_URI",
-			Usage:  "The URI for connecting to the link-graph (supported URIs: in-memory://, postgresql://user@host:26257/linkgraph?sslmode=disable)",
+			Usage:  "The URI for connecting to the link-graph (supported URIs: in-memory://, bolt:///path/to/data/dir, postgresql://user@host:26257/linkgraph?sslmode=disable)",
 		},
 		cli.IntFlag{
 			Name:   "grpc-port",
@@ -71,6 +73,7 @@ func makeApp() *cli.App {
 		},
 	}
 	app.Action = runMain
+	app.Commands = snapshotCommands()
 	return app
 }
 
@@ -84,6 +87,12 @@ func runMain(appCtx *cli.Context) error {
 		return err
 	}
 
+	// Ensure that graph stores that persist their data locally (e.g. bolt)
+	// get a chance to flush it and release their DB files on shutdown.
+	if closer, ok := graph.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+
 	// Start gRPC server
 	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", appCtx.Int("grpc-port")))
 	if err != nil {
@@ -148,6 +157,12 @@ func getLinkGraph(linkGraphURI string) (graph.Graph, error) {
 	case "in-memory":
 		logger.Info("using in-memory graph")
 		return memgraph.NewInMemoryGraph(), nil
+	case "bolt":
+		if uri.Path == "" {
+			return nil, xerrors.Errorf("bolt link graph URI must specify a data directory path")
+		}
+		logger.WithField("path", uri.Path).Info("using bolt graph")
+		return bolt.NewBoltGraph(uri.Path)
 	case "postgresql":
 		logger.Info("using CDB graph")
 		return cdb.NewCockroachDbGraph(linkGraphURI)
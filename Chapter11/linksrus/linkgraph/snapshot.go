@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi"
+	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/linkgraphapi/proto"
+	"github.com/urfave/cli"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+)
+
+func snapshotCommands() []cli.Command {
+	apiFlag := cli.StringFlag{
+		Name:   "link-graph-api",
+		Value:  "localhost:8080",
+		EnvVar: "LINK_GRAPH_API",
+		Usage:  "The gRPC endpoint for connecting to the link graph",
+	}
+
+	return []cli.Command{
+		{
+			Name:  "export",
+			Usage: "Export a compressed snapshot of the link graph",
+			Flags: []cli.Flag{
+				apiFlag,
+				cli.StringFlag{
+					Name:  "output, o",
+					Value: "-",
+					Usage: "The file to write the snapshot to; use - for STDOUT",
+				},
+			},
+			Action: runExport,
+		},
+		{
+			Name:  "import",
+			Usage: "Import a compressed snapshot into the link graph",
+			Flags: []cli.Flag{
+				apiFlag,
+				cli.StringFlag{
+					Name:  "input, i",
+					Value: "-",
+					Usage: "The file to read the snapshot from; use - for STDIN",
+				},
+			},
+			Action: runImport,
+		},
+	}
+}
+
+func runExport(appCtx *cli.Context) error {
+	graphCli, closeFn, err := getLinkGraphAPI(appCtx.String("link-graph-api"))
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	var w io.Writer = os.Stdout
+	if path := appCtx.String("output"); path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return xerrors.Errorf("could not create snapshot file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	if err = graphCli.Export(w); err != nil {
+		return xerrors.Errorf("export failed: %w", err)
+	}
+
+	logger.Info("exported link graph snapshot")
+	return nil
+}
+
+func runImport(appCtx *cli.Context) error {
+	graphCli, closeFn, err := getLinkGraphAPI(appCtx.String("link-graph-api"))
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	var r io.Reader = os.Stdin
+	if path := appCtx.String("input"); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return xerrors.Errorf("could not open snapshot file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	stats, err := graphCli.Import(r)
+	if err != nil {
+		return xerrors.Errorf("import failed: %w", err)
+	}
+
+	logger.WithField("links", stats.Links).WithField("edges", stats.Edges).Info("imported link graph snapshot")
+	return nil
+}
+
+func getLinkGraphAPI(linkGraphAPI string) (*linkgraphapi.LinkGraphClient, func(), error) {
+	if linkGraphAPI == "" {
+		return nil, nil, xerrors.Errorf("link graph API must be specified with --link-graph-api")
+	}
+
+	dialCtx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFn()
+	conn, err := grpc.DialContext(dialCtx, linkGraphAPI, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return nil, nil, xerrors.Errorf("could not connect to link graph API: %w", err)
+	}
+
+	graphCli := linkgraphapi.NewLinkGraphClient(context.Background(), proto.NewLinkGraphClient(conn))
+	return graphCli, func() { _ = conn.Close() }, nil
+}
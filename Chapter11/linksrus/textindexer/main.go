@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	_ "net/http/pprof"
@@ -14,6 +15,7 @@ import (
 	"syscall"
 
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/index"
+	diskindex "github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/disk"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/es"
 	memindex "github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter06/textindexer/store/memory"
 	"github.com/PacktPublishing/Hands-On-Software-Engineering-with-Golang/Chapter09/linksrus/textindexerapi"
@@ -56,7 +58,7 @@ func makeApp() *cli.App {
 			Name:   "text-indexer-uri",
 			Value:  "in-memory://",
 			EnvVar: "TEXT_INDEXER_URI",
-			Usage:  "The URI for connecting to the text indexer (supported URIs: in-memory://, es://node1:9200,...,nodeN:9200)",
+			Usage:  "The URI for connecting to the text indexer (supported URIs: in-memory://, bleve:///path/to/index/dir, es://node1:9200,...,nodeN:9200)",
 		},
 		cli.IntFlag{
 			Name:   "grpc-port",
@@ -84,6 +86,9 @@ func runMain(appCtx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if closer, ok := indexer.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
 
 	// Start gRPC server
 	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", appCtx.Int("grpc-port")))
@@ -149,6 +154,12 @@ func getTextIndexer(textIndexerURI string) (index.Indexer, error) {
 	case "in-memory":
 		logger.Info("using in-memory indexer")
 		return memindex.NewInMemoryBleveIndexer()
+	case "bleve":
+		if uri.Path == "" {
+			return nil, xerrors.Errorf("bleve text indexer URI must specify an index directory path")
+		}
+		logger.WithField("path", uri.Path).Info("using on-disk bleve indexer")
+		return diskindex.NewOnDiskBleveIndexer(uri.Path)
 	case "es":
 		nodes := strings.Split(uri.Host, ",")
 		for i := 0; i < len(nodes); i++ {